@@ -0,0 +1,26 @@
+package jupiter
+
+import rego.v1
+
+# Input là nội dung source.yml (đã convert sang JSON).
+
+# Service tier 1 phải có ít nhất 2 members để đảm bảo on-call
+deny contains msg if {
+	input.metadata.tier == 1
+	count(input.members) < 2
+	msg := sprintf("tier-1 service '%s' must declare at least 2 members", [input.name])
+}
+
+# Team nằm trong danh sách không được tạo public repo
+deny contains msg if {
+	input.visibility == "public"
+	input.metadata.team in data.jupiter.config.no_public_teams
+	msg := sprintf("team '%s' is not allowed to create public repositories", [input.metadata.team])
+}
+
+# Service xử lý PII chỉ được là private repo (internal vẫn lộ cho cả org)
+deny contains msg if {
+	input.metadata.data_classification == "pii"
+	input.visibility != "private"
+	msg := sprintf("pii service '%s' must use visibility private", [input.name])
+}
@@ -0,0 +1,60 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// writeStepOutputs ghi step outputs cho GitHub Actions (GITHUB_OUTPUT), không chạy trong Actions thì bỏ qua.
+// Giá trị nhiều dòng dùng cú pháp heredoc với delimiter ngẫu nhiên để nội dung không thể đóng heredoc sớm.
+func writeStepOutputs(outputs map[string]string) error {
+	path := os.Getenv("GITHUB_OUTPUT")
+	if path == "" {
+		return nil
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to open GITHUB_OUTPUT: %w", err)
+	}
+	defer f.Close()
+
+	keys := make([]string, 0, len(outputs))
+	for k := range outputs {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		value := outputs[k]
+		if !strings.Contains(value, "\n") {
+			if _, err := fmt.Fprintf(f, "%s=%s\n", k, value); err != nil {
+				return err
+			}
+			continue
+		}
+		random := make([]byte, 8)
+		if _, err := rand.Read(random); err != nil {
+			return err
+		}
+		delimiter := "ghadelimiter_" + hex.EncodeToString(random)
+		if _, err := fmt.Fprintf(f, "%s<<%s\n%s\n%s\n", k, delimiter, value, delimiter); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// provisionedRepos: repo được tạo mới trong lần chạy (có trong state sau nhưng không có trong state trước)
+func provisionedRepos(before, after *RegistryState) []string {
+	var repos []string
+	for name, entry := range after.Services {
+		if _, existed := before.Services[name]; !existed && entry.Origin == "provisioned" && entry.Repo != "" {
+			repos = append(repos, entry.Repo)
+		}
+	}
+	sort.Strings(repos)
+	return repos
+}
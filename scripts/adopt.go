@@ -0,0 +1,174 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// githubLanguages map GitHub linguist language -> programming_language của registry
+var githubLanguages = map[string]string{
+	"Go":         "golang",
+	"TypeScript": "nodejs",
+	"JavaScript": "nodejs",
+}
+
+// runAdoptCommand inspect repo có sẵn và ghi source.yml + state entry (best-effort)
+func runAdoptCommand(args []string) error {
+	fs := flag.NewFlagSet("adopt", flag.ExitOnError)
+	force := fs.Bool("force", false, "overwrite an existing source.yml")
+	fs.Parse(args)
+
+	if fs.NArg() < 1 || !strings.Contains(fs.Arg(0), "/") {
+		return fmt.Errorf("usage: go run ./scripts adopt [--force] <owner/repo>")
+	}
+	fullName := fs.Arg(0)
+	repoName := fullName[strings.LastIndex(fullName, "/")+1:]
+
+	servicePath := filepath.Join(sourcesDir, repoName)
+	sourceFile := filepath.Join(servicePath, "source.yml")
+	if _, err := os.Stat(sourceFile); err == nil && !*force {
+		return fmt.Errorf("%s already exists (use --force to overwrite)", sourceFile)
+	}
+
+	fmt.Printf("🔎 Inspecting %s...\n", fullName)
+
+	// Step 1: Detect ngôn ngữ chính
+	language, err := detectRepoLanguage(fullName)
+	if err != nil {
+		return err
+	}
+
+	// Step 2: Detect module path (go.mod / package.json)
+	module := detectRepoModule(fullName, language)
+
+	// Step 3: Collaborators -> members
+	members, err := listCollaborators(fullName)
+	if err != nil {
+		return err
+	}
+
+	sourceID, err := newSourceID()
+	if err != nil {
+		return err
+	}
+
+	config := SourceConfig{
+		SourceID: sourceID,
+		Name:     repoName,
+		Members:  members,
+		Metadata: Metadata{
+			ProgrammingLanguage: language,
+			Module:              module,
+		},
+	}
+
+	data, err := yaml.Marshal(config)
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(servicePath, 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", servicePath, err)
+	}
+	if err := os.WriteFile(sourceFile, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", sourceFile, err)
+	}
+
+	if err := recordServiceState(repoName, ServiceState{SourceID: sourceID, Repo: fullName, Origin: "adopted", Repository: enrichRepoMetadata(commandExecutor, fullName, nil)}); err != nil {
+		return fmt.Errorf("failed to record state: %w", err)
+	}
+
+	fmt.Printf("✅ Adopted %s -> %s (review the generated file before committing)\n", fullName, sourceFile)
+	return nil
+}
+
+func detectRepoLanguage(fullName string) (string, error) {
+	out, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/languages", fullName))
+	if err != nil {
+		return "", fmt.Errorf("failed to read languages of %s: %w", fullName, err)
+	}
+
+	var languages map[string]int
+	if err := json.Unmarshal([]byte(out), &languages); err != nil {
+		return "", fmt.Errorf("failed to parse languages: %w", err)
+	}
+
+	// Chọn ngôn ngữ được hỗ trợ có nhiều bytes nhất
+	best, bestBytes := "", 0
+	for lang, bytes := range languages {
+		if mapped, ok := githubLanguages[lang]; ok && bytes > bestBytes {
+			best, bestBytes = mapped, bytes
+		}
+	}
+	if best == "" {
+		fmt.Println("  ⚠️ No supported language detected, leaving programming_language empty")
+	}
+	return best, nil
+}
+
+func detectRepoModule(fullName, language string) string {
+	switch language {
+	case "golang":
+		content, err := readRepoFile(commandExecutor, fullName, "go.mod")
+		if err != nil {
+			return ""
+		}
+		for _, line := range strings.Split(content, "\n") {
+			if strings.HasPrefix(line, "module ") {
+				return strings.TrimSpace(strings.TrimPrefix(line, "module "))
+			}
+		}
+	case "nodejs":
+		content, err := readRepoFile(commandExecutor, fullName, "package.json")
+		if err != nil {
+			return ""
+		}
+		var pkg struct {
+			Name string `json:"name"`
+		}
+		if json.Unmarshal([]byte(content), &pkg) == nil {
+			return pkg.Name
+		}
+	}
+	return ""
+}
+
+func readRepoFile(ex Executor, fullName, path string) (string, error) {
+	out, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/contents/%s", fullName, path), "--jq", ".content")
+	if err != nil {
+		return "", err
+	}
+	decoded, err := base64.StdEncoding.DecodeString(strings.ReplaceAll(out, "\n", ""))
+	if err != nil {
+		return "", err
+	}
+	return string(decoded), nil
+}
+
+func listCollaborators(fullName string) ([]string, error) {
+	out, err := runCommandOutput(commandExecutor, "gh", "api", "--paginate", fmt.Sprintf("repos/%s/collaborators", fullName), "--jq", ".[].login")
+	if err != nil {
+		return nil, fmt.Errorf("failed to list collaborators of %s: %w", fullName, err)
+	}
+	if out == "" {
+		return nil, nil
+	}
+	return strings.Split(out, "\n"), nil
+}
+
+// newSourceID sinh source_id ngẫu nhiên 8 ký tự hex
+func newSourceID() (string, error) {
+	b := make([]byte, 4)
+	if _, err := rand.Read(b); err != nil {
+		return "", fmt.Errorf("failed to generate source_id: %w", err)
+	}
+	return hex.EncodeToString(b), nil
+}
@@ -0,0 +1,310 @@
+package main
+
+import (
+	"context"
+	"crypto"
+	"crypto/rsa"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math/big"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APIAuth bảo vệ /api/* của server mode. Provider được thử theo thứ tự khai báo:
+//   - api_key: header X-API-Key, key đọc từ env, role + teams gán sẵn cho key
+//   - oidc: `Authorization: Bearer <JWT>` (RS256) kiểm tra qua JWKS của issuer, teams lấy từ claim
+//
+// Role: viewer (đọc) < operator (provision, retry, restore) < admin (archive, delete, mọi team).
+// Viewer / operator chỉ thao tác được service của team mình.
+type APIAuth struct {
+	Providers []string    `yaml:"providers"` // api_key | oidc, trống = API không xác thực
+	APIKeys   []APIKey    `yaml:"api_keys"`
+	OIDC      APIAuthOIDC `yaml:"oidc"`
+	// Roles map team -> role cho principal OIDC (lấy role cao nhất trong các team)
+	Roles map[string]string `yaml:"roles"`
+}
+
+type APIKey struct {
+	Name   string   `yaml:"name"`
+	KeyEnv string   `yaml:"key_env"`
+	Role   string   `yaml:"role"`
+	Teams  []string `yaml:"teams"`
+}
+
+type APIAuthOIDC struct {
+	Issuer     string `yaml:"issuer"`
+	Audience   string `yaml:"audience"`
+	JWKSURL    string `yaml:"jwks_url"`    // mặc định <issuer>/.well-known/jwks.json
+	TeamsClaim string `yaml:"teams_claim"` // mặc định groups
+}
+
+const (
+	roleViewer   = "viewer"
+	roleOperator = "operator"
+	roleAdmin    = "admin"
+)
+
+var roleRank = map[string]int{roleViewer: 1, roleOperator: 2, roleAdmin: 3}
+
+// Principal là người gọi API đã xác thực
+type Principal struct {
+	Subject string
+	Role    string
+	Teams   []string
+}
+
+// can: đủ role và (admin hoặc service thuộc team của principal)
+func (p Principal) can(role, team string) bool {
+	if roleRank[p.Role] < roleRank[role] {
+		return false
+	}
+	return p.Role == roleAdmin || (team != "" && containsString(p.Teams, team))
+}
+
+type principalKey struct{}
+
+func principalFrom(r *http.Request) Principal {
+	p, _ := r.Context().Value(principalKey{}).(Principal)
+	return p
+}
+
+// requireRole trả lời 403 (và false) khi principal không được phép thao tác trên service của team
+func requireRole(w http.ResponseWriter, r *http.Request, role, team string) bool {
+	if p := principalFrom(r); !p.can(role, team) {
+		http.Error(w, fmt.Sprintf("%s requires role %s on team %q", p.Subject, role, team), http.StatusForbidden)
+		return false
+	}
+	return true
+}
+
+// withAuth xác thực request và kiểm tra role tối thiểu (chưa xét team) trước khi gọi handler
+func withAuth(auth APIAuth, role string, next http.HandlerFunc) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		p, err := auth.authenticate(r)
+		if err != nil {
+			w.Header().Set("WWW-Authenticate", `Bearer realm="jupiter-registry"`)
+			http.Error(w, err.Error(), http.StatusUnauthorized)
+			return
+		}
+		if roleRank[p.Role] < roleRank[role] {
+			http.Error(w, fmt.Sprintf("%s requires role %s", p.Subject, role), http.StatusForbidden)
+			return
+		}
+		next(w, r.WithContext(context.WithValue(r.Context(), principalKey{}, p)))
+	}
+}
+
+func (a APIAuth) authenticate(r *http.Request) (Principal, error) {
+	// Không cấu hình provider: API mở như trước (mọi request là admin)
+	if len(a.Providers) == 0 {
+		return Principal{Subject: "anonymous", Role: roleAdmin}, nil
+	}
+	for _, provider := range a.Providers {
+		switch provider {
+		case "api_key":
+			if key := r.Header.Get("X-API-Key"); key != "" {
+				return a.authenticateAPIKey(key)
+			}
+		case "oidc":
+			if token, ok := strings.CutPrefix(r.Header.Get("Authorization"), "Bearer "); ok {
+				return a.authenticateOIDC(token)
+			}
+		default:
+			return Principal{}, fmt.Errorf("unsupported server.auth provider: %s", provider)
+		}
+	}
+	return Principal{}, fmt.Errorf("missing credentials")
+}
+
+func (a APIAuth) authenticateAPIKey(key string) (Principal, error) {
+	for _, k := range a.APIKeys {
+		expected := os.Getenv(k.KeyEnv)
+		if expected != "" && subtle.ConstantTimeCompare([]byte(key), []byte(expected)) == 1 {
+			return Principal{Subject: "api-key:" + k.Name, Role: k.Role, Teams: k.Teams}, nil
+		}
+	}
+	return Principal{}, fmt.Errorf("invalid API key")
+}
+
+func (a APIAuth) authenticateOIDC(token string) (Principal, error) {
+	claims, err := verifyJWT(token, a.OIDC)
+	if err != nil {
+		return Principal{}, fmt.Errorf("invalid bearer token: %w", err)
+	}
+	subject, _ := claims["sub"].(string)
+	p := Principal{Subject: subject}
+
+	claim := a.OIDC.TeamsClaim
+	if claim == "" {
+		claim = "groups"
+	}
+	values, _ := claims[claim].([]interface{})
+	for _, v := range values {
+		team, ok := v.(string)
+		if !ok {
+			continue
+		}
+		p.Teams = append(p.Teams, team)
+		if role := a.Roles[team]; roleRank[role] > roleRank[p.Role] {
+			p.Role = role
+		}
+	}
+	if p.Role == "" {
+		return Principal{}, fmt.Errorf("%s has no role in server.auth.roles", subject)
+	}
+	return p, nil
+}
+
+// verifyJWT kiểm tra chữ ký RS256 theo JWKS của issuer, iss, aud, exp / nbf
+func verifyJWT(token string, cfg APIAuthOIDC) (map[string]interface{}, error) {
+	parts := strings.Split(token, ".")
+	if len(parts) != 3 {
+		return nil, fmt.Errorf("malformed JWT")
+	}
+	var header struct {
+		Alg string `json:"alg"`
+		Kid string `json:"kid"`
+	}
+	if err := decodeJWTPart(parts[0], &header); err != nil {
+		return nil, err
+	}
+	if header.Alg != "RS256" {
+		return nil, fmt.Errorf("unsupported alg %s", header.Alg)
+	}
+	key, err := jwks.key(cfg, header.Kid)
+	if err != nil {
+		return nil, err
+	}
+	signature, err := base64.RawURLEncoding.DecodeString(parts[2])
+	if err != nil {
+		return nil, fmt.Errorf("malformed signature")
+	}
+	digest := sha256.Sum256([]byte(parts[0] + "." + parts[1]))
+	if err := rsa.VerifyPKCS1v15(key, crypto.SHA256, digest[:], signature); err != nil {
+		return nil, fmt.Errorf("bad signature")
+	}
+
+	claims := map[string]interface{}{}
+	if err := decodeJWTPart(parts[1], &claims); err != nil {
+		return nil, err
+	}
+	if iss, _ := claims["iss"].(string); iss != cfg.Issuer {
+		return nil, fmt.Errorf("unexpected issuer %q", iss)
+	}
+	if !audienceMatches(claims["aud"], cfg.Audience) {
+		return nil, fmt.Errorf("token is not for audience %q", cfg.Audience)
+	}
+	now := float64(time.Now().Unix())
+	if exp, ok := claims["exp"].(float64); !ok || now > exp {
+		return nil, fmt.Errorf("token expired")
+	}
+	if nbf, ok := claims["nbf"].(float64); ok && now < nbf {
+		return nil, fmt.Errorf("token not valid yet")
+	}
+	return claims, nil
+}
+
+func decodeJWTPart(part string, v interface{}) error {
+	data, err := base64.RawURLEncoding.DecodeString(part)
+	if err != nil {
+		return fmt.Errorf("malformed JWT")
+	}
+	return json.Unmarshal(data, v)
+}
+
+func audienceMatches(aud interface{}, audience string) bool {
+	switch v := aud.(type) {
+	case string:
+		return v == audience
+	case []interface{}:
+		for _, a := range v {
+			if a == audience {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// jwksCache giữ public key của issuer 10 phút, kid lạ thì tải lại (key rotation)
+type jwksCache struct {
+	mu        sync.Mutex
+	keys      map[string]*rsa.PublicKey
+	fetchedAt time.Time
+}
+
+var jwks = &jwksCache{}
+
+func (c *jwksCache) key(cfg APIAuthOIDC, kid string) (*rsa.PublicKey, error) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if key, ok := c.keys[kid]; ok && time.Since(c.fetchedAt) < 10*time.Minute {
+		return key, nil
+	}
+	// kid lạ ngay sau lần tải gần nhất: không tải lại (tránh bị spam token giả)
+	if c.keys != nil && time.Since(c.fetchedAt) < time.Minute {
+		if key, ok := c.keys[kid]; ok {
+			return key, nil
+		}
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+
+	url := cfg.JWKSURL
+	if url == "" {
+		url = strings.TrimRight(cfg.Issuer, "/") + "/.well-known/jwks.json"
+	}
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, err
+	}
+	var set struct {
+		Keys []struct {
+			Kid string `json:"kid"`
+			Kty string `json:"kty"`
+			N   string `json:"n"`
+			E   string `json:"e"`
+		} `json:"keys"`
+	}
+	if err := doJSONRequest(req, &set); err != nil {
+		return nil, fmt.Errorf("failed to fetch JWKS: %w", err)
+	}
+	c.keys = map[string]*rsa.PublicKey{}
+	c.fetchedAt = time.Now()
+	for _, k := range set.Keys {
+		if k.Kty != "RSA" {
+			continue
+		}
+		n, errN := base64.RawURLEncoding.DecodeString(k.N)
+		e, errE := base64.RawURLEncoding.DecodeString(k.E)
+		if errN != nil || errE != nil {
+			continue
+		}
+		c.keys[k.Kid] = &rsa.PublicKey{N: new(big.Int).SetBytes(n), E: int(new(big.Int).SetBytes(e).Int64())}
+	}
+	key, ok := c.keys[kid]
+	if !ok {
+		return nil, fmt.Errorf("unknown signing key %q", kid)
+	}
+	return key, nil
+}
+
+// serviceTeam: metadata.team của service theo name (rỗng khi không tìm thấy, chỉ admin thao tác được)
+func serviceTeam(name string) string {
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return ""
+	}
+	for _, s := range services {
+		if s.Config.Name == name || s.Folder == name {
+			return s.Config.Metadata.Team
+		}
+	}
+	return ""
+}
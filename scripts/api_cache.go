@@ -0,0 +1,234 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// APICache cache các lookup GitHub chỉ đọc (repo tồn tại, user, org membership, org metadata)
+// để batch run trên hàng trăm service không gọi lại cùng một API và tốn rate limit.
+type APICache struct {
+	Disabled bool   `yaml:"disabled"` // mặc định bật cache in-memory
+	Disk     bool   `yaml:"disk"`     // giữ kết quả thành công giữa các lần chạy
+	Dir      string `yaml:"dir"`      // mặc định .jupiter-cache/api
+	TTL      string `yaml:"ttl"`      // mặc định 10m
+}
+
+func (a APICache) dir() string {
+	if a.Dir == "" {
+		return ".jupiter-cache/api"
+	}
+	return a.Dir
+}
+
+func (a APICache) ttl() (time.Duration, error) {
+	if a.TTL == "" {
+		return 10 * time.Minute, nil
+	}
+	ttl, err := time.ParseDuration(a.TTL)
+	if err != nil {
+		return 0, fmt.Errorf("invalid api_cache.ttl %q: %w", a.TTL, err)
+	}
+	return ttl, nil
+}
+
+// cacheableLookups: endpoint GET được cache (khớp trên path của `gh api <path>`)
+var cacheableLookups = []*regexp.Regexp{
+	regexp.MustCompile(`^repos/[^/]+/[^/]+$`),
+	regexp.MustCompile(`^users/[^/]+$`),
+	regexp.MustCompile(`^orgs/[^/]+$`),
+	regexp.MustCompile(`^orgs/[^/]+/members/[^/]+$`),
+	regexp.MustCompile(`^orgs/[^/]+/teams/[^/]+$`),
+}
+
+var repoRefPattern = regexp.MustCompile(`(?:repos/|^)([A-Za-z0-9_.-]+/[A-Za-z0-9_.-]+)`)
+
+type cachedLookup struct {
+	Output    string    `json:"output"`
+	Failed    bool      `json:"failed,omitempty"`
+	ExpiresAt time.Time `json:"expires_at"`
+}
+
+// cachingExecutor cache output của lookup cacheable; command ghi (POST/PATCH/DELETE, gh repo ...)
+// xoá entry của repo liên quan và mọi kết quả lỗi (vd. repo vừa được tạo).
+type cachingExecutor struct {
+	inner Executor
+	cfg   APICache
+	ttl   time.Duration
+
+	mu      sync.Mutex
+	entries map[string]cachedLookup
+}
+
+// apiCache là cache đang dùng (nil khi tắt), throttledExecutor cho cache hit đi thẳng
+var apiCache *cachingExecutor
+
+func newCachingExecutor(inner Executor, cfg APICache) (*cachingExecutor, error) {
+	ttl, err := cfg.ttl()
+	if err != nil {
+		return nil, err
+	}
+	return &cachingExecutor{inner: inner, cfg: cfg, ttl: ttl, entries: map[string]cachedLookup{}}, nil
+}
+
+func (c *cachingExecutor) Run(cmd Command) (string, error) {
+	key, cacheable := lookupKey(cmd)
+	if !cacheable {
+		out, err := c.inner.Run(cmd)
+		if isGitHubWrite(cmd) {
+			c.invalidate(cmd)
+		}
+		return out, err
+	}
+
+	if entry, ok := c.get(key); ok {
+		if entry.Failed {
+			return entry.Output, errors.New("cached lookup failed")
+		}
+		return entry.Output, nil
+	}
+	out, err := c.inner.Run(cmd)
+	c.put(key, cachedLookup{Output: out, Failed: err != nil, ExpiresAt: time.Now().Add(c.ttl)})
+	return out, err
+}
+
+// has: lookup đã có trong cache (không cần throttle)
+func (c *cachingExecutor) has(cmd Command) bool {
+	key, cacheable := lookupKey(cmd)
+	if !cacheable {
+		return false
+	}
+	_, ok := c.get(key)
+	return ok
+}
+
+// lookupKey: chỉ `gh api <path>` GET không có body. Key = <hash path>-<hash token + args>:
+// prefix theo path để invalidate mọi biến thể (--jq khác nhau), token vì tenant khác thấy dữ liệu khác.
+func lookupKey(cmd Command) (string, bool) {
+	if cmd.Name != "gh" || len(cmd.Args) < 2 || cmd.Args[0] != "api" || cmd.Stdin != nil {
+		return "", false
+	}
+	for _, arg := range cmd.Args[2:] {
+		switch arg {
+		case "-X", "--method", "-f", "-F", "--field", "--raw-field", "--input", "-i", "--include":
+			return "", false
+		}
+	}
+	matched := false
+	for _, pattern := range cacheableLookups {
+		if pattern.MatchString(cmd.Args[1]) {
+			matched = true
+			break
+		}
+	}
+	if !matched {
+		return "", false
+	}
+	sum := sha256.Sum256([]byte(os.Getenv("GH_TOKEN") + "\x00" + os.Getenv("GITHUB_TOKEN") + "\x00" + strings.Join(cmd.Args, "\x00")))
+	return pathPrefix(cmd.Args[1]) + hex.EncodeToString(sum[:16]), true
+}
+
+// isGitHubWrite: gh api có method / body (POST, PATCH, DELETE...) hoặc gh subcommand khác (repo create, pr create...)
+func isGitHubWrite(cmd Command) bool {
+	if cmd.Name != "gh" || len(cmd.Args) == 0 {
+		return false
+	}
+	if cmd.Args[0] != "api" {
+		return true
+	}
+	if cmd.Stdin != nil {
+		return true
+	}
+	for i, arg := range cmd.Args {
+		switch arg {
+		case "-f", "-F", "--field", "--raw-field", "--input":
+			return true
+		case "-X", "--method":
+			return i+1 < len(cmd.Args) && !strings.EqualFold(cmd.Args[i+1], "GET")
+		}
+	}
+	return false
+}
+
+func pathPrefix(path string) string {
+	sum := sha256.Sum256([]byte(path))
+	return hex.EncodeToString(sum[:8]) + "-"
+}
+
+func (c *cachingExecutor) get(key string) (cachedLookup, bool) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if entry, ok := c.entries[key]; ok {
+		if time.Now().Before(entry.ExpiresAt) {
+			return entry, true
+		}
+		delete(c.entries, key)
+	}
+	if !c.cfg.Disk {
+		return cachedLookup{}, false
+	}
+	data, err := os.ReadFile(filepath.Join(c.cfg.dir(), key+".json"))
+	if err != nil {
+		return cachedLookup{}, false
+	}
+	var entry cachedLookup
+	if json.Unmarshal(data, &entry) != nil || !time.Now().Before(entry.ExpiresAt) {
+		return cachedLookup{}, false
+	}
+	c.entries[key] = entry
+	return entry, true
+}
+
+func (c *cachingExecutor) put(key string, entry cachedLookup) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.entries[key] = entry
+	// Lỗi (404, mạng) chỉ cache trong run, không ghi xuống disk
+	if !c.cfg.Disk || entry.Failed {
+		return
+	}
+	data, _ := json.Marshal(entry)
+	if err := os.MkdirAll(c.cfg.dir(), 0700); err == nil {
+		os.WriteFile(filepath.Join(c.cfg.dir(), key+".json"), data, 0600)
+	}
+}
+
+// invalidate sau một command gh có thể ghi: bỏ mọi lookup lỗi và lookup của repo được nhắc tới
+func (c *cachingExecutor) invalidate(cmd Command) {
+	var prefixes []string
+	for _, arg := range cmd.Args {
+		if m := repoRefPattern.FindStringSubmatch(arg); m != nil {
+			prefixes = append(prefixes, pathPrefix("repos/"+m[1]))
+		}
+	}
+
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	for key, entry := range c.entries {
+		if entry.Failed {
+			delete(c.entries, key)
+		}
+	}
+	for _, prefix := range prefixes {
+		for key := range c.entries {
+			if strings.HasPrefix(key, prefix) {
+				delete(c.entries, key)
+			}
+		}
+		if c.cfg.Disk {
+			files, _ := filepath.Glob(filepath.Join(c.cfg.dir(), prefix+"*.json"))
+			for _, f := range files {
+				os.Remove(f)
+			}
+		}
+	}
+}
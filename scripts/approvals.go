@@ -0,0 +1,142 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Các action nhạy cảm cần approval
+const (
+	actionPublicVisibility = "public_visibility"
+	actionForcePush        = "force_push"
+	actionLargeBatch       = "large_batch"
+	actionArchive          = "archive"
+	actionDelete           = "delete"
+	actionDecommission     = "decommission"
+)
+
+// ApprovalPolicy: action nào cần approval và ai được phép approve
+type ApprovalPolicy struct {
+	Actions        []string `yaml:"actions"`
+	Approvers      []string `yaml:"approvers"`
+	MaxReposPerRun int      `yaml:"max_repos_per_run"`
+	// EnvironmentApprovalEnv: biến môi trường được set bởi job chạy trong GitHub Environment có required reviewers
+	EnvironmentApprovalEnv string `yaml:"environment_approval_env"`
+}
+
+// Approval là một dòng trong block `approvals:` của source.yml
+type Approval struct {
+	Action     string `yaml:"action"`
+	ApprovedBy string `yaml:"approved_by"`
+}
+
+// sensitiveActions trả về các action nhạy cảm mà lần chạy này sẽ thực hiện
+func sensitiveActions(dto GeneratorSourceDto, policy ApprovalPolicy, repoAlreadyExists bool, batchSize int) []string {
+	var actions []string
+	if dto.Visibility == "public" {
+		actions = append(actions, actionPublicVisibility)
+	}
+	// Repo đã tồn tại -> push --force sẽ ghi đè history
+	if repoAlreadyExists {
+		actions = append(actions, actionForcePush)
+	}
+	if policy.MaxReposPerRun > 0 && batchSize > policy.MaxReposPerRun {
+		actions = append(actions, actionLargeBatch)
+	}
+	return gatedActions(policy, actions)
+}
+
+// gatedActions lọc các action mà approval_policy.actions yêu cầu approval
+func gatedActions(policy ApprovalPolicy, actions []string) []string {
+	var gated []string
+	for _, action := range actions {
+		if containsString(policy.Actions, action) {
+			gated = append(gated, action)
+		}
+	}
+	return gated
+}
+
+// checkApprovals từ chối chạy nếu action nhạy cảm chưa được approve
+func checkApprovals(ex Executor, actions []string, approvals []Approval, policy ApprovalPolicy) error {
+	if len(actions) == 0 {
+		return nil
+	}
+
+	if policy.EnvironmentApprovalEnv != "" && os.Getenv(policy.EnvironmentApprovalEnv) == "true" {
+		fmt.Printf("  ✅ Approved via environment approval (%s)\n", policy.EnvironmentApprovalEnv)
+		return nil
+	}
+
+	// `approvals:` chỉ là khai báo: approver phải thật sự approve PR đã đưa nó vào.
+	// Ngoài PR không có gì để đối chiếu nên chỉ environment approval mới được chấp nhận.
+	repo, number, ok := triggeringPullRequest(ex)
+	if !ok {
+		if policy.EnvironmentApprovalEnv == "" {
+			return fmt.Errorf("%s requires approval, which outside a pull request needs approval_policy.environment_approval_env", strings.Join(actions, ", "))
+		}
+		return fmt.Errorf("%s requires approval: run from a pull request approved by one of %v, or in the approval environment (%s=true)",
+			strings.Join(actions, ", "), policy.Approvers, policy.EnvironmentApprovalEnv)
+	}
+	out, err := runCommandOutput(ex, "gh", "api", "--paginate", fmt.Sprintf("repos/%s/pulls/%d/reviews", repo, number),
+		"--jq", `.[] | select(.state == "APPROVED") | .user.login`)
+	if err != nil {
+		return fmt.Errorf("failed to read PR reviews: %w", err)
+	}
+	prApprovers := strings.Fields(out)
+
+	var missing []string
+	for _, action := range actions {
+		if !hasApproval(action, approvals, policy, prApprovers) {
+			missing = append(missing, action)
+		}
+	}
+	if len(missing) > 0 {
+		return fmt.Errorf("missing approval for: %s (add an `approvals:` entry signed off by one of %v)",
+			strings.Join(missing, ", "), policy.Approvers)
+	}
+	return nil
+}
+
+func hasApproval(action string, approvals []Approval, policy ApprovalPolicy, prApprovers []string) bool {
+	for _, a := range approvals {
+		if a.Action != action || !containsStringFold(policy.Approvers, a.ApprovedBy) {
+			continue
+		}
+		if !containsStringFold(prApprovers, a.ApprovedBy) {
+			fmt.Printf("  ⚠️ %s listed as approver for %s but has not approved the PR\n", a.ApprovedBy, action)
+			continue
+		}
+		return true
+	}
+	return false
+}
+
+// checkDestructiveApproval: archive / delete / decommission cần approval khi approval_policy.actions có action đó.
+// Approval lấy từ `approvals:` trong source.yml của service (nếu còn).
+func checkDestructiveApproval(action, name string, registryConfig RegistryConfig) error {
+	actions := gatedActions(registryConfig.ApprovalPolicy, []string{action})
+	if len(actions) == 0 {
+		return nil
+	}
+	var approvals []Approval
+	if services, err := loadRegisteredServices(sourcesDir); err == nil {
+		for _, s := range services {
+			if s.Config.Name == name {
+				approvals = s.Config.Approvals
+				break
+			}
+		}
+	}
+	if err := checkApprovals(commandExecutor, actions, approvals, registryConfig.ApprovalPolicy); err != nil {
+		return withCode(ErrApprovalRequired, fmt.Errorf("refusing to %s %s: %w", action, name, err))
+	}
+	return nil
+}
+
+// repoExists kiểm tra repo đã tồn tại trên GitHub chưa
+func repoExists(ex Executor, owner, repoName string) bool {
+	_, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/%s", owner, repoName), "--jq", ".id")
+	return err == nil
+}
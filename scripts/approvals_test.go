@@ -0,0 +1,84 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+// outsidePullRequest: process không chạy từ PR / push event nào
+func outsidePullRequest(t *testing.T) {
+	t.Helper()
+	t.Setenv("GITHUB_EVENT_NAME", "")
+	t.Setenv("JUPITER_APPROVED", "")
+}
+
+// mergedPullRequest: push event của commit đã merge từ PR #7
+func mergedPullRequest(t *testing.T, fake *fakeExecutor, approvers string) {
+	t.Helper()
+	t.Setenv("GITHUB_EVENT_NAME", "push")
+	t.Setenv("GITHUB_REPOSITORY", "acme/registry")
+	t.Setenv("GITHUB_SHA", "abc123")
+	t.Setenv("JUPITER_APPROVED", "")
+	fake.On("gh api repos/acme/registry/commits/abc123/pulls", FakeResponse{Output: "7"})
+	fake.On("gh api --paginate repos/acme/registry/pulls/7/reviews", FakeResponse{Output: approvers})
+}
+
+var testApprovalPolicy = ApprovalPolicy{
+	Actions:                []string{actionForcePush, actionArchive},
+	Approvers:              []string{"lead"},
+	EnvironmentApprovalEnv: "JUPITER_APPROVED",
+}
+
+func TestCheckApprovalsOutsidePullRequestIgnoresYAML(t *testing.T) {
+	outsidePullRequest(t)
+	useFakeExecutor(t)
+
+	err := checkApprovals(commandExecutor, []string{actionForcePush}, []Approval{{Action: actionForcePush, ApprovedBy: "lead"}}, testApprovalPolicy)
+	if err == nil || !strings.Contains(err.Error(), "JUPITER_APPROVED=true") {
+		t.Fatalf("err = %v, want unverified approvals to be rejected", err)
+	}
+}
+
+func TestCheckApprovalsEnvironmentMarker(t *testing.T) {
+	outsidePullRequest(t)
+	t.Setenv("JUPITER_APPROVED", "true")
+	useFakeExecutor(t)
+
+	if err := checkApprovals(commandExecutor, []string{actionForcePush}, nil, testApprovalPolicy); err != nil {
+		t.Fatalf("checkApprovals: %v", err)
+	}
+}
+
+func TestCheckApprovalsPullRequestReviews(t *testing.T) {
+	approvals := []Approval{{Action: actionForcePush, ApprovedBy: "lead"}}
+
+	fake := useFakeExecutor(t)
+	mergedPullRequest(t, fake, "lead\nsomeone")
+	if err := checkApprovals(commandExecutor, []string{actionForcePush}, approvals, testApprovalPolicy); err != nil {
+		t.Fatalf("approved PR: %v", err)
+	}
+
+	fake = useFakeExecutor(t)
+	mergedPullRequest(t, fake, "someone")
+	if err := checkApprovals(commandExecutor, []string{actionForcePush}, approvals, testApprovalPolicy); err == nil {
+		t.Fatalf("approver who did not approve the PR must not count")
+	}
+}
+
+func TestArchiveRequiresApproval(t *testing.T) {
+	inTempDir(t)
+	outsidePullRequest(t)
+	fake := useFakeExecutor(t)
+	state := &RegistryState{Services: map[string]*ServiceState{"orders": {Repo: "acme/orders"}}}
+	if err := state.save(registryStateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	err := archiveService("orders", []string{"orders"}, RegistryConfig{ApprovalPolicy: testApprovalPolicy})
+	if errorCode(err) != ErrApprovalRequired {
+		t.Fatalf("error code = %s (%v), want %s", errorCode(err), err, ErrApprovalRequired)
+	}
+	if indexOf(fake.Invocations(), "gh api -X PATCH repos/acme/orders") >= 0 {
+		t.Fatalf("repository must not be archived without approval")
+	}
+}
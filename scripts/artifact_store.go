@@ -0,0 +1,313 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// ArtifactStore lưu artifact của mỗi lần chạy (run log, manifest đã render, report) ra storage bền vững
+// để server mode có lịch sử mà không làm đầy disk local. Key có dạng <run-id>/<kind>/<name>,
+// run-id bắt đầu bằng timestamp nên retention chỉ cần liệt kê các run ở level đầu.
+//   - local: copy vào thư mục path
+//   - s3:    aws CLI, path dạng s3://bucket/prefix
+//   - gcs:   gcloud CLI, path dạng gs://bucket/prefix
+type ArtifactStore struct {
+	Type          string `yaml:"type"` // local | s3 | gcs | "" = tắt
+	Path          string `yaml:"path"`
+	RetentionDays int    `yaml:"retention_days"` // 0 = giữ mãi
+	// KeepLocal giữ run directory local sau khi upload (mặc định xoá log của service đã upload)
+	KeepLocal bool `yaml:"keep_local"`
+}
+
+var supportedArtifactStores = []string{"local", "s3", "gcs"}
+
+func (a ArtifactStore) validate() error {
+	if !containsString(supportedArtifactStores, a.Type) {
+		return fmt.Errorf("artifacts.type %q must be one of %v", a.Type, supportedArtifactStores)
+	}
+	if a.Path == "" {
+		return fmt.Errorf("artifacts.path is required")
+	}
+	prefix := map[string]string{"s3": "s3://", "gcs": "gs://"}[a.Type]
+	if prefix != "" && !strings.HasPrefix(a.Path, prefix) {
+		return fmt.Errorf("artifacts.path %q must start with %s", a.Path, prefix)
+	}
+	return nil
+}
+
+// artifactStore là backend đang dùng, nil khi artifacts.type rỗng
+var artifactStore *ArtifactStore
+
+func setupArtifactStore(cfg ArtifactStore) error {
+	if cfg.Type == "" {
+		return nil
+	}
+	if err := cfg.validate(); err != nil {
+		return err
+	}
+	artifactStore = &cfg
+	return nil
+}
+
+func (a ArtifactStore) url(key string) string {
+	if a.Type == "local" {
+		return filepath.Join(a.Path, filepath.FromSlash(key))
+	}
+	return strings.TrimSuffix(a.Path, "/") + "/" + key
+}
+
+// put upload file hoặc nội dung thư mục localPath vào key
+func (a ArtifactStore) put(key, localPath string) error {
+	info, err := os.Stat(localPath)
+	if err != nil {
+		return err
+	}
+	dest := a.url(key)
+	switch a.Type {
+	case "local":
+		if info.IsDir() {
+			return copyDir(localPath, dest)
+		}
+		return copyFile(localPath, dest)
+	case "s3":
+		args := []string{"s3", "cp", localPath, dest, "--only-show-errors"}
+		if info.IsDir() {
+			args = append(args, "--recursive")
+		}
+		_, err := runCommandOutput(commandExecutor, "aws", args...)
+		return err
+	case "gcs":
+		if info.IsDir() {
+			_, err := runCommandOutput(commandExecutor, "gcloud", "storage", "rsync", localPath, dest, "--recursive")
+			return err
+		}
+		_, err := runCommandOutput(commandExecutor, "gcloud", "storage", "cp", localPath, dest)
+		return err
+	}
+	return fmt.Errorf("unsupported artifact store: %s", a.Type)
+}
+
+// runs liệt kê run-id đang có trong store
+func (a ArtifactStore) runs() ([]string, error) {
+	var runs []string
+	switch a.Type {
+	case "local":
+		entries, err := os.ReadDir(a.Path)
+		if os.IsNotExist(err) {
+			return nil, nil
+		}
+		if err != nil {
+			return nil, err
+		}
+		for _, e := range entries {
+			if e.IsDir() {
+				runs = append(runs, e.Name())
+			}
+		}
+	case "s3":
+		// "                           PRE <run-id>/"
+		out, err := runCommandOutput(commandExecutor, "aws", "s3", "ls", strings.TrimSuffix(a.Path, "/")+"/")
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if name, ok := strings.CutPrefix(strings.TrimSpace(line), "PRE "); ok {
+				runs = append(runs, strings.TrimSuffix(name, "/"))
+			}
+		}
+	case "gcs":
+		// gs://bucket/prefix/<run-id>/
+		out, err := runCommandOutput(commandExecutor, "gcloud", "storage", "ls", strings.TrimSuffix(a.Path, "/")+"/")
+		if err != nil {
+			return nil, err
+		}
+		for _, line := range strings.Split(out, "\n") {
+			if line = strings.TrimSpace(line); strings.HasSuffix(line, "/") {
+				runs = append(runs, filepath.Base(strings.TrimSuffix(line, "/")))
+			}
+		}
+	default:
+		return nil, fmt.Errorf("unsupported artifact store: %s", a.Type)
+	}
+	sort.Strings(runs)
+	return runs, nil
+}
+
+func (a ArtifactStore) removeRun(runID string) error {
+	switch a.Type {
+	case "local":
+		return os.RemoveAll(a.url(runID))
+	case "s3":
+		_, err := runCommandOutput(commandExecutor, "aws", "s3", "rm", a.url(runID)+"/", "--recursive", "--only-show-errors")
+		return err
+	case "gcs":
+		_, err := runCommandOutput(commandExecutor, "gcloud", "storage", "rm", "--recursive", a.url(runID)+"/")
+		return err
+	}
+	return fmt.Errorf("unsupported artifact store: %s", a.Type)
+}
+
+// storeArtifact upload một artifact của run hiện tại (lỗi chỉ cảnh báo, không fail run)
+func storeArtifact(kind, name, localPath string) {
+	if artifactStore == nil {
+		return
+	}
+	key := fmt.Sprintf("%s/%s/%s", currentRunID, kind, name)
+	if err := artifactStore.put(key, localPath); err != nil {
+		fmt.Printf("⚠️ Failed to store %s artifact %s: %v\n", kind, name, err)
+	}
+}
+
+// storeServiceArtifacts upload run log và manifest của service sau khi provisioning xong
+func storeServiceArtifacts(service string) {
+	if artifactStore == nil {
+		return
+	}
+	if runLogs != nil {
+		dir := filepath.Join(runLogs.dir, service)
+		if _, err := os.Stat(dir); err == nil {
+			storeArtifact("logs", service, dir)
+			if !artifactStore.KeepLocal {
+				os.RemoveAll(dir)
+			}
+		}
+	}
+	manifest := filepath.Join(service, filepath.FromSlash(manifestPath))
+	if _, err := os.Stat(manifest); err == nil {
+		storeArtifact("manifests", service+".yaml", manifest)
+	}
+}
+
+// runTime đọc thời điểm của run từ run-id (<20060102T150405Z>-<pid>)
+func runTime(runID string) (time.Time, bool) {
+	stamp, _, _ := strings.Cut(runID, "-")
+	t, err := time.Parse("20060102T150405Z", stamp)
+	return t, err == nil
+}
+
+// pruneArtifacts xoá các run cũ hơn retention_days, trả về run-id đã xoá
+func pruneArtifacts(a ArtifactStore, dryRun bool) ([]string, error) {
+	if a.RetentionDays <= 0 {
+		return nil, nil
+	}
+	runs, err := a.runs()
+	if err != nil {
+		return nil, err
+	}
+	cutoff := time.Now().Add(-time.Duration(a.RetentionDays) * 24 * time.Hour)
+	var removed []string
+	for _, run := range runs {
+		// Không parse được thì không phải run do registry ghi, bỏ qua
+		if t, ok := runTime(run); !ok || !t.Before(cutoff) {
+			continue
+		}
+		if !dryRun {
+			if err := a.removeRun(run); err != nil {
+				return removed, fmt.Errorf("failed to remove run %s: %w", run, err)
+			}
+		}
+		removed = append(removed, run)
+	}
+	return removed, nil
+}
+
+// runArtifactRetention prune định kỳ trong server mode
+func runArtifactRetention(a ArtifactStore, interval time.Duration) {
+	for {
+		if removed, err := pruneArtifacts(a, false); err != nil {
+			fmt.Printf("⚠️ Artifact retention failed: %v\n", err)
+		} else if len(removed) > 0 {
+			fmt.Printf("🧹 Pruned %d artifact run(s) older than %d days\n", len(removed), a.RetentionDays)
+		}
+		time.Sleep(interval)
+	}
+}
+
+// runArtifactsCommand: `artifacts list` | `artifacts prune [--dry-run]`
+func runArtifactsCommand(args []string) error {
+	if len(args) == 0 {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts artifacts list|prune [--dry-run]"))
+	}
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	store := registryConfig.Artifacts
+	if store.Type == "" {
+		return withCode(ErrUsage, fmt.Errorf("artifacts.type is not configured in %s", registryConfigFile))
+	}
+	if err := store.validate(); err != nil {
+		return withCode(ErrUsage, err)
+	}
+
+	switch args[0] {
+	case "list":
+		runs, err := store.runs()
+		if err != nil {
+			return err
+		}
+		for _, run := range runs {
+			fmt.Println(store.url(run))
+		}
+		return nil
+	case "prune":
+		fs := flag.NewFlagSet("artifacts prune", flag.ExitOnError)
+		dryRun := fs.Bool("dry-run", false, "list runs that would be removed")
+		fs.Parse(args[1:])
+		if store.RetentionDays <= 0 {
+			fmt.Println("ℹ️ artifacts.retention_days is 0, nothing to prune")
+			return nil
+		}
+		removed, err := pruneArtifacts(store, *dryRun)
+		for _, run := range removed {
+			fmt.Printf("🗑️  %s\n", store.url(run))
+		}
+		if err != nil {
+			return err
+		}
+		fmt.Printf("✅ %d run(s) older than %d days pruned\n", len(removed), store.RetentionDays)
+		return nil
+	default:
+		return withCode(ErrUsage, fmt.Errorf("unknown artifacts command %q", args[0]))
+	}
+}
+
+func copyDir(src, dest string) error {
+	return filepath.WalkDir(src, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(src, path)
+		if err != nil {
+			return err
+		}
+		return copyFile(path, filepath.Join(dest, rel))
+	})
+}
+
+func copyFile(src, dest string) error {
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	in, err := os.Open(src)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	out, err := os.Create(dest)
+	if err != nil {
+		return err
+	}
+	if _, err := io.Copy(out, in); err != nil {
+		out.Close()
+		return err
+	}
+	return out.Close()
+}
@@ -0,0 +1,184 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Auth chọn cách registry lấy GitHub token.
+//   - static (mặc định): dùng GH_TOKEN / GITHUB_TOKEN có sẵn trong env (PAT)
+//   - oidc: đổi OIDC token của GitHub Actions (hoặc workload identity token ở server mode)
+//     lấy GitHub token ngắn hạn qua token broker, không cần PAT lưu trong secrets
+type Auth struct {
+	Mode string   `yaml:"mode"`
+	OIDC OIDCAuth `yaml:"oidc"`
+}
+
+type OIDCAuth struct {
+	Audience string `yaml:"audience"`
+	// ExchangeURL nhận `Authorization: Bearer <id token>` + {"owner": ...}, trả về {"token", "expires_at"}
+	ExchangeURL string `yaml:"exchange_url"`
+	// IdentityTokenFile: server mode đọc JWT-SVID / projected service account token từ file (được rotate)
+	IdentityTokenFile string `yaml:"identity_token_file"`
+}
+
+// credentialProvider cấp token theo owner; owner rỗng = token của chính registry
+var credentialProvider = &tokenProvider{}
+
+type tokenProvider struct {
+	mu     sync.Mutex
+	auth   Auth
+	static string
+	cache  map[string]exchangedToken
+}
+
+type exchangedToken struct {
+	Token     string `json:"token"`
+	ExpiresAt string `json:"expires_at"`
+}
+
+// fresh: còn hạn ít nhất 5 phút
+func (t exchangedToken) fresh() bool {
+	expires, err := time.Parse(time.RFC3339, t.ExpiresAt)
+	return err == nil && time.Until(expires) > 5*time.Minute
+}
+
+// setupAuth khởi tạo credentialProvider và ghi token của registry vào env cho gh/git
+func setupAuth(auth Auth) error {
+	p := credentialProvider
+	p.mu.Lock()
+	p.auth = auth
+	p.static = os.Getenv("GH_TOKEN")
+	if p.static == "" {
+		p.static = os.Getenv("GITHUB_TOKEN")
+	}
+	p.cache = map[string]exchangedToken{}
+	p.mu.Unlock()
+
+	if auth.Mode != "oidc" {
+		return nil
+	}
+	if !p.identityAvailable() {
+		fmt.Println("⚠️ auth.mode is oidc but no identity token is available, falling back to GH_TOKEN")
+		return nil
+	}
+
+	token, err := p.token("")
+	if err != nil {
+		return withCode(ErrUsage, fmt.Errorf("oidc token exchange failed: %w", err))
+	}
+	for _, env := range credentialEnvs {
+		os.Setenv(env, token)
+	}
+	fmt.Println("🔐 Using short-lived GitHub token from OIDC exchange")
+	return nil
+}
+
+func (p *tokenProvider) identityAvailable() bool {
+	if p.auth.OIDC.IdentityTokenFile != "" {
+		_, err := os.Stat(p.auth.OIDC.IdentityTokenFile)
+		return err == nil
+	}
+	return os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL") != ""
+}
+
+// token trả về GitHub token dùng được cho owner (đổi lại khi token cũ sắp hết hạn)
+func (p *tokenProvider) token(owner string) (string, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	if p.auth.Mode != "oidc" || !p.identityAvailable() {
+		return p.static, nil
+	}
+	if owner == "" {
+		owner = repoOwner
+	}
+	if cached, ok := p.cache[owner]; ok && cached.fresh() {
+		return cached.Token, nil
+	}
+
+	idToken, err := p.identityToken()
+	if err != nil {
+		return "", err
+	}
+	exchanged, err := p.exchange(idToken, owner)
+	if err != nil {
+		return "", err
+	}
+	registerSecret(exchanged.Token)
+	p.cache[owner] = exchanged
+	return exchanged.Token, nil
+}
+
+// identityToken lấy OIDC token từ file (server mode) hoặc từ GitHub Actions runtime
+func (p *tokenProvider) identityToken() (string, error) {
+	if p.auth.OIDC.IdentityTokenFile != "" {
+		data, err := os.ReadFile(p.auth.OIDC.IdentityTokenFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read identity token: %w", err)
+		}
+		return strings.TrimSpace(string(data)), nil
+	}
+
+	// Cần `permissions: id-token: write` trong workflow
+	requestURL := os.Getenv("ACTIONS_ID_TOKEN_REQUEST_URL")
+	if p.auth.OIDC.Audience != "" {
+		requestURL += "&audience=" + p.auth.OIDC.Audience
+	}
+	req, err := http.NewRequest(http.MethodGet, requestURL, nil)
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "bearer "+os.Getenv("ACTIONS_ID_TOKEN_REQUEST_TOKEN"))
+
+	var body struct {
+		Value string `json:"value"`
+	}
+	if err := doJSONRequest(req, &body); err != nil {
+		return "", fmt.Errorf("failed to request actions id token: %w", err)
+	}
+	return body.Value, nil
+}
+
+func (p *tokenProvider) exchange(idToken, owner string) (exchangedToken, error) {
+	var exchanged exchangedToken
+	payload, err := json.Marshal(map[string]string{"owner": owner})
+	if err != nil {
+		return exchanged, err
+	}
+	req, err := http.NewRequest(http.MethodPost, p.auth.OIDC.ExchangeURL, bytes.NewReader(payload))
+	if err != nil {
+		return exchanged, err
+	}
+	req.Header.Set("Authorization", "Bearer "+idToken)
+	req.Header.Set("Content-Type", "application/json")
+
+	if err := doJSONRequest(req, &exchanged); err != nil {
+		return exchanged, fmt.Errorf("failed to exchange token for %s: %w", owner, err)
+	}
+	if exchanged.Token == "" {
+		return exchanged, fmt.Errorf("token broker returned no token for %s", owner)
+	}
+	return exchanged, nil
+}
+
+func doJSONRequest(req *http.Request, out interface{}) error {
+	client := &http.Client{Timeout: 30 * time.Second}
+	start := time.Now()
+	resp, err := client.Do(req)
+	serverMetrics.observeProvider(req.URL.Host, time.Since(start), err)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("%s %s: HTTP %d", req.Method, req.URL.Host, resp.StatusCode)
+	}
+	return json.NewDecoder(resp.Body).Decode(out)
+}
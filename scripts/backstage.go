@@ -0,0 +1,296 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Tích hợp Backstage scaffolder: form của Software Template gọi registry qua action http:backstage:request
+// (proxy /proxy/jupiter -> server.public_url, kèm X-API-Key). Registry không ghi sources-service trực tiếp
+// mà mở PR source.yml trên jupiter-registry; PR được merge thì webhook push enqueue provisioning như mọi service.
+//   - POST /api/scaffolder/sources: validate form rồi mở PR (dry_run: chỉ validate, trả về source.yml)
+//   - GET  /scaffolder/template.yaml: Software Template dựng sẵn (enum lấy từ registry), register vào catalog của Backstage
+
+// ScaffolderRequest là giá trị form Backstage (team / members nhận cả entity ref như group:default/payments)
+type ScaffolderRequest struct {
+	Name       string   `json:"name"`
+	Team       string   `json:"team"`
+	Kind       string   `json:"kind"`
+	Language   string   `json:"language"`
+	Framework  string   `json:"framework"`
+	Module     string   `json:"module"`
+	Members    []string `json:"members"`
+	Visibility string   `json:"visibility"`
+	Tier       int      `json:"tier"`
+	DryRun     bool     `json:"dry_run"`
+}
+
+// backstageRef bỏ kind / namespace của entity ref: group:default/payments -> payments
+func backstageRef(ref string) string {
+	if i := strings.LastIndex(ref, "/"); i >= 0 {
+		return ref[i+1:]
+	}
+	if _, name, ok := strings.Cut(ref, ":"); ok {
+		return name
+	}
+	return ref
+}
+
+func (s ScaffolderRequest) sourceConfig() SourceConfig {
+	config := SourceConfig{
+		Name:       s.Name,
+		Visibility: s.Visibility,
+		Metadata: Metadata{
+			Kind:                s.Kind,
+			ProgrammingLanguage: s.Language,
+			Framework:           s.Framework,
+			Module:              s.Module,
+			Team:                backstageRef(s.Team),
+			Tier:                s.Tier,
+		},
+	}
+	if config.Metadata.Kind == "service" {
+		config.Metadata.Kind = ""
+	}
+	// User của Backstage được coi là GitHub handle (ingest từ GitHub org)
+	for _, member := range s.Members {
+		config.Members = append(config.Members, backstageRef(member))
+	}
+	return config
+}
+
+func handleScaffolderRequest(registryConfig RegistryConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req ScaffolderRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, "invalid body", http.StatusBadRequest)
+			return
+		}
+		if req.Name == "" || strings.ContainsAny(req.Name, `/\`) || strings.HasPrefix(req.Name, ".") {
+			http.Error(w, fmt.Sprintf("invalid service name: %q", req.Name), http.StatusBadRequest)
+			return
+		}
+		if _, err := os.Stat(filepath.Join(sourcesDir, req.Name)); err == nil {
+			http.Error(w, fmt.Sprintf("service %s is already registered", req.Name), http.StatusConflict)
+			return
+		}
+
+		config := req.sourceConfig()
+		if !requireRole(w, r, roleOperator, config.Metadata.Team) {
+			return
+		}
+		sourceID, err := newSourceID()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		config.SourceID = sourceID
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+
+		draft, err := validateDraftSource(req.Name, data, registryConfig)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		response := map[string]interface{}{"service": req.Name, "source_yml": string(data), "warnings": draft.Result.Warnings}
+		if !draft.Result.ok() {
+			response["problems"] = draft.Result.Problems
+			writeJSONResponse(w, http.StatusUnprocessableEntity, response)
+			return
+		}
+		if req.DryRun {
+			writeJSONResponse(w, http.StatusOK, response)
+			return
+		}
+
+		url, err := openSourcePR(req.Name, data, principalFrom(r).Subject)
+		if err != nil {
+			writeJSONResponse(w, http.StatusBadGateway, map[string]interface{}{"error": errorCode(err), "message": redact(err.Error())})
+			return
+		}
+		response["pull_request_url"] = url
+		writeJSONResponse(w, http.StatusCreated, response)
+	}
+}
+
+// openSourcePR tạo branch backstage/<name> trên jupiter-registry, commit source.yml qua contents API rồi mở PR
+func openSourcePR(name string, data []byte, requestedBy string) (string, error) {
+	branch := "backstage/" + name
+	sha, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/git/ref/heads/main", registryRepo), "--jq", ".object.sha")
+	if err != nil {
+		return "", withCode(ErrGitHubAPI, fmt.Errorf("failed to read %s main: %w", registryRepo, err))
+	}
+	if _, err := runCommandOutput(commandExecutor, "gh", "api", "-X", "POST", fmt.Sprintf("repos/%s/git/refs", registryRepo),
+		"-f", "ref=refs/heads/"+branch, "-f", "sha="+strings.TrimSpace(sha)); err != nil {
+		return "", withCode(ErrGitHubAPI, fmt.Errorf("failed to create branch %s: %w", branch, err))
+	}
+
+	payload, err := json.Marshal(map[string]string{
+		"message": fmt.Sprintf("feat(registry): add %s", name),
+		"content": base64.StdEncoding.EncodeToString(data),
+		"branch":  branch,
+	})
+	if err != nil {
+		return "", err
+	}
+	path := fmt.Sprintf("%s/%s/source.yml", sourcesDir, name)
+	if _, err := runCommandOutputWithInput(commandExecutor, payload, "gh", "api", "-X", "PUT", fmt.Sprintf("repos/%s/contents/%s", registryRepo, path), "--input", "-"); err != nil {
+		return "", withCode(ErrGitHubAPI, fmt.Errorf("failed to commit %s: %w", path, err))
+	}
+
+	body := fmt.Sprintf("Register `%s` in jupiter-registry, requested from Backstage by %s.\n\n"+
+		"The repository is provisioned automatically once this PR is merged.", name, requestedBy)
+	url, err := runCommandOutput(commandExecutor, "gh", "pr", "create",
+		"--repo", registryRepo,
+		"--base", "main",
+		"--head", branch,
+		"--title", "feat(registry): add "+name,
+		"--body", body)
+	if err != nil {
+		return "", withCode(ErrGitHubAPI, fmt.Errorf("failed to open PR for %s: %w", name, err))
+	}
+	fmt.Printf("🔀 Backstage request for %s: %s\n", name, url)
+	return strings.TrimSpace(url), nil
+}
+
+// backstageTemplate dùng delimiter [[ ]] vì ${{ }} là cú pháp của Backstage
+const backstageTemplate = `apiVersion: scaffolder.backstage.io/v1beta3
+kind: Template
+metadata:
+  name: jupiter-service
+  title: New service (jupiter-registry)
+  description: Register a service in jupiter-registry. The repository is provisioned once the source.yml PR is merged.
+  tags: [jupiter[[ range .Languages ]], [[ . ]][[ end ]]]
+spec:
+  owner: platform
+  type: service
+  parameters:
+    - title: Service
+      required: [name, team, language]
+      properties:
+        name:
+          title: Name
+          type: string
+[[- if .Pattern ]]
+          pattern: [[ quote .Pattern ]]
+[[- end ]]
+        team:
+          title: Team
+          type: string
+          ui:field: OwnerPicker
+          ui:options:
+            catalogFilter:
+              kind: Group
+        kind:
+          title: Kind
+          type: string
+          default: service
+          enum: [[ list .Kinds ]]
+        language:
+          title: Language
+          type: string
+          enum: [[ list .Languages ]]
+        framework:
+          title: Framework
+          description: Optional, defaults to the language default (library, cli, worker and cronjob take none)
+          type: string
+          enum: [[ list .Frameworks ]]
+        visibility:
+          title: Visibility
+          type: string
+          default: private
+          enum: [[ list .Visibilities ]]
+        tier:
+          title: Tier
+          description: Tier 1 and 2 get an on-call service
+          type: integer
+          default: 3
+          enum: [1, 2, 3]
+    - title: Members
+      required: [members]
+      properties:
+        members:
+          title: Members
+          type: array
+          items:
+            type: string
+            ui:field: EntityPicker
+            ui:options:
+              catalogFilter:
+                kind: User
+  steps:
+    - id: register
+      name: Open source.yml PR in jupiter-registry
+      action: http:backstage:request
+      input:
+        method: POST
+        path: /proxy/jupiter/api/scaffolder/sources
+        headers:
+          content-type: application/json
+        body:
+          name: ${{ parameters.name }}
+          team: ${{ parameters.team }}
+          kind: ${{ parameters.kind }}
+          language: ${{ parameters.language }}
+          framework: ${{ parameters.framework }}
+          visibility: ${{ parameters.visibility }}
+          tier: ${{ parameters.tier }}
+          members: ${{ parameters.members }}
+  output:
+    links:
+      - title: source.yml pull request
+        url: ${{ steps.register.output.body.pull_request_url }}
+`
+
+// GET /scaffolder/template.yaml (không cần auth: catalog của Backstage đọc URL trực tiếp, không chứa secret)
+func handleBackstageTemplate(registryConfig RegistryConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var frameworks []string
+		for _, list := range [][]string{golangFrameworks, nodejsFrameworks, frontendFrameworks} {
+			for _, framework := range list {
+				if !containsString(frameworks, framework) {
+					frameworks = append(frameworks, framework)
+				}
+			}
+		}
+		tmpl, err := template.New("backstage").Delims("[[", "]]").Funcs(template.FuncMap{
+			"quote": func(s string) string { return fmt.Sprintf("%q", s) },
+			"list":  func(values []string) string { return "[" + strings.Join(values, ", ") + "]" },
+		}).Parse(backstageTemplate)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		var b bytes.Buffer
+		err = tmpl.Execute(&b, map[string]interface{}{
+			"Pattern":      registryConfig.Validation.Naming.Pattern,
+			"Kinds":        supportedKinds,
+			"Languages":    supportedLanguages,
+			"Frameworks":   frameworks,
+			"Visibilities": supportedVisibilities,
+		})
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", "application/yaml")
+		w.Write(b.Bytes())
+	}
+}
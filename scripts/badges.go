@@ -0,0 +1,104 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// badgesDir chứa shields endpoint JSON của từng service, commit cùng catalog
+const badgesDir = "catalog/badges"
+
+// registryRepo là repo jupiter-registry, dùng cho raw URL của badge đã commit
+const registryRepo = repoOwner + "/jupiter-registry"
+
+// ShieldsBadge theo format https://shields.io/badges/endpoint-badge
+type ShieldsBadge struct {
+	SchemaVersion int    `json:"schemaVersion"`
+	Label         string `json:"label"`
+	Message       string `json:"message"`
+	Color         string `json:"color"`
+}
+
+// buildBadge: trạng thái lần generate gần nhất + template version
+func buildBadge(entry *ServiceState) ShieldsBadge {
+	badge := ShieldsBadge{SchemaVersion: 1, Label: "jupiter", Message: "unknown", Color: "lightgrey"}
+	if entry == nil {
+		return badge
+	}
+	switch entry.LastStatus {
+	case "success":
+		badge.Message, badge.Color = "generated", "brightgreen"
+	case "failed":
+		badge.Message, badge.Color = "failed", "red"
+	default:
+		if entry.Origin == "adopted" {
+			badge.Message, badge.Color = "adopted", "blue"
+		}
+	}
+	if version := shortTemplateVersion(entry.TemplateVersion); version != "" {
+		badge.Message += " · " + version
+	}
+	return badge
+}
+
+// shortTemplateVersion: sha256:abcdef... -> abcdef12
+func shortTemplateVersion(version string) string {
+	version = strings.TrimPrefix(version, "sha256:")
+	if len(version) > 8 {
+		version = version[:8]
+	}
+	return version
+}
+
+// badgeURL trả về URL ảnh shields cho service: endpoint của server nếu có public_url, ngược lại JSON đã commit
+func badgeURL(name string, server ServerConfig) string {
+	endpoint := fmt.Sprintf("https://raw.githubusercontent.com/%s/main/%s/%s.json", registryRepo, badgesDir, name)
+	if server.PublicURL != "" {
+		endpoint = fmt.Sprintf("%s/api/badges/%s.json", strings.TrimSuffix(server.PublicURL, "/"), name)
+	}
+	return "https://img.shields.io/endpoint?url=" + url.QueryEscape(endpoint)
+}
+
+// badgeMarkdown để nhúng vào README/CONTRIBUTING của repo được generate và CATALOG.md
+func badgeMarkdown(name string, server ServerConfig) string {
+	return fmt.Sprintf("[![jupiter](%s)](https://github.com/%s)", badgeURL(name, server), registryRepo)
+}
+
+// writeBadges ghi catalog/badges/<service>.json cho mọi service trong catalog
+func writeBadges(outDir string, catalog []CatalogEntry, state *RegistryState) error {
+	dir := filepath.Join(outDir, badgesDir)
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return fmt.Errorf("failed to create badges dir: %w", err)
+	}
+	for _, e := range catalog {
+		name := e.Name
+		data, err := json.MarshalIndent(buildBadge(state.Services[name]), "", "  ")
+		if err != nil {
+			return err
+		}
+		path := filepath.Join(dir, name+".json")
+		if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+			return fmt.Errorf("failed to write %s: %w", path, err)
+		}
+	}
+	return nil
+}
+
+// GET /api/badges/{service}.json
+func handleBadge() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name := strings.TrimSuffix(strings.TrimPrefix(r.URL.Path, "/api/badges/"), ".json")
+		state, err := loadRegistryState(registryStateFile)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Cache-Control", "max-age=300")
+		writeJSONResponse(w, http.StatusOK, buildBadge(state.Services[name]))
+	}
+}
@@ -0,0 +1,283 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// runBatchCommand provisioning nhiều service trong một lần chạy, áp dụng quotas trước khi bắt đầu
+func runBatchCommand(args []string) error {
+	fs := flag.NewFlagSet("batch", flag.ExitOnError)
+	maxGenerations := fs.Int("max-generations", 0, "max services generated concurrently (overrides throttle.max_concurrent_generations)")
+	maxGitHubOps := fs.Int("max-github-ops", 0, "max concurrent GitHub operations (overrides throttle.max_concurrent_github_ops)")
+	requestDelay := fs.String("request-delay", "", "min delay between GitHub requests, e.g. 250ms (overrides throttle.request_delay)")
+	confirm := fs.String("confirm", "", "comma-separated app names confirmed for destructive actions (force push)")
+	onError := fs.String("on-error", "", "continue | fail-fast | skip-dependents (overrides batch.on_error)")
+	maxFailurePercent := fs.Int("max-failure-percent", -1, "exit non-zero only when more than this % of services fail or are skipped (overrides batch.max_failure_percent)")
+	canary := fs.Bool("canary", false, "run the canary even if the template version already passed it")
+	skipCanary := fs.Bool("skip-canary", false, "start the batch without the canary gate")
+	fs.Parse(args)
+
+	servicePaths := fs.Args()
+	if len(servicePaths) == 0 {
+		fmt.Println("ℹ️ No services to process")
+		return nil
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return fmt.Errorf("error loading registry config: %w", err)
+	}
+
+	policy := registryConfig.Batch
+	if *onError != "" {
+		policy.OnError = *onError
+	}
+	if *maxFailurePercent >= 0 {
+		policy.MaxFailurePercent = *maxFailurePercent
+	}
+	if err := policy.validate(); err != nil {
+		return withCode(ErrUsage, err)
+	}
+
+	// Quotas được check cho cả batch trước khi tạo bất kỳ repo nào
+	if err := enforceQuotas(servicePaths, registryConfig); err != nil {
+		return withCode(ErrQuotaExceeded, err)
+	}
+
+	// Canary trên sandbox org trước khi đụng tới repo thật (canary.org)
+	if registryConfig.Canary.enabled() && !*skipCanary {
+		if err := runCanaryGate(registryConfig, *canary); err != nil {
+			return err
+		}
+	}
+
+	// Flag override cấu hình throttle trong jupiter.yml
+	throttle := registryConfig.Throttle
+	if *maxGenerations > 0 {
+		throttle.MaxConcurrentGenerations = *maxGenerations
+	}
+	if *maxGitHubOps > 0 {
+		throttle.MaxConcurrentGitHubOps = *maxGitHubOps
+	}
+	if *requestDelay != "" {
+		throttle.RequestDelay = *requestDelay
+	}
+	throttled, err := newThrottledExecutor(commandExecutor, throttle)
+	if err != nil {
+		return withCode(ErrUsage, err)
+	}
+	commandExecutor = throttled
+
+	before, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	failed, skipped, firstErr := provisionPool(servicePaths, registryConfig, throttle.generations(), len(servicePaths), parseConfirm(*confirm), policy.onError())
+
+	// Step outputs cho workflow: created_repos / failed_services / skipped_services (mỗi dòng một giá trị)
+	after, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	if err := writeStepOutputs(map[string]string{
+		"created_repos":    strings.Join(provisionedRepos(before, after), "\n"),
+		"failed_services":  strings.Join(failed, "\n"),
+		"skipped_services": strings.Join(skipped, "\n"),
+	}); err != nil {
+		fmt.Printf("⚠️ Failed to write step outputs: %v\n", err)
+	}
+	if len(skipped) > 0 {
+		fmt.Printf("⏭️  %d service(s) skipped: %s\n", len(skipped), strings.Join(skipped, ", "))
+	}
+	if len(failed) == 0 && len(skipped) == 0 {
+		fmt.Println("✅ All services processed!")
+		return nil
+	}
+
+	summary := fmt.Sprintf("%d/%d service(s) failed: %s", len(failed), len(servicePaths), strings.Join(failed, ", "))
+	if len(skipped) > 0 {
+		summary += fmt.Sprintf(", %d skipped", len(skipped))
+	}
+	if (len(failed)+len(skipped))*100 <= policy.MaxFailurePercent*len(servicePaths) {
+		fmt.Printf("⚠️ %s (within threshold of %d%%)\n", summary, policy.MaxFailurePercent)
+		return nil
+	}
+	// Exit code theo loại lỗi của service fail đầu tiên
+	return withCode(errorCode(firstErr), fmt.Errorf("%s", summary))
+}
+
+// BatchPolicy quyết định batch run xử lý thế nào khi một service fail
+type BatchPolicy struct {
+	// OnError: continue (mặc định) chạy tiếp mọi service, fail-fast không bắt đầu service mới
+	// sau lỗi đầu tiên, skip-dependents bỏ qua service có depends_on tới service fail
+	OnError string `yaml:"on_error"`
+	// MaxFailurePercent: % service fail (hoặc bị skip) tối đa mà batch vẫn exit 0, mặc định 0
+	MaxFailurePercent int `yaml:"max_failure_percent"`
+}
+
+var supportedOnError = []string{"continue", "fail-fast", "skip-dependents"}
+
+func (p BatchPolicy) onError() string {
+	if p.OnError == "" {
+		return "continue"
+	}
+	return p.OnError
+}
+
+func (p BatchPolicy) validate() error {
+	if !containsString(supportedOnError, p.onError()) {
+		return fmt.Errorf("on-error %q must be one of %v", p.OnError, supportedOnError)
+	}
+	if p.MaxFailurePercent < 0 || p.MaxFailurePercent > 100 {
+		return fmt.Errorf("max failure percent %d must be between 0 and 100", p.MaxFailurePercent)
+	}
+	return nil
+}
+
+// provisionPool provisioning các service với tối đa `concurrency` service song song,
+// service có depends_on trong cùng batch chỉ bắt đầu sau khi dependency xong.
+// Trả về folder của các service fail, các service bị bỏ qua theo onError và lỗi đầu tiên (để lấy exit code).
+func provisionPool(servicePaths []string, registryConfig RegistryConfig, concurrency, batchSize int, confirmed []string, onError string) ([]string, []string, error) {
+	ordered, deps, err := batchOrder(servicePaths)
+	if err != nil {
+		// Không xếp được thứ tự thì không chạy service nào, coi như cả batch fail
+		fmt.Printf("❌ %v\n", err)
+		failed := make([]string, len(servicePaths))
+		for i, servicePath := range servicePaths {
+			failed[i] = filepath.Base(servicePath)
+		}
+		return failed, nil, withCode(ErrUsage, err)
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		failed   []string
+		skipped  []string
+		firstErr error
+		// notOK: service fail hoặc bị skip, dùng cho skip-dependents
+		notOK = map[string]bool{}
+	)
+	done := make(map[string]chan struct{}, len(ordered))
+	slots := make(chan struct{}, concurrency)
+	for _, servicePath := range ordered {
+		done[servicePath] = make(chan struct{})
+		for _, dep := range deps[servicePath] {
+			<-done[dep]
+		}
+		slots <- struct{}{}
+
+		name := filepath.Base(servicePath)
+		mu.Lock()
+		reason := ""
+		switch onError {
+		case "fail-fast":
+			if firstErr != nil {
+				reason = "an earlier service failed (--on-error fail-fast)"
+			}
+		case "skip-dependents":
+			for _, dep := range deps[servicePath] {
+				if notOK[dep] {
+					reason = fmt.Sprintf("dependency %s was not provisioned", filepath.Base(dep))
+					break
+				}
+			}
+		}
+		if reason != "" {
+			skipped = append(skipped, name)
+			notOK[servicePath] = true
+		}
+		mu.Unlock()
+		if reason != "" {
+			fmt.Printf("⏭️  Skipping %s: %s\n", name, reason)
+			close(done[servicePath])
+			<-slots
+			continue
+		}
+
+		wg.Add(1)
+		go func(servicePath string) {
+			defer wg.Done()
+			defer close(done[servicePath])
+			defer func() { <-slots }()
+
+			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+			fmt.Printf("📦 Processing: %s\n", name)
+			fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+
+			if err := provisionService(servicePath, registryConfig, batchSize, confirmed); err != nil {
+				fmt.Printf("❌ [%s] %v\n", errorCode(err), err)
+				printLogExcerpt(err)
+				mu.Lock()
+				failed = append(failed, name)
+				notOK[servicePath] = true
+				if firstErr == nil {
+					firstErr = err
+				}
+				mu.Unlock()
+				return
+			}
+			fmt.Printf("✅ %s generated and pushed successfully!\n\n", name)
+		}(servicePath)
+	}
+	wg.Wait()
+	return failed, skipped, firstErr
+}
+
+// batchOrder sắp xếp service theo depends_on (dependency trước, còn lại giữ thứ tự đầu vào)
+// và trả về dependency nằm trong cùng batch của mỗi service. Dependency ngoài batch coi như đã có sẵn.
+func batchOrder(servicePaths []string) ([]string, map[string][]string, error) {
+	byName := map[string]string{}
+	dependsOn := map[string][]string{}
+	for _, servicePath := range servicePaths {
+		config, err := loadSourceConfig(servicePath)
+		if err != nil {
+			// provisionService sẽ báo lỗi này, ở đây chỉ coi như không có dependency
+			continue
+		}
+		byName[config.Name] = servicePath
+		dependsOn[servicePath] = config.DependsOn
+	}
+
+	deps := map[string][]string{}
+	for servicePath, names := range dependsOn {
+		for _, dep := range names {
+			if depPath, ok := byName[dep]; ok && depPath != servicePath {
+				deps[servicePath] = append(deps[servicePath], depPath)
+			}
+		}
+	}
+
+	// DFS: 1 = đang thăm (gặp lại là vòng lặp), 2 = đã xếp
+	var ordered []string
+	state := map[string]int{}
+	var visit func(servicePath string, trail []string) error
+	visit = func(servicePath string, trail []string) error {
+		trail = append(trail, filepath.Base(servicePath))
+		switch state[servicePath] {
+		case 1:
+			return fmt.Errorf("depends_on cycle: %s", strings.Join(trail, " → "))
+		case 2:
+			return nil
+		}
+		state[servicePath] = 1
+		for _, dep := range deps[servicePath] {
+			if err := visit(dep, trail); err != nil {
+				return err
+			}
+		}
+		state[servicePath] = 2
+		ordered = append(ordered, servicePath)
+		return nil
+	}
+	for _, servicePath := range servicePaths {
+		if err := visit(servicePath, nil); err != nil {
+			return nil, nil, err
+		}
+	}
+	return ordered, deps, nil
+}
@@ -0,0 +1,332 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"runtime"
+	"sort"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// benchBaselineFile lưu kết quả bench đã chấp nhận, cập nhật bằng `bench --update`. Số đo phụ thuộc máy nên
+// baseline không commit: tạo trên chính runner CI (cache) rồi so các lần chạy sau với nó
+const benchBaselineFile = "testdata/bench/baseline.json"
+
+// Bench cấu hình `bench`: kích thước registry giả lập và ngưỡng hiệu năng
+type Bench struct {
+	Services             int     `yaml:"services"`               // số service giả lập, mặc định 500
+	MaxRegressionPercent float64 `yaml:"max_regression_percent"` // chậm hơn baseline quá % này thì fail, mặc định 20
+	// Budgets: thời gian tối đa mỗi service theo stage (vd. validate: 200us), không phụ thuộc baseline
+	Budgets map[string]string `yaml:"budgets"`
+}
+
+func (b Bench) services() int {
+	if b.Services <= 0 {
+		return 500
+	}
+	return b.Services
+}
+
+func (b Bench) maxRegressionPercent() float64 {
+	if b.MaxRegressionPercent <= 0 {
+		return 20
+	}
+	return b.MaxRegressionPercent
+}
+
+// BenchResult là kết quả một stage, tính theo từng service
+type BenchResult struct {
+	Stage             string  `json:"stage"`
+	NsPerService      int64   `json:"ns_per_service"`
+	AllocsPerService  int64   `json:"allocs_per_service"`
+	ServicesPerSecond float64 `json:"services_per_second"`
+}
+
+type BenchBaseline struct {
+	Services int                    `json:"services"`
+	Stages   map[string]BenchResult `json:"stages"`
+}
+
+// benchStage chạy một lượt qua toàn bộ registry giả lập
+type benchStage struct {
+	Name string
+	Run  func(services []RegisteredService, dir string) error
+}
+
+// benchStages: parse -> validate (chỉ check tĩnh, không gọi GitHub) -> manifest -> generate (template, không CLI ngoài)
+func benchStages(registryConfig RegistryConfig) []benchStage {
+	return []benchStage{
+		{Name: "parse", Run: func(_ []RegisteredService, dir string) error {
+			_, err := loadRegisteredServices(dir)
+			return err
+		}},
+		{Name: "validate", Run: func(services []RegisteredService, dir string) error {
+			for _, s := range services {
+				problems := validateSourceKeys(filepath.Join(dir, s.Folder))
+				problems = append(problems, validateSourceConfig(s.Config)...)
+				problems = append(problems, validateServiceName(s.Config.Name, s.Config.Metadata.Team, registryConfig.Validation.Naming)...)
+				if len(problems) > 0 {
+					return fmt.Errorf("%s: %v", s.Folder, problems)
+				}
+			}
+			return nil
+		}},
+		{Name: "manifest", Run: func(services []RegisteredService, dir string) error {
+			for _, s := range services {
+				if _, err := buildManifest(filepath.Join(dir, s.Folder), s.Config, registryConfig); err != nil {
+					return err
+				}
+			}
+			return nil
+		}},
+		{Name: "generate", Run: func(services []RegisteredService, dir string) error {
+			out, err := os.MkdirTemp("", "jupiter-bench-out-")
+			if err != nil {
+				return err
+			}
+			defer os.RemoveAll(out)
+			for _, s := range services {
+				if err := benchGenerate(filepath.Join(out, s.Folder), toGeneratorSourceDto(resolveSourceConfig(s.Config, registryConfig), registryConfig), registryConfig); err != nil {
+					return fmt.Errorf("%s: %w", s.Folder, err)
+				}
+			}
+			return nil
+		}},
+	}
+}
+
+// benchGenerate render phần generate thuần template của một service (framework + .github + community files)
+func benchGenerate(repoDir string, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	srcDir := filepath.Join(registryConfig.FrameworkTemplates, dto.ProgrammingLanguage, dto.Framework)
+	var data interface{} = golangTemplateData{Service: dto, Module: goModulePath(dto)}
+	if dto.ProgrammingLanguage == "nodejs" {
+		data = nodeTemplateData{Service: dto, PackageName: nodePackageName(dto)}
+	}
+	if err := renderTemplateDir(srcDir, repoDir, data); err != nil {
+		return err
+	}
+	if err := writeGithubTemplates(repoDir, dto, registryConfig.GithubTemplates); err != nil {
+		return err
+	}
+	return writeCommunityFiles(repoDir, dto, registryConfig.CommunityFiles, registryConfig.Server)
+}
+
+// writeSyntheticRegistry ghi n service hợp lệ, xoay vòng language / framework / team / tier
+func writeSyntheticRegistry(dir string, n int) error {
+	frameworks := []struct{ language, framework string }{
+		{"golang", "gin"}, {"golang", "echo"}, {"golang", "fiber"}, {"golang", "chi"},
+		{"nodejs", "express"}, {"nodejs", "fastify"},
+	}
+	teams := []string{"platform", "payments", "growth", "data"}
+	for i := 0; i < n; i++ {
+		f := frameworks[i%len(frameworks)]
+		name := fmt.Sprintf("bench-svc-%04d", i)
+		config := SourceConfig{
+			SourceID: fmt.Sprintf("bench-%04d", i),
+			Name:     name,
+			Members:  []string{"tqhuy1996"},
+			Metadata: Metadata{
+				ProgrammingLanguage: f.language,
+				Framework:           f.framework,
+				Team:                teams[i%len(teams)],
+				Tier:                i%3 + 1,
+			},
+		}
+		data, err := yaml.Marshal(config)
+		if err != nil {
+			return err
+		}
+		servicePath := filepath.Join(dir, name)
+		if err := os.MkdirAll(servicePath, 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(filepath.Join(servicePath, "source.yml"), data, 0644); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// runBenchCommand: `bench [--services N] [--update] [--json] [stage...]`
+func runBenchCommand(args []string) error {
+	fs := flag.NewFlagSet("bench", flag.ExitOnError)
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	cfg := registryConfig.Bench
+	n := fs.Int("services", cfg.services(), "number of synthetic services")
+	update := fs.Bool("update", false, "write the results as the new baseline")
+	baselinePath := fs.String("baseline", benchBaselineFile, "baseline file")
+	asJSON := fs.Bool("json", false, "print results as JSON")
+	fs.Parse(args)
+
+	dir, err := os.MkdirTemp("", "jupiter-bench-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(dir)
+	if err := writeSyntheticRegistry(dir, *n); err != nil {
+		return err
+	}
+	services, err := loadRegisteredServices(dir)
+	if err != nil {
+		return err
+	}
+
+	var results []BenchResult
+	for _, stage := range benchStages(registryConfig) {
+		if fs.NArg() > 0 && !containsString(fs.Args(), stage.Name) {
+			continue
+		}
+		if !*asJSON {
+			fmt.Printf("⏱️  %s (%d services)...\n", stage.Name, *n)
+		}
+		// Log của từng bước (→ Using templates ...) không tính vào thời gian đo
+		stdout := os.Stdout
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			return err
+		}
+		os.Stdout = devNull
+		nsPerOp, allocsPerOp, stageErr := timeStage(stage, services, dir)
+		os.Stdout = stdout
+		devNull.Close()
+		if stageErr != nil {
+			return fmt.Errorf("bench stage %s failed: %w", stage.Name, stageErr)
+		}
+		perService := nsPerOp / int64(*n)
+		result := BenchResult{Stage: stage.Name, NsPerService: perService, AllocsPerService: allocsPerOp / int64(*n)}
+		if perService > 0 {
+			result.ServicesPerSecond = float64(time.Second) / float64(perService)
+		}
+		results = append(results, result)
+	}
+
+	if *update {
+		baseline := BenchBaseline{Services: *n, Stages: map[string]BenchResult{}}
+		for _, r := range results {
+			baseline.Stages[r.Stage] = r
+		}
+		data, err := json.MarshalIndent(baseline, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := os.MkdirAll(filepath.Dir(*baselinePath), 0755); err != nil {
+			return err
+		}
+		if err := os.WriteFile(*baselinePath, append(data, '\n'), 0644); err != nil {
+			return err
+		}
+		fmt.Printf("📝 Baseline written to %s\n", *baselinePath)
+		return nil
+	}
+
+	baseline, err := loadBenchBaseline(*baselinePath)
+	if err != nil {
+		return err
+	}
+	// Baseline đo với số service khác thì không so được (chi phí / service đổi theo kích thước registry)
+	if baseline.Services != *n {
+		baseline.Stages = map[string]BenchResult{}
+	}
+	violations := checkBenchBudgets(results, baseline, cfg)
+
+	if *asJSON {
+		out, err := json.MarshalIndent(map[string]interface{}{"services": *n, "results": results, "violations": violations}, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
+		fmt.Printf("\n%-10s %14s %12s %14s %10s\n", "STAGE", "PER SERVICE", "ALLOCS", "SERVICES/S", "BASELINE")
+		for _, r := range results {
+			delta := "-"
+			if base, ok := baseline.Stages[r.Stage]; ok && base.NsPerService > 0 {
+				delta = fmt.Sprintf("%+.1f%%", float64(r.NsPerService-base.NsPerService)*100/float64(base.NsPerService))
+			}
+			fmt.Printf("%-10s %14s %12d %14.0f %10s\n", r.Stage, time.Duration(r.NsPerService), r.AllocsPerService, r.ServicesPerSecond, delta)
+		}
+		for _, v := range violations {
+			fmt.Printf("❌ %s\n", v)
+		}
+	}
+
+	if len(violations) > 0 {
+		return withCode(ErrValidationFailed, fmt.Errorf("%d performance budget(s) exceeded, run `bench --update` if the change is intended", len(violations)))
+	}
+	return nil
+}
+
+// benchMinDuration: chạy lặp một stage ít nhất chừng này để số đo ổn định (như -benchtime mặc định của go test)
+const benchMinDuration = time.Second
+
+// timeStage chạy stage lặp lại tới khi đủ benchMinDuration, trả thời gian và số allocation trung bình mỗi lượt
+func timeStage(stage benchStage, services []RegisteredService, dir string) (int64, int64, error) {
+	var before, after runtime.MemStats
+	runtime.GC()
+	runtime.ReadMemStats(&before)
+	started := time.Now()
+	iterations := int64(0)
+	for iterations == 0 || time.Since(started) < benchMinDuration {
+		if err := stage.Run(services, dir); err != nil {
+			return 0, 0, err
+		}
+		iterations++
+	}
+	elapsed := time.Since(started)
+	runtime.ReadMemStats(&after)
+	return elapsed.Nanoseconds() / iterations, int64(after.Mallocs-before.Mallocs) / iterations, nil
+}
+
+func loadBenchBaseline(path string) (BenchBaseline, error) {
+	baseline := BenchBaseline{Stages: map[string]BenchResult{}}
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return baseline, nil
+	}
+	if err != nil {
+		return baseline, err
+	}
+	if err := json.Unmarshal(data, &baseline); err != nil {
+		return baseline, fmt.Errorf("failed to parse %s: %w", path, err)
+	}
+	return baseline, nil
+}
+
+// checkBenchBudgets so với baseline (bench.max_regression_percent) và budget tuyệt đối (bench.budgets)
+func checkBenchBudgets(results []BenchResult, baseline BenchBaseline, cfg Bench) []string {
+	violations := []string{}
+	for _, r := range results {
+		if base, ok := baseline.Stages[r.Stage]; ok && base.NsPerService > 0 {
+			limit := float64(base.NsPerService) * (1 + cfg.maxRegressionPercent()/100)
+			if float64(r.NsPerService) > limit {
+				violations = append(violations, fmt.Sprintf("%s: %s per service is more than %.0f%% slower than baseline %s",
+					r.Stage, time.Duration(r.NsPerService), cfg.maxRegressionPercent(), time.Duration(base.NsPerService)))
+			}
+		}
+	}
+	stages := make([]string, 0, len(cfg.Budgets))
+	for stage := range cfg.Budgets {
+		stages = append(stages, stage)
+	}
+	sort.Strings(stages)
+	for _, stage := range stages {
+		budget, err := time.ParseDuration(cfg.Budgets[stage])
+		if err != nil {
+			violations = append(violations, fmt.Sprintf("bench.budgets.%s: %v", stage, err))
+			continue
+		}
+		for _, r := range results {
+			if r.Stage == stage && time.Duration(r.NsPerService) > budget {
+				violations = append(violations, fmt.Sprintf("%s: %s per service exceeds budget %s", stage, time.Duration(r.NsPerService), budget))
+			}
+		}
+	}
+	return violations
+}
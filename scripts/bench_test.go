@@ -0,0 +1,61 @@
+package main
+
+import (
+	"os"
+	"testing"
+)
+
+// benchServices: kích thước registry giả lập cho go test -bench (`bench` command dùng bench.services)
+const benchServices = 50
+
+// runBenchStage đo một stage của benchStages trên registry giả lập, cùng code path với `bench`
+func runBenchStage(b *testing.B, name string) {
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		b.Fatal(err)
+	}
+	dir := b.TempDir()
+	if err := writeSyntheticRegistry(dir, benchServices); err != nil {
+		b.Fatal(err)
+	}
+	services, err := loadRegisteredServices(dir)
+	if err != nil {
+		b.Fatal(err)
+	}
+	for _, stage := range benchStages(registryConfig) {
+		if stage.Name != name {
+			continue
+		}
+		// Log của từng bước không tính vào thời gian đo
+		stdout := os.Stdout
+		devNull, err := os.OpenFile(os.DevNull, os.O_WRONLY, 0)
+		if err != nil {
+			b.Fatal(err)
+		}
+		defer func() { os.Stdout = stdout; devNull.Close() }()
+		os.Stdout = devNull
+
+		b.ReportAllocs()
+		b.ResetTimer()
+		for i := 0; i < b.N; i++ {
+			if err := stage.Run(services, dir); err != nil {
+				b.Fatal(err)
+			}
+		}
+		b.ReportMetric(float64(b.Elapsed().Nanoseconds())/float64(b.N)/benchServices, "ns/service")
+		return
+	}
+	b.Fatalf("unknown bench stage %s", name)
+}
+
+func BenchmarkParse(b *testing.B) {
+	runBenchStage(b, "parse")
+}
+
+func BenchmarkValidate(b *testing.B) {
+	runBenchStage(b, "validate")
+}
+
+func BenchmarkRender(b *testing.B) {
+	runBenchStage(b, "generate")
+}
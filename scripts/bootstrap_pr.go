@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// initialPush: cách đưa nội dung generated lên repo mới (git.initial_push)
+//   - direct (mặc định): push thẳng lên default branch
+//   - pull_request:      default branch chỉ có một commit rỗng, nội dung nằm trên bootstrap branch + PR để review
+func (g GitConfig) initialPush() string {
+	if g.InitialPush == "" {
+		return "direct"
+	}
+	return g.InitialPush
+}
+
+func (g GitConfig) bootstrapBranch() string {
+	if g.BootstrapBranch == "" {
+		return "bootstrap"
+	}
+	return g.BootstrapBranch
+}
+
+// pushBootstrapPR push commit rỗng lên default branch, nội dung generated lên bootstrap branch rồi mở PR.
+// Rulesets được apply ngay sau bước push nên PR phải qua review như mọi thay đổi khác.
+func pushBootstrapPR(ex Executor, repoDir, repoURL string, dto GeneratorSourceDto, gitConfig GitConfig) error {
+	bootstrap := gitConfig.bootstrapBranch()
+	if bootstrap == dto.branch() {
+		return withCode(ErrUsage, fmt.Errorf("git.bootstrap_branch must differ from the default branch %s", dto.branch()))
+	}
+
+	commands := [][]string{
+		{"init"},
+		{"config", "user.email", "github-actions[bot]@users.noreply.github.com"},
+		{"config", "user.name", "github-actions[bot]"},
+		{"remote", "add", "origin", repoURL},
+		{"commit", "--allow-empty", "-m", "Initialize repository"},
+		{"branch", "-M", dto.branch()},
+		{"push", "-u", "origin", dto.branch()},
+		{"checkout", "-b", bootstrap},
+		{"add", "-A"},
+		{"commit", "-m", "Initial commit from jupiter-registry"},
+		{"push", "-u", "origin", bootstrap, "--force"},
+	}
+	for _, args := range commands {
+		if err := runCommandInDir(ex, repoDir, "git", args...); err != nil {
+			err = fmt.Errorf("command 'git %s' failed: %w", strings.Join(args, " "), err)
+			if args[0] == "push" {
+				return withCode(ErrPushDenied, err)
+			}
+			return err
+		}
+	}
+
+	version := "unknown"
+	if dto.Manifest != nil {
+		version = dto.Manifest.TemplateVersion
+	}
+	body := fmt.Sprintf("Initial content for `%s` generated by jupiter-registry (templates %s).\n\n"+
+		"`%s` only contains an empty commit: review and merge this PR to bootstrap the repository.", dto.AppName, version, dto.branch())
+	url, err := runCommandOutput(ex, "gh", "pr", "create",
+		"--repo", fmt.Sprintf("%s/%s", dto.Owner, dto.AppName),
+		"--base", dto.branch(),
+		"--head", bootstrap,
+		"--title", "chore(jupiter): bootstrap "+dto.AppName,
+		"--body", body)
+	if err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to open bootstrap PR: %w", err))
+	}
+	fmt.Printf("  🔀 Bootstrap PR opened: %s\n", url)
+	return nil
+}
@@ -0,0 +1,196 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Canary chặn batch khi template / generator mới bị hỏng: trước batch, provision một service canary vào
+// sandbox org, chờ CI generated của nó chạy xong và chỉ chạy batch thật khi CI xanh.
+// Version đã qua canary được ghi vào canaryStateFile nên các batch sau cùng version không chạy lại.
+type Canary struct {
+	Org string `yaml:"org"` // sandbox org, "" = tắt
+	// Targets: <language>[/<framework>] cần thử, mặc định golang (framework mặc định là uranus)
+	Targets []string `yaml:"targets"`
+	Timeout string   `yaml:"timeout"` // chờ CI tối đa, mặc định 20m
+	// Always chạy canary mọi batch (uranus cài @latest có thể đổi mà template không đổi)
+	Always bool `yaml:"always"`
+	Keep   bool `yaml:"keep"` // giữ repo canary sau khi chạy (debug)
+}
+
+const canaryStateFile = "state/canary.json"
+
+// canaryPollInterval là khoảng cách giữa các lần đọc trạng thái workflow run
+const canaryPollInterval = 15 * time.Second
+
+func (c Canary) enabled() bool {
+	return c.Org != ""
+}
+
+func (c Canary) targets() []string {
+	if len(c.Targets) == 0 {
+		return []string{"golang"}
+	}
+	return c.Targets
+}
+
+func (c Canary) timeout() (time.Duration, error) {
+	if c.Timeout == "" {
+		return 20 * time.Minute, nil
+	}
+	d, err := time.ParseDuration(c.Timeout)
+	if err != nil {
+		return 0, fmt.Errorf("invalid canary.timeout %q: %w", c.Timeout, err)
+	}
+	return d, nil
+}
+
+// CanaryState là lần canary xanh gần nhất
+type CanaryState struct {
+	TemplateVersion string   `json:"template_version"`
+	Targets         []string `json:"targets"`
+	PassedAt        string   `json:"passed_at"`
+	Runs            []string `json:"runs"`
+}
+
+func loadCanaryState() (CanaryState, error) {
+	var state CanaryState
+	data, err := os.ReadFile(canaryStateFile)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return state, err
+	}
+	if err := json.Unmarshal(data, &state); err != nil {
+		return state, fmt.Errorf("failed to parse %s: %w", canaryStateFile, err)
+	}
+	return state, nil
+}
+
+// runCanaryGate: force bỏ qua kiểm tra version đã qua canary
+func runCanaryGate(registryConfig RegistryConfig, force bool) error {
+	c := registryConfig.Canary
+	timeout, err := c.timeout()
+	if err != nil {
+		return withCode(ErrUsage, err)
+	}
+	version, err := templateVersion(registryConfig.FrameworkTemplates)
+	if err != nil {
+		return err
+	}
+	state, err := loadCanaryState()
+	if err != nil {
+		return err
+	}
+	if !force && !c.Always && state.TemplateVersion == version && strings.Join(state.Targets, ",") == strings.Join(c.targets(), ",") {
+		fmt.Printf("⏭️  Template version %s already passed canary at %s\n", version, state.PassedAt)
+		return nil
+	}
+
+	fmt.Printf("🐤 Canary for template version %s in %s...\n", version, c.Org)
+	var runs []string
+	for _, target := range c.targets() {
+		url, err := runCanary(target, c, timeout, registryConfig)
+		if err != nil {
+			return withCode(ErrCanaryFailed, fmt.Errorf("canary %s failed, batch not started: %w", target, err))
+		}
+		if url != "" {
+			runs = append(runs, url)
+		}
+	}
+
+	state = CanaryState{TemplateVersion: version, Targets: c.targets(), PassedAt: time.Now().UTC().Format(time.RFC3339), Runs: runs}
+	data, err := json.MarshalIndent(state, "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(canaryStateFile), 0755); err != nil {
+		return err
+	}
+	if err := os.WriteFile(canaryStateFile, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	fmt.Println("✅ Canary is green, starting batch")
+	return nil
+}
+
+// runCanary provision một service canary, chờ workflow run đầu tiên trên main và dọn repo.
+// Trả về URL của workflow run ("" khi template không có CI).
+func runCanary(target string, c Canary, timeout time.Duration, registryConfig RegistryConfig) (string, error) {
+	language, framework, _ := strings.Cut(target, "/")
+	suffix, err := newSourceID()
+	if err != nil {
+		return "", err
+	}
+	dto := GeneratorSourceDto{
+		AppName:             "jupiter-canary-" + suffix,
+		Owner:               c.Org,
+		ProgrammingLanguage: language,
+		Framework:           framework,
+		Visibility:          "private",
+		Team:                "canary",
+	}
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	fmt.Printf("🧪 Canary %s: %s\n", target, fullName)
+
+	runErr := processService(commandExecutor, dto, registryConfig)
+	var runURL string
+	if runErr == nil {
+		// Template không có workflow thì không có CI để chờ, provisioning thành công là đủ
+		workflows, _ := filepath.Glob(filepath.Join(dto.AppName, ".github", "workflows", "*.y*ml"))
+		if len(workflows) == 0 {
+			fmt.Printf("  ⚠️ %s has no workflows, skipping CI check\n", target)
+		} else {
+			runURL, runErr = waitForCanaryCI(fullName, timeout)
+		}
+	}
+
+	os.RemoveAll(dto.AppName)
+	if !c.Keep && repoExists(commandExecutor, dto.Owner, dto.AppName) {
+		fmt.Println("🧹 Cleaning up canary repository...")
+		if err := cleanupE2ERepo(fullName); err != nil {
+			fmt.Printf("  ⚠️ Cleanup failed: %v\n", err)
+		}
+	}
+	return runURL, runErr
+}
+
+// waitForCanaryCI chờ workflow run mới nhất trên main hoàn tất, lỗi nếu conclusion khác success
+func waitForCanaryCI(fullName string, timeout time.Duration) (string, error) {
+	fmt.Printf("⏳ Waiting for CI of %s (timeout %s)...\n", fullName, timeout)
+	deadline := time.Now().Add(timeout)
+	for {
+		out, err := runCommandOutput(commandExecutor, "gh", "run", "list", "--repo", fullName, "--branch", "main", "--limit", "1",
+			"--json", "status,conclusion,url")
+		if err != nil {
+			return "", withCode(ErrGitHubAPI, fmt.Errorf("failed to list workflow runs: %w", err))
+		}
+		var runs []struct {
+			Status     string `json:"status"`
+			Conclusion string `json:"conclusion"`
+			URL        string `json:"url"`
+		}
+		if err := json.Unmarshal([]byte(out), &runs); err != nil {
+			return "", fmt.Errorf("failed to parse workflow runs: %w", err)
+		}
+		if len(runs) > 0 && runs[0].Status == "completed" {
+			if runs[0].Conclusion != "success" {
+				return runs[0].URL, fmt.Errorf("CI concluded %s: %s", runs[0].Conclusion, runs[0].URL)
+			}
+			fmt.Printf("  ✅ CI passed: %s\n", runs[0].URL)
+			return runs[0].URL, nil
+		}
+		if time.Now().After(deadline) {
+			if len(runs) == 0 {
+				return "", fmt.Errorf("no workflow run started within %s", timeout)
+			}
+			return runs[0].URL, fmt.Errorf("CI still %s after %s: %s", runs[0].Status, timeout, runs[0].URL)
+		}
+		time.Sleep(canaryPollInterval)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Capabilities mô tả mọi giá trị source.yml / jupiter.yml chấp nhận
+type Capabilities struct {
+	Languages []LanguageCapability `json:"languages"`
+	Kinds     []KindCapability     `json:"kinds"`
+	Providers map[string][]string  `json:"providers"`
+	// Compatibility: framework -> language/kind mà validate chấp nhận
+	Compatibility map[string]FrameworkSupport `json:"compatibility"`
+	// TemplateRepos: service khai báo publish_as_template, dùng làm golden path ("Use this template")
+	TemplateRepos []TemplateRepoCapability `json:"template_repos"`
+}
+
+type TemplateRepoCapability struct {
+	Name      string `json:"name"`
+	Repo      string `json:"repo"`
+	Kind      string `json:"kind"`
+	Language  string `json:"language"`
+	Framework string `json:"framework,omitempty"`
+	Team      string `json:"team,omitempty"`
+}
+
+type LanguageCapability struct {
+	Name       string   `json:"name"`
+	Frameworks []string `json:"frameworks"`
+	// DefaultModule là module path khi metadata.module để trống
+	DefaultModule string `json:"default_module"`
+}
+
+type KindCapability struct {
+	Name       string   `json:"name"`
+	Languages  []string `json:"languages"`
+	Frameworks []string `json:"frameworks,omitempty"`
+}
+
+// buildCapabilities gom các danh sách mà processor và validate đang dùng
+func buildCapabilities(registryConfig RegistryConfig) Capabilities {
+	caps := Capabilities{
+		Languages: []LanguageCapability{
+			{Name: "golang", Frameworks: golangFrameworks, DefaultModule: "github.com/<owner>/<name>"},
+			{Name: "nodejs", Frameworks: nodejsFrameworks, DefaultModule: "<name>"},
+		},
+		Providers: map[string][]string{
+			"deploy.cloud":       supportedClouds,
+			"secret_store":       {"org_secret", "command"},
+			"container_registry": {"ghcr", "ecr", "command"},
+			"dns":                {"route53", "cloudflare", "command"},
+			"on_call":            {"pagerduty", "opsgenie"},
+			"error_tracking":     {"sentry"},
+			"mirror":             {"git", "github_org", "bundle"},
+			"locking":            {"auto", "file", "github", "none"},
+			"git.history":        {"squash", "template_version", "preserve", "merge"},
+			"git.on_conflict":    conflictStrategies,
+			"git.transport":      {"https", "ssh"},
+			"git.initial_push":   {"direct", "pull_request"},
+			"events.broker":      {"nats", "kafka"},
+			"auth.mode":          {"static", "oidc"},
+			"visibility":         supportedVisibilities,
+			"docs_language":      supportedDocsLanguages,
+			"approval_actions":   {actionPublicVisibility, actionForcePush, actionLargeBatch},
+		},
+		Compatibility: frameworkMatrix(),
+	}
+
+	caps.TemplateRepos = templateRepos(registryConfig)
+
+	for _, kind := range supportedKinds {
+		switch kind {
+		case "service":
+			caps.Kinds = append(caps.Kinds, KindCapability{Name: kind, Languages: supportedLanguages})
+		case "frontend":
+			caps.Kinds = append(caps.Kinds, KindCapability{Name: kind, Languages: []string{"nodejs"}, Frameworks: frontendFrameworks})
+		default:
+			// Kind dùng template: ngôn ngữ = các thư mục templates/<kind>/<language> hiện có
+			caps.Kinds = append(caps.Kinds, KindCapability{Name: kind, Languages: templateLanguages(registryConfig.FrameworkTemplates, kind)})
+		}
+	}
+	return caps
+}
+
+// templateRepos liệt kê các service publish_as_template đang có trong sources-service
+func templateRepos(registryConfig RegistryConfig) []TemplateRepoCapability {
+	repos := []TemplateRepoCapability{}
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return repos
+	}
+	for _, s := range services {
+		if !s.Config.PublishAsTemplate {
+			continue
+		}
+		config := resolveSourceConfig(s.Config, registryConfig)
+		repos = append(repos, TemplateRepoCapability{
+			Name:      config.Name,
+			Repo:      fmt.Sprintf("%s/%s", registryConfig.ownerFor(config), config.Name),
+			Kind:      config.Metadata.Kind,
+			Language:  config.Metadata.ProgrammingLanguage,
+			Framework: config.Metadata.Framework,
+			Team:      config.Metadata.Team,
+		})
+	}
+	return repos
+}
+
+func templateLanguages(templatesDir, kind string) []string {
+	languages := []string{}
+	if templatesDir == "" {
+		return languages
+	}
+	entries, err := os.ReadDir(filepath.Join(templatesDir, kind))
+	if err != nil {
+		return languages
+	}
+	for _, e := range entries {
+		if e.IsDir() && containsString(supportedLanguages, e.Name()) {
+			languages = append(languages, e.Name())
+		}
+	}
+	sort.Strings(languages)
+	return languages
+}
+
+// runCapabilitiesCommand in ma trận language/framework/kind/provider (text hoặc --json)
+func runCapabilitiesCommand(args []string) error {
+	fs := flag.NewFlagSet("capabilities", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print capabilities as JSON")
+	fs.Parse(args)
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	caps := buildCapabilities(registryConfig)
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(caps)
+	}
+
+	fmt.Println("Languages:")
+	for _, l := range caps.Languages {
+		fmt.Printf("  %-8s frameworks: %s (module default: %s)\n", l.Name, strings.Join(l.Frameworks, ", "), l.DefaultModule)
+	}
+	fmt.Println("\nKinds:")
+	for _, k := range caps.Kinds {
+		line := fmt.Sprintf("  %-9s languages: %s", k.Name, strings.Join(k.Languages, ", "))
+		if len(k.Frameworks) > 0 {
+			line += fmt.Sprintf("; frameworks: %s", strings.Join(k.Frameworks, ", "))
+		}
+		fmt.Println(line)
+	}
+	if len(caps.TemplateRepos) > 0 {
+		fmt.Println("\nTemplate repositories:")
+		for _, t := range caps.TemplateRepos {
+			fmt.Printf("  %-20s %s (%s %s %s)\n", t.Name, t.Repo, t.Kind, t.Language, t.Framework)
+		}
+	}
+	fmt.Println("\nProviders:")
+	keys := make([]string, 0, len(caps.Providers))
+	for k := range caps.Providers {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	for _, k := range keys {
+		fmt.Printf("  %-17s %s\n", k, strings.Join(caps.Providers[k], ", "))
+	}
+	return nil
+}
@@ -0,0 +1,155 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+)
+
+// CatalogEntry là một dòng trong catalog/index.json
+type CatalogEntry struct {
+	Folder              string   `json:"folder"`
+	SourceID            string   `json:"source_id"`
+	Name                string   `json:"name"`
+	ProgrammingLanguage string   `json:"programming_language"`
+	Framework           string   `json:"framework"`
+	Module              string   `json:"module"`
+	Team                string   `json:"team,omitempty"`
+	CostCenter          string   `json:"cost_center,omitempty"`
+	DataClassification  string   `json:"data_classification"`
+	Members             []string `json:"members"`
+	DependsOn           []string `json:"depends_on,omitempty"`
+	RepoURL             string   `json:"repo_url"`
+	Status              string   `json:"status,omitempty"` // kết quả generate gần nhất
+	TemplateVersion     string   `json:"template_version,omitempty"`
+	Badge               string   `json:"badge,omitempty"` // markdown badge (shields endpoint)
+}
+
+func buildCatalog(services []RegisteredService) []CatalogEntry {
+	// Repo thực tế lấy từ state (owner có thể do tenant quyết định), fallback owner trong source.yml
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		state = &RegistryState{}
+	}
+	// Badge trỏ về server nếu có public_url (lỗi config đã được báo ở chỗ khác)
+	registryConfig, _ := loadRegistryConfig(registryConfigFile)
+
+	catalog := make([]CatalogEntry, 0, len(services))
+	for _, s := range services {
+		repo := fmt.Sprintf("%s/%s", repoOwner, s.Config.Name)
+		if s.Config.Owner != "" {
+			repo = fmt.Sprintf("%s/%s", s.Config.Owner, s.Config.Name)
+		}
+		entry := state.Services[s.Config.Name]
+		if entry != nil && entry.Repo != "" {
+			repo = entry.Repo
+		}
+		status, version := "", ""
+		repoURL := "https://github.com/" + repo
+		if entry != nil {
+			status, version = entry.LastStatus, entry.TemplateVersion
+			if entry.Repository != nil && entry.Repository.HTMLURL != "" {
+				repoURL = entry.Repository.HTMLURL
+			}
+		}
+
+		catalog = append(catalog, CatalogEntry{
+			Folder:              s.Folder,
+			SourceID:            s.Config.SourceID,
+			Name:                s.Config.Name,
+			ProgrammingLanguage: s.Config.Metadata.ProgrammingLanguage,
+			Framework:           s.Config.Metadata.Framework,
+			Module:              s.Config.Metadata.Module,
+			Team:                s.Config.Metadata.Team,
+			CostCenter:          s.Config.Metadata.CostCenter,
+			DataClassification:  dataClassification(s.Config.Metadata.DataClassification),
+			Members:             s.Config.Members,
+			DependsOn:           s.Config.DependsOn,
+			RepoURL:             repoURL,
+			Status:              status,
+			TemplateVersion:     version,
+			Badge:               badgeMarkdown(s.Config.Name, registryConfig.Server),
+		})
+	}
+	return catalog
+}
+
+// runCatalogCommand regenerate catalog/index.json và CATALOG.md từ sources-service/
+func runCatalogCommand(args []string) error {
+	fs := flag.NewFlagSet("catalog", flag.ExitOnError)
+	outDir := fs.String("out", ".", "directory to write catalog/index.json and CATALOG.md into")
+	fs.Parse(args)
+
+	indexPath, err := writeCatalog(*outDir)
+	if err != nil {
+		return err
+	}
+	return writeStepOutputs(map[string]string{"catalog_path": indexPath})
+}
+
+// catalogMu serialize việc ghi catalog (subscriber "catalog" của event bus chạy trong batch song song)
+var catalogMu sync.Mutex
+
+// writeCatalog ghi catalog/index.json, badges và CATALOG.md vào outDir, trả về đường dẫn index.json
+func writeCatalog(outDir string) (string, error) {
+	catalogMu.Lock()
+	defer catalogMu.Unlock()
+
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return "", err
+	}
+	catalog := buildCatalog(services)
+
+	indexPath := filepath.Join(outDir, "catalog", "index.json")
+	if err := os.MkdirAll(filepath.Dir(indexPath), 0755); err != nil {
+		return "", fmt.Errorf("failed to create catalog dir: %w", err)
+	}
+	data, err := json.MarshalIndent(catalog, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(indexPath, append(data, '\n'), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", indexPath, err)
+	}
+
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return "", err
+	}
+	if err := writeBadges(outDir, catalog, state); err != nil {
+		return "", err
+	}
+
+	markdownPath := filepath.Join(outDir, "CATALOG.md")
+	if err := os.WriteFile(markdownPath, []byte(renderCatalogMarkdown(catalog)), 0644); err != nil {
+		return "", fmt.Errorf("failed to write %s: %w", markdownPath, err)
+	}
+
+	fmt.Printf("📚 Catalog updated: %d service(s) -> %s, %s\n", len(catalog), indexPath, markdownPath)
+	return indexPath, nil
+}
+
+func renderCatalogMarkdown(catalog []CatalogEntry) string {
+	var b strings.Builder
+	b.WriteString("# Service Catalog\n\n")
+	b.WriteString("<!-- Generated by jupiter-registry, do not edit manually. -->\n\n")
+	b.WriteString("| Service | Status | Language | Framework | Team | Data | Members | Repository |\n")
+	b.WriteString("|---|---|---|---|---|---|---|---|\n")
+	for _, e := range catalog {
+		fmt.Fprintf(&b, "| %s | %s | %s | %s | %s | %s | %s | [%s](%s) |\n",
+			e.Name, e.Badge, e.ProgrammingLanguage, e.Framework, e.Team, e.DataClassification,
+			strings.Join(e.Members, ", "), strings.TrimPrefix(e.RepoURL, "https://github.com/"), e.RepoURL)
+	}
+	// Có depends_on thì nhúng đồ thị mermaid (GitHub render trực tiếp), xuất format khác: `graph --format dot|json`
+	if graph := buildServiceGraph(catalog); len(graph.Edges) > 0 {
+		b.WriteString("\n## Dependencies\n\n```mermaid\n")
+		b.WriteString(graph.mermaid())
+		b.WriteString("```\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,63 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+)
+
+// pullRequestContext là thông tin PR khi tool chạy trong workflow pull_request
+type pullRequestContext struct {
+	Repository string
+	Number     int
+	HeadSHA    string
+}
+
+// currentPullRequest đọc GITHUB_EVENT_PATH để lấy PR hiện tại (nếu có)
+func currentPullRequest() (pullRequestContext, bool) {
+	if os.Getenv("GITHUB_EVENT_NAME") != "pull_request" {
+		return pullRequestContext{}, false
+	}
+
+	data, err := os.ReadFile(os.Getenv("GITHUB_EVENT_PATH"))
+	if err != nil {
+		return pullRequestContext{}, false
+	}
+
+	var event struct {
+		PullRequest struct {
+			Number int `json:"number"`
+			Head   struct {
+				SHA string `json:"sha"`
+			} `json:"head"`
+		} `json:"pull_request"`
+	}
+	if err := json.Unmarshal(data, &event); err != nil {
+		return pullRequestContext{}, false
+	}
+
+	return pullRequestContext{
+		Repository: os.Getenv("GITHUB_REPOSITORY"),
+		Number:     event.PullRequest.Number,
+		HeadSHA:    event.PullRequest.Head.SHA,
+	}, true
+}
+
+func reportCheckRun(pr pullRequestContext, name, conclusion, title, summary string) error {
+	body, err := json.Marshal(map[string]interface{}{
+		"name":       name,
+		"head_sha":   pr.HeadSHA,
+		"status":     "completed",
+		"conclusion": conclusion,
+		"output": map[string]string{
+			"title":   title,
+			"summary": summary,
+		},
+	})
+	if err != nil {
+		return err
+	}
+
+	return ghRegistryWithInput(commandExecutor, body, "api", "-X", "POST",
+		fmt.Sprintf("repos/%s/check-runs", pr.Repository), "--input", "-")
+}
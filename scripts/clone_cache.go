@@ -0,0 +1,60 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+)
+
+// cloneDepth: mặc định 2 để `commit --amend` (template_version) vẫn giữ được parent thật
+func (g GitConfig) cloneDepth() int {
+	if g.CloneDepth < 1 {
+		return 2
+	}
+	return g.CloneDepth
+}
+
+func (g GitConfig) cacheDir() string {
+	if g.CacheDir == "" {
+		return ".jupiter-cache/repos"
+	}
+	return g.CacheDir
+}
+
+// cachedClone trả về shallow clone branch của repo trong cache, chỉ fetch phần mới nếu đã có.
+// URL (có thể chứa token) không được lưu vào .git/config của cache, luôn truyền trực tiếp khi fetch.
+func cachedClone(ex Executor, owner, repoName, repoURL, branch string, gitConfig GitConfig) (string, error) {
+	dir := filepath.Join(gitConfig.cacheDir(), owner, repoName)
+	depth := strconv.Itoa(gitConfig.cloneDepth())
+
+	if _, err := os.Stat(filepath.Join(dir, ".git")); err == nil {
+		fmt.Printf("  ♻️  Refreshing cached clone %s/%s\n", owner, repoName)
+		commands := [][]string{
+			{"fetch", "--quiet", "--depth", depth, repoURL, branch},
+			{"reset", "--quiet", "--hard", "FETCH_HEAD"},
+			{"clean", "-fdxq"},
+		}
+		for _, args := range commands {
+			if err := runCommandInDir(ex, dir, "git", args...); err != nil {
+				// Cache hỏng thì clone lại từ đầu
+				fmt.Printf("  ⚠️ Cached clone is unusable, re-cloning: %v\n", err)
+				os.RemoveAll(dir)
+				return cachedClone(ex, owner, repoName, repoURL, branch, gitConfig)
+			}
+		}
+		return dir, nil
+	}
+
+	if err := os.MkdirAll(filepath.Dir(dir), 0755); err != nil {
+		return "", err
+	}
+	if err := runCommand(ex, "git", "clone", "--quiet", "--depth", depth, "--branch", branch, repoURL, dir); err != nil {
+		os.RemoveAll(dir)
+		return "", fmt.Errorf("failed to clone %s/%s: %w", owner, repoName, err)
+	}
+	if err := runCommandInDir(ex, dir, "git", "remote", "remove", "origin"); err != nil {
+		return "", err
+	}
+	return dir, nil
+}
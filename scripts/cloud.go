@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// supportedClouds là các cloud target có template deploy
+var supportedClouds = []string{"aws", "gcp", "azure"}
+
+// Deploy là block `deploy:` trong source.yml
+type Deploy struct {
+	Cloud   string `yaml:"cloud,omitempty"`   // aws | gcp | azure
+	Region  string `yaml:"region,omitempty"`  // ap-southeast-1, asia-southeast1, southeastasia, ...
+	Account string `yaml:"account,omitempty"` // AWS account ID / GCP project ID / Azure subscription ID
+}
+
+type cloudScaffoldData struct {
+	Service GeneratorSourceDto
+	Deploy  Deploy
+}
+
+// writeCloudScaffold render templates/cloud/<cloud>/ vào repo (CI push image, provider Terraform, runtime config)
+func writeCloudScaffold(repoDir string, dto GeneratorSourceDto, templatesDir string) error {
+	if dto.Deploy.Cloud == "" {
+		return nil
+	}
+	if templatesDir == "" {
+		return fmt.Errorf("deploy.cloud is set but cloud_templates is not configured")
+	}
+
+	srcDir := filepath.Join(templatesDir, dto.Deploy.Cloud)
+	if _, err := os.Stat(srcDir); err != nil {
+		return fmt.Errorf("no templates for cloud %s in %s", dto.Deploy.Cloud, templatesDir)
+	}
+
+	fmt.Printf("☁️  Rendering %s deployment scaffolding...\n", dto.Deploy.Cloud)
+	return renderTemplateDir(srcDir, repoDir, cloudScaffoldData{Service: dto, Deploy: dto.Deploy})
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+)
+
+// defaultCollisionSuffixes được thử lần lượt với on_collision: suffix
+var defaultCollisionSuffixes = []string{"-svc", "-2"}
+
+func (n NamingConvention) onCollision() string {
+	if n.OnCollision == "" {
+		return "fail"
+	}
+	return n.OnCollision
+}
+
+func (n NamingConvention) collisionSuffixes() []string {
+	if len(n.CollisionSuffixes) == 0 {
+		return defaultCollisionSuffixes
+	}
+	return n.CollisionSuffixes
+}
+
+// repoManagedBy: repo có trong state hoặc mang marker manifest của chính service này (state bị mất)
+func repoManagedBy(ex Executor, owner, name, sourceID string, state *RegistryState) bool {
+	if entry, ok := state.Services[name]; ok && entry.Repo == owner+"/"+name {
+		return true
+	}
+	manifest, ok := readRemoteManifest(ex, owner, name)
+	return ok && (manifest.SourceID == "" || manifest.SourceID == sourceID)
+}
+
+// resolveNameCollision xử lý repo trùng tên không do registry quản lý theo validation.naming.on_collision:
+//   - fail: dừng với E_REPO_EXISTS (mặc định)
+//   - suffix: dùng tên <name><suffix> đầu tiên còn trống (hoặc đã là của service này)
+//   - adopt: tiếp quản repo có sẵn, bắt buộc --confirm <app-name>
+func resolveNameCollision(ex Executor, config SourceConfig, dto *GeneratorSourceDto, naming NamingConvention, confirmed []string) error {
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	if repoManagedBy(ex, dto.Owner, dto.AppName, config.SourceID, state) || !repoExists(ex, dto.Owner, dto.AppName) {
+		return nil
+	}
+	repo := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+
+	switch naming.onCollision() {
+	case "fail":
+		return withCode(ErrRepoExists, fmt.Errorf("repository %s already exists and is not managed by the registry (set validation.naming.on_collision to suffix or adopt)", repo))
+	case "suffix":
+		for _, suffix := range naming.collisionSuffixes() {
+			candidate := dto.AppName + suffix
+			if problems := validateServiceName(candidate, dto.Team, naming); len(problems) > 0 {
+				return withCode(ErrRepoExists, fmt.Errorf("repository %s already exists and %s", repo, strings.Join(problems, "; ")))
+			}
+			if repoManagedBy(ex, dto.Owner, candidate, config.SourceID, state) || !repoExists(ex, dto.Owner, candidate) {
+				fmt.Printf("⚠️ %s already exists and is not managed by the registry, using %s/%s instead\n", repo, dto.Owner, candidate)
+				dto.AppName = candidate
+				return nil
+			}
+		}
+		return withCode(ErrRepoExists, fmt.Errorf("repository %s already exists and every suffix %v is taken", repo, naming.collisionSuffixes()))
+	case "adopt":
+		// Luôn cần xác nhận, kể cả khi destructive.confirm không bật force_push: repo có thể là của người khác
+		if !containsString(confirmed, dto.AppName) {
+			return withCode(ErrConfirmationRequired, fmt.Errorf("repository %s already exists and is not managed by the registry, re-run with --confirm %s to adopt it", repo, dto.AppName))
+		}
+		fmt.Printf("⚠️ Adopting existing repository %s (confirmed)\n", repo)
+		return nil
+	default:
+		return withCode(ErrUsage, fmt.Errorf("unsupported validation.naming.on_collision: %s", naming.OnCollision))
+	}
+}
@@ -0,0 +1,36 @@
+package main
+
+// subcommands được dispatch từ main khi argument đầu tiên trùng tên command.
+// Nếu không trùng, argument được hiểu là đường dẫn service folder như trước.
+var subcommands = map[string]func(args []string) error{
+	"catalog":       runCatalogCommand,
+	"docs":          runDocsCommand,
+	"search":        runSearchCommand,
+	"export":        runExportCommand,
+	"adopt":         runAdoptCommand,
+	"batch":         runBatchCommand,
+	"bench":         runBenchCommand,
+	"e2e":           runE2ECommand,
+	"drift":         runDriftCommand,
+	"gc":            runGCCommand,
+	"operator":      runOperatorCommand,
+	"serve":         runServeCommand,
+	"capabilities":  runCapabilitiesCommand,
+	"config":        runConfigCommand,
+	"template":      runTemplateCommand,
+	"archive":       runArchiveCommand,
+	"restore":       runRestoreCommand,
+	"delete":        runDeleteCommand,
+	"decommission":  runDecommissionCommand,
+	"confirm-token": runConfirmTokenCommand,
+	"history":       runHistoryCommand,
+	"plan":          runPlanCommand,
+	"rollout":       runRolloutCommand,
+	"refresh":       runRefreshCommand,
+	"upgrade":       runUpgradeCommand,
+	"graph":         runGraphCommand,
+	"provenance":    runProvenanceCommand,
+	"managed-files": runManagedFilesCommand,
+	"events":        runEventsCommand,
+	"artifacts":     runArtifactsCommand,
+}
@@ -0,0 +1,50 @@
+package main
+
+import (
+	"fmt"
+	"os"
+)
+
+// CommunityFiles holds the org policy used to render SECURITY.md and CONTRIBUTING.md
+type CommunityFiles struct {
+	Templates        string               `yaml:"templates"`
+	SecurityContacts []string             `yaml:"security_contacts"`
+	DisclosureURL    string               `yaml:"disclosure_url"`
+	Toolchains       map[string]Toolchain `yaml:"toolchains"`
+}
+
+// Toolchain là các lệnh build/test/lint của từng ngôn ngữ, dùng trong CONTRIBUTING.md
+type Toolchain struct {
+	Setup string `yaml:"setup"`
+	Build string `yaml:"build"`
+	Test  string `yaml:"test"`
+	Lint  string `yaml:"lint"`
+}
+
+type communityFilesData struct {
+	Service          GeneratorSourceDto
+	SecurityContacts []string
+	DisclosureURL    string
+	Toolchain        Toolchain
+	Badge            string // markdown badge trạng thái generate từ jupiter-registry
+}
+
+func writeCommunityFiles(repoDir string, dto GeneratorSourceDto, files CommunityFiles, server ServerConfig) error {
+	if files.Templates == "" {
+		fmt.Println("  ⚠️ No community file templates configured, skipping")
+		return nil
+	}
+
+	if _, err := os.Stat(files.Templates); err != nil {
+		return fmt.Errorf("template dir not found: %s", files.Templates)
+	}
+
+	data := communityFilesData{
+		Service:          dto,
+		SecurityContacts: files.SecurityContacts,
+		DisclosureURL:    files.DisclosureURL,
+		Toolchain:        files.Toolchains[dto.ProgrammingLanguage],
+		Badge:            badgeMarkdown(dto.AppName, server),
+	}
+	return renderLocalizedTemplateDir(files.Templates, repoDir, dto.DocsLanguage, data)
+}
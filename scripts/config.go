@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// registryConfigFile là file cấu hình chung của registry (nằm ở root repo)
+const registryConfigFile = "jupiter.yml"
+
+// RegistryConfig represents org-level settings shared by every service
+type RegistryConfig struct {
+	RepoSettings RepoSettings `yaml:"repo_settings"`
+	Rulesets     []Ruleset    `yaml:"rulesets"`
+
+	GithubTemplates   GithubTemplates   `yaml:"github_templates"`
+	CommunityFiles    CommunityFiles    `yaml:"community_files"`
+	ManagedFiles      ManagedFiles      `yaml:"managed_files"`
+	DeployKeys        DeployKeys        `yaml:"deploy_keys"`
+	Server            ServerConfig      `yaml:"server"`
+	Validation        Validation        `yaml:"validation"`
+	ApprovalPolicy    ApprovalPolicy    `yaml:"approval_policy"`
+	Policies          Policies          `yaml:"policies"`
+	Quotas            Quotas            `yaml:"quotas"`
+	OwnershipTags     OwnershipTags     `yaml:"ownership_tags"`
+	Throttle          Throttle          `yaml:"throttle"`
+	Batch             BatchPolicy       `yaml:"batch"`
+	Bench             Bench             `yaml:"bench"`
+	Canary            Canary            `yaml:"canary"`
+	Mirror            Mirror            `yaml:"mirror"`
+	RunLogs           RunLogs           `yaml:"run_logs"`
+	Artifacts         ArtifactStore     `yaml:"artifacts"`
+	OCI               OCIArtifacts      `yaml:"oci"`
+	Provenance        Provenance        `yaml:"provenance"`
+	Tenants           []Tenant          `yaml:"tenants"`
+	Auth              Auth              `yaml:"auth"`
+	Locking           Locking           `yaml:"locking"`
+	Git               GitConfig         `yaml:"git"`
+	Defaults          Defaults          `yaml:"defaults"`
+	Guardrails        Guardrails        `yaml:"guardrails"`
+	Licenses          LicensePolicy     `yaml:"licenses"`
+	ContainerRegistry ContainerRegistry `yaml:"container_registry"`
+	Publishing        Publishing        `yaml:"publishing"`
+	DNS               DNS               `yaml:"dns"`
+	OnCall            OnCall            `yaml:"on_call"`
+	ErrorTracking     ErrorTracking     `yaml:"error_tracking"`
+	Tickets           Tickets           `yaml:"tickets"`
+	Sonar             Sonar             `yaml:"sonar"`
+	SLO               SLOConfig         `yaml:"slo"`
+	Runbook           Runbook           `yaml:"runbook"`
+	Destructive       Destructive       `yaml:"destructive"`
+	Decommission      Decommission      `yaml:"decommission"`
+	Rollout           Rollout           `yaml:"rollout"`
+	APICache          APICache          `yaml:"api_cache"`
+	Events            Events            `yaml:"events"`
+	Telemetry         Telemetry         `yaml:"telemetry"`
+	Generator         Generator         `yaml:"generator"`
+	CloudTemplates    string            `yaml:"cloud_templates"`
+	// FrameworkTemplates chứa templates/<language>/<framework>/ cho các framework không dùng uranus
+	FrameworkTemplates string `yaml:"framework_templates"`
+}
+
+// loadRegistryConfig đọc jupiter.yml, nếu không có file thì trả về config rỗng
+func loadRegistryConfig(path string) (RegistryConfig, error) {
+	var cfg RegistryConfig
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return cfg, nil
+	}
+	if err != nil {
+		return cfg, fmt.Errorf("failed to read %s: %w", path, err)
+	}
+
+	if err := yaml.Unmarshal(data, &cfg); err != nil {
+		return cfg, withCode(ErrYAMLInvalid, fmt.Errorf("failed to parse %s: %w", path, err))
+	}
+	return cfg, nil
+}
+
+// Validation gom các cấu hình validate source.yml
+type Validation struct {
+	Members MemberValidation `yaml:"members"`
+	Naming  NamingConvention `yaml:"naming"`
+}
@@ -0,0 +1,250 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"go/format"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// ConfigVar là một biến môi trường trong block `config:` của source.yml
+type ConfigVar struct {
+	Name        string `yaml:"name"`
+	Type        string `yaml:"type,omitempty"` // string (mặc định) | int | bool | duration | url
+	Required    bool   `yaml:"required,omitempty"`
+	Default     string `yaml:"default,omitempty"`
+	Description string `yaml:"description,omitempty"`
+	Secret      bool   `yaml:"secret,omitempty"` // chỉ là metadata: giá trị không được commit
+}
+
+// configContractFile là env schema (JSON Schema) ở root repo generated
+const configContractFile = "config.schema.json"
+
+var (
+	configVarTypes  = []string{"string", "int", "bool", "duration", "url"}
+	envVarNameRegex = regexp.MustCompile(`^[A-Z][A-Z0-9_]*$`)
+)
+
+func (v ConfigVar) varType() string {
+	if v.Type == "" {
+		return "string"
+	}
+	return v.Type
+}
+
+// validateConfigContract chạy trong validateSourceConfig
+func validateConfigContract(vars []ConfigVar) []string {
+	var problems []string
+	seen := map[string]bool{}
+	for _, v := range vars {
+		if !envVarNameRegex.MatchString(v.Name) {
+			problems = append(problems, fmt.Sprintf("config: '%s' must be an UPPER_SNAKE_CASE environment variable name", v.Name))
+		}
+		if seen[v.Name] {
+			problems = append(problems, fmt.Sprintf("config: duplicate variable '%s'", v.Name))
+		}
+		seen[v.Name] = true
+		if !containsString(configVarTypes, v.varType()) {
+			problems = append(problems, fmt.Sprintf("config: %s type '%s' is not supported (supported: %v)", v.Name, v.Type, configVarTypes))
+			continue
+		}
+		if v.Default != "" {
+			if err := checkConfigDefault(v); err != nil {
+				problems = append(problems, fmt.Sprintf("config: %s default %q: %v", v.Name, v.Default, err))
+			}
+			if v.Secret {
+				problems = append(problems, fmt.Sprintf("config: %s is secret and must not have a default", v.Name))
+			}
+		}
+	}
+	return problems
+}
+
+func checkConfigDefault(v ConfigVar) error {
+	var err error
+	switch v.varType() {
+	case "int":
+		_, err = strconv.Atoi(v.Default)
+	case "bool":
+		_, err = strconv.ParseBool(v.Default)
+	case "duration":
+		_, err = time.ParseDuration(v.Default)
+	case "url":
+		if !strings.Contains(v.Default, "://") {
+			err = fmt.Errorf("not a URL")
+		}
+	}
+	return err
+}
+
+type configContractVar struct {
+	ConfigVar
+	GoName  string
+	GoType  string
+	ZodType string
+}
+
+type configContractData struct {
+	Service   GeneratorSourceDto
+	Vars      []configContractVar
+	NeedsTime bool // có biến duration (Go import time)
+}
+
+func buildConfigContractData(dto GeneratorSourceDto) configContractData {
+	data := configContractData{Service: dto}
+	for _, v := range dto.Config {
+		cv := configContractVar{ConfigVar: v, GoName: goFieldName(v.Name)}
+		switch v.varType() {
+		case "int":
+			cv.GoType, cv.ZodType = "int", "z.coerce.number().int()"
+		case "bool":
+			cv.GoType, cv.ZodType = "bool", `z.enum(["true", "false"]).transform((v) => v === "true")`
+		case "duration":
+			cv.GoType, cv.ZodType = "time.Duration", "z.string()"
+			data.NeedsTime = true
+		case "url":
+			cv.GoType, cv.ZodType = "string", "z.string().url()"
+		default:
+			cv.GoType, cv.ZodType = "string", "z.string()"
+		}
+		switch {
+		case v.Default != "":
+			switch v.varType() {
+			case "int":
+				cv.ZodType += fmt.Sprintf(".default(%s)", v.Default)
+			case "bool":
+				cv.ZodType = fmt.Sprintf(`z.enum(["true", "false"]).default(%q).transform((v) => v === "true")`, v.Default)
+			default:
+				cv.ZodType += fmt.Sprintf(".default(%q)", v.Default)
+			}
+		case !v.Required:
+			cv.ZodType += ".optional()"
+		}
+		data.Vars = append(data.Vars, cv)
+	}
+	return data
+}
+
+// goFieldName: DATABASE_URL -> DatabaseURL
+func goFieldName(name string) string {
+	var b strings.Builder
+	for _, part := range strings.Split(strings.ToLower(name), "_") {
+		if part == "" {
+			continue
+		}
+		switch part {
+		case "url", "id", "api", "http", "tls", "ttl", "db":
+			b.WriteString(strings.ToUpper(part))
+		default:
+			b.WriteString(strings.ToUpper(part[:1]) + part[1:])
+		}
+	}
+	return b.String()
+}
+
+// configSchema là JSON Schema mô tả env của service (tài liệu + validate ngoài code)
+func configSchema(dto GeneratorSourceDto) map[string]interface{} {
+	properties := map[string]interface{}{}
+	required := []string{}
+	for _, v := range dto.Config {
+		prop := map[string]interface{}{"type": "string"}
+		switch v.varType() {
+		case "int":
+			prop["pattern"] = `^-?[0-9]+$`
+		case "bool":
+			prop["enum"] = []string{"true", "false"}
+		case "duration":
+			prop["pattern"] = `^([0-9]+(\.[0-9]+)?(ns|us|ms|s|m|h))+$`
+		case "url":
+			prop["format"] = "uri"
+		}
+		prop["x-type"] = v.varType()
+		if v.Description != "" {
+			prop["description"] = v.Description
+		}
+		if v.Default != "" {
+			prop["default"] = v.Default
+		}
+		if v.Secret {
+			prop["x-secret"] = true
+		}
+		properties[v.Name] = prop
+		if v.Required {
+			required = append(required, v.Name)
+		}
+	}
+	return map[string]interface{}{
+		"$schema":    "https://json-schema.org/draft/2020-12/schema",
+		"title":      dto.AppName + " environment",
+		"type":       "object",
+		"properties": properties,
+		"required":   required,
+	}
+}
+
+// writeConfigContract ghi config.schema.json và code load env theo language rồi cập nhật dependency
+func writeConfigContract(ex Executor, repoDir string, dto GeneratorSourceDto, templatesDir string) error {
+	if len(dto.Config) == 0 {
+		return nil
+	}
+	fmt.Printf("🧾 Generating config contract (%d variables)...\n", len(dto.Config))
+
+	variant := dto.ProgrammingLanguage
+	if variant == "nodejs" {
+		// Project TypeScript (nestjs, nextjs, ...) dùng env.ts
+		if _, err := os.Stat(filepath.Join(repoDir, "tsconfig.json")); err == nil {
+			variant = "typescript"
+		}
+	}
+	if err := renderConfigContract(repoDir, dto, templatesDir, variant); err != nil {
+		return err
+	}
+
+	switch dto.ProgrammingLanguage {
+	case "golang":
+		return runCommandInDir(ex, repoDir, "go", "mod", "tidy")
+	case "nodejs":
+		return runCommandInDir(ex, repoDir, "npm", "install", "--save", "--package-lock-only", "zod")
+	}
+	return nil
+}
+
+// renderConfigContract ghi schema + code load env (variant: golang | nodejs | typescript)
+func renderConfigContract(repoDir string, dto GeneratorSourceDto, templatesDir, variant string) error {
+	schema, err := json.MarshalIndent(configSchema(dto), "", "  ")
+	if err != nil {
+		return err
+	}
+	if err := writeRepoFile(repoDir, configContractFile, append(schema, '\n')); err != nil {
+		return err
+	}
+
+	if templatesDir == "" {
+		return fmt.Errorf("framework_templates is not configured")
+	}
+	if err := renderTemplateDir(filepath.Join(templatesDir, "config_contract", variant), repoDir, buildConfigContractData(dto)); err != nil {
+		return err
+	}
+	if variant == "golang" {
+		return gofmtFile(filepath.Join(repoDir, "internal", "appconfig", "config.go"))
+	}
+	return nil
+}
+
+// gofmtFile format code Go generated (căn chỉnh struct tag phụ thuộc độ dài tên field)
+func gofmtFile(path string) error {
+	src, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	formatted, err := format.Source(src)
+	if err != nil {
+		return fmt.Errorf("generated %s is not valid Go: %w", path, err)
+	}
+	return os.WriteFile(path, formatted, 0644)
+}
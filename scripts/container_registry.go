@@ -0,0 +1,124 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ContainerRegistry tạo image repository đi kèm git repo để Docker workflow chạy được ngay lần đầu.
+// Chỉ áp dụng cho service có Dockerfile trong output generated.
+//   - ghcr:    render workflow push ghcr.io/<owner>/<name> (package tự link với repo qua label source)
+//   - ecr:     tạo ECR repository + repository policy cho role CI, set secret AWS_DEPLOY_ROLE_ARN
+//   - command: chạy command tuỳ chỉnh (Terraform, API nội bộ, ...)
+type ContainerRegistry struct {
+	Type string `yaml:"type"` // "" = tắt
+
+	// ECR: region mặc định lấy deploy.region của service
+	Region        string `yaml:"region"`
+	PushRoleARN   string `yaml:"push_role_arn"` // role CI assume qua OIDC, {name} = tên service
+	ScanOnPush    bool   `yaml:"scan_on_push"`
+	ImmutableTags bool   `yaml:"immutable_tags"`
+	Command       string `yaml:"command"`
+	Required      bool   `yaml:"required"` // true: lỗi thì provisioning fail
+}
+
+type containerWorkflowData struct {
+	Service GeneratorSourceDto
+	Image   string
+	Branch  string
+}
+
+// needsContainerRepository: output generated có Dockerfile ở root
+func needsContainerRepository(repoDir string) bool {
+	_, err := os.Stat(filepath.Join(repoDir, "Dockerfile"))
+	return err == nil
+}
+
+func provisionContainerRepository(ex Executor, repoDir string, dto GeneratorSourceDto, registry ContainerRegistry, templatesDir string) error {
+	switch registry.Type {
+	case "ghcr":
+		// deploy.cloud đã có workflow push image lên registry của cloud đó
+		if dto.Deploy.Cloud != "" {
+			fmt.Printf("  ℹ️ deploy.cloud %s pushes images itself, skipping GHCR workflow\n", dto.Deploy.Cloud)
+			return nil
+		}
+		if templatesDir == "" {
+			return fmt.Errorf("framework_templates is not configured")
+		}
+		image := strings.ToLower(fmt.Sprintf("ghcr.io/%s/%s", dto.Owner, dto.AppName))
+		return renderTemplateDir(filepath.Join(templatesDir, "container", "ghcr"), repoDir, containerWorkflowData{Service: dto, Image: image, Branch: dto.branch()})
+	case "ecr":
+		return provisionECRRepository(ex, dto, registry)
+	case "command":
+		fmt.Printf("  → Running container registry command for %s\n", dto.AppName)
+		_, err := ex.Run(Command{
+			Name: "sh",
+			Args: []string{"-c", registry.Command},
+			Env:  []string{"JUPITER_SERVICE=" + dto.AppName, "JUPITER_OWNER=" + dto.Owner, "JUPITER_REGION=" + dto.Deploy.Region},
+		})
+		return err
+	default:
+		return fmt.Errorf("unsupported container registry type: %s", registry.Type)
+	}
+}
+
+// provisionECRRepository tạo repository (idempotent), cho role CI quyền push và set secret cho workflow deploy
+func provisionECRRepository(ex Executor, dto GeneratorSourceDto, registry ContainerRegistry) error {
+	region := dto.Deploy.Region
+	if region == "" {
+		region = registry.Region
+	}
+	if region == "" {
+		return fmt.Errorf("ecr requires deploy.region or container_registry.region")
+	}
+
+	if _, err := runCommandOutput(ex, "aws", "ecr", "describe-repositories", "--region", region, "--repository-names", dto.AppName); err == nil {
+		fmt.Printf("  ℹ️ ECR repository %s already exists\n", dto.AppName)
+	} else {
+		mutability := "MUTABLE"
+		if registry.ImmutableTags {
+			mutability = "IMMUTABLE"
+		}
+		if err := runCommand(ex, "aws", "ecr", "create-repository", "--region", region,
+			"--repository-name", dto.AppName,
+			"--image-tag-mutability", mutability,
+			"--image-scanning-configuration", fmt.Sprintf("scanOnPush=%t", registry.ScanOnPush),
+			"--tags", "Key=owner,Value="+dto.Owner, "Key=team,Value="+dto.Team); err != nil {
+			return fmt.Errorf("failed to create ECR repository: %w", err)
+		}
+	}
+
+	if registry.PushRoleARN == "" {
+		return nil
+	}
+	roleARN := strings.ReplaceAll(registry.PushRoleARN, "{name}", dto.AppName)
+	policy, err := json.Marshal(map[string]interface{}{
+		"Version": "2012-10-17",
+		"Statement": []map[string]interface{}{{
+			"Sid":       "JupiterCIPush",
+			"Effect":    "Allow",
+			"Principal": map[string]string{"AWS": roleARN},
+			"Action": []string{
+				"ecr:BatchCheckLayerAvailability", "ecr:InitiateLayerUpload", "ecr:UploadLayerPart",
+				"ecr:CompleteLayerUpload", "ecr:PutImage", "ecr:BatchGetImage",
+			},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	if err := runCommand(ex, "aws", "ecr", "set-repository-policy", "--region", region,
+		"--repository-name", dto.AppName, "--policy-text", string(policy)); err != nil {
+		return fmt.Errorf("failed to grant CI push access: %w", err)
+	}
+
+	// Workflow deploy (templates/cloud/aws) assume role này qua secret AWS_DEPLOY_ROLE_ARN
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	if err := runCommandWithInput(ex, []byte(roleARN), "gh", "secret", "set", "AWS_DEPLOY_ROLE_ARN", "--repo", fullName); err != nil {
+		return fmt.Errorf("failed to set AWS_DEPLOY_ROLE_ARN: %w", err)
+	}
+	return nil
+}
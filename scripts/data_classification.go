@@ -0,0 +1,72 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// dataClassifications là các giá trị của metadata.data_classification (trống = internal)
+var dataClassifications = []string{"public", "internal", "pii"}
+
+// dataClassification trả về classification hiệu lực (trống = internal)
+func dataClassification(class string) string {
+	if class == "" {
+		return "internal"
+	}
+	return class
+}
+
+type dataProtectionData struct {
+	Service GeneratorSourceDto
+}
+
+func validateDataClassification(config SourceConfig) []string {
+	class := config.Metadata.DataClassification
+	if class == "" {
+		return nil
+	}
+	if !containsString(dataClassifications, class) {
+		return []string{fmt.Sprintf("metadata.data_classification '%s' is not supported (supported: %v)", class, dataClassifications)}
+	}
+	var problems []string
+	if class == "pii" {
+		if config.Visibility == "public" {
+			problems = append(problems, "data_classification pii does not allow visibility public")
+		}
+		if config.Metadata.Team == "" {
+			problems = append(problems, "data_classification pii requires metadata.team as data owner")
+		}
+	}
+	return problems
+}
+
+// writeDataProtection overlay templates/data_classification/pii/ cho service PII:
+// common (policy check workflow), <cloud> (Terraform mã hoá at rest), <language> (audit middleware)
+func writeDataProtection(repoDir string, dto GeneratorSourceDto, templatesDir string) error {
+	if dto.DataClassification != "pii" {
+		return nil
+	}
+	fmt.Println("🔒 Adding PII controls (encryption at rest, audit middleware, policy checks)...")
+
+	baseDir := filepath.Join(templatesDir, "data_classification", "pii")
+	dirs := []string{"common"}
+	if dto.Deploy.Cloud != "" {
+		dirs = append(dirs, dto.Deploy.Cloud)
+	}
+	// Audit middleware chỉ có nghĩa với service nhận HTTP request
+	if dto.Kind == "" || dto.Kind == "service" {
+		dirs = append(dirs, dto.ProgrammingLanguage)
+	}
+	for _, dir := range dirs {
+		srcDir := filepath.Join(baseDir, dir)
+		if _, err := os.Stat(srcDir); err != nil {
+			fmt.Printf("  ⚠️ No PII templates for %s, skipping\n", dir)
+			continue
+		}
+		if err := renderTemplateDir(srcDir, repoDir, dataProtectionData{Service: dto}); err != nil {
+			return err
+		}
+	}
+	return nil
+}
@@ -0,0 +1,392 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// Decommission là chiều ngược của provisioning: gỡ quyền truy cập và tích hợp của repo, archive repo,
+// bỏ service khỏi registry / catalog rồi ghi report. Step chạy theo thứ tự, dừng ở step lỗi đầu tiên;
+// các step idempotent (liệt kê lại trạng thái hiện tại) nên chạy lại sau khi sửa lỗi là đủ.
+type Decommission struct {
+	// Steps: các step cần chạy, mặc định toàn bộ decommissionSteps
+	Steps     []string `yaml:"steps"`
+	ReportDir string   `yaml:"report_dir"` // mặc định state/decommission
+}
+
+func (d Decommission) steps() []string {
+	if len(d.Steps) == 0 {
+		names := make([]string, len(decommissionSteps))
+		for i, s := range decommissionSteps {
+			names[i] = s.Name
+		}
+		return names
+	}
+	return d.Steps
+}
+
+func (d Decommission) reportDir() string {
+	if d.ReportDir == "" {
+		return "state/decommission"
+	}
+	return d.ReportDir
+}
+
+// decommissionTarget là service đang được decommission
+type decommissionTarget struct {
+	Name           string
+	Repo           string
+	DryRun         bool
+	RegistryConfig RegistryConfig
+}
+
+// decommissionStep trả về chi tiết đã làm (dry run: sẽ làm)
+type decommissionStep struct {
+	Name string
+	Run  func(t decommissionTarget) ([]string, error)
+}
+
+// decommissionSteps: archive chạy sau các step gỡ quyền / tích hợp vì repo archived là read-only với API
+var decommissionSteps = []decommissionStep{
+	{Name: "revoke_collaborators", Run: revokeCollaborators},
+	{Name: "delete_webhooks", Run: func(t decommissionTarget) ([]string, error) {
+		return deleteRepoItems(t, "hooks", "webhook", ".[] | \"\\(.id) \\(.config.url)\"")
+	}},
+	{Name: "delete_deploy_keys", Run: func(t decommissionTarget) ([]string, error) {
+		return deleteRepoItems(t, "keys", "deploy key", ".[] | \"\\(.id) \\(.title)\"")
+	}},
+	{Name: "delete_secrets", Run: deleteRepoSecrets},
+	{Name: "archive", Run: func(t decommissionTarget) ([]string, error) {
+		if t.DryRun {
+			return []string{"archive " + t.Repo}, nil
+		}
+		// decommission đã được xác nhận và approve, archive không hỏi lại
+		return []string{"archived " + t.Repo}, archiveRepository(t.Name, t.RegistryConfig)
+	}},
+	{Name: "remove_source", Run: removeServiceSource},
+}
+
+// DecommissionReport được ghi vào <report_dir>/<name>.json
+type DecommissionReport struct {
+	Service    string                   `json:"service"`
+	Repo       string                   `json:"repo"`
+	Actor      string                   `json:"actor"`
+	DryRun     bool                     `json:"dry_run"`
+	StartedAt  string                   `json:"started_at"`
+	FinishedAt string                   `json:"finished_at"`
+	Result     string                   `json:"result"` // success | failed | planned
+	Steps      []DecommissionStepResult `json:"steps"`
+	// Completed: các step đã xong qua mọi lần chạy (--steps / decommission.steps có thể chỉ chạy một phần)
+	Completed []string `json:"completed"`
+}
+
+type DecommissionStepResult struct {
+	Name    string   `json:"name"`
+	Status  string   `json:"status"` // done | planned | failed | skipped
+	Details []string `json:"details,omitempty"`
+	Error   string   `json:"error,omitempty"`
+}
+
+// runDecommissionCommand: `decommission [--dry-run] [--steps a,b] --confirm <app-name> <app-name>`
+func runDecommissionCommand(args []string) error {
+	fs := flag.NewFlagSet("decommission", flag.ExitOnError)
+	confirm := fs.String("confirm", "", "repeat the app name to confirm")
+	dryRun := fs.Bool("dry-run", false, "list what would be torn down without changing anything")
+	steps := fs.String("steps", "", "comma separated steps to run (overrides decommission.steps)")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts decommission [--dry-run] [--steps a,b] [--confirm <app-name>] <app-name>"))
+	}
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	if *steps != "" {
+		registryConfig.Decommission.Steps = strings.Split(*steps, ",")
+	}
+	return decommissionService(fs.Arg(0), parseConfirm(*confirm), *dryRun, registryConfig)
+}
+
+func decommissionService(name string, confirmed []string, dryRun bool, registryConfig RegistryConfig) error {
+	if !dryRun {
+		if err := checkConfirmation(registryConfig.Destructive, actionDecommission, name, confirmed); err != nil {
+			return err
+		}
+		if err := checkDestructiveApproval(actionDecommission, name, registryConfig); err != nil {
+			return err
+		}
+	}
+	entry, err := serviceStateEntry(name)
+	if err != nil {
+		return err
+	}
+	if entry.LastStatus == "decommissioned" {
+		fmt.Printf("⏭️  %s is already decommissioned\n", name)
+		return nil
+	}
+
+	var selected []decommissionStep
+	for _, stepName := range registryConfig.Decommission.steps() {
+		step, ok := findDecommissionStep(strings.TrimSpace(stepName))
+		if !ok {
+			return withCode(ErrUsage, fmt.Errorf("unknown decommission step %q", stepName))
+		}
+		selected = append(selected, step)
+	}
+	if !dryRun {
+		if err := verifyTokenScopes(commandExecutor, []TokenScope{{"repo", "remove collaborators, deploy keys and secrets"}, {"admin:repo_hook", "delete repository webhooks"}}); err != nil {
+			return err
+		}
+	}
+
+	target := decommissionTarget{Name: name, Repo: resolveRepo(entry), DryRun: dryRun, RegistryConfig: registryConfig}
+	report := DecommissionReport{Service: name, Repo: target.Repo, Actor: currentActor(), DryRun: dryRun,
+		StartedAt: time.Now().UTC().Format(time.RFC3339), Result: "success"}
+	if dryRun {
+		report.Result = "planned"
+	} else if previous, ok := loadDecommissionReport(name, registryConfig.Decommission); ok {
+		report.Completed = previous.completedSteps()
+	}
+	fmt.Printf("🧹 Decommissioning %s (%s)...\n", name, target.Repo)
+
+	// Repo archived là read-only: bỏ archive tạm thời, step archive sẽ archive lại
+	if entry.ArchivedAt != "" && !dryRun {
+		fmt.Printf("  ♻️  %s is archived, unarchiving it for the teardown\n", target.Repo)
+		if err := runCommand(commandExecutor, "gh", "api", "-X", "PATCH", "repos/"+target.Repo, "-F", "archived=false"); err != nil {
+			return withCode(ErrGitHubAPI, fmt.Errorf("failed to unarchive %s: %w", target.Repo, err))
+		}
+		if err := updateServiceState(name, func(s *ServiceState) { s.ArchivedAt = "" }); err != nil {
+			return err
+		}
+	}
+
+	var failure error
+	for _, step := range selected {
+		result := DecommissionStepResult{Name: step.Name, Status: "done"}
+		if failure != nil {
+			result.Status = "skipped"
+			report.Steps = append(report.Steps, result)
+			continue
+		}
+		fmt.Printf("  → %s\n", step.Name)
+		details, err := step.Run(target)
+		result.Details = details
+		if dryRun {
+			result.Status = "planned"
+		}
+		if err != nil {
+			result.Status = "failed"
+			result.Error = redact(err.Error())
+			report.Result = "failed"
+			failure = fmt.Errorf("decommission step %s failed: %w", step.Name, err)
+		}
+		for _, d := range details {
+			fmt.Printf("    - %s\n", d)
+		}
+		if result.Status == "done" && !containsString(report.Completed, step.Name) {
+			report.Completed = append(report.Completed, step.Name)
+		}
+		report.Steps = append(report.Steps, result)
+	}
+	report.FinishedAt = time.Now().UTC().Format(time.RFC3339)
+
+	path, err := writeDecommissionReport(report, registryConfig.Decommission)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to write decommission report: %v\n", err)
+	} else {
+		fmt.Printf("📝 Report written to %s\n", path)
+	}
+	if failure != nil || dryRun {
+		return failure
+	}
+
+	// Chỉ decommissioned khi mọi step đã xong, nếu không lần chạy sau sẽ dừng ở "already decommissioned"
+	var remaining []string
+	for _, step := range decommissionSteps {
+		if !containsString(report.Completed, step.Name) {
+			remaining = append(remaining, step.Name)
+		}
+	}
+	if len(remaining) > 0 {
+		if err := updateServiceState(name, func(s *ServiceState) {
+			s.LastStatus = "decommissioning"
+		}); err != nil {
+			return err
+		}
+		fmt.Printf("⏸️  %s partially decommissioned, remaining steps: %s\n", name, strings.Join(remaining, ", "))
+		return nil
+	}
+
+	if err := updateServiceState(name, func(s *ServiceState) {
+		s.LastStatus = "decommissioned"
+	}); err != nil {
+		return err
+	}
+	events.Publish(Event{Type: EventServiceDecommissioned, Service: name, Repo: target.Repo})
+	fmt.Printf("✅ %s decommissioned\n", name)
+	return nil
+}
+
+func findDecommissionStep(name string) (decommissionStep, bool) {
+	for _, s := range decommissionSteps {
+		if s.Name == name {
+			return s, true
+		}
+	}
+	return decommissionStep{}, false
+}
+
+// loadDecommissionReport đọc report của lần chạy thật trước đó (không tính dry run)
+func loadDecommissionReport(service string, d Decommission) (DecommissionReport, bool) {
+	var report DecommissionReport
+	data, err := os.ReadFile(filepath.Join(d.reportDir(), service+".json"))
+	if err != nil || json.Unmarshal(data, &report) != nil {
+		return DecommissionReport{}, false
+	}
+	return report, true
+}
+
+// completedSteps: Completed của report, cộng các step done (report cũ chưa có Completed)
+func (r DecommissionReport) completedSteps() []string {
+	completed := append([]string{}, r.Completed...)
+	for _, step := range r.Steps {
+		if step.Status == "done" && !containsString(completed, step.Name) {
+			completed = append(completed, step.Name)
+		}
+	}
+	return completed
+}
+
+func writeDecommissionReport(report DecommissionReport, d Decommission) (string, error) {
+	if err := os.MkdirAll(d.reportDir(), 0755); err != nil {
+		return "", err
+	}
+	name := report.Service + ".json"
+	if report.DryRun {
+		name = report.Service + ".dry-run.json"
+	}
+	path := filepath.Join(d.reportDir(), name)
+	data, err := json.MarshalIndent(report, "", "  ")
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(path, append(data, '\n'), 0644); err != nil {
+		return "", err
+	}
+	storeArtifact("reports", "decommission-"+name, path)
+	return path, nil
+}
+
+// listLines chạy gh api --paginate --jq, mỗi dòng output là một item
+func listLines(args ...string) ([]string, error) {
+	out, err := runCommandOutput(commandExecutor, "gh", args...)
+	if err != nil {
+		return nil, err
+	}
+	var lines []string
+	for _, line := range strings.Split(out, "\n") {
+		if line = strings.TrimSpace(line); line != "" {
+			lines = append(lines, line)
+		}
+	}
+	return lines, nil
+}
+
+// revokeCollaborators gỡ collaborator trực tiếp và team khỏi repo
+func revokeCollaborators(t decommissionTarget) ([]string, error) {
+	var details []string
+	users, err := listLines("api", "--paginate", fmt.Sprintf("repos/%s/collaborators?affiliation=direct", t.Repo), "--jq", ".[].login")
+	if err != nil {
+		return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to list collaborators: %w", err))
+	}
+	for _, user := range users {
+		if t.DryRun {
+			details = append(details, "remove collaborator "+user)
+			continue
+		}
+		if err := runCommand(commandExecutor, "gh", "api", "-X", "DELETE", fmt.Sprintf("repos/%s/collaborators/%s", t.Repo, user)); err != nil {
+			return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to remove collaborator %s: %w", user, err))
+		}
+		details = append(details, "removed collaborator "+user)
+	}
+
+	owner, _, _ := strings.Cut(t.Repo, "/")
+	teams, err := listLines("api", "--paginate", fmt.Sprintf("repos/%s/teams", t.Repo), "--jq", ".[].slug")
+	if err != nil {
+		return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to list teams: %w", err))
+	}
+	for _, team := range teams {
+		if t.DryRun {
+			details = append(details, "remove team "+team)
+			continue
+		}
+		if err := runCommand(commandExecutor, "gh", "api", "-X", "DELETE", fmt.Sprintf("orgs/%s/teams/%s/repos/%s", owner, team, t.Repo)); err != nil {
+			return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to remove team %s: %w", team, err))
+		}
+		details = append(details, "removed team "+team)
+	}
+	return details, nil
+}
+
+// deleteRepoItems xoá mọi item của repos/<repo>/<kind> (hooks, keys); jq in "<id> <mô tả>"
+func deleteRepoItems(t decommissionTarget, kind, label, jq string) ([]string, error) {
+	var details []string
+	items, err := listLines("api", "--paginate", fmt.Sprintf("repos/%s/%s", t.Repo, kind), "--jq", jq)
+	if err != nil {
+		return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to list %ss: %w", label, err))
+	}
+	for _, item := range items {
+		id, description, _ := strings.Cut(item, " ")
+		if t.DryRun {
+			details = append(details, fmt.Sprintf("delete %s %s", label, description))
+			continue
+		}
+		if err := runCommand(commandExecutor, "gh", "api", "-X", "DELETE", fmt.Sprintf("repos/%s/%s/%s", t.Repo, kind, id)); err != nil {
+			return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to delete %s %s: %w", label, description, err))
+		}
+		details = append(details, fmt.Sprintf("deleted %s %s", label, description))
+	}
+	return details, nil
+}
+
+func deleteRepoSecrets(t decommissionTarget) ([]string, error) {
+	var details []string
+	secrets, err := listLines("secret", "list", "--repo", t.Repo, "--json", "name", "--jq", ".[].name")
+	if err != nil {
+		return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to list secrets: %w", err))
+	}
+	for _, secret := range secrets {
+		if t.DryRun {
+			details = append(details, "delete secret "+secret)
+			continue
+		}
+		if err := runCommand(commandExecutor, "gh", "secret", "delete", secret, "--repo", t.Repo); err != nil {
+			return details, withCode(ErrGitHubAPI, fmt.Errorf("failed to delete secret %s: %w", secret, err))
+		}
+		details = append(details, "deleted secret "+secret)
+	}
+	return details, nil
+}
+
+// removeServiceSource xoá sources-service/<name> và dựng lại catalog (thay đổi cần được commit vào jupiter-registry)
+func removeServiceSource(t decommissionTarget) ([]string, error) {
+	dir := filepath.Join(sourcesDir, t.Name)
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return []string{dir + " already removed"}, nil
+	}
+	if t.DryRun {
+		return []string{"remove " + dir, "regenerate catalog"}, nil
+	}
+	if err := os.RemoveAll(dir); err != nil {
+		return nil, err
+	}
+	if _, err := writeCatalog("."); err != nil {
+		return []string{"removed " + dir}, err
+	}
+	return []string{"removed " + dir + " (commit the change to jupiter-registry)", "regenerated catalog"}, nil
+}
@@ -0,0 +1,37 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestDecommissionPartialRunKeepsServiceResumable(t *testing.T) {
+	inTempDir(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	useFakeExecutor(t)
+	state := &RegistryState{Services: map[string]*ServiceState{"orders": {Repo: "acme/orders", LastStatus: "success"}}}
+	if err := state.save(registryStateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	partial := RegistryConfig{Decommission: Decommission{Steps: []string{"revoke_collaborators", "delete_webhooks"}}}
+	if err := decommissionService("orders", []string{"orders"}, false, partial); err != nil {
+		t.Fatalf("partial decommission: %v", err)
+	}
+	if entry, _ := serviceStateEntry("orders"); entry.LastStatus == "decommissioned" {
+		t.Fatalf("partial run marked the service decommissioned")
+	}
+	report, ok := loadDecommissionReport("orders", partial.Decommission)
+	if !ok || len(report.Completed) != 2 {
+		t.Fatalf("report completed = %v, want the two steps that ran", report.Completed)
+	}
+
+	// Lần sau chạy phần còn lại: các step của lần trước được tính là đã xong
+	rest := RegistryConfig{Decommission: Decommission{Steps: []string{"delete_deploy_keys", "delete_secrets", "archive", "remove_source"}}}
+	if err := decommissionService("orders", []string{"orders"}, false, rest); err != nil {
+		t.Fatalf("remaining decommission: %v", err)
+	}
+	if entry, _ := serviceStateEntry("orders"); entry.LastStatus != "decommissioned" {
+		t.Fatalf("last status = %q, want decommissioned", entry.LastStatus)
+	}
+}
@@ -0,0 +1,117 @@
+package main
+
+import (
+	"fmt"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Defaults là convention cho các field optional của source.yml (jupiter.yml defaults:)
+type Defaults struct {
+	Visibility string `yaml:"visibility"` // mặc định private
+	Branch     string `yaml:"branch"`     // mặc định main
+	// Frameworks: framework mặc định theo language cho kind service
+	Frameworks map[string]string `yaml:"frameworks"`
+	// ModulePrefix: prefix theo language, module = <prefix>/<name> ({owner} được thay bằng owner)
+	ModulePrefix map[string]string `yaml:"module_prefix"`
+	// DocsLanguage: ngôn ngữ docs mặc định (en), TeamDocsLanguages override theo metadata.team
+	DocsLanguage      string            `yaml:"docs_language"`
+	TeamDocsLanguages map[string]string `yaml:"team_docs_languages"`
+}
+
+// builtinFrameworks khớp với framework generateGolangApp / generateNodeApp dùng khi để trống
+var builtinFrameworks = map[string]string{"golang": "uranus", "nodejs": "nestjs"}
+
+func (d Defaults) framework(language string) string {
+	if f, ok := d.Frameworks[language]; ok {
+		return f
+	}
+	return builtinFrameworks[language]
+}
+
+func (d Defaults) docsLanguage(team string) string {
+	if lang, ok := d.TeamDocsLanguages[team]; ok && team != "" {
+		return lang
+	}
+	if d.DocsLanguage != "" {
+		return d.DocsLanguage
+	}
+	return "en"
+}
+
+// modulePath: golang mặc định github.com/<owner>/<name>, nodejs mặc định <name>
+func (d Defaults) modulePath(language, owner, name string) string {
+	prefix, ok := d.ModulePrefix[language]
+	if !ok && language == "golang" {
+		prefix = "github.com/{owner}"
+	}
+	if prefix == "" {
+		return name
+	}
+	return strings.TrimSuffix(strings.ReplaceAll(prefix, "{owner}", owner), "/") + "/" + name
+}
+
+// resolveSourceConfig áp defaults sau khi parse, trả về config hiệu lực dùng cho validate + generate
+func resolveSourceConfig(config SourceConfig, registryConfig RegistryConfig) SourceConfig {
+	d := registryConfig.Defaults
+
+	config.Owner = registryConfig.ownerFor(config)
+	if config.Metadata.Kind == "" {
+		config.Metadata.Kind = "service"
+	}
+	if config.Visibility == "" {
+		config.Visibility = d.Visibility
+		if config.Visibility == "" {
+			config.Visibility = "private"
+		}
+	}
+	if config.Branch == "" {
+		config.Branch = d.Branch
+		if config.Branch == "" {
+			config.Branch = "main"
+		}
+	}
+	if config.DocsLanguage == "" {
+		config.DocsLanguage = d.docsLanguage(config.Metadata.Team)
+	}
+	// Framework mặc định chỉ áp cho service; frontend bắt buộc khai báo, kind template không có framework
+	if config.Metadata.Kind == "service" && config.Metadata.Framework == "" {
+		config.Metadata.Framework = d.framework(config.Metadata.ProgrammingLanguage)
+	}
+	if config.Metadata.Module == "" && config.Name != "" && containsString(supportedLanguages, config.Metadata.ProgrammingLanguage) {
+		config.Metadata.Module = d.modulePath(config.Metadata.ProgrammingLanguage, config.Owner, config.Name)
+	}
+	return config
+}
+
+// branch: default branch của repo (DTO không đi qua resolveSourceConfig thì dùng main)
+func (dto GeneratorSourceDto) branch() string {
+	if dto.Branch == "" {
+		return "main"
+	}
+	return dto.Branch
+}
+
+// runConfigCommand: `config resolve <service>` in source.yml sau khi áp defaults
+func runConfigCommand(args []string) error {
+	if len(args) < 2 || args[0] != "resolve" {
+		return withCode(ErrUsage, fmt.Errorf("usage: config resolve <service-path>"))
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	config, err := loadSourceConfig(args[1])
+	if err != nil {
+		return err
+	}
+
+	data, err := yaml.Marshal(resolveSourceConfig(config, registryConfig))
+	if err != nil {
+		return err
+	}
+	fmt.Print(string(data))
+	return nil
+}
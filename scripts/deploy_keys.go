@@ -0,0 +1,119 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// DeployKeys configures per-service SSH deploy key provisioning
+type DeployKeys struct {
+	Enabled     bool        `yaml:"enabled"`
+	ReadOnly    bool        `yaml:"read_only"`
+	SecretStore SecretStore `yaml:"secret_store"`
+}
+
+// SecretStore là nơi nhận private key sau khi generate.
+//   - org_secret: lưu thành GitHub organization secret
+//   - command:    pipe private key vào stdin của một lệnh shell (vault, aws secretsmanager, ...)
+type SecretStore struct {
+	Type       string `yaml:"type"`
+	Org        string `yaml:"org"`
+	Visibility string `yaml:"visibility"`
+	Command    string `yaml:"command"`
+}
+
+// deployKeyTitle là title của deploy key do registry quản lý trên mỗi repo
+const deployKeyTitle = "jupiter-registry"
+
+// provisionDeployKey chỉ tạo key khi repo chưa có deploy key của registry. Key sai read_only (đổi policy)
+// hoặc bản trùng từ các lần chạy trước bị xoá rồi thay bằng một key mới, regenerate không cộng dồn key.
+func provisionDeployKey(ex Executor, owner, repoName string, cfg DeployKeys) error {
+	keysPath := fmt.Sprintf("repos/%s/%s/keys", owner, repoName)
+	out, err := runCommandOutput(ex, "gh", "api", "--paginate", keysPath, "--jq", fmt.Sprintf(".[] | select(.title == %q) | \"\\(.id)\\t\\(.read_only)\"", deployKeyTitle))
+	if err != nil {
+		return fmt.Errorf("failed to list deploy keys: %w", err)
+	}
+	var existing []string
+	current := false
+	for _, line := range strings.Split(out, "\n") {
+		if id, readOnly, ok := strings.Cut(line, "\t"); ok {
+			existing = append(existing, id)
+			current = readOnly == fmt.Sprint(cfg.ReadOnly)
+		}
+	}
+	if len(existing) == 1 && current {
+		fmt.Printf("  ⏭️  Deploy key %s already exists\n", deployKeyTitle)
+		return nil
+	}
+	for _, id := range existing {
+		if err := runCommand(ex, "gh", "api", "-X", "DELETE", keysPath+"/"+id); err != nil {
+			return fmt.Errorf("failed to remove deploy key %s: %w", id, err)
+		}
+	}
+
+	tmpDir, err := os.MkdirTemp("", "jupiter-deploy-key-")
+	if err != nil {
+		return fmt.Errorf("failed to create temp dir: %w", err)
+	}
+	defer os.RemoveAll(tmpDir)
+
+	// Step 1: Generate key pair ed25519 (không passphrase)
+	keyPath := filepath.Join(tmpDir, "id_ed25519")
+	if err := runCommand(ex, "ssh-keygen", "-q", "-t", "ed25519", "-N", "", "-C", "jupiter-"+repoName, "-f", keyPath); err != nil {
+		return fmt.Errorf("failed to generate key pair: %w", err)
+	}
+
+	publicKey, err := os.ReadFile(keyPath + ".pub")
+	if err != nil {
+		return fmt.Errorf("failed to read public key: %w", err)
+	}
+	privateKey, err := os.ReadFile(keyPath)
+	if err != nil {
+		return fmt.Errorf("failed to read private key: %w", err)
+	}
+
+	// Step 2: Add public key vào repo
+	if err := runCommand(ex, "gh", "api", "-X", "POST", fmt.Sprintf("repos/%s/%s/keys", owner, repoName),
+		"-f", "title="+deployKeyTitle,
+		"-f", "key="+strings.TrimSpace(string(publicKey)),
+		"-F", fmt.Sprintf("read_only=%t", cfg.ReadOnly)); err != nil {
+		return fmt.Errorf("failed to add deploy key: %w", err)
+	}
+
+	// Step 3: Giao private key cho secret store
+	if err := storePrivateKey(ex, repoName, privateKey, cfg.SecretStore); err != nil {
+		return fmt.Errorf("failed to store private key: %w", err)
+	}
+	return nil
+}
+
+func storePrivateKey(ex Executor, repoName string, privateKey []byte, store SecretStore) error {
+	switch store.Type {
+	case "org_secret":
+		visibility := store.Visibility
+		if visibility == "" {
+			visibility = "private"
+		}
+		return runCommandWithInput(ex, privateKey, "gh", "secret", "set", deployKeySecretName(repoName),
+			"--org", store.Org, "--visibility", visibility)
+	case "command":
+		fmt.Printf("  → Running secret store command for %s\n", repoName)
+		_, err := ex.Run(Command{
+			Name:  "sh",
+			Args:  []string{"-c", store.Command},
+			Env:   []string{"JUPITER_SERVICE=" + repoName, "JUPITER_SECRET_NAME=" + deployKeySecretName(repoName)},
+			Stdin: privateKey,
+		})
+		return err
+	default:
+		return fmt.Errorf("unsupported secret store type: %s", store.Type)
+	}
+}
+
+// deployKeySecretName: sample-api -> DEPLOY_KEY_SAMPLE_API
+func deployKeySecretName(repoName string) string {
+	name := strings.ToUpper(strings.NewReplacer("-", "_", ".", "_").Replace(repoName))
+	return "DEPLOY_KEY_" + name
+}
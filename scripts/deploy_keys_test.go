@@ -0,0 +1,36 @@
+package main
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestProvisionDeployKeySkipsExistingKey(t *testing.T) {
+	fake := useFakeExecutor(t)
+	fake.On("gh api --paginate repos/acme/orders/keys", FakeResponse{Output: "7\ttrue"})
+
+	if err := provisionDeployKey(commandExecutor, "acme", "orders", DeployKeys{Enabled: true, ReadOnly: true}); err != nil {
+		t.Fatal(err)
+	}
+	for _, line := range fake.Invocations() {
+		if strings.HasPrefix(line, "ssh-keygen") || strings.Contains(line, "-X POST") || strings.Contains(line, "-X DELETE") {
+			t.Fatalf("existing deploy key must be kept, ran %q", line)
+		}
+	}
+}
+
+func TestProvisionDeployKeyReplacesStaleKeys(t *testing.T) {
+	fake := useFakeExecutor(t)
+	// read_only đổi policy + một key trùng từ lần chạy cũ
+	fake.On("gh api --paginate repos/acme/orders/keys", FakeResponse{Output: "7\tfalse\n8\tfalse"})
+
+	// Fake không chạy ssh-keygen thật nên dừng ở bước đọc key, đủ để kiểm tra key cũ đã bị xoá trước
+	provisionDeployKey(commandExecutor, "acme", "orders", DeployKeys{Enabled: true, ReadOnly: true})
+	got := fake.Invocations()
+	keygen := indexOf(got, "ssh-keygen")
+	first := indexOf(got, "gh api -X DELETE repos/acme/orders/keys/7")
+	second := indexOf(got, "gh api -X DELETE repos/acme/orders/keys/8")
+	if first < 0 || second < 0 || keygen < first || keygen < second {
+		t.Fatalf("stale keys must be deleted before generating a new one:\n%s", strings.Join(got, "\n"))
+	}
+}
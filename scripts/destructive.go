@@ -0,0 +1,313 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+)
+
+// Destructive bảo vệ các thao tác phá huỷ (archive / delete / force push) khỏi batch command gõ nhầm:
+//   - CLI phải truyền `--confirm <app-name>` trùng tên service
+//   - server mode nhận confirmation token ký HMAC (tạo bằng `confirm-token`)
+//   - archive giữ được trong recycle window, `restore` trong window, `delete` chỉ sau window
+type Destructive struct {
+	// Confirm: action cần xác nhận, mặc định archive, delete, decommission, force_push
+	Confirm        []string `yaml:"confirm"`
+	RecycleDays    int      `yaml:"recycle_days"`     // mặc định 30
+	TokenSecretEnv string   `yaml:"token_secret_env"` // mặc định JUPITER_CONFIRM_SECRET
+}
+
+func (d Destructive) requiresConfirmation(action string) bool {
+	if len(d.Confirm) == 0 {
+		return action == actionArchive || action == actionDelete || action == actionDecommission || action == actionForcePush
+	}
+	return containsString(d.Confirm, action)
+}
+
+func (d Destructive) recycleWindow() time.Duration {
+	if d.RecycleDays <= 0 {
+		return 30 * 24 * time.Hour
+	}
+	return time.Duration(d.RecycleDays) * 24 * time.Hour
+}
+
+func (d Destructive) tokenSecret() string {
+	env := d.TokenSecretEnv
+	if env == "" {
+		env = "JUPITER_CONFIRM_SECRET"
+	}
+	return os.Getenv(env)
+}
+
+// checkConfirmation: confirmed là các tên service người chạy đã gõ lại bằng --confirm
+func checkConfirmation(d Destructive, action, name string, confirmed []string) error {
+	if !d.requiresConfirmation(action) || containsString(confirmed, name) {
+		return nil
+	}
+	return withCode(ErrConfirmationRequired, fmt.Errorf("%s on %s is destructive, re-run with --confirm %s", action, name, name))
+}
+
+// parseConfirm tách giá trị --confirm (batch cho phép nhiều tên, phân cách bằng dấu phẩy)
+func parseConfirm(value string) []string {
+	var names []string
+	for _, name := range strings.Split(value, ",") {
+		if name = strings.TrimSpace(name); name != "" {
+			names = append(names, name)
+		}
+	}
+	return names
+}
+
+// confirmationClaims là payload của confirmation token: chỉ dùng cho đúng service + action, có hạn
+type confirmationClaims struct {
+	Service   string `json:"service"`
+	Action    string `json:"action"`
+	ExpiresAt int64  `json:"exp"`
+}
+
+// signConfirmation tạo token dạng <payload base64url>.<hmac-sha256 hex>
+func signConfirmation(secret string, claims confirmationClaims) (string, error) {
+	payload, err := json.Marshal(claims)
+	if err != nil {
+		return "", err
+	}
+	encoded := base64.RawURLEncoding.EncodeToString(payload)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	return encoded + "." + hex.EncodeToString(mac.Sum(nil)), nil
+}
+
+func verifyConfirmation(secret, token, service, action string) error {
+	if secret == "" {
+		return fmt.Errorf("confirmation token secret is not configured")
+	}
+	encoded, signature, ok := strings.Cut(token, ".")
+	if !ok {
+		return fmt.Errorf("malformed confirmation token")
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(encoded))
+	if !hmac.Equal([]byte(hex.EncodeToString(mac.Sum(nil))), []byte(signature)) {
+		return fmt.Errorf("invalid confirmation token signature")
+	}
+	payload, err := base64.RawURLEncoding.DecodeString(encoded)
+	if err != nil {
+		return fmt.Errorf("malformed confirmation token")
+	}
+	var claims confirmationClaims
+	if err := json.Unmarshal(payload, &claims); err != nil {
+		return fmt.Errorf("malformed confirmation token")
+	}
+	if claims.Service != service || claims.Action != action {
+		return fmt.Errorf("confirmation token is for %s on %s, not %s on %s", claims.Action, claims.Service, action, service)
+	}
+	if time.Now().Unix() > claims.ExpiresAt {
+		return fmt.Errorf("confirmation token expired")
+	}
+	return nil
+}
+
+// runConfirmTokenCommand: `confirm-token --action archive|delete|force_push [--ttl 10m] <app-name>`
+func runConfirmTokenCommand(args []string) error {
+	fs := flag.NewFlagSet("confirm-token", flag.ExitOnError)
+	action := fs.String("action", "", "archive | delete | force_push")
+	ttl := fs.Duration("ttl", 10*time.Minute, "token lifetime")
+	fs.Parse(args)
+	if fs.NArg() != 1 || *action == "" {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts confirm-token --action archive|delete|force_push [--ttl 10m] <app-name>"))
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	secret := registryConfig.Destructive.tokenSecret()
+	if secret == "" {
+		return withCode(ErrUsage, fmt.Errorf("confirmation token secret is not set"))
+	}
+	token, err := signConfirmation(secret, confirmationClaims{Service: fs.Arg(0), Action: *action, ExpiresAt: time.Now().Add(*ttl).Unix()})
+	if err != nil {
+		return err
+	}
+	fmt.Println(token)
+	return nil
+}
+
+// runArchiveCommand: `archive --confirm <app-name> <app-name>` archive repo, restore được trong recycle window
+func runArchiveCommand(args []string) error {
+	name, confirmed, registryConfig, err := parseDestructiveArgs("archive", args)
+	if err != nil {
+		return err
+	}
+	return archiveService(name, confirmed, registryConfig)
+}
+
+func archiveService(name string, confirmed []string, registryConfig RegistryConfig) error {
+	if err := checkConfirmation(registryConfig.Destructive, actionArchive, name, confirmed); err != nil {
+		return err
+	}
+	if err := checkDestructiveApproval(actionArchive, name, registryConfig); err != nil {
+		return err
+	}
+	return archiveRepository(name, registryConfig)
+}
+
+// archiveRepository archive repo và ghi state, không kiểm tra confirmation / approval (caller đã làm)
+func archiveRepository(name string, registryConfig RegistryConfig) error {
+	entry, err := serviceStateEntry(name)
+	if err != nil {
+		return err
+	}
+	if entry.ArchivedAt != "" {
+		fmt.Printf("⏭️  %s is already archived since %s\n", name, entry.ArchivedAt)
+		return nil
+	}
+
+	if err := verifyTokenScopes(commandExecutor, []TokenScope{{"repo", "archive the repository"}}); err != nil {
+		return err
+	}
+	repo := resolveRepo(entry)
+	fmt.Printf("🗄️  Archiving %s...\n", repo)
+	if err := runCommand(commandExecutor, "gh", "api", "-X", "PATCH", "repos/"+repo, "-F", "archived=true"); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to archive %s: %w", repo, err))
+	}
+	now := time.Now().UTC()
+	if err := updateServiceState(name, func(s *ServiceState) {
+		s.ArchivedAt = now.Format(time.RFC3339)
+		s.LastStatus = "archived"
+	}); err != nil {
+		return err
+	}
+	events.Publish(Event{Type: EventServiceArchived, Service: name, Repo: repo})
+	fmt.Printf("✅ %s archived, restorable until %s (go run ./scripts restore %s)\n",
+		name, now.Add(registryConfig.Destructive.recycleWindow()).Format(time.RFC3339), name)
+	return nil
+}
+
+// runRestoreCommand: `restore <app-name>` bỏ archive nếu còn trong recycle window
+func runRestoreCommand(args []string) error {
+	name, _, registryConfig, err := parseDestructiveArgs("restore", args)
+	if err != nil {
+		return err
+	}
+	return restoreService(name, registryConfig)
+}
+
+func restoreService(name string, registryConfig RegistryConfig) error {
+	entry, err := serviceStateEntry(name)
+	if err != nil {
+		return err
+	}
+	if entry.ArchivedAt == "" {
+		return withCode(ErrUsage, fmt.Errorf("%s is not archived", name))
+	}
+	archivedAt, err := time.Parse(time.RFC3339, entry.ArchivedAt)
+	if err != nil {
+		return fmt.Errorf("invalid archived_at for %s: %w", name, err)
+	}
+	if time.Since(archivedAt) > registryConfig.Destructive.recycleWindow() {
+		return withCode(ErrUsage, fmt.Errorf("recycle window for %s expired at %s",
+			name, archivedAt.Add(registryConfig.Destructive.recycleWindow()).Format(time.RFC3339)))
+	}
+
+	if err := verifyTokenScopes(commandExecutor, []TokenScope{{"repo", "unarchive the repository"}}); err != nil {
+		return err
+	}
+	repo := resolveRepo(entry)
+	fmt.Printf("♻️  Restoring %s...\n", repo)
+	if err := runCommand(commandExecutor, "gh", "api", "-X", "PATCH", "repos/"+repo, "-F", "archived=false"); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to unarchive %s: %w", repo, err))
+	}
+	if err := updateServiceState(name, func(s *ServiceState) {
+		s.ArchivedAt = ""
+		s.LastStatus = "restored"
+	}); err != nil {
+		return err
+	}
+	events.Publish(Event{Type: EventServiceRestored, Service: name, Repo: repo})
+	fmt.Printf("✅ %s restored\n", name)
+	return nil
+}
+
+// runDeleteCommand: `delete --confirm <app-name> <app-name>` xoá hẳn repo đã archive quá recycle window
+func runDeleteCommand(args []string) error {
+	name, confirmed, registryConfig, err := parseDestructiveArgs("delete", args)
+	if err != nil {
+		return err
+	}
+	return deleteService(name, confirmed, registryConfig)
+}
+
+func deleteService(name string, confirmed []string, registryConfig RegistryConfig) error {
+	if err := checkConfirmation(registryConfig.Destructive, actionDelete, name, confirmed); err != nil {
+		return err
+	}
+	if err := checkDestructiveApproval(actionDelete, name, registryConfig); err != nil {
+		return err
+	}
+	entry, err := serviceStateEntry(name)
+	if err != nil {
+		return err
+	}
+	// Chỉ xoá repo đã archive và hết recycle window: gõ nhầm thì vẫn còn thời gian restore
+	if entry.ArchivedAt == "" {
+		return withCode(ErrUsage, fmt.Errorf("%s must be archived before it can be deleted (go run ./scripts archive --confirm %s %s)", name, name, name))
+	}
+	archivedAt, err := time.Parse(time.RFC3339, entry.ArchivedAt)
+	if err != nil {
+		return fmt.Errorf("invalid archived_at for %s: %w", name, err)
+	}
+	if until := archivedAt.Add(registryConfig.Destructive.recycleWindow()); time.Now().Before(until) {
+		return withCode(ErrUsage, fmt.Errorf("%s is in its recycle window until %s, delete is not allowed yet", name, until.Format(time.RFC3339)))
+	}
+
+	if err := verifyTokenScopes(commandExecutor, []TokenScope{{"delete_repo", "delete the repository"}}); err != nil {
+		return err
+	}
+	repo := resolveRepo(entry)
+	fmt.Printf("🗑️  Deleting %s...\n", repo)
+	if err := runCommand(commandExecutor, "gh", "api", "-X", "DELETE", "repos/"+repo); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to delete %s: %w", repo, err))
+	}
+	if err := updateServiceState(name, func(s *ServiceState) {
+		s.LastStatus = "deleted"
+	}); err != nil {
+		return err
+	}
+	events.Publish(Event{Type: EventServiceDeleted, Service: name, Repo: repo})
+	fmt.Printf("✅ %s deleted\n", name)
+	return nil
+}
+
+func parseDestructiveArgs(command string, args []string) (string, []string, RegistryConfig, error) {
+	fs := flag.NewFlagSet(command, flag.ExitOnError)
+	confirm := fs.String("confirm", "", "repeat the app name to confirm")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return "", nil, RegistryConfig{}, withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts %s [--confirm <app-name>] <app-name>", command))
+	}
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return "", nil, RegistryConfig{}, err
+	}
+	return fs.Arg(0), parseConfirm(*confirm), registryConfig, nil
+}
+
+func serviceStateEntry(name string) (ServiceState, error) {
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return ServiceState{}, err
+	}
+	entry, ok := state.Services[name]
+	if !ok || entry.Repo == "" {
+		return ServiceState{}, withCode(ErrSourceNotFound, fmt.Errorf("service %s is not managed by the registry", name))
+	}
+	return *entry, nil
+}
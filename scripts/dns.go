@@ -0,0 +1,141 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+)
+
+// DNS reserve hostname <name>.<domain> cho service / frontend mới.
+//   - route53:    UPSERT record trong hosted zone (aws cli)
+//   - cloudflare: tạo record qua Cloudflare API nếu chưa có
+//   - command:    chạy command tuỳ chỉnh với JUPITER_HOSTNAME / JUPITER_TARGET
+type DNS struct {
+	Backend    string `yaml:"backend"` // "" = tắt
+	Domain     string `yaml:"domain"`  // vd. internal.example.com
+	ZoneID     string `yaml:"zone_id"`
+	RecordType string `yaml:"record_type"` // mặc định CNAME
+	Target     string `yaml:"target"`      // ingress / load balancer mà hostname trỏ tới
+	TTL        int    `yaml:"ttl"`         // mặc định 300
+	TokenEnv   string `yaml:"token_env"`   // cloudflare API token
+	Command    string `yaml:"command"`
+	Required   bool   `yaml:"required"` // true: reserve lỗi thì provisioning fail
+}
+
+// hostname trả về hostname của service, rỗng khi DNS tắt hoặc kind không nhận traffic
+func (d DNS) hostname(name, kind string) string {
+	if d.Backend == "" || d.Domain == "" {
+		return ""
+	}
+	if kind != "" && kind != "service" && kind != "frontend" {
+		return ""
+	}
+	return name + "." + strings.TrimPrefix(d.Domain, ".")
+}
+
+func (d DNS) recordType() string {
+	if d.RecordType == "" {
+		return "CNAME"
+	}
+	return d.RecordType
+}
+
+func (d DNS) ttl() int {
+	if d.TTL <= 0 {
+		return 300
+	}
+	return d.TTL
+}
+
+func reserveHostname(ex Executor, dto GeneratorSourceDto, d DNS) error {
+	switch d.Backend {
+	case "route53":
+		return reserveRoute53(ex, dto.Hostname, d)
+	case "cloudflare":
+		return reserveCloudflare(dto.Hostname, d)
+	case "command":
+		_, err := ex.Run(Command{
+			Name: "sh",
+			Args: []string{"-c", d.Command},
+			Env:  []string{"JUPITER_SERVICE=" + dto.AppName, "JUPITER_OWNER=" + dto.Owner, "JUPITER_HOSTNAME=" + dto.Hostname, "JUPITER_TARGET=" + d.Target},
+		})
+		return err
+	default:
+		return fmt.Errorf("unsupported dns backend: %s", d.Backend)
+	}
+}
+
+func reserveRoute53(ex Executor, hostname string, d DNS) error {
+	batch, err := json.Marshal(map[string]interface{}{
+		"Comment": "reserved by jupiter-registry",
+		"Changes": []map[string]interface{}{{
+			"Action": "UPSERT",
+			"ResourceRecordSet": map[string]interface{}{
+				"Name":            hostname,
+				"Type":            d.recordType(),
+				"TTL":             d.ttl(),
+				"ResourceRecords": []map[string]string{{"Value": d.Target}},
+			},
+		}},
+	})
+	if err != nil {
+		return err
+	}
+	return runCommand(ex, "aws", "route53", "change-resource-record-sets",
+		"--hosted-zone-id", d.ZoneID, "--change-batch", string(batch))
+}
+
+// reserveCloudflare: record đã tồn tại thì giữ nguyên (hostname đã được reserve trước đó)
+func reserveCloudflare(hostname string, d DNS) error {
+	token := os.Getenv(d.TokenEnv)
+	if token == "" {
+		return fmt.Errorf("cloudflare token $%s is not set", d.TokenEnv)
+	}
+	base := fmt.Sprintf("https://api.cloudflare.com/client/v4/zones/%s/dns_records", d.ZoneID)
+
+	req, err := http.NewRequest(http.MethodGet, base+"?name="+url.QueryEscape(hostname), nil)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	var existing struct {
+		Result []struct {
+			Content string `json:"content"`
+		} `json:"result"`
+	}
+	if err := doJSONRequest(req, &existing); err != nil {
+		return fmt.Errorf("failed to look up %s: %w", hostname, err)
+	}
+	if len(existing.Result) > 0 {
+		fmt.Printf("  ℹ️ %s already exists (-> %s)\n", hostname, existing.Result[0].Content)
+		return nil
+	}
+
+	payload, err := json.Marshal(map[string]interface{}{
+		"type":    d.recordType(),
+		"name":    hostname,
+		"content": d.Target,
+		"ttl":     d.ttl(),
+		"comment": "reserved by jupiter-registry",
+	})
+	if err != nil {
+		return err
+	}
+	req, err = http.NewRequest(http.MethodPost, base, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Authorization", "Bearer "+token)
+	req.Header.Set("Content-Type", "application/json")
+	var created struct {
+		Success bool `json:"success"`
+	}
+	if err := doJSONRequest(req, &created); err != nil {
+		return fmt.Errorf("failed to create %s: %w", hostname, err)
+	}
+	return nil
+}
@@ -0,0 +1,157 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"html/template"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// HistoryEntry là một commit đã thay đổi source.yml của service
+type HistoryEntry struct {
+	Commit  string
+	Author  string
+	Date    string
+	Subject string
+}
+
+type serviceDocPage struct {
+	Entry   CatalogEntry
+	History []HistoryEntry
+}
+
+// docsBaseTemplate chứa phần CSS dùng chung cho mọi trang
+var docsBaseTemplate = template.Must(template.New("style").Parse(`
+body { font-family: -apple-system, sans-serif; max-width: 960px; margin: 2rem auto; color: #24292f; }
+table { border-collapse: collapse; width: 100%; }
+th, td { text-align: left; padding: .4rem .6rem; border-bottom: 1px solid #d0d7de; }
+code { font-size: .85em; }
+`))
+
+var docsIndexTemplate = template.Must(template.Must(docsBaseTemplate.Clone()).New("index").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>Service Catalog</title>
+<style>{{ template "style" }}</style>
+</head>
+<body>
+<h1>🪐 Service Catalog</h1>
+<p>{{ len . }} service(s) registered in jupiter-registry.</p>
+<table>
+<tr><th>Service</th><th>Language</th><th>Framework</th><th>Team</th><th>Owners</th></tr>
+{{- range . }}
+<tr>
+<td><a href="services/{{ .Folder }}.html">{{ .Name }}</a></td>
+<td>{{ .ProgrammingLanguage }}</td>
+<td>{{ .Framework }}</td>
+<td>{{ .Team }}</td>
+<td>{{ range $i, $m := .Members }}{{ if $i }}, {{ end }}<a href="https://github.com/{{ $m }}">@{{ $m }}</a>{{ end }}</td>
+</tr>
+{{- end }}
+</table>
+</body>
+</html>
+`))
+
+var docsServiceTemplate = template.Must(template.Must(docsBaseTemplate.Clone()).New("service").Parse(`<!DOCTYPE html>
+<html lang="en">
+<head>
+<meta charset="utf-8">
+<title>{{ .Entry.Name }} · Service Catalog</title>
+<style>{{ template "style" }}</style>
+</head>
+<body>
+<p><a href="../index.html">← Catalog</a></p>
+<h1>{{ .Entry.Name }}</h1>
+<table>
+<tr><th>Repository</th><td><a href="{{ .Entry.RepoURL }}">{{ .Entry.RepoURL }}</a></td></tr>
+<tr><th>Language</th><td>{{ .Entry.ProgrammingLanguage }}</td></tr>
+<tr><th>Framework</th><td>{{ .Entry.Framework }}</td></tr>
+<tr><th>Module</th><td>{{ .Entry.Module }}</td></tr>
+<tr><th>Team</th><td>{{ .Entry.Team }}</td></tr>
+<tr><th>Source ID</th><td>{{ .Entry.SourceID }}</td></tr>
+<tr><th>Owners</th><td>{{ range $i, $m := .Entry.Members }}{{ if $i }}, {{ end }}<a href="https://github.com/{{ $m }}">@{{ $m }}</a>{{ end }}</td></tr>
+</table>
+<h2>Generation history</h2>
+{{- if .History }}
+<table>
+<tr><th>Date</th><th>Commit</th><th>Author</th><th>Change</th></tr>
+{{- range .History }}
+<tr><td>{{ .Date }}</td><td><code>{{ .Commit }}</code></td><td>{{ .Author }}</td><td>{{ .Subject }}</td></tr>
+{{- end }}
+</table>
+{{- else }}
+<p>No history recorded.</p>
+{{- end }}
+</body>
+</html>
+`))
+
+// runDocsCommand render registry thành static site (GitHub Pages ready)
+func runDocsCommand(args []string) error {
+	fs := flag.NewFlagSet("docs", flag.ExitOnError)
+	outDir := fs.String("out", "site", "output directory for the static site")
+	fs.Parse(args)
+
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	catalog := buildCatalog(services)
+
+	if err := os.MkdirAll(filepath.Join(*outDir, "services"), 0755); err != nil {
+		return fmt.Errorf("failed to create %s: %w", *outDir, err)
+	}
+
+	if err := writeHTML(filepath.Join(*outDir, "index.html"), docsIndexTemplate, catalog); err != nil {
+		return err
+	}
+	for _, entry := range catalog {
+		page := serviceDocPage{Entry: entry, History: serviceHistory(entry.Folder)}
+		if err := writeHTML(filepath.Join(*outDir, "services", entry.Folder+".html"), docsServiceTemplate, page); err != nil {
+			return err
+		}
+	}
+
+	// GitHub Pages: tắt Jekyll để serve nguyên file HTML
+	if err := os.WriteFile(filepath.Join(*outDir, ".nojekyll"), nil, 0644); err != nil {
+		return err
+	}
+
+	fmt.Printf("📖 Docs site generated: %d service page(s) in %s\n", len(catalog), *outDir)
+	return nil
+}
+
+func writeHTML(path string, tmpl *template.Template, data interface{}) error {
+	f, err := os.Create(path)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", path, err)
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, data); err != nil {
+		return fmt.Errorf("failed to render %s: %w", path, err)
+	}
+	return nil
+}
+
+// serviceHistory lấy lịch sử thay đổi source.yml từ git log của registry
+func serviceHistory(folder string) []HistoryEntry {
+	out, err := runCommandOutput(commandExecutor, "git", "log", "--format=%h|%an|%as|%s", "--", filepath.Join(sourcesDir, folder))
+	if err != nil || out == "" {
+		return nil
+	}
+
+	var history []HistoryEntry
+	for _, line := range strings.Split(out, "\n") {
+		parts := strings.SplitN(line, "|", 4)
+		if len(parts) != 4 {
+			continue
+		}
+		history = append(history, HistoryEntry{Commit: parts[0], Author: parts[1], Date: parts[2], Subject: parts[3]})
+	}
+	return history
+}
@@ -0,0 +1,120 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// ModificationReport phân loại các file đã generate theo trạng thái hiện tại trên repo downstream.
+// upgrade chỉ overwrite Unchanged; Modified cần merge; Deleted là file team đã chủ động xoá.
+type ModificationReport struct {
+	Repo      string   `json:"repo"`
+	Unchanged []string `json:"unchanged"`
+	Modified  []string `json:"modified"`
+	Deleted   []string `json:"deleted"`
+}
+
+// detectModifications so hash trong manifest với git tree hiện tại của default branch
+func detectModifications(owner, repoName string) (ModificationReport, error) {
+	report := ModificationReport{Repo: fmt.Sprintf("%s/%s", owner, repoName)}
+
+	manifest, ok := readRemoteManifest(commandExecutor, owner, repoName)
+	if !ok {
+		return report, fmt.Errorf("%s has no readable %s", report.Repo, manifestPath)
+	}
+	if len(manifest.Files) == 0 {
+		return report, fmt.Errorf("%s manifest has no file hashes (generated before hashes were recorded)", report.Repo)
+	}
+
+	out, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/git/trees/HEAD?recursive=1", report.Repo),
+		"--jq", `.tree[] | select(.type == "blob") | "\(.path) \(.sha)"`)
+	if err != nil {
+		return report, withCode(ErrGitHubAPI, fmt.Errorf("failed to read tree of %s: %w", report.Repo, err))
+	}
+	current := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if path, sha, ok := strings.Cut(line, " "); ok {
+			current[path] = sha
+		}
+	}
+
+	for path, generated := range manifest.Files {
+		sha, exists := current[path]
+		switch {
+		case !exists:
+			report.Deleted = append(report.Deleted, path)
+		case sha != generated:
+			report.Modified = append(report.Modified, path)
+		default:
+			report.Unchanged = append(report.Unchanged, path)
+		}
+	}
+	sort.Strings(report.Unchanged)
+	sort.Strings(report.Modified)
+	sort.Strings(report.Deleted)
+	return report, nil
+}
+
+// runDriftCommand report các file generated đã bị sửa tay trên các repo được quản lý
+func runDriftCommand(args []string) error {
+	fs := flag.NewFlagSet("drift", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print reports as JSON")
+	failOnModified := fs.Bool("fail-on-modified", false, "exit non-zero when any generated file was modified")
+	fs.Parse(args)
+
+	// Mặc định: tất cả service trong state
+	names := fs.Args()
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	if len(names) == 0 {
+		for name := range state.Services {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+	}
+
+	var reports []ModificationReport
+	modified := 0
+	for _, name := range names {
+		owner, repoName := repoOwner, name
+		if entry, ok := state.Services[name]; ok && entry.Repo != "" {
+			owner, repoName, _ = strings.Cut(resolveRepo(*entry), "/")
+		}
+
+		report, err := detectModifications(owner, repoName)
+		if err != nil {
+			fmt.Printf("⚠️ %s: %v\n", name, err)
+			continue
+		}
+		reports = append(reports, report)
+		modified += len(report.Modified)
+	}
+
+	if *asJSON {
+		data, err := json.MarshalIndent(reports, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	} else {
+		for _, r := range reports {
+			fmt.Printf("\n📦 %s: %d unchanged, %d modified, %d deleted\n", r.Repo, len(r.Unchanged), len(r.Modified), len(r.Deleted))
+			for _, path := range r.Modified {
+				fmt.Printf("  ✏️  %s (needs merge)\n", path)
+			}
+			for _, path := range r.Deleted {
+				fmt.Printf("  🗑️  %s\n", path)
+			}
+		}
+	}
+
+	if *failOnModified && modified > 0 {
+		return fmt.Errorf("%d generated file(s) modified downstream", modified)
+	}
+	return nil
+}
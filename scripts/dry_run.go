@@ -0,0 +1,113 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"strings"
+)
+
+// runDryRun validate source.yml, in ra plan và (khi chạy trong PR) post check run cho service
+func runDryRun(servicePath string, registryConfig RegistryConfig) error {
+	config, err := loadSourceConfig(servicePath)
+	if err != nil {
+		return err
+	}
+	config = resolveSourceConfig(config, registryConfig)
+	dto := toGeneratorSourceDto(config, registryConfig)
+	printDTO(dto)
+
+	service := filepath.Base(servicePath)
+	// Validate bằng credential của tenant (org của tenant có thể không đọc được bằng token registry)
+	var validation ValidationResult
+	var summary string
+	if err := withTenantCredentials(registryConfig.tenantFor(dto.Team), func() error {
+		validation = validateService(commandExecutor, servicePath, config, registryConfig)
+		summary = buildPlanSummary(dto, registryConfig, validation)
+		return nil
+	}); err != nil {
+		return err
+	}
+
+	fmt.Println("\n🧪 Dry-run plan:")
+	fmt.Println(summary)
+
+	if pr, ok := currentPullRequest(); ok {
+		conclusion := "success"
+		title := fmt.Sprintf("%s is valid", service)
+		if !validation.ok() {
+			conclusion = "failure"
+			title = fmt.Sprintf("%s has %d validation problem(s)", service, len(validation.Problems))
+		}
+		if err := reportCheckRun(pr, "jupiter / "+service, conclusion, title, summary); err != nil {
+			fmt.Printf("⚠️ Failed to report check run: %v\n", err)
+		}
+		if err := upsertStickyComment(pr.Repository, pr.Number, dto.AppName, "#### 🧪 "+dto.AppName+" (dry-run)\n\n"+summary); err != nil {
+			fmt.Printf("⚠️ Failed to update PR comment: %v\n", err)
+		}
+	}
+
+	if !validation.ok() {
+		return fmt.Errorf("validation failed for %s", service)
+	}
+	return nil
+}
+
+func buildPlanSummary(dto GeneratorSourceDto, registryConfig RegistryConfig, validation ValidationResult) string {
+	var b strings.Builder
+
+	if !validation.ok() {
+		b.WriteString("### ❌ Validation\n\n")
+		for _, p := range validation.Problems {
+			fmt.Fprintf(&b, "- %s\n", p)
+		}
+		b.WriteString("\n")
+	} else {
+		b.WriteString("### ✅ Validation\n\nsource.yml is valid.\n\n")
+	}
+	if len(validation.Warnings) > 0 {
+		b.WriteString("### ⚠️ Warnings\n\n")
+		for _, w := range validation.Warnings {
+			fmt.Fprintf(&b, "- %s\n", w)
+		}
+		b.WriteString("\n")
+	}
+
+	b.WriteString("### Plan\n\n")
+	visibility := dto.Visibility
+	if visibility == "" {
+		visibility = "private"
+	}
+	if fromRepo, moving := previousRepo(commandExecutor, dto); moving {
+		fmt.Fprintf(&b, "- transfer `%s` to `%s/%s`\n", fromRepo, dto.Owner, dto.AppName)
+	}
+	fmt.Fprintf(&b, "- create %s repository `%s/%s` (%s", visibility, dto.Owner, dto.AppName, dto.ProgrammingLanguage)
+	if dto.Framework != "" {
+		fmt.Fprintf(&b, ", %s", dto.Framework)
+	}
+	b.WriteString(")\n")
+	if len(dto.Members) > 0 {
+		fmt.Fprintf(&b, "- members: %s\n", strings.Join(dto.Members, ", "))
+	}
+	if registryConfig.Git.initialPush() == "pull_request" {
+		fmt.Fprintf(&b, "- open bootstrap PR `%s` -> `%s` (new repository only)\n", registryConfig.Git.bootstrapBranch(), dto.branch())
+	}
+	for _, env := range dto.Environments {
+		fmt.Fprintf(&b, "- create environment `%s`\n", env.Name)
+	}
+	for _, rs := range registryConfig.Rulesets {
+		fmt.Fprintf(&b, "- apply ruleset `%s`\n", rs.Name)
+	}
+	if registryConfig.DeployKeys.Enabled {
+		b.WriteString("- provision deploy key\n")
+	}
+	if registryConfig.Server.PublicURL != "" {
+		b.WriteString("- register registry webhook\n")
+	}
+
+	b.WriteString("\n### Token scopes\n\n")
+	for _, s := range requiredScopes(dto, registryConfig) {
+		fmt.Fprintf(&b, "- `%s`: %s\n", s.Scope, s.Reason)
+	}
+
+	return b.String()
+}
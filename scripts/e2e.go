@@ -0,0 +1,117 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// runE2ECommand provision một service throwaway vào sandbox org, verify repo rồi xoá/archive.
+// Dùng làm canary định kỳ cho toàn bộ provisioning path.
+func runE2ECommand(args []string) error {
+	fs := flag.NewFlagSet("e2e", flag.ExitOnError)
+	org := fs.String("org", "", "sandbox GitHub org to provision into (required)")
+	language := fs.String("language", "golang", "programming language of the throwaway service")
+	keep := fs.Bool("keep", false, "keep the repository after verification")
+	fs.Parse(args)
+
+	if *org == "" {
+		return fmt.Errorf("--org is required")
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+
+	suffix, err := newSourceID()
+	if err != nil {
+		return err
+	}
+
+	// Tất cả thao tác GitHub trong run này trỏ sang sandbox org
+	dto := GeneratorSourceDto{
+		AppName:             "jupiter-e2e-" + suffix,
+		Owner:               *org,
+		ProgrammingLanguage: *language,
+		Visibility:          "private",
+		Team:                "e2e",
+	}
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	fmt.Printf("🧪 E2E smoke test: %s\n", fullName)
+
+	// Step 1: Provision giống một service thật
+	provisionErr := processService(commandExecutor, dto, registryConfig)
+
+	// Step 2: Verify repo contents + settings
+	var verifyErr error
+	if provisionErr == nil {
+		fmt.Println("\n🔍 Verifying provisioned repository...")
+		verifyErr = verifyE2ERepo(fullName, dto, registryConfig)
+	}
+
+	// Step 3: Dọn dẹp, kể cả khi provision/verify fail
+	os.RemoveAll(dto.AppName)
+	if !*keep && repoExists(commandExecutor, dto.Owner, dto.AppName) {
+		fmt.Println("\n🧹 Cleaning up sandbox repository...")
+		if err := cleanupE2ERepo(fullName); err != nil {
+			fmt.Printf("  ⚠️ Cleanup failed: %v\n", err)
+		}
+	}
+
+	if provisionErr != nil {
+		return fmt.Errorf("e2e provisioning failed: %w", provisionErr)
+	}
+	if verifyErr != nil {
+		return fmt.Errorf("e2e verification failed: %w", verifyErr)
+	}
+	fmt.Printf("\n✅ E2E smoke test passed for %s\n", fullName)
+	return nil
+}
+
+// verifyE2ERepo kiểm tra visibility, default branch, template files và repo settings
+func verifyE2ERepo(fullName string, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	out, err := runCommandOutput(commandExecutor, "gh", "api", "repos/"+fullName)
+	if err != nil {
+		return fmt.Errorf("failed to read repository: %w", err)
+	}
+	var repo map[string]interface{}
+	if err := json.Unmarshal([]byte(out), &repo); err != nil {
+		return fmt.Errorf("failed to parse repository: %w", err)
+	}
+
+	var problems []string
+	if repo["visibility"] != dto.Visibility {
+		problems = append(problems, fmt.Sprintf("visibility is %v, expected %s", repo["visibility"], dto.Visibility))
+	}
+	if repo["default_branch"] != "main" {
+		problems = append(problems, fmt.Sprintf("default branch is %v, expected main", repo["default_branch"]))
+	}
+	for _, f := range registryConfig.RepoSettings.fields() {
+		if f.value != nil && repo[f.key] != *f.value {
+			problems = append(problems, fmt.Sprintf("%s is %v, expected %t", f.key, repo[f.key], *f.value))
+		}
+	}
+
+	if registryConfig.GithubTemplates.resolve(dto) != "" {
+		if _, err := readRepoFile(commandExecutor, fullName, ".github/PULL_REQUEST_TEMPLATE.md"); err != nil {
+			problems = append(problems, ".github/PULL_REQUEST_TEMPLATE.md missing from main")
+		}
+	}
+
+	if len(problems) > 0 {
+		return fmt.Errorf("%s", strings.Join(problems, "; "))
+	}
+	return nil
+}
+
+// cleanupE2ERepo xoá repo; nếu token không có quyền delete_repo thì archive lại
+func cleanupE2ERepo(fullName string) error {
+	if err := runCommand(commandExecutor, "gh", "api", "-X", "DELETE", "repos/"+fullName); err == nil {
+		return nil
+	}
+	fmt.Println("  ⚠️ Delete not permitted, archiving instead")
+	return runCommand(commandExecutor, "gh", "api", "-X", "PATCH", "repos/"+fullName, "-F", "archived=true")
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// Environment is a GitHub deployment environment declared in source.yml
+type Environment struct {
+	Name      string   `yaml:"name"`
+	Reviewers []string `yaml:"reviewers"`
+	WaitTimer int      `yaml:"wait_timer"`
+	// Secrets map tên secret -> tên biến môi trường chứa giá trị (không để giá trị trong YAML)
+	Secrets map[string]string `yaml:"secrets"`
+}
+
+type environmentReviewer struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+}
+
+func createEnvironments(ex Executor, owner, repoName string, environments []Environment) error {
+	fullName := fmt.Sprintf("%s/%s", owner, repoName)
+
+	for _, env := range environments {
+		fmt.Printf("  🌐 Environment: %s\n", env.Name)
+
+		// Resolve reviewer login -> user ID (API yêu cầu ID)
+		reviewers := make([]environmentReviewer, 0, len(env.Reviewers))
+		for _, login := range env.Reviewers {
+			id, err := lookupUserID(ex, login)
+			if err != nil {
+				return fmt.Errorf("environment '%s': failed to resolve reviewer '%s': %w", env.Name, login, err)
+			}
+			reviewers = append(reviewers, environmentReviewer{Type: "User", ID: id})
+		}
+
+		body, err := json.Marshal(map[string]interface{}{
+			"wait_timer": env.WaitTimer,
+			"reviewers":  reviewers,
+		})
+		if err != nil {
+			return err
+		}
+
+		endpoint := fmt.Sprintf("repos/%s/environments/%s", fullName, env.Name)
+		if err := runCommandWithInput(ex, body, "gh", "api", "-X", "PUT", endpoint, "--input", "-"); err != nil {
+			return fmt.Errorf("failed to create environment '%s': %w", env.Name, err)
+		}
+
+		for secretName, envVar := range env.Secrets {
+			value := os.Getenv(envVar)
+			if value == "" {
+				fmt.Printf("  ⚠️ Secret %s skipped: $%s is not set\n", secretName, envVar)
+				continue
+			}
+			if err := runCommandWithInput(ex, []byte(value), "gh", "secret", "set", secretName,
+				"--env", env.Name, "--repo", fullName); err != nil {
+				return fmt.Errorf("failed to set secret '%s' on environment '%s': %w", secretName, env.Name, err)
+			}
+		}
+	}
+	return nil
+}
+
+func lookupUserID(ex Executor, login string) (int, error) {
+	out, err := runCommandOutput(ex, "gh", "api", "users/"+login, "--jq", ".id")
+	if err != nil {
+		return 0, err
+	}
+	return strconv.Atoi(out)
+}
@@ -0,0 +1,159 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ErrorTracking tạo project error tracking (Sentry) cho service mới, set DSN thành secret
+// SENTRY_DSN của repo và overlay code init SDK vào output generated.
+type ErrorTracking struct {
+	Provider string `yaml:"provider"` // sentry | "" = tắt
+	URL      string `yaml:"url"`      // mặc định https://sentry.io (self-hosted thì đổi)
+	Org      string `yaml:"org"`
+	Team     string `yaml:"team"` // team sở hữu project, mặc định metadata.team
+	TokenEnv string `yaml:"token_env"`
+	Required bool   `yaml:"required"`
+}
+
+type errorTrackingData struct {
+	Service GeneratorSourceDto
+}
+
+// sentryPlatforms: platform Sentry theo language (cũng là các language có overlay SDK)
+var sentryPlatforms = map[string]string{"golang": "go", "nodejs": "node"}
+
+func (e ErrorTracking) enabledFor(dto GeneratorSourceDto) bool {
+	if e.Provider == "" || sentryPlatforms[dto.ProgrammingLanguage] == "" {
+		return false
+	}
+	return dto.Kind == "" || dto.Kind == "service" || dto.Kind == "worker" || dto.Kind == "cronjob"
+}
+
+func provisionErrorTracking(ex Executor, repoDir string, dto GeneratorSourceDto, e ErrorTracking, templatesDir string) error {
+	if e.Provider != "sentry" {
+		return fmt.Errorf("unsupported error tracking provider: %s", e.Provider)
+	}
+	token := os.Getenv(e.TokenEnv)
+	if token == "" {
+		return fmt.Errorf("sentry token $%s is not set", e.TokenEnv)
+	}
+
+	dsn, err := ensureSentryProject(dto, e, token)
+	if err != nil {
+		return err
+	}
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	if err := runCommandWithInput(ex, []byte(dsn), "gh", "secret", "set", "SENTRY_DSN", "--repo", fullName); err != nil {
+		return fmt.Errorf("failed to set SENTRY_DSN: %w", err)
+	}
+
+	return wireSentrySDK(ex, repoDir, dto, templatesDir)
+}
+
+// ensureSentryProject tạo project nếu chưa có rồi trả về public DSN
+func ensureSentryProject(dto GeneratorSourceDto, e ErrorTracking, token string) (string, error) {
+	base := strings.TrimSuffix(e.URL, "/")
+	if base == "" {
+		base = "https://sentry.io"
+	}
+	team := e.Team
+	if team == "" {
+		team = dto.Team
+	}
+
+	sentryRequest := func(method, path string, body, out interface{}) error {
+		var payload []byte
+		if body != nil {
+			var err error
+			if payload, err = json.Marshal(body); err != nil {
+				return err
+			}
+		}
+		req, err := http.NewRequest(method, base+path, bytes.NewReader(payload))
+		if err != nil {
+			return err
+		}
+		req.Header.Set("Authorization", "Bearer "+token)
+		req.Header.Set("Content-Type", "application/json")
+		return doJSONRequest(req, out)
+	}
+
+	var keys []struct {
+		DSN struct {
+			Public string `json:"public"`
+		} `json:"dsn"`
+	}
+	keysPath := fmt.Sprintf("/api/0/projects/%s/%s/keys/", e.Org, dto.AppName)
+	if err := sentryRequest(http.MethodGet, keysPath, nil, &keys); err != nil {
+		// Chưa có project: tạo mới dưới team
+		if team == "" {
+			return "", fmt.Errorf("sentry project needs a team: set error_tracking.team or metadata.team")
+		}
+		var project struct {
+			Slug string `json:"slug"`
+		}
+		err := sentryRequest(http.MethodPost, fmt.Sprintf("/api/0/teams/%s/%s/projects/", e.Org, team), map[string]string{
+			"name":     dto.AppName,
+			"slug":     dto.AppName,
+			"platform": sentryPlatforms[dto.ProgrammingLanguage],
+		}, &project)
+		if err != nil {
+			return "", fmt.Errorf("failed to create sentry project: %w", err)
+		}
+		if err := sentryRequest(http.MethodGet, keysPath, nil, &keys); err != nil {
+			return "", fmt.Errorf("failed to read sentry keys: %w", err)
+		}
+	} else {
+		fmt.Printf("  ℹ️ Sentry project %s/%s already exists\n", e.Org, dto.AppName)
+	}
+
+	if len(keys) == 0 || keys[0].DSN.Public == "" {
+		return "", fmt.Errorf("sentry project %s/%s has no client key", e.Org, dto.AppName)
+	}
+	return keys[0].DSN.Public, nil
+}
+
+// wireSentrySDK overlay templates/error_tracking/sentry/<language>/ và thêm dependency SDK
+func wireSentrySDK(ex Executor, repoDir string, dto GeneratorSourceDto, templatesDir string) error {
+	switch dto.ProgrammingLanguage {
+	case "golang":
+		// Overlay là init() của package main ở root, layout khác (vd. uranus cmd/) thì chỉ cấp DSN
+		if _, err := os.Stat(filepath.Join(repoDir, "main.go")); err != nil {
+			fmt.Println("  ⚠️ No main.go at repo root, SENTRY_DSN is set but SDK init is not wired")
+			return nil
+		}
+		if err := renderTemplateDir(filepath.Join(templatesDir, "error_tracking", "sentry", "golang"), repoDir, errorTrackingData{Service: dto}); err != nil {
+			return err
+		}
+		return runCommandInDir(ex, repoDir, "go", "mod", "tidy")
+	case "nodejs":
+		if err := renderTemplateDir(filepath.Join(templatesDir, "error_tracking", "sentry", "nodejs"), repoDir, errorTrackingData{Service: dto}); err != nil {
+			return err
+		}
+		if err := runCommandInDir(ex, repoDir, "npm", "install", "--save", "--package-lock-only", "@sentry/node"); err != nil {
+			return err
+		}
+		// Load instrument.js trước app cho các script chạy bằng node
+		for _, script := range []string{"start", "start:prod"} {
+			out, err := runCommandOutputInDir(ex, repoDir, "npm", "pkg", "get", "scripts."+script)
+			if err != nil {
+				continue
+			}
+			var command string
+			if json.Unmarshal([]byte(strings.TrimSpace(out)), &command) != nil || !strings.HasPrefix(command, "node ") {
+				continue
+			}
+			command = "node --require ./src/instrument.js " + strings.TrimPrefix(command, "node ")
+			if err := runCommandInDir(ex, repoDir, "npm", "pkg", "set", "scripts."+script+"="+command); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,138 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"os"
+	"strconv"
+)
+
+// ErrorCode là mã lỗi ổn định để automation phân nhánh theo loại lỗi thay vì grep message.
+// Không đổi tên/exit code của các mã đã có, chỉ thêm mã mới.
+type ErrorCode string
+
+const (
+	ErrInternal             ErrorCode = "E_INTERNAL"
+	ErrUsage                ErrorCode = "E_USAGE"
+	ErrYAMLInvalid          ErrorCode = "E_YAML_INVALID"
+	ErrSourceNotFound       ErrorCode = "E_SOURCE_NOT_FOUND"
+	ErrValidationFailed     ErrorCode = "E_VALIDATION_FAILED"
+	ErrApprovalRequired     ErrorCode = "E_APPROVAL_REQUIRED"
+	ErrQuotaExceeded        ErrorCode = "E_QUOTA_EXCEEDED"
+	ErrRepoExists           ErrorCode = "E_REPO_EXISTS"
+	ErrRepoCreateFailed     ErrorCode = "E_REPO_CREATE_FAILED"
+	ErrGeneratorFailed      ErrorCode = "E_GENERATOR_FAILED"
+	ErrGitHubAPI            ErrorCode = "E_GITHUB_API"
+	ErrGitFailed            ErrorCode = "E_GIT_FAILED"
+	ErrPushDenied           ErrorCode = "E_PUSH_DENIED"
+	ErrLocked               ErrorCode = "E_LOCKED"
+	ErrGuardrailViolation   ErrorCode = "E_GUARDRAIL_VIOLATION"
+	ErrConfirmationRequired ErrorCode = "E_CONFIRMATION_REQUIRED"
+	ErrInsufficientScopes   ErrorCode = "E_INSUFFICIENT_SCOPES"
+	ErrCanaryFailed         ErrorCode = "E_CANARY_FAILED"
+	ErrMergeConflict        ErrorCode = "E_MERGE_CONFLICT"
+)
+
+// exitCodes: process exit code tương ứng với từng ErrorCode
+var exitCodes = map[ErrorCode]int{
+	ErrInternal:             1,
+	ErrUsage:                2,
+	ErrYAMLInvalid:          3,
+	ErrSourceNotFound:       4,
+	ErrValidationFailed:     5,
+	ErrApprovalRequired:     6,
+	ErrQuotaExceeded:        7,
+	ErrRepoExists:           8,
+	ErrRepoCreateFailed:     9,
+	ErrGeneratorFailed:      10,
+	ErrGitHubAPI:            11,
+	ErrGitFailed:            12,
+	ErrPushDenied:           13,
+	ErrLocked:               14,
+	ErrGuardrailViolation:   15,
+	ErrConfirmationRequired: 16,
+	ErrInsufficientScopes:   17,
+	ErrCanaryFailed:         18,
+	ErrMergeConflict:        19,
+}
+
+// JupiterError gắn ErrorCode vào một error, vẫn unwrap được về error gốc
+type JupiterError struct {
+	Code ErrorCode
+	Err  error
+}
+
+func (e *JupiterError) Error() string {
+	return e.Err.Error()
+}
+
+func (e *JupiterError) Unwrap() error {
+	return e.Err
+}
+
+// withCode gắn code cho err. Nếu err đã có code (từ tầng sâu hơn) thì giữ nguyên code cụ thể đó.
+func withCode(code ErrorCode, err error) error {
+	if err == nil {
+		return nil
+	}
+	var coded *JupiterError
+	if errors.As(err, &coded) {
+		return err
+	}
+	return &JupiterError{Code: code, Err: err}
+}
+
+// errorCode trả về code của err, mặc định E_INTERNAL
+func errorCode(err error) ErrorCode {
+	var coded *JupiterError
+	if errors.As(err, &coded) {
+		return coded.Code
+	}
+	return ErrInternal
+}
+
+func exitCode(err error) int {
+	if code, ok := exitCodes[errorCode(err)]; ok {
+		return code
+	}
+	return 1
+}
+
+// exitWithError in lỗi (text hoặc JSON khi JUPITER_OUTPUT=json) rồi exit với code tương ứng
+func exitWithError(err error) {
+	code := errorCode(err)
+	if os.Getenv("JUPITER_OUTPUT") == "json" {
+		out, _ := json.Marshal(map[string]interface{}{
+			"error":     code,
+			"exit_code": exitCode(err),
+			"message":   redact(err.Error()),
+		})
+		fmt.Println(string(out))
+	} else {
+		fmt.Printf("❌ [%s] %s\n", code, redact(err.Error()))
+		printLogExcerpt(err)
+	}
+	// os.Exit bỏ qua defer: trả lock đang giữ, không để run sau chờ hết TTL
+	releaseHeldLocks()
+	finishTelemetry(err)
+	flushOutput()
+	os.Exit(exitCode(err))
+}
+
+// ghErrorStatus đọc HTTP status từ body lỗi mà `gh api` in ra stdout ({"message": ..., "status": "422"}).
+// 0 khi không có body (network, token thiếu, gh không chạy được...).
+func ghErrorStatus(out string) (int, string) {
+	var body struct {
+		Message string `json:"message"`
+		Status  string `json:"status"`
+	}
+	if json.Unmarshal([]byte(out), &body) != nil {
+		return 0, ""
+	}
+	status, _ := strconv.Atoi(body.Status)
+	if status == 0 && body.Message == "Not Found" {
+		status = 404
+	}
+	return status, body.Message
+}
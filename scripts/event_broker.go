@@ -0,0 +1,132 @@
+package main
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+)
+
+// eventSchemaVersion tăng khi đổi field của message theo cách không tương thích ngược
+const eventSchemaVersion = 1
+
+// EventBroker publish lifecycle event lên message broker để service khác không phải poll API.
+// Dùng CLI giống các tích hợp khác: `nats pub` (natscli) hoặc `kcat -P`.
+type EventBroker struct {
+	Type string `yaml:"type"` // nats | kafka
+	// URL: nats://host:4222 (nats) hoặc danh sách broker host:9092,host2:9092 (kafka)
+	URL string `yaml:"url"`
+	// Subject: NATS subject prefix (<subject>.<event type>) hoặc Kafka topic, mặc định jupiter.registry
+	Subject string `yaml:"subject"`
+	// CredentialsEnv: env chứa đường dẫn file credentials của NATS (--creds), kafka dùng cấu hình của kcat
+	CredentialsEnv string `yaml:"credentials_env"`
+}
+
+func (b EventBroker) subject() string {
+	if b.Subject == "" {
+		return "jupiter.registry"
+	}
+	return b.Subject
+}
+
+// brokerMessage là payload trên broker: envelope (schema_version, id, source) + field của Event
+type brokerMessage struct {
+	SchemaVersion int    `json:"schema_version"`
+	ID            string `json:"id"`
+	Source        string `json:"source"`
+	Event
+}
+
+func newBrokerMessage(e Event) (brokerMessage, error) {
+	random := make([]byte, 16)
+	if _, err := rand.Read(random); err != nil {
+		return brokerMessage{}, err
+	}
+	source := os.Getenv("GITHUB_REPOSITORY")
+	if source == "" {
+		source = registryRepo
+	}
+	return brokerMessage{SchemaVersion: eventSchemaVersion, ID: hex.EncodeToString(random), Source: source, Event: e}, nil
+}
+
+func publishToBroker(b EventBroker, e Event) error {
+	if b.URL == "" {
+		return fmt.Errorf("events.broker.url is not set")
+	}
+	msg, err := newBrokerMessage(e)
+	if err != nil {
+		return err
+	}
+	payload, err := json.Marshal(msg)
+	if err != nil {
+		return err
+	}
+
+	switch b.Type {
+	case "nats":
+		args := []string{"pub", "--server", b.URL}
+		if b.CredentialsEnv != "" {
+			creds := os.Getenv(b.CredentialsEnv)
+			if creds == "" {
+				return fmt.Errorf("NATS credentials $%s is not set", b.CredentialsEnv)
+			}
+			args = append(args, "--creds", creds)
+		}
+		args = append(args, b.subject()+"."+string(e.Type), string(payload))
+		return runCommand(commandExecutor, "nats", args...)
+	case "kafka":
+		// Key theo service: mọi event của một service nằm cùng partition, giữ đúng thứ tự
+		return runCommandWithInput(commandExecutor, append(payload, '\n'), "kcat", "-P", "-b", b.URL, "-t", b.subject(),
+			"-k", e.Service, "-H", "jupiter-event="+string(e.Type))
+	default:
+		return fmt.Errorf("unsupported events.broker.type: %s", b.Type)
+	}
+}
+
+// eventSchema là JSON Schema của brokerMessage (tài liệu cho consumer, in bằng `events schema`)
+func eventSchema() map[string]interface{} {
+	types := make([]string, 0, len(runActions)+2)
+	for t := range runActions {
+		types = append(types, string(t))
+	}
+	types = append(types, string(EventRepoCreated), string(EventPushFailed))
+	sort.Strings(types)
+
+	str := func(description string) map[string]interface{} {
+		return map[string]interface{}{"type": "string", "description": description}
+	}
+	return map[string]interface{}{
+		"$schema": "https://json-schema.org/draft/2020-12/schema",
+		"title":   "jupiter-registry lifecycle event",
+		"type":    "object",
+		"properties": map[string]interface{}{
+			"schema_version":   map[string]interface{}{"type": "integer", "const": eventSchemaVersion},
+			"id":               str("unique message id, use it to deduplicate redeliveries"),
+			"source":           str("registry repository that emitted the event"),
+			"type":             map[string]interface{}{"type": "string", "enum": types},
+			"timestamp":        map[string]interface{}{"type": "string", "format": "date-time"},
+			"service":          str("service name (source.yml name)"),
+			"repo":             str("owner/name of the generated repository"),
+			"template_version": str("template version used for the generation"),
+			"commit_sha":       str("head commit of the default branch after the push"),
+			"error_code":       str("E_* error code for failure events"),
+		},
+		"required": []string{"schema_version", "id", "source", "type", "timestamp", "service"},
+	}
+}
+
+// runEventsCommand: `events schema` in JSON Schema của message publish lên broker
+func runEventsCommand(args []string) error {
+	if len(args) != 1 || args[0] != "schema" {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts events schema"))
+	}
+	out, err := json.MarshalIndent(eventSchema(), "", "  ")
+	if err != nil {
+		return err
+	}
+	fmt.Println(strings.TrimSpace(string(out)))
+	return nil
+}
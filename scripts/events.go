@@ -0,0 +1,211 @@
+package main
+
+import (
+	"bytes"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"sync"
+	"time"
+)
+
+// EventType là loại event của pipeline provisioning
+type EventType string
+
+const (
+	EventRepoCreated      EventType = "repo.created"
+	EventPushFailed       EventType = "push.failed"
+	EventServiceGenerated EventType = "service.generated"
+	EventServiceFailed    EventType = "service.failed"
+	EventServiceArchived  EventType = "service.archived"
+	EventServiceRestored  EventType = "service.restored"
+	EventServiceDeleted   EventType = "service.deleted"
+	// EventServiceDecommissioned: teardown hoàn tất (decommission)
+	EventServiceDecommissioned EventType = "service.decommissioned"
+)
+
+// Event được publish lên bus; DTO chỉ dùng cho subscriber trong process (không serialize)
+type Event struct {
+	Type            EventType           `json:"type"`
+	Timestamp       string              `json:"timestamp"`
+	Service         string              `json:"service"`
+	Repo            string              `json:"repo,omitempty"`
+	TemplateVersion string              `json:"template_version,omitempty"`
+	CommitSHA       string              `json:"commit_sha,omitempty"`
+	ErrorCode       string              `json:"error_code,omitempty"`
+	Err             error               `json:"-"`
+	DTO             *GeneratorSourceDto `json:"-"`
+}
+
+type subscription struct {
+	name    string
+	types   map[EventType]bool // rỗng = mọi event
+	handler func(Event) error
+}
+
+// EventBus phát event tới các subscriber (audit log, PR comment, webhooks, catalog...),
+// integration mới chỉ cần subscribe thay vì chèn lời gọi vào processGolang / provisionService.
+// Subscriber chạy đồng bộ theo thứ tự đăng ký; lỗi / panic chỉ cảnh báo, không làm fail run.
+type EventBus struct {
+	mu   sync.RWMutex
+	subs []subscription
+}
+
+// events là bus dùng chung của process
+var events = &EventBus{}
+
+func (b *EventBus) Subscribe(name string, handler func(Event) error, types ...EventType) {
+	s := subscription{name: name, handler: handler, types: map[EventType]bool{}}
+	for _, t := range types {
+		s.types[t] = true
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.subs = append(b.subs, s)
+}
+
+func (b *EventBus) Publish(e Event) {
+	if e.Timestamp == "" {
+		e.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	}
+	if e.Err != nil && e.ErrorCode == "" {
+		e.ErrorCode = string(errorCode(e.Err))
+	}
+
+	b.mu.RLock()
+	subs := append([]subscription(nil), b.subs...)
+	b.mu.RUnlock()
+	for _, s := range subs {
+		if len(s.types) > 0 && !s.types[e.Type] {
+			continue
+		}
+		deliver(s, e)
+	}
+}
+
+func deliver(s subscription, e Event) {
+	defer func() {
+		if r := recover(); r != nil {
+			fmt.Printf("⚠️ Event subscriber %s panicked on %s: %v\n", s.name, e.Type, r)
+		}
+	}()
+	if err := s.handler(e); err != nil {
+		fmt.Printf("⚠️ Event subscriber %s failed on %s: %v\n", s.name, e.Type, err)
+	}
+}
+
+// Events cấu hình các subscriber tuỳ chọn của event bus
+type Events struct {
+	// Webhooks nhận POST JSON của event (Slack workflow, hệ thống nội bộ, ...)
+	Webhooks []EventWebhook `yaml:"webhooks"`
+	// Catalog: ghi lại catalog/index.json + CATALOG.md ngay sau mỗi thay đổi (hữu ích cho server mode)
+	Catalog bool `yaml:"catalog"`
+	// Broker: publish mọi event lên NATS / Kafka (schema: `go run ./scripts events schema`)
+	Broker EventBroker `yaml:"broker"`
+}
+
+// EventWebhook: body được ký HMAC-SHA256 (header X-Jupiter-Signature) nếu có SecretEnv
+type EventWebhook struct {
+	URL       string   `yaml:"url"`
+	Events    []string `yaml:"events"` // rỗng = mọi event
+	SecretEnv string   `yaml:"secret_env"`
+}
+
+// runActions: action trong history tương ứng với event
+var runActions = map[EventType]string{
+	EventServiceGenerated:      "provision",
+	EventServiceFailed:         "provision",
+	EventServiceArchived:       "archive",
+	EventServiceRestored:       "restore",
+	EventServiceDeleted:        "delete",
+	EventServiceDecommissioned: "decommission",
+}
+
+// registerSubscribers đăng ký subscriber built-in và subscriber theo jupiter.yml events:
+func registerSubscribers(registryConfig RegistryConfig) {
+	// Audit log: state/history/<name>.jsonl
+	events.Subscribe("history", func(e Event) error {
+		record := RunRecord{Action: runActions[e.Type], TemplateVersion: e.TemplateVersion, CommitSHA: e.CommitSHA, Result: "success", ErrorCode: e.ErrorCode}
+		if e.Type == EventServiceFailed {
+			record.Result = "failed"
+		}
+		recordRun(e.Service, record)
+		return nil
+	}, EventServiceGenerated, EventServiceFailed, EventServiceArchived, EventServiceRestored, EventServiceDeleted, EventServiceDecommissioned)
+
+	// Ticket Jira / Linear (tickets:), đăng ký sau history vì dựa vào record của chính lần chạy này
+	if tickets := registryConfig.Tickets; tickets.Provider != "" {
+		events.Subscribe("tickets "+tickets.Provider, func(e Event) error {
+			return openTicketForEvent(tickets, e)
+		}, EventServiceGenerated, EventServiceFailed)
+	}
+
+	// Sticky comment trên PR của jupiter-registry (nếu có)
+	events.Subscribe("pr-comment", func(e Event) error {
+		if e.DTO != nil {
+			reportProvisioningOutcome(*e.DTO, registryConfig, e.Err)
+		}
+		return nil
+	}, EventServiceGenerated, EventServiceFailed)
+
+	if registryConfig.Events.Catalog {
+		events.Subscribe("catalog", func(e Event) error {
+			_, err := writeCatalog(".")
+			return err
+		}, EventServiceGenerated, EventServiceArchived, EventServiceRestored, EventServiceDeleted, EventServiceDecommissioned)
+	}
+
+	if broker := registryConfig.Events.Broker; broker.Type != "" {
+		events.Subscribe("broker "+broker.Type, func(e Event) error {
+			return publishToBroker(broker, e)
+		})
+	}
+
+	for _, hook := range registryConfig.Events.Webhooks {
+		hook := hook
+		types := make([]EventType, len(hook.Events))
+		for i, t := range hook.Events {
+			types[i] = EventType(t)
+		}
+		events.Subscribe("webhook "+hook.URL, func(e Event) error {
+			return postEventWebhook(hook, e)
+		}, types...)
+	}
+}
+
+func postEventWebhook(hook EventWebhook, e Event) error {
+	payload, err := json.Marshal(e)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequest(http.MethodPost, hook.URL, bytes.NewReader(payload))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("X-Jupiter-Event", string(e.Type))
+	if hook.SecretEnv != "" {
+		secret := os.Getenv(hook.SecretEnv)
+		if secret == "" {
+			return fmt.Errorf("webhook secret $%s is not set", hook.SecretEnv)
+		}
+		mac := hmac.New(sha256.New, []byte(secret))
+		mac.Write(payload)
+		req.Header.Set("X-Jupiter-Signature", "sha256="+hex.EncodeToString(mac.Sum(nil)))
+	}
+
+	client := &http.Client{Timeout: 10 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return err
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook returned %s", resp.Status)
+	}
+	return nil
+}
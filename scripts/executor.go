@@ -0,0 +1,63 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// Command mô tả một lệnh external (git, gh, uranus, npm, ...) cần chạy
+type Command struct {
+	Dir  string
+	Name string
+	Args []string
+	// Env được append vào environment hiện tại
+	Env   []string
+	Stdin []byte
+	// CaptureOutput: trả stdout về cho caller thay vì stream ra console
+	CaptureOutput bool
+	// Log: log của service đang provisioning (serviceExecutor gắn vào), nil ngoài provisionService
+	Log *serviceLog
+}
+
+// Executor chạy external command. Pipeline chỉ gọi command qua commandExecutor (hoặc serviceExecutor bọc nó,
+// truyền tường minh từ provisionService) nên test có thể thay bằng fakeExecutor (executor_fake_test.go)
+// để chạy flow mà không đụng tới git/gh/uranus thật.
+type Executor interface {
+	Run(cmd Command) (string, error)
+}
+
+// commandExecutor là executor được pipeline sử dụng
+var commandExecutor Executor = shellExecutor{}
+
+// shellExecutor chạy command thật bằng os/exec
+type shellExecutor struct{}
+
+func (shellExecutor) Run(c Command) (string, error) {
+	cmd := exec.Command(c.Name, c.Args...)
+	cmd.Dir = c.Dir
+	if len(c.Env) > 0 {
+		cmd.Env = append(os.Environ(), c.Env...)
+	}
+	if c.Stdin != nil {
+		cmd.Stdin = bytes.NewReader(c.Stdin)
+	}
+	if c.Log != nil {
+		return runLogged(cmd, c, c.Log)
+	}
+	cmd.Stderr = os.Stderr
+
+	if c.CaptureOutput {
+		out, err := cmd.Output()
+		return string(out), err
+	}
+
+	cmd.Stdout = os.Stdout
+	return "", cmd.Run()
+}
+
+// commandLine là dạng "name args..." của command, dùng cho log và fake executor trong test
+func commandLine(c Command) string {
+	return strings.TrimSpace(c.Name + " " + strings.Join(c.Args, " "))
+}
@@ -0,0 +1,74 @@
+package main
+
+import (
+	"strings"
+	"sync"
+	"testing"
+)
+
+// FakeResponse là kết quả trả về cho các command khớp prefix
+type FakeResponse struct {
+	Output string
+	Err    error
+}
+
+// fakeExecutor ghi lại mọi invocation và trả response đã cấu hình,
+// không chạy command thật. Response được chọn theo prefix dài nhất của "name args...".
+type fakeExecutor struct {
+	mu        sync.Mutex
+	Calls     []Command
+	Responses map[string]FakeResponse
+}
+
+func newFakeExecutor() *fakeExecutor {
+	return &fakeExecutor{Responses: map[string]FakeResponse{}}
+}
+
+// On đăng ký response cho các command bắt đầu bằng prefix (vd "gh api repos/")
+func (f *fakeExecutor) On(prefix string, response FakeResponse) *fakeExecutor {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.Responses[prefix] = response
+	return f
+}
+
+func (f *fakeExecutor) Run(c Command) (string, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.Calls = append(f.Calls, c)
+
+	line := commandLine(c)
+	best, found := "", false
+	for prefix := range f.Responses {
+		if strings.HasPrefix(line, prefix) && len(prefix) >= len(best) {
+			best, found = prefix, true
+		}
+	}
+	if !found {
+		return "", nil
+	}
+	return f.Responses[best].Output, f.Responses[best].Err
+}
+
+// Invocations trả về danh sách command đã chạy dạng "name args..."
+func (f *fakeExecutor) Invocations() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	lines := make([]string, 0, len(f.Calls))
+	for _, c := range f.Calls {
+		lines = append(lines, commandLine(c))
+	}
+	return lines
+}
+
+// useFakeExecutor thay commandExecutor bằng fake cho tới hết test
+func useFakeExecutor(t *testing.T) *fakeExecutor {
+	t.Helper()
+	fake := newFakeExecutor()
+	previous := commandExecutor
+	commandExecutor = fake
+	t.Cleanup(func() { commandExecutor = previous })
+	return fake
+}
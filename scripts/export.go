@@ -0,0 +1,136 @@
+package main
+
+import (
+	"encoding/csv"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"os"
+	"strings"
+)
+
+// exportFields map tên field (giống json tag của CatalogEntry) -> giá trị
+var exportFields = []struct {
+	name  string
+	value func(e CatalogEntry) string
+}{
+	{"folder", func(e CatalogEntry) string { return e.Folder }},
+	{"source_id", func(e CatalogEntry) string { return e.SourceID }},
+	{"name", func(e CatalogEntry) string { return e.Name }},
+	{"programming_language", func(e CatalogEntry) string { return e.ProgrammingLanguage }},
+	{"framework", func(e CatalogEntry) string { return e.Framework }},
+	{"module", func(e CatalogEntry) string { return e.Module }},
+	{"team", func(e CatalogEntry) string { return e.Team }},
+	{"cost_center", func(e CatalogEntry) string { return e.CostCenter }},
+	{"members", func(e CatalogEntry) string { return strings.Join(e.Members, ";") }},
+	{"repo_url", func(e CatalogEntry) string { return e.RepoURL }},
+}
+
+func runExportCommand(args []string) error {
+	fs := flag.NewFlagSet("export", flag.ExitOnError)
+	format := fs.String("format", "json", "export format: csv|json")
+	fieldList := fs.String("fields", "", "comma separated fields to export (default: all)")
+	outFile := fs.String("out", "", "write to file instead of stdout")
+	fs.Parse(args)
+
+	fields, err := selectExportFields(*fieldList)
+	if err != nil {
+		return err
+	}
+
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	catalog := buildCatalog(services)
+
+	var w io.Writer = os.Stdout
+	if *outFile != "" {
+		f, err := os.Create(*outFile)
+		if err != nil {
+			return fmt.Errorf("failed to create %s: %w", *outFile, err)
+		}
+		defer f.Close()
+		w = f
+	}
+
+	switch *format {
+	case "csv":
+		return exportCSV(w, catalog, fields)
+	case "json":
+		return exportJSON(w, catalog, fields)
+	default:
+		return fmt.Errorf("unsupported export format: %s", *format)
+	}
+}
+
+func selectExportFields(fieldList string) ([]int, error) {
+	var selected []int
+	if fieldList == "" {
+		for i := range exportFields {
+			selected = append(selected, i)
+		}
+		return selected, nil
+	}
+
+	for _, name := range strings.Split(fieldList, ",") {
+		name = strings.TrimSpace(name)
+		found := false
+		for i, f := range exportFields {
+			if f.name == name {
+				selected = append(selected, i)
+				found = true
+				break
+			}
+		}
+		if !found {
+			return nil, fmt.Errorf("unknown export field: %s", name)
+		}
+	}
+	return selected, nil
+}
+
+func exportCSV(w io.Writer, catalog []CatalogEntry, fields []int) error {
+	cw := csv.NewWriter(w)
+
+	header := make([]string, 0, len(fields))
+	for _, i := range fields {
+		header = append(header, exportFields[i].name)
+	}
+	if err := cw.Write(header); err != nil {
+		return err
+	}
+
+	for _, entry := range catalog {
+		row := make([]string, 0, len(fields))
+		for _, i := range fields {
+			row = append(row, exportFields[i].value(entry))
+		}
+		if err := cw.Write(row); err != nil {
+			return err
+		}
+	}
+
+	cw.Flush()
+	return cw.Error()
+}
+
+func exportJSON(w io.Writer, catalog []CatalogEntry, fields []int) error {
+	rows := make([]map[string]interface{}, 0, len(catalog))
+	for _, entry := range catalog {
+		row := map[string]interface{}{}
+		for _, i := range fields {
+			if exportFields[i].name == "members" {
+				row["members"] = entry.Members
+				continue
+			}
+			row[exportFields[i].name] = exportFields[i].value(entry)
+		}
+		rows = append(rows, row)
+	}
+
+	enc := json.NewEncoder(w)
+	enc.SetIndent("", "  ")
+	return enc.Encode(rows)
+}
@@ -0,0 +1,172 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"math/rand"
+	"os"
+	"path/filepath"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// FaultSpec mô tả lỗi giả lập cho reliability test (option ẩn --fault-inject), vd.
+// "push=30%,api-fail=10%,api-delay=2s,truncate=50%,seed=42"
+type FaultSpec struct {
+	// PushFail: xác suất (%) `git push` thất bại
+	PushFail int
+	// APIFail: xác suất (%) `gh` call thất bại
+	APIFail int
+	// APIDelay: delay trước mỗi `gh` call (giả lập API chậm / rate limit)
+	APIDelay time.Duration
+	// Truncate: xác suất (%) output generated bị cắt (mất một nửa file)
+	Truncate int
+	// Seed cố định để reproduce một lần chạy; 0 = theo thời gian
+	Seed int64
+}
+
+// faultInjector != nil khi fault injection được bật cho process
+var faultInjector *faultExecutor
+
+func parseFaultSpec(spec string) (FaultSpec, error) {
+	var fault FaultSpec
+	for _, part := range strings.Split(spec, ",") {
+		part = strings.TrimSpace(part)
+		if part == "" {
+			continue
+		}
+		key, value, ok := strings.Cut(part, "=")
+		if !ok {
+			return fault, fmt.Errorf("invalid fault %q (expected key=value)", part)
+		}
+		var err error
+		switch key {
+		case "push":
+			fault.PushFail, err = parsePercent(value)
+		case "api-fail":
+			fault.APIFail, err = parsePercent(value)
+		case "api-delay":
+			fault.APIDelay, err = time.ParseDuration(value)
+		case "truncate":
+			fault.Truncate, err = parsePercent(value)
+		case "seed":
+			fault.Seed, err = strconv.ParseInt(value, 10, 64)
+		default:
+			return fault, fmt.Errorf("unknown fault %q (allowed: push, api-fail, api-delay, truncate, seed)", key)
+		}
+		if err != nil {
+			return fault, fmt.Errorf("invalid fault %s: %w", part, err)
+		}
+	}
+	return fault, nil
+}
+
+func parsePercent(value string) (int, error) {
+	n, err := strconv.Atoi(strings.TrimSuffix(value, "%"))
+	if err != nil {
+		return 0, err
+	}
+	if n < 0 || n > 100 {
+		return 0, fmt.Errorf("percentage must be between 0 and 100")
+	}
+	return n, nil
+}
+
+// extractFaultInjectFlag bỏ --fault-inject khỏi args (để subcommand / flag.Parse không thấy option ẩn) và trả spec.
+// Chỉ bật qua flag, không qua env: injection phải luôn thấy được trên command line của run.
+func extractFaultInjectFlag(args []string) ([]string, string) {
+	var spec string
+	rest := make([]string, 0, len(args))
+	for i := 0; i < len(args); i++ {
+		switch {
+		case args[i] == "--fault-inject" && i+1 < len(args):
+			spec = args[i+1]
+			i++
+		case strings.HasPrefix(args[i], "--fault-inject="):
+			spec = strings.TrimPrefix(args[i], "--fault-inject=")
+		default:
+			rest = append(rest, args[i])
+		}
+	}
+	return rest, spec
+}
+
+// faultExecutor bọc executor thật và inject lỗi theo FaultSpec
+type faultExecutor struct {
+	inner Executor
+	spec  FaultSpec
+
+	mu  sync.Mutex
+	rng *rand.Rand
+}
+
+func newFaultExecutor(inner Executor, spec FaultSpec) *faultExecutor {
+	seed := spec.Seed
+	if seed == 0 {
+		seed = time.Now().UnixNano()
+	}
+	return &faultExecutor{inner: inner, spec: spec, rng: rand.New(rand.NewSource(seed))}
+}
+
+// hit: true với xác suất percent%
+func (f *faultExecutor) hit(percent int) bool {
+	if percent <= 0 {
+		return false
+	}
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.rng.Intn(100) < percent
+}
+
+func (f *faultExecutor) Run(cmd Command) (string, error) {
+	if cmd.Name == "git" && len(cmd.Args) > 0 && cmd.Args[0] == "push" && f.hit(f.spec.PushFail) {
+		fmt.Printf("%s💥 fault-inject: failing %s\n", cmd.Log.prefix(), commandLine(cmd))
+		return "", fmt.Errorf("fault-inject: simulated push failure")
+	}
+	if cmd.Name == "gh" {
+		if f.spec.APIDelay > 0 {
+			fmt.Printf("%s💥 fault-inject: delaying %s by %s\n", cmd.Log.prefix(), commandLine(cmd), f.spec.APIDelay)
+			time.Sleep(f.spec.APIDelay)
+		}
+		if f.hit(f.spec.APIFail) {
+			fmt.Printf("%s💥 fault-inject: failing %s\n", cmd.Log.prefix(), commandLine(cmd))
+			return "", fmt.Errorf("fault-inject: simulated GitHub API failure")
+		}
+	}
+	return f.inner.Run(cmd)
+}
+
+// injectTruncation giả lập generator dừng giữa chừng: xoá nửa sau danh sách file đã generate
+func injectTruncation(ex Executor, appDir string) error {
+	if faultInjector == nil || !faultInjector.hit(faultInjector.spec.Truncate) {
+		return nil
+	}
+	var files []string
+	err := filepath.WalkDir(appDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if !d.IsDir() {
+			files = append(files, path)
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	sort.Strings(files)
+	dropped := files[len(files)/2:]
+	for _, path := range dropped {
+		if err := os.Remove(path); err != nil {
+			return err
+		}
+	}
+	fmt.Printf("%s💥 fault-inject: truncated generation of %s (%d/%d files removed)\n", logPrefix(ex), appDir, len(dropped), len(files))
+	return nil
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+func TestExtractFaultInjectFlag(t *testing.T) {
+	// Env không bật injection: chỉ flag trên command line
+	t.Setenv("JUPITER_FAULT_INJECT", "push=100%")
+	if rest, spec := extractFaultInjectFlag([]string{"sources-service/orders"}); spec != "" || !reflect.DeepEqual(rest, []string{"sources-service/orders"}) {
+		t.Fatalf("got (%v, %q) without the flag", rest, spec)
+	}
+
+	for _, args := range [][]string{
+		{"--fault-inject", "push=30%", "--dry-run", "sources-service/orders"},
+		{"--dry-run", "--fault-inject=push=30%", "sources-service/orders"},
+	} {
+		rest, spec := extractFaultInjectFlag(args)
+		if spec != "push=30%" || !reflect.DeepEqual(rest, []string{"--dry-run", "sources-service/orders"}) {
+			t.Errorf("extractFaultInjectFlag(%v) = (%v, %q)", args, rest, spec)
+		}
+	}
+}
+
+func TestParseFaultSpec(t *testing.T) {
+	spec, err := parseFaultSpec("push=30%,api-fail=10,api-delay=2s,truncate=50%,seed=42")
+	if err != nil {
+		t.Fatal(err)
+	}
+	want := FaultSpec{PushFail: 30, APIFail: 10, APIDelay: 2 * time.Second, Truncate: 50, Seed: 42}
+	if spec != want {
+		t.Fatalf("got %+v, want %+v", spec, want)
+	}
+	for _, invalid := range []string{"push", "push=150%", "api-delay=soon", "explode=1"} {
+		if _, err := parseFaultSpec(invalid); err == nil {
+			t.Errorf("parseFaultSpec(%q) accepted", invalid)
+		}
+	}
+}
@@ -0,0 +1,58 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// frontendFrameworks là các framework frontend được hỗ trợ cho kind: frontend
+var frontendFrameworks = []string{"react", "nextjs", "vue"}
+
+// processFrontend scaffold app frontend + CI static hosting, sau đó tạo repo như service thường
+func processFrontend(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	fmt.Printf("\n🎨 Processing frontend app (%s)...\n", dto.Framework)
+
+	if err := generateFrontendApp(ex, dto, registryConfig); err != nil {
+		return withCode(ErrGeneratorFailed, err)
+	}
+
+	return provisionRepository(ex, dto, registryConfig)
+}
+
+func generateFrontendApp(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	fmt.Printf("🚀 Generating %s app: %s\n", dto.Framework, dto.AppName)
+
+	var err error
+	switch dto.Framework {
+	case "react":
+		err = runCommand(ex, "npm", "create", "vite@latest", dto.AppName, "--", "--template", "react-ts")
+	case "vue":
+		err = runCommand(ex, "npm", "create", "vite@latest", dto.AppName, "--", "--template", "vue-ts")
+	case "nextjs":
+		err = runCommand(ex, "npx", "--yes", "create-next-app@latest", dto.AppName,
+			"--ts", "--eslint", "--app", "--src-dir", "--use-npm", "--skip-install", "--disable-git", "--yes")
+	default:
+		return fmt.Errorf("unsupported frontend framework: %s", dto.Framework)
+	}
+	if err != nil {
+		return fmt.Errorf("failed to generate app: %w", err)
+	}
+
+	// Overlay CI build + deploy static hosting (GitHub Pages)
+	if registryConfig.FrameworkTemplates == "" {
+		return fmt.Errorf("framework_templates is not configured")
+	}
+	srcDir := filepath.Join(registryConfig.FrameworkTemplates, "frontend", dto.Framework)
+	if _, err := os.Stat(srcDir); err != nil {
+		return fmt.Errorf("no templates for frontend/%s in %s", dto.Framework, registryConfig.FrameworkTemplates)
+	}
+	if err := renderTemplateDir(srcDir, dto.AppName, dto); err != nil {
+		return fmt.Errorf("failed to render %s templates: %w", dto.Framework, err)
+	}
+
+	if err := runCommandInDir(ex, dto.AppName, "npm", "install", "--package-lock-only"); err != nil {
+		return fmt.Errorf("npm install failed: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+)
+
+// OrphanRepo là repo downstream có marker manifest nhưng source.yml không còn trong registry
+type OrphanRepo struct {
+	Repo     string `json:"repo"`
+	SourceID string `json:"source_id,omitempty"`
+	// Service: entry trong state trỏ tới repo này (nếu còn)
+	Service string `json:"service,omitempty"`
+}
+
+// runGCCommand: `gc [--owner org] [--archive --confirm repo,...]` tìm repo mồ côi và archive (khi xác nhận)
+func runGCCommand(args []string) error {
+	fs := flag.NewFlagSet("gc", flag.ExitOnError)
+	owner := fs.String("owner", "", "only scan this owner (default: every owner used by the registry)")
+	archive := fs.Bool("archive", false, "archive orphaned repos (each one needs --confirm)")
+	confirm := fs.String("confirm", "", "comma-separated repo names confirmed for archiving")
+	asJSON := fs.Bool("json", false, "print orphaned repos as JSON")
+	fs.Parse(args)
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return fmt.Errorf("error loading registry config: %w", err)
+	}
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+
+	owners := []string{*owner}
+	if *owner == "" {
+		owners = registryOwners(services, registryConfig)
+	}
+	orphans, err := findOrphanRepos(owners, services, state)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		out, err := json.MarshalIndent(orphans, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else if len(orphans) == 0 {
+		fmt.Println("✅ No orphaned repositories")
+	} else {
+		fmt.Printf("🧟 %d orphaned repo(s) whose source.yml no longer exists:\n", len(orphans))
+		for _, o := range orphans {
+			fmt.Printf("  - %s (source_id: %q)\n", o.Repo, o.SourceID)
+		}
+		if !*archive {
+			fmt.Println("ℹ️  Re-run with --archive --confirm <repo-name>,... to archive them")
+		}
+	}
+
+	if !*archive || len(orphans) == 0 {
+		return nil
+	}
+	if err := verifyTokenScopes(commandExecutor, []TokenScope{{"repo", "archive orphaned repositories"}}); err != nil {
+		return err
+	}
+	var failed []string
+	for _, o := range orphans {
+		if err := archiveOrphan(o, parseConfirm(*confirm), registryConfig); err != nil {
+			fmt.Printf("❌ [%s] %v\n", errorCode(err), err)
+			failed = append(failed, o.Repo)
+		}
+	}
+	if len(failed) > 0 {
+		return withCode(ErrGitHubAPI, fmt.Errorf("%d orphaned repo(s) not archived: %s", len(failed), strings.Join(failed, ", ")))
+	}
+	return nil
+}
+
+// registryOwners: repoOwner, org của tenant và owner khai báo trong source.yml
+func registryOwners(services []RegisteredService, registryConfig RegistryConfig) []string {
+	seen := map[string]bool{repoOwner: true}
+	for _, t := range registryConfig.Tenants {
+		if t.Org != "" {
+			seen[t.Org] = true
+		}
+	}
+	for _, s := range services {
+		seen[registryConfig.ownerFor(s.Config)] = true
+	}
+	owners := make([]string, 0, len(seen))
+	for o := range seen {
+		owners = append(owners, o)
+	}
+	sort.Strings(owners)
+	return owners
+}
+
+// findOrphanRepos đọc manifest của mọi repo chưa archive. Repo chỉ là orphan khi cả source_id
+// lẫn tên repo không khớp service nào trong registry (trường hợp mơ hồ thì giữ lại).
+func findOrphanRepos(owners []string, services []RegisteredService, state *RegistryState) ([]OrphanRepo, error) {
+	sourceIDs := map[string]bool{}
+	names := map[string]bool{}
+	for _, s := range services {
+		if s.Config.SourceID != "" {
+			sourceIDs[s.Config.SourceID] = true
+		}
+		names[s.Config.Name] = true
+	}
+
+	var orphans []OrphanRepo
+	for _, owner := range owners {
+		fmt.Printf("🔍 Scanning %s for repositories generated by the registry...\n", owner)
+		out, err := runCommandOutput(commandExecutor, "gh", "repo", "list", owner, "--no-archived", "--limit", "1000",
+			"--json", "nameWithOwner", "--jq", ".[].nameWithOwner")
+		if err != nil {
+			return nil, withCode(ErrGitHubAPI, fmt.Errorf("failed to list repositories of %s: %w", owner, err))
+		}
+		for _, fullName := range strings.Fields(out) {
+			repoName := strings.TrimPrefix(fullName, owner+"/")
+			manifest, ok := readRemoteManifest(commandExecutor, owner, repoName)
+			if !ok {
+				continue
+			}
+			if sourceIDs[manifest.SourceID] || names[repoName] {
+				continue
+			}
+			orphan := OrphanRepo{Repo: fullName, SourceID: manifest.SourceID}
+			for name, entry := range state.Services {
+				if entry.Repo == fullName {
+					orphan.Service = name
+				}
+			}
+			orphans = append(orphans, orphan)
+		}
+	}
+	sort.Slice(orphans, func(i, j int) bool { return orphans[i].Repo < orphans[j].Repo })
+	return orphans, nil
+}
+
+func archiveOrphan(o OrphanRepo, confirmed []string, registryConfig RegistryConfig) error {
+	_, repoName, _ := strings.Cut(o.Repo, "/")
+	if err := checkConfirmation(registryConfig.Destructive, actionArchive, repoName, confirmed); err != nil {
+		return err
+	}
+	fmt.Printf("🗄️  Archiving orphaned %s...\n", o.Repo)
+	if err := runCommand(commandExecutor, "gh", "api", "-X", "PATCH", "repos/"+o.Repo, "-F", "archived=true"); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to archive %s: %w", o.Repo, err))
+	}
+
+	service := o.Service
+	if service == "" {
+		service = repoName
+	} else if err := updateServiceState(service, func(s *ServiceState) {
+		s.ArchivedAt = time.Now().UTC().Format(time.RFC3339)
+		s.LastStatus = "archived"
+	}); err != nil {
+		return err
+	}
+	events.Publish(Event{Type: EventServiceArchived, Service: service, Repo: o.Repo})
+	fmt.Printf("✅ %s archived\n", o.Repo)
+	return nil
+}
@@ -1,88 +1,422 @@
 package main
 
 import (
+	"flag"
 	"fmt"
 	"os"
-	"os/exec"
 	"path/filepath"
 	"runtime"
+	"strconv"
 	"strings"
 
 	"gopkg.in/yaml.v3"
 )
 
+// repoOwner là GitHub owner mặc định chứa các repo được generate (source.yml có thể override bằng owner)
+const repoOwner = "tqhuy-dev"
+
 // SourceConfig represents the full YAML structure
 type SourceConfig struct {
 	SourceID string   `yaml:"source_id"` // Sẽ bỏ qua khi convert to DTO
 	Name     string   `yaml:"name"`
+	Owner    string   `yaml:"owner,omitempty"` // GitHub owner/org, mặc định repoOwner
 	Members  []string `yaml:"members"`
 	Metadata Metadata `yaml:"metadata"`
+
+	Visibility   string        `yaml:"visibility,omitempty"` // private (default) | internal | public
+	Branch       string        `yaml:"branch,omitempty"`     // default branch, mặc định main
+	Environments []Environment `yaml:"environments,omitempty"`
+	Deploy       Deploy        `yaml:"deploy,omitempty"`
+	Schedule     string        `yaml:"schedule,omitempty"` // cron expression, chỉ dùng cho kind cronjob
+	Approvals    []Approval    `yaml:"approvals,omitempty"`
+	Quality      Quality       `yaml:"quality,omitempty"`
+	Config       []ConfigVar   `yaml:"config,omitempty"` // contract biến môi trường của service
+	SLO          SLO           `yaml:"slo,omitempty"`
+	DocsLanguage string        `yaml:"docs_language,omitempty"` // en (mặc định) | vi, ngôn ngữ docs được generate
+	DependsOn    []string      `yaml:"depends_on,omitempty"`    // name của service khác trong registry cần provisioning trước
+	Refresh      string        `yaml:"refresh,omitempty"`       // TTL của scaffolding (vd. 90d), quá hạn thì `refresh` mở upgrade PR
+	// PublishAsTemplate đánh dấu repo là GitHub template repository (golden path cho team khác "Use this template")
+	PublishAsTemplate bool `yaml:"publish_as_template,omitempty"`
 }
 
 type Metadata struct {
-	ProgrammingLanguage string `yaml:"programming_language"`
-	Framework           string `yaml:"framework"`
-	Module              string `yaml:"module"`
+	Kind                string            `yaml:"kind,omitempty"` // service (default) | frontend | library | cli | worker | cronjob
+	ProgrammingLanguage string            `yaml:"programming_language"`
+	Framework           string            `yaml:"framework"`
+	Module              string            `yaml:"module"`
+	Team                string            `yaml:"team,omitempty"`
+	CostCenter          string            `yaml:"cost_center,omitempty"`
+	Labels              map[string]string `yaml:"labels,omitempty"`
+	Tier                int               `yaml:"tier,omitempty"`                // 1 | 2 cần on-call, 3 (hoặc trống) thì không
+	DataClassification  string            `yaml:"data_classification,omitempty"` // public | internal (mặc định) | pii
 }
 
 // GeneratorSourceDto - DTO không chứa source_id
 type GeneratorSourceDto struct {
-	AppName             string
-	ProgrammingLanguage string
-	Framework           string
-	Module              string
-	Members             []string
+	AppName             string            `yaml:"app_name,omitempty"`
+	Owner               string            `yaml:"owner,omitempty"`
+	Kind                string            `yaml:"kind,omitempty"`
+	ProgrammingLanguage string            `yaml:"programming_language,omitempty"`
+	Framework           string            `yaml:"framework,omitempty"`
+	Module              string            `yaml:"module,omitempty"`
+	Team                string            `yaml:"team,omitempty"`
+	CostCenter          string            `yaml:"cost_center,omitempty"`
+	Labels              map[string]string `yaml:"labels,omitempty"`
+	Tier                int               `yaml:"tier,omitempty"`
+	DataClassification  string            `yaml:"data_classification,omitempty"`
+	Members             []string          `yaml:"members,omitempty"`
+	Visibility          string            `yaml:"visibility,omitempty"`
+	Branch              string            `yaml:"branch,omitempty"`
+	Environments        []Environment     `yaml:"environments,omitempty"`
+	Deploy              Deploy            `yaml:"deploy,omitempty"`
+	Schedule            string            `yaml:"schedule,omitempty"`
+	Quality             Quality           `yaml:"quality,omitempty"`
+	Config              []ConfigVar       `yaml:"config,omitempty"`
+	SLO                 SLO               `yaml:"slo,omitempty"`
+	DocsLanguage        string            `yaml:"docs_language,omitempty"`
+	PublishAsTemplate   bool              `yaml:"publish_as_template,omitempty"`
+	// Hostname reserve qua dns backend (rỗng khi không cấu hình)
+	Hostname string `yaml:"hostname,omitempty"`
+	// Manifest được ghi vào .jupiter/manifest.yaml (nil khi không generate từ source.yml)
+	Manifest *Manifest `yaml:"manifest,omitempty"`
 }
 
 func main() {
-	if len(os.Args) < 2 {
-		fmt.Println("Usage: go run generate_source.go <path-to-service-folder>")
-		fmt.Println("Example: go run generate_source.go sources-service/sample")
-		os.Exit(1)
+	// Che token khỏi mọi output (log, error, command được echo, output của subprocess)
+	if err := installRedactingOutput(); err != nil {
+		fmt.Printf("⚠️ Failed to install output redaction: %v\n", err)
+	}
+	defer flushOutput()
+	// Usage report opt-in (telemetry:), exitWithError tự gửi trước os.Exit
+	defer finishTelemetry(nil)
+
+	// Option ẩn cho reliability test: inject lỗi push / API / generation (không có trong usage)
+	args, faultSpec := extractFaultInjectFlag(os.Args[1:])
+	os.Args = append(os.Args[:1], args...)
+	if faultSpec != "" {
+		spec, err := parseFaultSpec(faultSpec)
+		if err != nil {
+			exitWithError(withCode(ErrUsage, fmt.Errorf("invalid --fault-inject: %w", err)))
+		}
+		faultInjector = newFaultExecutor(commandExecutor, spec)
+		commandExecutor = faultInjector
 	}
 
-	servicePath := os.Args[1]
-	sourceFile := filepath.Join(servicePath, "source.yml")
+	// Token cho gh/git: PAT có sẵn trong env hoặc OIDC exchange
+	if cfg, err := loadRegistryConfig(registryConfigFile); err == nil {
+		if err := setupAuth(cfg.Auth); err != nil {
+			exitWithError(err)
+		}
+		registerSubscribers(cfg)
+		startTelemetry(cfg.Telemetry, telemetryCommand(os.Args[1:]))
+		setupRunLogs(cfg.RunLogs)
+		if err := setupArtifactStore(cfg.Artifacts); err != nil {
+			exitWithError(withCode(ErrUsage, err))
+		}
+		// Cache lookup GitHub chỉ đọc cho cả process (batch / rollout / serve)
+		if !cfg.APICache.Disabled {
+			cache, err := newCachingExecutor(commandExecutor, cfg.APICache)
+			if err != nil {
+				exitWithError(withCode(ErrUsage, err))
+			}
+			apiCache, commandExecutor = cache, cache
+		}
+	}
+
+	// Subcommand: go run ./scripts <command> [args]
+	if len(os.Args) > 1 {
+		if command, ok := subcommands[os.Args[1]]; ok {
+			if err := command(os.Args[2:]); err != nil {
+				exitWithError(err)
+			}
+			return
+		}
+	}
+
+	dryRun := flag.Bool("dry-run", false, "validate source.yml and print the plan without provisioning")
+	confirm := flag.String("confirm", "", "repeat the app name to confirm destructive actions (force push)")
+	flag.Parse()
+
+	if flag.NArg() < 1 {
+		fmt.Println("Usage: go run ./scripts [--dry-run] [--confirm <app-name>] <path-to-service-folder | - | https://...>")
+		fmt.Println("Example: go run ./scripts sources-service/sample")
+		fmt.Println("         cat source.yml | go run ./scripts -")
+		flushOutput()
+		os.Exit(exitCodes[ErrUsage])
+	}
+
+	servicePath := flag.Arg(0)
+
+	// Đọc cấu hình chung của registry
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		exitWithError(fmt.Errorf("error loading registry config: %w", err))
+	}
+
+	// "-" (stdin) hoặc https:// URL: source.yml được ghi vào thư mục tạm, không cần file trong registry checkout
+	cleanup := func() {}
+	if isRemoteSource(servicePath) {
+		if servicePath, cleanup, err = materializeSource(servicePath); err != nil {
+			exitWithError(err)
+		}
+	}
+
+	// Dry-run: chỉ validate và report, không tạo gì cả
+	if *dryRun {
+		err = runDryRun(servicePath, registryConfig)
+	} else {
+		batchSize, convErr := strconv.Atoi(os.Getenv("JUPITER_BATCH_SIZE"))
+		if convErr != nil {
+			batchSize = 1
+		}
+		err = provisionService(servicePath, registryConfig, batchSize, parseConfirm(*confirm))
+	}
+	// exitWithError gọi os.Exit, dọn thư mục tạm trước
+	cleanup()
+	if err != nil {
+		exitWithError(err)
+	}
+	if !*dryRun {
+		fmt.Println("✅ Service generated and pushed successfully!")
+	}
+}
+
+// provisionService chạy toàn bộ flow cho một service: validate -> approval -> generate -> push -> state.
+// confirmed là các tên service người chạy đã xác nhận cho action phá huỷ (--confirm / confirmation token).
+func provisionService(servicePath string, registryConfig RegistryConfig, batchSize int, confirmed []string) error {
+	config, err := loadSourceConfig(servicePath)
+	if err != nil {
+		return err
+	}
+	config = resolveSourceConfig(config, registryConfig)
+
+	// Output của mọi subprocess phía sau được gắn [service] và lưu vào run directory:
+	// pipeline chạy command qua ex thay vì commandExecutor
+	ex := newServiceExecutor(commandExecutor, config.Name)
+	defer storeServiceArtifacts(config.Name)
+
+	// Convert to DTO (bỏ qua source_id)
+	dto := toGeneratorSourceDto(config, registryConfig)
+
+	// Print DTO
+	printDTO(dto)
+
+	// Lock theo source_id: 2 run chồng nhau không được cùng force push một repo
+	lockKey := config.SourceID
+	if lockKey == "" {
+		lockKey = config.Name
+	}
+	release, err := acquireServiceLock(lockKey, registryConfig.Locking)
+	if err != nil {
+		return err
+	}
+	defer release()
+
+	// Mọi thao tác GitHub phía sau dùng credential của tenant sở hữu service
+	return withTenantCredentials(registryConfig.tenantFor(dto.Team), func() error {
+		return provisionLoadedService(ex, servicePath, config, dto, registryConfig, batchSize, confirmed)
+	})
+}
+
+func provisionLoadedService(ex Executor, servicePath string, config SourceConfig, dto GeneratorSourceDto, registryConfig RegistryConfig, batchSize int, confirmed []string) error {
+	// Validate trước khi provisioning
+	validation := validateService(ex, servicePath, config, registryConfig)
+	for _, w := range validation.Warnings {
+		fmt.Printf("⚠️ %s\n", w)
+	}
+	if !validation.ok() {
+		fmt.Println("❌ Invalid source.yml:")
+		for _, p := range validation.Problems {
+			fmt.Printf("  - %s\n", p)
+		}
+		code := validation.Code
+		if code == "" {
+			code = ErrValidationFailed
+		}
+		if code == ErrGitHubAPI {
+			return withCode(code, fmt.Errorf("could not validate source.yml: %s", servicePath))
+		}
+		return withCode(code, fmt.Errorf("invalid source.yml: %s", servicePath))
+	}
+
+	// oci.mode only: không có repo GitHub, bỏ qua mọi bước gọi GitHub (môi trường air-gapped)
+	if err := registryConfig.OCI.validate(); err != nil {
+		return err
+	}
+	artifactOnly := registryConfig.OCI.only()
+
+	if !artifactOnly {
+		// Repo trùng tên không do registry quản lý: fail / đổi tên / tiếp quản theo validation.naming.on_collision
+		if err := resolveNameCollision(ex, config, &dto, registryConfig.Validation.Naming, confirmed); err != nil {
+			return err
+		}
+
+		// Kiểm tra scope của token trước mọi thao tác ghi, thay vì 403 giữa chừng khi repo đã được tạo
+		if err := verifyTokenScopes(ex, requiredScopes(dto, registryConfig)); err != nil {
+			return err
+		}
+	}
+
+	// Incremental: bỏ qua service có source.yml và templates không đổi so với lần generate trước
+	manifest, err := buildManifest(servicePath, config, registryConfig)
+	if err != nil {
+		return err
+	}
+	dto.Manifest = &manifest
+	if os.Getenv("JUPITER_FORCE") != "true" {
+		if artifactOnly {
+			if entry, err := serviceStateEntry(dto.AppName); err == nil && entry.Artifact.upToDate(manifest) {
+				fmt.Printf("⏭️  %s is up to date (artifact %s), skipping\n", dto.AppName, entry.Artifact.Ref)
+				return nil
+			}
+		} else if previous, ok := readRemoteManifest(ex, dto.Owner, dto.AppName); ok && previous.upToDate(manifest) {
+			fmt.Printf("⏭️  %s is up to date (source %s, templates %s), skipping\n", dto.AppName, manifest.SourceDigest[:19], manifest.TemplateVersion)
+			return nil
+		}
+	}
+
+	// Owner đổi so với state: transfer repo cũ thay vì tạo repo trùng
+	fromRepo, moving := previousRepo(ex, dto)
+	moving = moving && !artifactOnly
+
+	// Repo đã archive phải restore trước, không regenerate đè lên
+	if entry, err := serviceStateEntry(dto.AppName); err == nil && entry.ArchivedAt != "" {
+		return withCode(ErrUsage, fmt.Errorf("%s was archived at %s, restore it first (go run ./scripts restore %s)", dto.AppName, entry.ArchivedAt, dto.AppName))
+	}
+
+	// Force push ghi đè history của repo có sẵn: phải --confirm đúng tên service
+	forcePush := registryConfig.Git.forcePushes() && !artifactOnly && (moving || repoExists(ex, dto.Owner, dto.AppName))
+	if forcePush {
+		if err := checkConfirmation(registryConfig.Destructive, actionForcePush, dto.AppName, confirmed); err != nil {
+			return err
+		}
+	}
+
+	// Approval gating cho các action nhạy cảm
+	actions := sensitiveActions(dto, registryConfig.ApprovalPolicy, forcePush, batchSize)
+	if err := checkApprovals(ex, actions, config.Approvals, registryConfig.ApprovalPolicy); err != nil {
+		return withCode(ErrApprovalRequired, fmt.Errorf("refusing to provision %s: %w", dto.AppName, err))
+	}
+
+	if moving {
+		if err := transferRepository(ex, fromRepo, dto); err != nil {
+			return err
+		}
+	}
+
+	// Process based on programming language
+	processErr := processService(ex, dto, registryConfig)
+
+	// Snapshot OCI của đúng nội dung vừa generate (oci.registry), digest được ghi vào state
+	var artifact *ScaffoldArtifact
+	if processErr == nil && registryConfig.OCI.enabled() {
+		artifact, processErr = publishScaffold(ex, dto, manifest, registryConfig.OCI)
+	}
 
-	// Kiểm tra file phải là source.yml
-	if filepath.Base(sourceFile) != "source.yml" {
-		fmt.Printf("❌ Skipped: File must be named 'source.yml', got: %s\n", filepath.Base(sourceFile))
-		os.Exit(0)
+	if processErr != nil {
+		if err := recordGenerationFailure(dto.AppName, ServiceState{
+			SourceID: config.SourceID,
+			Repo:     fmt.Sprintf("%s/%s", dto.Owner, dto.AppName),
+			Origin:   "provisioned",
+		}); err != nil {
+			fmt.Printf("⚠️ Failed to record state: %v\n", err)
+		}
+		events.Publish(Event{Type: EventServiceFailed, Service: dto.AppName, Repo: fmt.Sprintf("%s/%s", dto.Owner, dto.AppName),
+			TemplateVersion: manifest.TemplateVersion, Err: processErr, DTO: &dto})
+		return fmt.Errorf("error processing service: %w", processErr)
+	}
+
+	// Service tier 1/2 phải có người trực ngay khi repo tồn tại
+	var onCallID string
+	if registryConfig.OnCall.enabledFor(dto.Tier) {
+		fmt.Printf("📟 Provisioning %s on-call service...\n", registryConfig.OnCall.Provider)
+		id, err := provisionOnCall(dto, registryConfig.OnCall)
+		if err != nil {
+			if registryConfig.OnCall.Required {
+				return withCode(ErrInternal, fmt.Errorf("failed to provision on-call service: %w", err))
+			}
+			fmt.Printf("  ⚠️ On-call provisioning failed: %v\n", err)
+		}
+		onCallID = id
+	}
+
+	// Đọc lại metadata canonical của repo (ID, default branch, clone URL) để lưu vào state
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	var previous *RepoMetadata
+	if entry, err := serviceStateEntry(dto.AppName); err == nil {
+		previous = entry.Repository
+	}
+	repository, commitSHA := previous, ""
+	if !artifactOnly {
+		repository = enrichRepoMetadata(ex, fullName, previous)
+		commitSHA = remoteHeadSHA(ex, dto)
 	}
 
+	// Ghi state entry cho service vừa provisioning
+	if err := recordServiceState(dto.AppName, ServiceState{
+		SourceID:        config.SourceID,
+		Repo:            fullName,
+		Origin:          "provisioned",
+		LastStatus:      "success",
+		TemplateVersion: manifest.TemplateVersion,
+		Hostname:        dto.Hostname,
+		OnCallProvider:  registryConfig.OnCall.Provider,
+		OnCallServiceID: onCallID,
+		Repository:      repository,
+		Artifact:        artifact,
+	}); err != nil {
+		fmt.Printf("⚠️ Failed to record state: %v\n", err)
+	}
+	events.Publish(Event{Type: EventServiceGenerated, Service: dto.AppName, Repo: fullName,
+		TemplateVersion: manifest.TemplateVersion, CommitSHA: commitSHA, DTO: &dto})
+
+	return nil
+}
+
+// loadSourceConfig đọc và parse <servicePath>/source.yml
+func loadSourceConfig(servicePath string) (SourceConfig, error) {
+	var config SourceConfig
+	sourceFile := filepath.Join(servicePath, "source.yml")
+
 	// Đọc file
 	data, err := os.ReadFile(sourceFile)
 	if err != nil {
-		fmt.Printf("❌ Error reading file %s: %v\n", sourceFile, err)
-		os.Exit(1)
+		return config, withCode(ErrSourceNotFound, fmt.Errorf("error reading file %s: %w", sourceFile, err))
 	}
 
 	// Parse YAML
-	var config SourceConfig
 	if err := yaml.Unmarshal(data, &config); err != nil {
-		fmt.Printf("❌ Error parsing YAML: %v\n", err)
-		os.Exit(1)
+		return config, withCode(ErrYAMLInvalid, fmt.Errorf("error parsing YAML %s: %w", sourceFile, err))
 	}
+	return config, nil
+}
 
-	// Convert to DTO (bỏ qua source_id)
-	dto := GeneratorSourceDto{
+func toGeneratorSourceDto(config SourceConfig, registryConfig RegistryConfig) GeneratorSourceDto {
+	return GeneratorSourceDto{
 		AppName:             config.Name,
+		Owner:               registryConfig.ownerFor(config),
+		Kind:                config.Metadata.Kind,
 		ProgrammingLanguage: config.Metadata.ProgrammingLanguage,
 		Framework:           config.Metadata.Framework,
 		Module:              config.Metadata.Module,
+		Team:                config.Metadata.Team,
+		CostCenter:          config.Metadata.CostCenter,
+		Labels:              config.Metadata.Labels,
+		Tier:                config.Metadata.Tier,
+		DataClassification:  config.Metadata.DataClassification,
 		Members:             config.Members,
+		Visibility:          config.Visibility,
+		Branch:              config.Branch,
+		Environments:        config.Environments,
+		Deploy:              config.Deploy,
+		Schedule:            config.Schedule,
+		Quality:             config.Quality,
+		Config:              config.Config,
+		SLO:                 config.SLO,
+		DocsLanguage:        config.DocsLanguage,
+		PublishAsTemplate:   config.PublishAsTemplate,
+		Hostname:            registryConfig.DNS.hostname(config.Name, config.Metadata.Kind),
 	}
-
-	// Print DTO
-	printDTO(dto)
-
-	// Process based on programming language
-	if err := processService(dto); err != nil {
-		fmt.Printf("❌ Error processing service: %v\n", err)
-		os.Exit(1)
-	}
-
-	fmt.Println("✅ Service generated and pushed successfully!")
 }
 
 func printDTO(dto GeneratorSourceDto) {
@@ -90,26 +424,38 @@ func printDTO(dto GeneratorSourceDto) {
 	fmt.Println("        GENERATOR SOURCE DTO")
 	fmt.Println("========================================")
 	fmt.Printf("AppName:             %s\n", dto.AppName)
+	fmt.Printf("Owner:               %s\n", dto.Owner)
 	fmt.Printf("ProgrammingLanguage: %s\n", dto.ProgrammingLanguage)
 	fmt.Printf("Framework:           %s\n", dto.Framework)
 	fmt.Printf("Module:              %s\n", dto.Module)
+	fmt.Printf("Team:                %s\n", dto.Team)
 	fmt.Printf("Members:             %v\n", dto.Members)
 	fmt.Println("========================================")
 }
 
-func processService(dto GeneratorSourceDto) error {
+func processService(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	switch dto.Kind {
+	case "", "service":
+	case "frontend":
+		return processFrontend(ex, dto, registryConfig)
+	case "library", "cli", "worker", "cronjob":
+		return processTemplateKind(ex, dto, registryConfig)
+	default:
+		return fmt.Errorf("unsupported kind: %s", dto.Kind)
+	}
+
 	switch dto.ProgrammingLanguage {
 	case "golang":
-		return processGolang(dto)
+		return processGolang(ex, dto, registryConfig)
 	case "nodejs":
-		return processNodeJS(dto)
+		return processNodeJS(ex, dto, registryConfig)
 	default:
 		return fmt.Errorf("unsupported programming language: %s", dto.ProgrammingLanguage)
 	}
 }
 
 // getUranusBinary tìm uranus binary phù hợp với OS/Arch hiện tại
-func getUranusBinary() (string, error) {
+func getUranusBinary(ex Executor) (string, error) {
 	// Tìm thư mục dist (relative to working directory)
 	distDir := "dist"
 
@@ -132,7 +478,7 @@ func getUranusBinary() (string, error) {
 
 	// Nếu không tìm thấy binary local, fallback to go install
 	fmt.Printf("⚠️  Local binary not found for %s-%s, using go install...\n", goos, goarch)
-	if err := runCommand("go", "install", "github.com/tqhuy-dev/xgen-uranus@latest"); err != nil {
+	if err := runCommand(ex, "go", "install", "github.com/tqhuy-dev/xgen-uranus@latest"); err != nil {
 		return "", fmt.Errorf("failed to install uranus CLI: %w", err)
 	}
 
@@ -140,77 +486,330 @@ func getUranusBinary() (string, error) {
 	return "uranus", nil
 }
 
-func processGolang(dto GeneratorSourceDto) error {
-	fmt.Println("\n🔧 Processing Golang service...")
-
+// generateWithUranus generate app Go bằng uranus CLI (framework mặc định)
+func generateWithUranus(ex Executor, dto GeneratorSourceDto, generator Generator) error {
 	// Step 1: Tìm uranus binary
 	fmt.Println("📦 Finding uranus CLI...")
-	uranusBin, err := getUranusBinary()
+	uranusBin, err := getUranusBinary(ex)
 	if err != nil {
 		return fmt.Errorf("failed to get uranus binary: %w", err)
 	}
 
-	// Step 2: Generate app using uranus
+	// Step 2: Generate app using uranus (flags hoặc JSON contract theo generator.invocation)
 	fmt.Printf("🚀 Generating app: %s\n", dto.AppName)
-	if err := runCommand(uranusBin, "generate", "app",
-		"--name", dto.AppName, "--module" , fmt.Sprintf("github.com/tqhuy-dev/%s" ,dto.AppName), "--skip_init=true"); err != nil {
+	contract, err := newGeneratorContract(dto, generator)
+	if err != nil {
+		return fmt.Errorf("failed to build generator contract: %w", err)
+	}
+	if err := invokeGenerator(uranusBin, contract, generator); err != nil {
 		return fmt.Errorf("failed to generate app: %w", err)
 	}
+	return nil
+}
+
+func processGolang(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	fmt.Println("\n🔧 Processing Golang service...")
+
+	// Generate app theo metadata.framework
+	if err := generateGolangApp(ex, dto, registryConfig); err != nil {
+		return withCode(ErrGeneratorFailed, err)
+	}
+
+	return provisionRepository(ex, dto, registryConfig)
+}
+
+func processNodeJS(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	fmt.Println("\n🔧 Processing NodeJS service...")
+
+	// Generate app theo metadata.framework (nestjs, express, fastify)
+	if err := generateNodeApp(ex, dto, registryConfig); err != nil {
+		return withCode(ErrGeneratorFailed, err)
+	}
+
+	return provisionRepository(ex, dto, registryConfig)
+}
+
+// provisionRepository là phần chung sau khi đã generate code: bổ sung file, tạo repo, push
+func provisionRepository(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	if err := injectTruncation(ex, dto.AppName); err != nil {
+		return err
+	}
+
+	// Step 1: Thêm issue/PR templates vào initial commit
+	fmt.Println("📝 Rendering GitHub issue/PR templates...")
+	if err := writeGithubTemplates(dto.AppName, dto, registryConfig.GithubTemplates); err != nil {
+		return fmt.Errorf("failed to render github templates: %w", err)
+	}
+
+	// Step 2: SECURITY.md và CONTRIBUTING.md theo policy của org
+	fmt.Println("📝 Rendering SECURITY.md and CONTRIBUTING.md...")
+	if err := writeCommunityFiles(dto.AppName, dto, registryConfig.CommunityFiles, registryConfig.Server); err != nil {
+		return fmt.Errorf("failed to render community files: %w", err)
+	}
+
+	// Step 3: Ownership tags cho Terraform / Kubernetes / Helm
+	if err := writeOwnershipTags(dto.AppName, dto, registryConfig.OwnershipTags); err != nil {
+		return fmt.Errorf("failed to write ownership tags: %w", err)
+	}
+
+	// Step 4: CI / Terraform / runtime config theo cloud target
+	if err := writeCloudScaffold(dto.AppName, dto, registryConfig.CloudTemplates); err != nil {
+		return fmt.Errorf("failed to render cloud scaffolding: %w", err)
+	}
+
+	// Step 5: Config contract (env schema + code load env) từ block config:
+	if err := writeConfigContract(ex, dto.AppName, dto, registryConfig.FrameworkTemplates); err != nil {
+		return withCode(ErrGeneratorFailed, fmt.Errorf("failed to generate config contract: %w", err))
+	}
+
+	// Step 6: SLO dashboard + Prometheus rules (repo service hoặc monitoring repo)
+	if err := writeSLOFiles(ex, dto.AppName, dto, registryConfig.SLO); err != nil {
+		return fmt.Errorf("failed to write SLO files: %w", err)
+	}
+
+	// Step 7: Control bắt buộc cho service PII (Terraform mã hoá, audit middleware, policy checks)
+	if err := writeDataProtection(dto.AppName, dto, registryConfig.FrameworkTemplates); err != nil {
+		return fmt.Errorf("failed to render PII controls: %w", err)
+	}
+
+	// Step 8: docs/runbook.md (alert handling, dashboards, escalation)
+	if err := writeRunbook(dto.AppName, dto, registryConfig); err != nil {
+		return fmt.Errorf("failed to render runbook: %w", err)
+	}
+
+	// Step 9: Marker manifest cho incremental regeneration
+	if dto.Manifest != nil {
+		if err := writeManifest(dto.AppName, *dto.Manifest); err != nil {
+			return fmt.Errorf("failed to write manifest: %w", err)
+		}
+	}
+
+	// Step 10: Guardrails trên output generated, fail trước khi tạo repo / push
+	if err := enforceGuardrails(dto.AppName, registryConfig.Guardrails); err != nil {
+		return err
+	}
+
+	// License của dependency trong go.mod / package.json theo licenses: (allow / deny)
+	if err := enforceLicensePolicy(ex, dto.AppName, dto, registryConfig.Licenses); err != nil {
+		return err
+	}
+
+	// oci.mode only: scaffold được giao qua OCI artifact, không tạo repo / push
+	if registryConfig.OCI.only() {
+		fmt.Println("⏭️  oci.mode is only, skipping GitHub repository and push")
+		return nil
+	}
 
-	// Step 3: Create GitHub repository
+	// Step 11: Create GitHub repository
 	fmt.Printf("📁 Creating GitHub repository: %s\n", dto.AppName)
-	if err := createGitHubRepo(dto.AppName); err != nil {
-		return fmt.Errorf("failed to create GitHub repo: %w", err)
+	if err := createGitHubRepo(ex, dto.Owner, dto.AppName, dto.Visibility); err != nil {
+		return withCode(ErrRepoCreateFailed, fmt.Errorf("failed to create GitHub repo: %w", err))
 	}
 
-	// Step 4: Push code to repository
+	// Step 12: Apply settings profile cho repo mới
+	fmt.Println("🛡️  Applying repository settings...")
+	if err := applyRepoSettings(ex, dto.Owner, dto.AppName, registryConfig.RepoSettings); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to apply repo settings: %w", err))
+	}
+	if dto.PublishAsTemplate {
+		fmt.Println("🧬 Marking repository as a template repository...")
+		if err := markTemplateRepo(ex, dto.Owner, dto.AppName); err != nil {
+			return withCode(ErrGitHubAPI, err)
+		}
+	}
+
+	// Step 13: Tạo deployment environments (dev/staging/prod) nếu service có khai báo
+	if len(dto.Environments) > 0 {
+		fmt.Println("🌐 Creating deployment environments...")
+		if err := createEnvironments(ex, dto.Owner, dto.AppName, dto.Environments); err != nil {
+			return withCode(ErrGitHubAPI, fmt.Errorf("failed to create environments: %w", err))
+		}
+	}
+
+	// Step 14: Deploy key cho các hệ thống pull repo non-interactive
+	if registryConfig.DeployKeys.Enabled {
+		fmt.Println("🔑 Provisioning deploy key...")
+		if err := provisionDeployKey(ex, dto.Owner, dto.AppName, registryConfig.DeployKeys); err != nil {
+			return withCode(ErrGitHubAPI, fmt.Errorf("failed to provision deploy key: %w", err))
+		}
+	}
+
+	// Step 15: Publish target cho library (npm token / Go proxy allowlist)
+	if dto.Kind == "library" {
+		fmt.Println("📦 Configuring package publishing...")
+		if err := setupLibraryPublishing(ex, dto, registryConfig.Publishing); err != nil {
+			return withCode(ErrGitHubAPI, fmt.Errorf("failed to configure publishing: %w", err))
+		}
+	}
+
+	// Step 16: Webhook trỏ về registry (chỉ khi registry chạy server mode)
+	if registryConfig.Server.PublicURL != "" {
+		fmt.Println("🪝 Registering registry webhook...")
+		if err := registerRegistryWebhook(ex, dto.Owner, dto.AppName, registryConfig.Server); err != nil {
+			return withCode(ErrGitHubAPI, fmt.Errorf("failed to register webhook: %w", err))
+		}
+	}
+
+	// Step 17: Reserve hostname (đã được render vào config của repo)
+	if dto.Hostname != "" {
+		fmt.Printf("🌍 Reserving hostname %s...\n", dto.Hostname)
+		if err := reserveHostname(ex, dto, registryConfig.DNS); err != nil {
+			if registryConfig.DNS.Required {
+				return withCode(ErrInternal, fmt.Errorf("failed to reserve hostname: %w", err))
+			}
+			fmt.Printf("  ⚠️ Hostname reservation failed: %v\n", err)
+		}
+	}
+
+	// Step 18: Error tracking project + DSN secret + SDK init (trước push)
+	if registryConfig.ErrorTracking.enabledFor(dto) {
+		fmt.Println("🐞 Provisioning error tracking project...")
+		if err := provisionErrorTracking(ex, dto.AppName, dto, registryConfig.ErrorTracking, registryConfig.FrameworkTemplates); err != nil {
+			if registryConfig.ErrorTracking.Required {
+				return withCode(ErrInternal, fmt.Errorf("failed to provision error tracking: %w", err))
+			}
+			fmt.Printf("  ⚠️ Error tracking provisioning failed: %v\n", err)
+		}
+	}
+
+	// Step 19: SonarQube project + token secret + workflow phân tích (quality.sonar: true)
+	if dto.Quality.Sonar {
+		fmt.Println("🔍 Bootstrapping SonarQube project...")
+		if err := provisionSonarProject(ex, dto.AppName, dto, registryConfig.Sonar, registryConfig.FrameworkTemplates); err != nil {
+			if registryConfig.Sonar.Required {
+				return withCode(ErrInternal, fmt.Errorf("failed to bootstrap sonar project: %w", err))
+			}
+			fmt.Printf("  ⚠️ SonarQube bootstrap failed: %v\n", err)
+		}
+	}
+
+	// Step 20: Container image repository cho Docker workflow (ghcr workflow được render trước push)
+	if registryConfig.ContainerRegistry.Type != "" && needsContainerRepository(dto.AppName) {
+		fmt.Println("🐳 Provisioning container image repository...")
+		if err := provisionContainerRepository(ex, dto.AppName, dto, registryConfig.ContainerRegistry, registryConfig.FrameworkTemplates); err != nil {
+			if registryConfig.ContainerRegistry.Required {
+				return withCode(ErrGitHubAPI, fmt.Errorf("failed to provision container repository: %w", err))
+			}
+			fmt.Printf("  ⚠️ Container repository failed: %v\n", err)
+		}
+	}
+
+	// Provenance attestation: sau mọi bước ghi file, ngay trước push để digest khớp với commit
+	uploadProvenance := func() error { return nil }
+	if registryConfig.Provenance.Enabled {
+		fmt.Println("🔏 Generating provenance attestation...")
+		if err := registryConfig.Provenance.validate(); err != nil {
+			return withCode(ErrUsage, err)
+		}
+		upload, err := attachProvenance(ex, dto.AppName, dto, registryConfig.Provenance)
+		if err != nil {
+			return withCode(ErrInternal, fmt.Errorf("failed to generate provenance: %w", err))
+		}
+		uploadProvenance = upload
+	}
+
+	// Step 21: Push code to repository
 	fmt.Println("📤 Pushing code to repository...")
-	if err := pushToRepo(dto.AppName); err != nil {
-		return fmt.Errorf("failed to push to repo: %w", err)
+	if err := pushToRepo(ex, dto, registryConfig.Git); err != nil {
+		err = withCode(ErrGitFailed, fmt.Errorf("failed to push to repo: %w", err))
+		events.Publish(Event{Type: EventPushFailed, Service: dto.AppName, Repo: fmt.Sprintf("%s/%s", dto.Owner, dto.AppName), Err: err, DTO: &dto})
+		return err
+	}
+	if err := uploadProvenance(); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to attach provenance to release: %w", err))
+	}
+
+	// Step 22: Mirror initial push sang backup remote (nếu có cấu hình)
+	if registryConfig.Mirror.Type != "" {
+		fmt.Println("🪞 Mirroring to backup remote...")
+		if err := mirrorRepository(ex, dto.AppName, dto, registryConfig.Mirror); err != nil {
+			if registryConfig.Mirror.Required {
+				return withCode(ErrGitFailed, fmt.Errorf("failed to mirror repo: %w", err))
+			}
+			fmt.Printf("  ⚠️ Mirror failed: %v\n", err)
+		}
+	}
+
+	// Step 23: Long-lived branches (develop, release) + default branch, trước rulesets để ruleset áp được lên chúng
+	if len(registryConfig.Git.Branches) > 0 {
+		fmt.Println("🌿 Creating additional branches...")
+		if err := createBranches(ex, dto, registryConfig.Git.Branches); err != nil {
+			return withCode(ErrGitHubAPI, err)
+		}
+	}
+
+	// Step 24: Apply rulesets sau khi push (tránh bị chặn force push lần đầu)
+	fmt.Println("📜 Applying repository rulesets...")
+	if err := applyRulesets(ex, dto.Owner, dto.AppName, registryConfig.Rulesets); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to apply rulesets: %w", err))
 	}
 
 	return nil
 }
 
-func processNodeJS(dto GeneratorSourceDto) error {
-	// TODO: Implement NodeJS processing (NestJS, Express, etc.)
-	fmt.Println("⚠️ NodeJS processing not implemented yet")
-	return nil
+func runCommand(ex Executor, name string, args ...string) error {
+	fmt.Printf("%s  → Running: %s %s\n", logPrefix(ex), name, strings.Join(args, " "))
+	_, err := ex.Run(Command{Name: name, Args: args})
+	return err
 }
 
-func runCommand(name string, args ...string) error {
-	fmt.Printf("  → Running: %s %s\n", name, strings.Join(args, " "))
-	cmd := exec.Command(name, args...)
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+func runCommandOutput(ex Executor, name string, args ...string) (string, error) {
+	fmt.Printf("%s  → Running: %s %s\n", logPrefix(ex), name, strings.Join(args, " "))
+	out, err := ex.Run(Command{Name: name, Args: args, CaptureOutput: true})
+	return strings.TrimSpace(out), err
 }
 
-func runCommandInDir(dir string, name string, args ...string) error {
-	fmt.Printf("  → Running in %s: %s %s\n", dir, name, strings.Join(args, " "))
-	cmd := exec.Command(name, args...)
-	cmd.Dir = dir
-	cmd.Stdout = os.Stdout
-	cmd.Stderr = os.Stderr
-	return cmd.Run()
+func runCommandOutputWithInput(ex Executor, input []byte, name string, args ...string) (string, error) {
+	fmt.Printf("%s  → Running: %s %s\n", logPrefix(ex), name, strings.Join(args, " "))
+	out, err := ex.Run(Command{Name: name, Args: args, Stdin: input, CaptureOutput: true})
+	return strings.TrimSpace(out), err
 }
 
-func createGitHubRepo(repoName string) error {
+func runCommandWithInput(ex Executor, input []byte, name string, args ...string) error {
+	fmt.Printf("%s  → Running: %s %s\n", logPrefix(ex), name, strings.Join(args, " "))
+	_, err := ex.Run(Command{Name: name, Args: args, Stdin: input})
+	return err
+}
+
+func runCommandInDir(ex Executor, dir string, name string, args ...string) error {
+	fmt.Printf("%s  → Running in %s: %s %s\n", logPrefix(ex), dir, name, strings.Join(args, " "))
+	_, err := ex.Run(Command{Dir: dir, Name: name, Args: args})
+	return err
+}
+
+func runCommandOutputInDir(ex Executor, dir string, name string, args ...string) (string, error) {
+	fmt.Printf("%s  → Running in %s: %s %s\n", logPrefix(ex), dir, name, strings.Join(args, " "))
+	out, err := ex.Run(Command{Dir: dir, Name: name, Args: args, CaptureOutput: true})
+	return strings.TrimSpace(out), err
+}
+
+func createGitHubRepo(ex Executor, owner, repoName string, visibility string) error {
+	if visibility == "" {
+		visibility = "private"
+	}
+
 	// Sử dụng gh CLI để tạo repo (đã có sẵn trên GitHub Actions)
 	// GH_TOKEN environment variable cần được set
-	err := runCommand("gh", "repo", "create",
-		fmt.Sprintf("tqhuy-dev/%s", repoName),
-		"--private",
+	err := runCommand(ex, "gh", "repo", "create",
+		fmt.Sprintf("%s/%s", owner, repoName),
+		"--"+visibility,
 		"--confirm")
 
 	if err != nil {
-		// Repo có thể đã tồn tại, không phải lỗi critical
-		fmt.Printf("  ⚠️ Note: %v (repo might already exist)\n", err)
+		// Repo đã tồn tại thì không phải lỗi critical (re-provision)
+		if repoExists(ex, owner, repoName) {
+			fmt.Printf("  ⚠️ Note: %v (repo already exists)\n", err)
+			return nil
+		}
+		return withCode(ErrRepoCreateFailed, err)
 	}
+	events.Publish(Event{Type: EventRepoCreated, Service: repoName, Repo: fmt.Sprintf("%s/%s", owner, repoName)})
 	return nil
 }
 
-func pushToRepo(appName string) error {
+func pushToRepo(ex Executor, dto GeneratorSourceDto, gitConfig GitConfig) error {
+	owner, appName := dto.Owner, dto.AppName
+
 	// Generated code nằm trong folder có tên = appName
 	repoDir := appName
 
@@ -219,20 +818,26 @@ func pushToRepo(appName string) error {
 		return fmt.Errorf("generated folder not found: %s", repoDir)
 	}
 
-	// Get GitHub token from environment
-	ghToken := os.Getenv("GH_TOKEN")
-	if ghToken == "" {
-		ghToken = os.Getenv("GITHUB_TOKEN")
+	// Build repo URL theo git.transport (https + token hoặc ssh)
+	repoURL, err := remoteURL(owner, appName, gitConfig)
+	if err != nil {
+		return withCode(ErrUsage, err)
 	}
 
-	repoOwner := "tqhuy-dev"
+	// Repo đã có history: commit lên trên thay vì orphan init + force push (tuỳ git.history / git.update_push)
+	if (gitConfig.history() != "squash" || gitConfig.updatePush() == "pull_request") && remoteHasBranch(ex, repoURL, dto.branch()) {
+		return pushOnHistory(ex, repoDir, repoURL, dto, gitConfig)
+	}
 
-	// Build repo URL with token for authentication
-	var repoURL string
-	if ghToken != "" {
-		repoURL = fmt.Sprintf("https://x-access-token:%s@github.com/%s/%s.git", ghToken, repoOwner, appName)
-	} else {
-		repoURL = fmt.Sprintf("https://github.com/%s/%s.git", repoOwner, appName)
+	// Team cấm push thẳng lên main: commit đầu tiên cũng đi qua review
+	switch gitConfig.initialPush() {
+	case "direct":
+	case "pull_request":
+		if !remoteHasBranch(ex, repoURL, dto.branch()) {
+			return pushBootstrapPR(ex, repoDir, repoURL, dto, gitConfig)
+		}
+	default:
+		return withCode(ErrUsage, fmt.Errorf("unsupported git.initial_push: %s", gitConfig.InitialPush))
 	}
 
 	// Git commands
@@ -246,13 +851,17 @@ func pushToRepo(appName string) error {
 		{"git", []string{"remote", "add", "origin", repoURL}},
 		{"git", []string{"add", "-A"}},
 		{"git", []string{"commit", "-m", "Initial commit from jupiter-registry"}},
-		{"git", []string{"branch", "-M", "main"}},
-		{"git", []string{"push", "-u", "origin", "main", "--force"}},
+		{"git", []string{"branch", "-M", dto.branch()}},
+		{"git", []string{"push", "-u", "origin", dto.branch(), "--force"}},
 	}
 
 	for _, cmd := range commands {
-		if err := runCommandInDir(repoDir, cmd.name, cmd.args...); err != nil {
-			return fmt.Errorf("command '%s %s' failed: %w", cmd.name, strings.Join(cmd.args, " "), err)
+		if err := runCommandInDir(ex, repoDir, cmd.name, cmd.args...); err != nil {
+			err = fmt.Errorf("command '%s %s' failed: %w", cmd.name, strings.Join(cmd.args, " "), err)
+			if cmd.args[0] == "push" {
+				return withCode(ErrPushDenied, err)
+			}
+			return err
 		}
 	}
 
@@ -0,0 +1,233 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+// inTempDir chạy test trong thư mục tạm: code generated nằm ở ./<app name> giống lúc chạy thật
+func inTempDir(t *testing.T) string {
+	t.Helper()
+	dir := t.TempDir()
+	previous, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(previous) })
+	return dir
+}
+
+// generatedApp tạo ./<app> với một file như sau bước generate
+func generatedApp(t *testing.T) GeneratorSourceDto {
+	t.Helper()
+	inTempDir(t)
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+	dto := GeneratorSourceDto{AppName: "orders", Owner: "acme"}
+	if err := writeRepoFile(dto.AppName, "main.go", []byte("package main\n")); err != nil {
+		t.Fatal(err)
+	}
+	return dto
+}
+
+// indexOf: vị trí invocation đầu tiên bắt đầu bằng prefix, -1 nếu không có
+func indexOf(invocations []string, prefix string) int {
+	for i, line := range invocations {
+		if strings.HasPrefix(line, prefix) {
+			return i
+		}
+	}
+	return -1
+}
+
+func TestPushToRepoInitialPush(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+
+	if err := pushToRepo(commandExecutor, dto, GitConfig{}); err != nil {
+		t.Fatalf("pushToRepo: %v", err)
+	}
+
+	want := []string{
+		"git init",
+		"git config user.email github-actions[bot]@users.noreply.github.com",
+		"git config user.name github-actions[bot]",
+		"git remote add origin https://github.com/acme/orders.git",
+		"git add -A",
+		"git commit -m Initial commit from jupiter-registry",
+		"git branch -M main",
+		"git push -u origin main --force",
+	}
+	if got := fake.Invocations(); !reflect.DeepEqual(got, want) {
+		t.Fatalf("invocations:\n%s\nwant:\n%s", strings.Join(got, "\n"), strings.Join(want, "\n"))
+	}
+	for _, c := range fake.Calls {
+		if c.Dir != dto.AppName {
+			t.Errorf("%s ran in %q, want %q", commandLine(c), c.Dir, dto.AppName)
+		}
+	}
+}
+
+func TestPushToRepoPushDenied(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+	fake.On("git push", FakeResponse{Err: errors.New("remote: Permission denied")})
+
+	err := pushToRepo(commandExecutor, dto, GitConfig{})
+	if errorCode(err) != ErrPushDenied {
+		t.Fatalf("error code = %s (%v), want %s", errorCode(err), err, ErrPushDenied)
+	}
+}
+
+func TestPushToRepoMissingFolder(t *testing.T) {
+	inTempDir(t)
+	fake := useFakeExecutor(t)
+
+	err := pushToRepo(commandExecutor, GeneratorSourceDto{AppName: "missing", Owner: "acme"}, GitConfig{})
+	if err == nil || !strings.Contains(err.Error(), "generated folder not found") {
+		t.Fatalf("err = %v, want generated folder not found", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Fatalf("unexpected invocations: %v", fake.Invocations())
+	}
+}
+
+func TestPushToRepoBootstrapPR(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+	fake.On("gh pr create", FakeResponse{Output: "https://github.com/acme/orders/pull/1"})
+
+	if err := pushToRepo(commandExecutor, dto, GitConfig{InitialPush: "pull_request"}); err != nil {
+		t.Fatalf("pushToRepo: %v", err)
+	}
+	got := fake.Invocations()
+	for _, prefix := range []string{
+		"git commit --allow-empty -m Initialize repository",
+		"git push -u origin main",
+		"git checkout -b bootstrap",
+		"git push -u origin bootstrap --force",
+		"gh pr create --repo acme/orders --base main --head bootstrap",
+	} {
+		if indexOf(got, prefix) < 0 {
+			t.Errorf("missing %q in:\n%s", prefix, strings.Join(got, "\n"))
+		}
+	}
+	if indexOf(got, "git push -u origin main --force") >= 0 {
+		t.Errorf("bootstrap mode must not force push the default branch")
+	}
+}
+
+func TestPushToRepoUnsupportedInitialPush(t *testing.T) {
+	dto := generatedApp(t)
+	useFakeExecutor(t)
+
+	err := pushToRepo(commandExecutor, dto, GitConfig{InitialPush: "carrier-pigeon"})
+	if errorCode(err) != ErrUsage {
+		t.Fatalf("error code = %s (%v), want %s", errorCode(err), err, ErrUsage)
+	}
+}
+
+func TestProvisionRepository(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+	enabled := true
+	registryConfig := RegistryConfig{
+		RepoSettings: RepoSettings{HasWiki: &enabled},
+		Rulesets:     []Ruleset{{Name: "protect-default-branch", BlockForcePushes: true}},
+	}
+
+	if err := provisionRepository(commandExecutor, dto, registryConfig); err != nil {
+		t.Fatalf("provisionRepository: %v", err)
+	}
+
+	got := fake.Invocations()
+	create := indexOf(got, "gh repo create acme/orders --private --confirm")
+	settings := indexOf(got, "gh api -X PATCH repos/acme/orders -F has_wiki=true")
+	push := indexOf(got, "git push -u origin main --force")
+	ruleset := indexOf(got, "gh api -X POST repos/acme/orders/rulesets --input -")
+	if create < 0 || settings < 0 || push < 0 || ruleset < 0 {
+		t.Fatalf("missing steps in:\n%s", strings.Join(got, "\n"))
+	}
+	// Rulesets sau push, nếu không thì chặn force push lần đầu
+	if !(create < settings && settings < push && push < ruleset) {
+		t.Fatalf("steps out of order (create %d, settings %d, push %d, ruleset %d):\n%s", create, settings, push, ruleset, strings.Join(got, "\n"))
+	}
+}
+
+func TestProvisionRepositoryUpdatesExistingRuleset(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+	fake.On("gh api --paginate repos/acme/orders/rulesets", FakeResponse{Output: "41\tother\n42\tprotect-default-branch"})
+
+	registryConfig := RegistryConfig{Rulesets: []Ruleset{{Name: "protect-default-branch"}}}
+	if err := provisionRepository(commandExecutor, dto, registryConfig); err != nil {
+		t.Fatalf("provisionRepository: %v", err)
+	}
+	got := fake.Invocations()
+	if indexOf(got, "gh api -X PUT repos/acme/orders/rulesets/42 --input -") < 0 {
+		t.Fatalf("expected ruleset 42 to be updated:\n%s", strings.Join(got, "\n"))
+	}
+	if indexOf(got, "gh api -X POST repos/acme/orders/rulesets") >= 0 {
+		t.Fatalf("existing ruleset must not be created again")
+	}
+}
+
+func TestProvisionRepositoryRepoCreateFailed(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+	fake.On("gh repo create", FakeResponse{Err: errors.New("HTTP 403")})
+	fake.On("gh api repos/acme/orders --jq .id", FakeResponse{Err: errors.New("HTTP 404")})
+
+	err := provisionRepository(commandExecutor, dto, RegistryConfig{})
+	if errorCode(err) != ErrRepoCreateFailed {
+		t.Fatalf("error code = %s (%v), want %s", errorCode(err), err, ErrRepoCreateFailed)
+	}
+	if indexOf(fake.Invocations(), "git push") >= 0 {
+		t.Fatalf("must not push when the repository could not be created")
+	}
+}
+
+func TestProvisionRepositoryPushFailed(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+	fake.On("git push", FakeResponse{Err: errors.New("remote rejected")})
+
+	var published []Event
+	events.Subscribe("test", func(e Event) error {
+		published = append(published, e)
+		return nil
+	}, EventPushFailed)
+
+	err := provisionRepository(commandExecutor, dto, RegistryConfig{Rulesets: []Ruleset{{Name: "protect-default-branch"}}})
+	if errorCode(err) != ErrPushDenied {
+		t.Fatalf("error code = %s (%v), want %s", errorCode(err), err, ErrPushDenied)
+	}
+	if len(published) != 1 || published[0].Repo != "acme/orders" {
+		t.Fatalf("push.failed events = %+v", published)
+	}
+	if indexOf(fake.Invocations(), "gh api -X POST repos/acme/orders/rulesets") >= 0 {
+		t.Fatalf("rulesets must not be applied after a failed push")
+	}
+}
+
+func TestProvisionRepositoryOCIOnly(t *testing.T) {
+	dto := generatedApp(t)
+	fake := useFakeExecutor(t)
+
+	if err := provisionRepository(commandExecutor, dto, RegistryConfig{OCI: OCIArtifacts{Registry: "ghcr.io/acme/scaffolds", Mode: "only"}}); err != nil {
+		t.Fatalf("provisionRepository: %v", err)
+	}
+	if len(fake.Calls) != 0 {
+		t.Fatalf("oci.mode only must not touch GitHub:\n%s", strings.Join(fake.Invocations(), "\n"))
+	}
+	if _, err := os.Stat(filepath.Join(dto.AppName, "main.go")); err != nil {
+		t.Fatal(err)
+	}
+}
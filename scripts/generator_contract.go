@@ -0,0 +1,121 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+
+	"gopkg.in/yaml.v3"
+)
+
+// generatorContractVersion tăng khi đổi field của contract theo cách không tương thích ngược
+const generatorContractVersion = 1
+
+// Generator cấu hình cách gọi generator (uranus).
+// flags chỉ truyền được --name / --module; stdin và file truyền toàn bộ GeneratorContract dạng JSON.
+type Generator struct {
+	Invocation string `yaml:"invocation"` // flags (mặc định) | stdin | file
+	// Modules: module của generator bật cho mọi app (chỉ truyền được qua contract)
+	Modules []string `yaml:"modules"`
+}
+
+func (g Generator) invocation() string {
+	if g.Invocation == "" {
+		return "flags"
+	}
+	return g.Invocation
+}
+
+// GeneratorContract là input có cấu trúc của `uranus generate app --contract <file|->`
+type GeneratorContract struct {
+	ContractVersion int `json:"contract_version"`
+	// Service là toàn bộ GeneratorSourceDto, key snake_case giống source.yml
+	Service map[string]interface{} `json:"service"`
+	Modules []string               `json:"modules"`
+	Options GeneratorOptions       `json:"options"`
+}
+
+type GeneratorOptions struct {
+	Name      string `json:"name"`
+	Module    string `json:"module"`
+	OutputDir string `json:"output_dir"`
+	SkipInit  bool   `json:"skip_init"`
+}
+
+func newGeneratorContract(dto GeneratorSourceDto, generator Generator) (GeneratorContract, error) {
+	// Round-trip qua yaml để dùng yaml tag (snake_case) của DTO và các struct lồng nhau
+	data, err := yaml.Marshal(dto)
+	if err != nil {
+		return GeneratorContract{}, err
+	}
+	service := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &service); err != nil {
+		return GeneratorContract{}, err
+	}
+	modules := generator.Modules
+	if modules == nil {
+		modules = []string{}
+	}
+	return GeneratorContract{
+		ContractVersion: generatorContractVersion,
+		Service:         service,
+		Modules:         modules,
+		Options: GeneratorOptions{
+			Name:      dto.AppName,
+			Module:    goModulePath(dto),
+			OutputDir: dto.AppName,
+			SkipInit:  true,
+		},
+	}, nil
+}
+
+// generatorInvokers: cách truyền contract cho generator binary, thêm invoker mới bằng cách đăng ký ở đây
+var generatorInvokers = map[string]func(bin string, contract GeneratorContract) error{
+	"flags": invokeWithFlags,
+	"stdin": invokeWithStdin,
+	"file":  invokeWithFile,
+}
+
+func invokeGenerator(bin string, contract GeneratorContract, generator Generator) error {
+	invoke, ok := generatorInvokers[generator.invocation()]
+	if !ok {
+		return withCode(ErrUsage, fmt.Errorf("unsupported generator.invocation: %s", generator.Invocation))
+	}
+	return invoke(bin, contract)
+}
+
+func invokeWithFlags(bin string, contract GeneratorContract) error {
+	if len(contract.Modules) > 0 {
+		return withCode(ErrUsage, fmt.Errorf("generator.modules requires generator.invocation stdin or file"))
+	}
+	return runCommand(commandExecutor, bin, "generate", "app",
+		"--name", contract.Options.Name, "--module", contract.Options.Module, fmt.Sprintf("--skip_init=%t", contract.Options.SkipInit))
+}
+
+func invokeWithStdin(bin string, contract GeneratorContract) error {
+	payload, err := json.Marshal(contract)
+	if err != nil {
+		return err
+	}
+	return runCommandWithInput(commandExecutor, payload, bin, "generate", "app", "--contract", "-")
+}
+
+func invokeWithFile(bin string, contract GeneratorContract) error {
+	payload, err := json.MarshalIndent(contract, "", "  ")
+	if err != nil {
+		return err
+	}
+	file, err := os.CreateTemp("", "jupiter-contract-*.json")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(file.Name())
+	if _, err := file.Write(payload); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Close(); err != nil {
+		return err
+	}
+	return runCommand(commandExecutor, bin, "generate", "app", "--contract", file.Name())
+}
@@ -0,0 +1,37 @@
+package main
+
+import "testing"
+
+func TestGeneratorContractModuleMatchesResolvedModule(t *testing.T) {
+	for name, tc := range map[string]struct {
+		config   SourceConfig
+		defaults Defaults
+		want     string
+	}{
+		"builtin": {
+			config: SourceConfig{Name: "orders", Owner: "acme", Metadata: Metadata{ProgrammingLanguage: "golang"}},
+			want:   "github.com/acme/orders",
+		},
+		"module_prefix": {
+			config:   SourceConfig{Name: "orders", Owner: "acme", Metadata: Metadata{ProgrammingLanguage: "golang"}},
+			defaults: Defaults{ModulePrefix: map[string]string{"golang": "go.acme.dev/{owner}"}},
+			want:     "go.acme.dev/acme/orders",
+		},
+		"metadata.module": {
+			config: SourceConfig{Name: "orders", Owner: "acme", Metadata: Metadata{ProgrammingLanguage: "golang", Module: "go.acme.dev/payments/orders"}},
+			want:   "go.acme.dev/payments/orders",
+		},
+	} {
+		t.Run(name, func(t *testing.T) {
+			registryConfig := RegistryConfig{Defaults: tc.defaults}
+			resolved := resolveSourceConfig(tc.config, registryConfig)
+			contract, err := newGeneratorContract(toGeneratorSourceDto(resolved, registryConfig), Generator{})
+			if err != nil {
+				t.Fatal(err)
+			}
+			if contract.Options.Module != resolved.Metadata.Module || contract.Options.Module != tc.want {
+				t.Fatalf("contract module = %q, resolved module = %q, want %q", contract.Options.Module, resolved.Metadata.Module, tc.want)
+			}
+		})
+	}
+}
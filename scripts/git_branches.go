@@ -0,0 +1,62 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Branch là long-lived branch tạo thêm trên repo sau initial push (git-flow: develop, release, ...)
+type Branch struct {
+	Name string `yaml:"name"`
+	// From: branch gốc, mặc định default branch của repo
+	From string `yaml:"from"`
+	// Default: đặt branch này làm default branch của repo (tối đa một branch)
+	Default bool `yaml:"default"`
+}
+
+// createBranches tạo các branch trong git.branches còn thiếu rồi đổi default branch nếu được yêu cầu.
+// Branch đã có thì giữ nguyên (regenerate không reset develop về main).
+func createBranches(ex Executor, dto GeneratorSourceDto, branches []Branch) error {
+	repo := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+
+	defaultBranch := ""
+	for _, b := range branches {
+		if b.Name == "" || b.Name == dto.branch() {
+			return withCode(ErrUsage, fmt.Errorf("invalid git.branches entry %q: name must be set and differ from %s", b.Name, dto.branch()))
+		}
+		if b.Default {
+			if defaultBranch != "" {
+				return withCode(ErrUsage, fmt.Errorf("git.branches: both %s and %s are marked default", defaultBranch, b.Name))
+			}
+			defaultBranch = b.Name
+		}
+	}
+
+	for _, b := range branches {
+		if _, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/branches/%s", repo, b.Name), "--jq", ".name"); err == nil {
+			fmt.Printf("  ⏭️  Branch %s already exists\n", b.Name)
+			continue
+		}
+		from := b.From
+		if from == "" {
+			from = dto.branch()
+		}
+		sha, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/commits/%s", repo, from), "--jq", ".sha")
+		if err != nil {
+			return fmt.Errorf("failed to resolve %s for branch %s: %w", from, b.Name, err)
+		}
+		body, _ := json.Marshal(map[string]string{"ref": "refs/heads/" + b.Name, "sha": strings.TrimSpace(sha)})
+		if err := runCommandWithInput(ex, body, "gh", "api", "-X", "POST", fmt.Sprintf("repos/%s/git/refs", repo), "--input", "-"); err != nil {
+			return fmt.Errorf("failed to create branch %s: %w", b.Name, err)
+		}
+		fmt.Printf("  🌿 Created branch %s from %s\n", b.Name, from)
+	}
+
+	if defaultBranch != "" {
+		if err := runCommand(ex, "gh", "api", "-X", "PATCH", "repos/"+repo, "-f", "default_branch="+defaultBranch); err != nil {
+			return fmt.Errorf("failed to set default branch to %s: %w", defaultBranch, err)
+		}
+	}
+	return nil
+}
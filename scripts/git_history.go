@@ -0,0 +1,200 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// GitConfig cấu hình cách registry đẩy code lên repo (history strategy + transport)
+type GitConfig struct {
+	// History quyết định nội dung regenerate được đưa lên repo đã tồn tại thế nào:
+	//   - squash (mặc định):  init mới, một commit duy nhất, force push (xoá history cũ)
+	//   - template_version:   clone history, mỗi template version một commit (cùng version thì amend)
+	//   - preserve:           clone history, commit đè file generated lên trên, giữ file team tự thêm, không force push
+	//   - merge:              như preserve, file generated bị sửa tay được merge 3-way với template mới
+	History string `yaml:"history"`
+	// OnConflict: conflict của history merge, fail (mặc định) | ours | theirs | union | interactive
+	OnConflict string `yaml:"on_conflict"`
+
+	// Transport: https (mặc định, token trong URL) | ssh (git@github.com, cho org cấm push bằng PAT)
+	Transport string `yaml:"transport"`
+	// SSHKeyEnv: env chứa private key; để trống thì dùng ssh-agent (SSH_AUTH_SOCK)
+	SSHKeyEnv string `yaml:"ssh_key_env"`
+
+	// Shallow clone cache cho các thao tác cần history của repo đã tồn tại
+	CacheDir   string `yaml:"cache_dir"`   // mặc định .jupiter-cache/repos
+	CloneDepth int    `yaml:"clone_depth"` // mặc định 2
+
+	// InitialPush: direct (mặc định) | pull_request (repo mới: main rỗng + PR từ bootstrap branch)
+	InitialPush     string `yaml:"initial_push"`
+	BootstrapBranch string `yaml:"bootstrap_branch"` // mặc định bootstrap
+	// UpdatePush: direct (mặc định) | pull_request (repo đã tồn tại: regenerate lên jupiter/upgrade-<version> + PR)
+	UpdatePush string `yaml:"update_push"`
+
+	// Branches: long-lived branch tạo thêm sau initial push (git-flow), có thể đặt làm default
+	Branches []Branch `yaml:"branches"`
+}
+
+func (g GitConfig) history() string {
+	if g.History == "" {
+		return "squash"
+	}
+	return g.History
+}
+
+func (g GitConfig) updatePush() string {
+	if g.UpdatePush == "" {
+		return "direct"
+	}
+	return g.UpdatePush
+}
+
+// forcePushes: strategy có ghi đè history của repo đã tồn tại không (dùng cho approval force_push)
+func (g GitConfig) forcePushes() bool {
+	return g.history() != "preserve" && g.history() != "merge" && g.updatePush() != "pull_request"
+}
+
+// remoteHasBranch kiểm tra repo đã có default branch (tức là đang regenerate chứ không phải tạo mới)
+func remoteHasBranch(ex Executor, repoURL, branch string) bool {
+	out, err := runCommandOutput(ex, "git", "ls-remote", "--heads", repoURL, branch)
+	return err == nil && strings.TrimSpace(out) != ""
+}
+
+// pushOnHistory clone history hiện có rồi commit nội dung generated lên trên default branch
+// (update_push: pull_request thì lên upgrade branch và mở PR)
+func pushOnHistory(ex Executor, repoDir, repoURL string, dto GeneratorSourceDto, gitConfig GitConfig) error {
+	strategy := gitConfig.history()
+
+	// Shallow clone từ cache rồi clone local sang thư mục tạm (nhanh, không tải lại từ GitHub)
+	cached, err := cachedClone(ex, dto.Owner, dto.AppName, repoURL, dto.branch(), gitConfig)
+	if err != nil {
+		return fmt.Errorf("failed to clone existing history: %w", err)
+	}
+	cloneDir, err := os.MkdirTemp("", "jupiter-history-")
+	if err != nil {
+		return err
+	}
+	defer os.RemoveAll(cloneDir)
+
+	absCached, err := filepath.Abs(cached)
+	if err != nil {
+		return err
+	}
+	if err := runCommand(ex, "git", "clone", "--quiet", "--no-local", "--depth", strconv.Itoa(gitConfig.cloneDepth()), "file://"+absCached, cloneDir); err != nil {
+		return fmt.Errorf("failed to clone from cache: %w", err)
+	}
+	if err := runCommandInDir(ex, cloneDir, "git", "remote", "set-url", "origin", repoURL); err != nil {
+		return err
+	}
+	// Dùng .git của bản clone cho thư mục generated: working tree = output mới, HEAD = history cũ
+	if err := os.RemoveAll(filepath.Join(repoDir, ".git")); err != nil {
+		return err
+	}
+	if err := os.Rename(filepath.Join(cloneDir, ".git"), filepath.Join(repoDir, ".git")); err != nil {
+		return fmt.Errorf("failed to reuse cloned history: %w", err)
+	}
+
+	version := "unknown"
+	if dto.Manifest != nil {
+		version = dto.Manifest.TemplateVersion
+	}
+
+	// merge: file generated bị sửa tay được merge với bản trên repo trước khi add
+	if strategy == "merge" {
+		if _, err := mergeGeneratedFiles(ex, repoDir, fmt.Sprintf("%s/%s", dto.Owner, dto.AppName), gitConfig); err != nil {
+			return err
+		}
+	}
+
+	// preserve / merge: không xoá file team tự thêm (không có trong output generated)
+	addArgs := []string{"add", "-A"}
+	message := fmt.Sprintf("chore(jupiter): regenerate from templates %s", version)
+	switch strategy {
+	case "preserve":
+		addArgs = []string{"add", "--ignore-removal", "."}
+		message = fmt.Sprintf("chore(jupiter): update generated files (templates %s)", version)
+	case "merge":
+		addArgs = []string{"add", "--ignore-removal", "."}
+		message = fmt.Sprintf("chore(jupiter): merge templates %s with manual changes", version)
+	}
+
+	commands := [][]string{
+		{"config", "user.email", "github-actions[bot]@users.noreply.github.com"},
+		{"config", "user.name", "github-actions[bot]"},
+		addArgs,
+	}
+	for _, args := range commands {
+		if err := runCommandInDir(ex, repoDir, "git", args...); err != nil {
+			return fmt.Errorf("command 'git %s' failed: %w", strings.Join(args, " "), err)
+		}
+	}
+
+	if err := runCommandInDir(ex, repoDir, "git", "diff", "--cached", "--quiet"); err == nil {
+		fmt.Println("  ℹ️ Generated content unchanged, nothing to push")
+		return nil
+	}
+
+	if gitConfig.updatePush() == "pull_request" {
+		return pushUpgradePR(ex, repoDir, dto, message, version)
+	}
+
+	// template_version: cùng template version với commit trước thì amend thay vì thêm commit
+	pushArgs := []string{"push", "origin", dto.branch()}
+	commitArgs := []string{"commit", "-m", message}
+	if strategy == "template_version" {
+		last, _ := runCommandOutputInDir(ex, repoDir, "git", "log", "-1", "--format=%s")
+		if last == message {
+			commitArgs = []string{"commit", "--amend", "-m", message}
+			pushArgs = []string{"push", "--force-with-lease", "origin", dto.branch()}
+		}
+	}
+
+	if err := runCommandInDir(ex, repoDir, "git", commitArgs...); err != nil {
+		return fmt.Errorf("command 'git %s' failed: %w", strings.Join(commitArgs, " "), err)
+	}
+	if err := runCommandInDir(ex, repoDir, "git", pushArgs...); err != nil {
+		return withCode(ErrPushDenied, fmt.Errorf("command 'git %s' failed: %w", strings.Join(pushArgs, " "), err))
+	}
+	return nil
+}
+
+// pushUpgradePR commit lên jupiter/upgrade-<version> rồi mở PR vào default branch (PR đã mở thì chỉ cập nhật branch)
+func pushUpgradePR(ex Executor, repoDir string, dto GeneratorSourceDto, message, version string) error {
+	branch := "jupiter/upgrade-" + version
+	commands := [][]string{
+		{"checkout", "-B", branch},
+		{"commit", "-m", message},
+		{"push", "--force", "origin", branch},
+	}
+	for _, args := range commands {
+		if err := runCommandInDir(ex, repoDir, "git", args...); err != nil {
+			err = fmt.Errorf("command 'git %s' failed: %w", strings.Join(args, " "), err)
+			if args[0] == "push" {
+				return withCode(ErrPushDenied, err)
+			}
+			return err
+		}
+	}
+
+	repo := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	if url, err := runCommandOutput(ex, "gh", "pr", "list", "--repo", repo, "--head", branch, "--json", "url", "--jq", ".[0].url"); err == nil && strings.TrimSpace(url) != "" {
+		fmt.Printf("  🔀 Upgrade PR updated: %s\n", strings.TrimSpace(url))
+		return nil
+	}
+	body := fmt.Sprintf("Regenerates `%s` from jupiter-registry templates %s.\n\n"+
+		"Review the generated changes and merge to upgrade the scaffolding.", dto.AppName, version)
+	url, err := runCommandOutput(ex, "gh", "pr", "create",
+		"--repo", repo,
+		"--base", dto.branch(),
+		"--head", branch,
+		"--title", "chore(jupiter): upgrade templates to "+version,
+		"--body", body)
+	if err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to open upgrade PR: %w", err))
+	}
+	fmt.Printf("  🔀 Upgrade PR opened: %s\n", url)
+	return nil
+}
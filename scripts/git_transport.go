@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+)
+
+// remoteURL trả về URL push của repo theo git.transport
+func remoteURL(owner, repoName string, gitConfig GitConfig) (string, error) {
+	switch gitConfig.Transport {
+	case "", "https":
+		// Get GitHub token from environment
+		ghToken := os.Getenv("GH_TOKEN")
+		if ghToken == "" {
+			ghToken = os.Getenv("GITHUB_TOKEN")
+		}
+		if ghToken != "" {
+			return fmt.Sprintf("https://x-access-token:%s@github.com/%s/%s.git", ghToken, owner, repoName), nil
+		}
+		return fmt.Sprintf("https://github.com/%s/%s.git", owner, repoName), nil
+	case "ssh":
+		if err := setupSSHTransport(gitConfig); err != nil {
+			return "", err
+		}
+		return fmt.Sprintf("git@github.com:%s/%s.git", owner, repoName), nil
+	default:
+		return "", fmt.Errorf("unsupported git transport: %s", gitConfig.Transport)
+	}
+}
+
+var sshSetup struct {
+	once sync.Once
+	err  error
+}
+
+// setupSSHTransport cấu hình GIT_SSH_COMMAND một lần cho cả process:
+// key lấy từ env (ghi ra file tạm 0600) hoặc để ssh dùng agent
+func setupSSHTransport(gitConfig GitConfig) error {
+	sshSetup.once.Do(func() {
+		sshCommand := "ssh -o StrictHostKeyChecking=accept-new"
+		if gitConfig.SSHKeyEnv != "" {
+			key := os.Getenv(gitConfig.SSHKeyEnv)
+			if key == "" {
+				sshSetup.err = fmt.Errorf("ssh key $%s is not set", gitConfig.SSHKeyEnv)
+				return
+			}
+			registerSecret(key)
+			dir, err := os.MkdirTemp("", "jupiter-ssh-")
+			if err != nil {
+				sshSetup.err = err
+				return
+			}
+			keyPath := filepath.Join(dir, "id_push")
+			if err := os.WriteFile(keyPath, []byte(key+"\n"), 0600); err != nil {
+				sshSetup.err = fmt.Errorf("failed to write ssh key: %w", err)
+				return
+			}
+			sshCommand += fmt.Sprintf(" -o IdentitiesOnly=yes -i %s", keyPath)
+		} else if os.Getenv("SSH_AUTH_SOCK") == "" {
+			sshSetup.err = fmt.Errorf("git.transport is ssh but neither git.ssh_key_env nor an ssh-agent is available")
+			return
+		}
+		os.Setenv("GIT_SSH_COMMAND", sshCommand)
+	})
+	return sshSetup.err
+}
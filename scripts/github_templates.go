@@ -0,0 +1,40 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// GithubTemplates chọn bộ issue/PR template theo team > language > default
+type GithubTemplates struct {
+	Default   string            `yaml:"default"`
+	Languages map[string]string `yaml:"languages"`
+	Teams     map[string]string `yaml:"teams"`
+}
+
+func (t GithubTemplates) resolve(dto GeneratorSourceDto) string {
+	if dir, ok := t.Teams[dto.Team]; ok && dto.Team != "" {
+		return dir
+	}
+	if dir, ok := t.Languages[dto.ProgrammingLanguage]; ok {
+		return dir
+	}
+	return t.Default
+}
+
+// writeGithubTemplates render ISSUE_TEMPLATE/ và PULL_REQUEST_TEMPLATE.md vào repoDir/.github
+func writeGithubTemplates(repoDir string, dto GeneratorSourceDto, templates GithubTemplates) error {
+	srcDir := templates.resolve(dto)
+	if srcDir == "" {
+		fmt.Println("  ⚠️ No github templates configured, skipping")
+		return nil
+	}
+
+	if _, err := os.Stat(srcDir); err != nil {
+		return fmt.Errorf("template dir not found: %s", srcDir)
+	}
+
+	fmt.Printf("  → Using templates from %s\n", srcDir)
+	return renderLocalizedTemplateDir(srcDir, filepath.Join(repoDir, ".github"), dto.DocsLanguage, dto)
+}
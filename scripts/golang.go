@@ -0,0 +1,59 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// golangFrameworks là các framework Go được hỗ trợ. "uranus" (mặc định) dùng uranus CLI,
+// các framework còn lại render từ templates/golang/<framework>/.
+var golangFrameworks = []string{"uranus", "gin", "echo", "fiber", "chi"}
+
+type golangTemplateData struct {
+	Service GeneratorSourceDto
+	Module  string
+}
+
+// generateGolangApp dispatch theo metadata.framework
+func generateGolangApp(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	switch dto.Framework {
+	case "", "uranus":
+		return generateWithUranus(ex, dto, registryConfig.Generator)
+	case "gin", "echo", "fiber", "chi":
+		return generateFromFrameworkTemplate(ex, dto, registryConfig.FrameworkTemplates)
+	default:
+		return fmt.Errorf("unsupported golang framework: %s", dto.Framework)
+	}
+}
+
+func generateFromFrameworkTemplate(ex Executor, dto GeneratorSourceDto, templatesDir string) error {
+	if templatesDir == "" {
+		return fmt.Errorf("framework_templates is not configured")
+	}
+
+	srcDir := filepath.Join(templatesDir, dto.ProgrammingLanguage, dto.Framework)
+	if _, err := os.Stat(srcDir); err != nil {
+		return fmt.Errorf("no templates for %s/%s in %s", dto.ProgrammingLanguage, dto.Framework, templatesDir)
+	}
+
+	fmt.Printf("🚀 Generating %s app: %s\n", dto.Framework, dto.AppName)
+	data := golangTemplateData{Service: dto, Module: goModulePath(dto)}
+	if err := renderTemplateDir(srcDir, dto.AppName, data); err != nil {
+		return fmt.Errorf("failed to generate app: %w", err)
+	}
+
+	// Resolve dependencies để go.sum có sẵn trong initial commit
+	if err := runCommandInDir(ex, dto.AppName, "go", "mod", "tidy"); err != nil {
+		return fmt.Errorf("go mod tidy failed: %w", err)
+	}
+	return nil
+}
+
+// goModulePath: metadata.module nếu có, ngược lại github.com/<owner>/<name>
+func goModulePath(dto GeneratorSourceDto) string {
+	if dto.Module != "" {
+		return dto.Module
+	}
+	return fmt.Sprintf("github.com/%s/%s", dto.Owner, dto.AppName)
+}
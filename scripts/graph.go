@@ -0,0 +1,210 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+)
+
+// ServiceGraph là đồ thị depends_on của các service trong registry (cạnh From → To: From cần To)
+type ServiceGraph struct {
+	Nodes []GraphNode `json:"nodes"`
+	Edges []GraphEdge `json:"edges"`
+}
+
+type GraphNode struct {
+	Name     string `json:"name"`
+	Team     string `json:"team,omitempty"`
+	Language string `json:"language,omitempty"`
+	RepoURL  string `json:"repo_url,omitempty"`
+	// External: depends_on trỏ tới service không có trong sources-service
+	External bool `json:"external,omitempty"`
+}
+
+type GraphEdge struct {
+	From string `json:"from"`
+	To   string `json:"to"`
+}
+
+func buildServiceGraph(catalog []CatalogEntry) ServiceGraph {
+	var graph ServiceGraph
+	known := map[string]bool{}
+	for _, e := range catalog {
+		if known[e.Name] {
+			continue
+		}
+		known[e.Name] = true
+		graph.Nodes = append(graph.Nodes, GraphNode{Name: e.Name, Team: e.Team, Language: e.ProgrammingLanguage, RepoURL: e.RepoURL})
+	}
+	external := map[string]bool{}
+	for _, e := range catalog {
+		for _, dep := range e.DependsOn {
+			graph.Edges = append(graph.Edges, GraphEdge{From: e.Name, To: dep})
+			if !known[dep] && !external[dep] {
+				external[dep] = true
+				graph.Nodes = append(graph.Nodes, GraphNode{Name: dep, External: true})
+			}
+		}
+	}
+	sort.Slice(graph.Nodes, func(i, j int) bool { return graph.Nodes[i].Name < graph.Nodes[j].Name })
+	sort.Slice(graph.Edges, func(i, j int) bool {
+		if graph.Edges[i].From != graph.Edges[j].From {
+			return graph.Edges[i].From < graph.Edges[j].From
+		}
+		return graph.Edges[i].To < graph.Edges[j].To
+	})
+	return graph
+}
+
+// impact thu gọn đồ thị về service, các dependency (trực tiếp + gián tiếp) và các service bị ảnh hưởng khi nó đổi
+func (g ServiceGraph) impact(service string) (ServiceGraph, error) {
+	keep := map[string]bool{}
+	for _, n := range g.Nodes {
+		if n.Name == service {
+			keep[service] = true
+		}
+	}
+	if !keep[service] {
+		return g, fmt.Errorf("service %q is not in the registry", service)
+	}
+	walk := func(next func(GraphEdge) (string, string)) {
+		queue := []string{service}
+		for len(queue) > 0 {
+			current := queue[0]
+			queue = queue[1:]
+			for _, e := range g.Edges {
+				if from, to := next(e); from == current && !keep[to] {
+					keep[to] = true
+					queue = append(queue, to)
+				}
+			}
+		}
+	}
+	walk(func(e GraphEdge) (string, string) { return e.From, e.To }) // dependencies
+	walk(func(e GraphEdge) (string, string) { return e.To, e.From }) // dependents
+
+	var sub ServiceGraph
+	for _, n := range g.Nodes {
+		if keep[n.Name] {
+			sub.Nodes = append(sub.Nodes, n)
+		}
+	}
+	for _, e := range g.Edges {
+		if keep[e.From] && keep[e.To] {
+			sub.Edges = append(sub.Edges, e)
+		}
+	}
+	return sub, nil
+}
+
+func (g ServiceGraph) dot() string {
+	var b strings.Builder
+	b.WriteString("digraph services {\n  rankdir=LR;\n  node [shape=box];\n")
+	for _, n := range g.Nodes {
+		var attrs []string
+		if label := nodeLabel(n); label != n.Name {
+			attrs = append(attrs, fmt.Sprintf("label=%q", label))
+		}
+		if n.RepoURL != "" {
+			attrs = append(attrs, fmt.Sprintf("URL=%q", n.RepoURL))
+		}
+		if n.External {
+			attrs = append(attrs, "style=dashed")
+		}
+		fmt.Fprintf(&b, "  %q", n.Name)
+		if len(attrs) > 0 {
+			fmt.Fprintf(&b, " [%s]", strings.Join(attrs, ", "))
+		}
+		b.WriteString(";\n")
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %q -> %q;\n", e.From, e.To)
+	}
+	b.WriteString("}\n")
+	return b.String()
+}
+
+var mermaidIDPattern = regexp.MustCompile(`[^A-Za-z0-9_]`)
+
+func (g ServiceGraph) mermaid() string {
+	id := func(name string) string { return "svc_" + mermaidIDPattern.ReplaceAllString(name, "_") }
+	var b strings.Builder
+	b.WriteString("graph LR\n")
+	for _, n := range g.Nodes {
+		label := strings.ReplaceAll(nodeLabel(n), `"`, "'")
+		if n.External {
+			fmt.Fprintf(&b, "  %s([\"%s\"])\n", id(n.Name), label)
+		} else {
+			fmt.Fprintf(&b, "  %s[\"%s\"]\n", id(n.Name), label)
+		}
+	}
+	for _, e := range g.Edges {
+		fmt.Fprintf(&b, "  %s --> %s\n", id(e.From), id(e.To))
+	}
+	return b.String()
+}
+
+// nodeLabel: tên service kèm team (nếu có) để review kiến trúc theo team
+func nodeLabel(n GraphNode) string {
+	switch {
+	case n.External:
+		return n.Name + " (external)"
+	case n.Team != "":
+		return fmt.Sprintf("%s (%s)", n.Name, n.Team)
+	}
+	return n.Name
+}
+
+// renderGraph xuất đồ thị theo format dot | mermaid | json
+func renderGraph(g ServiceGraph, format string) (string, error) {
+	switch format {
+	case "dot":
+		return g.dot(), nil
+	case "mermaid":
+		return g.mermaid(), nil
+	case "json":
+		data, err := json.MarshalIndent(g, "", "  ")
+		if err != nil {
+			return "", err
+		}
+		return string(data) + "\n", nil
+	}
+	return "", withCode(ErrUsage, fmt.Errorf("unsupported graph format %q (supported: dot, mermaid, json)", format))
+}
+
+// runGraphCommand: `graph [--format dot|mermaid|json] [--service name] [--out file]`
+func runGraphCommand(args []string) error {
+	fs := flag.NewFlagSet("graph", flag.ExitOnError)
+	format := fs.String("format", "mermaid", "output format: dot | mermaid | json")
+	service := fs.String("service", "", "only the service, its dependencies and its dependents (impact analysis)")
+	out := fs.String("out", "", "write the graph to this file instead of stdout")
+	fs.Parse(args)
+
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	graph := buildServiceGraph(buildCatalog(services))
+	if *service != "" {
+		if graph, err = graph.impact(*service); err != nil {
+			return withCode(ErrSourceNotFound, err)
+		}
+	}
+	rendered, err := renderGraph(graph, *format)
+	if err != nil {
+		return err
+	}
+	if *out == "" {
+		fmt.Print(rendered)
+		return nil
+	}
+	if err := os.WriteFile(*out, []byte(rendered), 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", *out, err)
+	}
+	fmt.Printf("🕸️  Dependency graph: %d service(s), %d edge(s) -> %s\n", len(graph.Nodes), len(graph.Edges), *out)
+	return nil
+}
@@ -0,0 +1,136 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// Guardrails giới hạn output generated trước khi push (chặn bug generator đẩy node_modules / build artifact lên repo)
+type Guardrails struct {
+	MaxRepoSizeMB int `yaml:"max_repo_size_mb"` // 0 = không giới hạn
+	MaxFileSizeMB int `yaml:"max_file_size_mb"`
+	MaxFileCount  int `yaml:"max_file_count"`
+	// ForbiddenExtensions: đuôi file không được commit (vd. .env, .pem, .exe)
+	ForbiddenExtensions []string `yaml:"forbidden_extensions"`
+	// ForbiddenPaths: tên thư mục/file không được xuất hiện ở bất kỳ level nào (vd. node_modules, dist)
+	ForbiddenPaths []string `yaml:"forbidden_paths"`
+	// ForbidBinaries chặn file binary, trừ các đuôi trong BinaryAllowlist (icon, font, ...)
+	ForbidBinaries  bool     `yaml:"forbid_binaries"`
+	BinaryAllowlist []string `yaml:"binary_allowlist"`
+}
+
+const megabyte = 1024 * 1024
+
+// checkGuardrails duyệt thư mục generated (bỏ qua .git) và trả về danh sách vi phạm
+func checkGuardrails(repoDir string, g Guardrails) ([]string, error) {
+	var violations []string
+	var totalSize int64
+	fileCount := 0
+
+	err := filepath.WalkDir(repoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		rel, err := filepath.Rel(repoDir, path)
+		if err != nil || rel == "." {
+			return err
+		}
+		rel = filepath.ToSlash(rel)
+
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if containsString(g.ForbiddenPaths, d.Name()) {
+			violations = append(violations, fmt.Sprintf("%s: forbidden path", rel))
+			if d.IsDir() {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if d.IsDir() {
+			return nil
+		}
+
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		fileCount++
+		totalSize += info.Size()
+
+		ext := strings.ToLower(filepath.Ext(d.Name()))
+		if ext == "" && strings.HasPrefix(d.Name(), ".") {
+			ext = strings.ToLower(d.Name()) // .env không có Ext
+		}
+		if containsStringFold(g.ForbiddenExtensions, ext) {
+			violations = append(violations, fmt.Sprintf("%s: forbidden file type %s", rel, ext))
+		}
+		if g.MaxFileSizeMB > 0 && info.Size() > int64(g.MaxFileSizeMB)*megabyte {
+			violations = append(violations, fmt.Sprintf("%s: %s exceeds max_file_size_mb %d", rel, formatBytes(info.Size()), g.MaxFileSizeMB))
+		}
+		if g.ForbidBinaries && !containsStringFold(g.BinaryAllowlist, ext) {
+			binary, err := isBinaryFile(path)
+			if err != nil {
+				return err
+			}
+			if binary {
+				violations = append(violations, fmt.Sprintf("%s: binary file", rel))
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if g.MaxFileCount > 0 && fileCount > g.MaxFileCount {
+		violations = append(violations, fmt.Sprintf("%d files exceeds max_file_count %d", fileCount, g.MaxFileCount))
+	}
+	if g.MaxRepoSizeMB > 0 && totalSize > int64(g.MaxRepoSizeMB)*megabyte {
+		violations = append(violations, fmt.Sprintf("total size %s exceeds max_repo_size_mb %d", formatBytes(totalSize), g.MaxRepoSizeMB))
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
+
+// isBinaryFile: có byte NUL trong 8KB đầu (cùng heuristic với git)
+func isBinaryFile(path string) (bool, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return false, err
+	}
+	defer f.Close()
+
+	buf := make([]byte, 8000)
+	n, _ := f.Read(buf)
+	return bytes.IndexByte(buf[:n], 0) >= 0, nil
+}
+
+func formatBytes(n int64) string {
+	if n >= megabyte {
+		return fmt.Sprintf("%.1fMB", float64(n)/megabyte)
+	}
+	return fmt.Sprintf("%.1fKB", float64(n)/1024)
+}
+
+// enforceGuardrails in report rõ ràng và fail khi output generated vượt giới hạn
+func enforceGuardrails(repoDir string, g Guardrails) error {
+	violations, err := checkGuardrails(repoDir, g)
+	if err != nil {
+		return fmt.Errorf("failed to inspect generated output: %w", err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	fmt.Printf("❌ Generated output of %s violates guardrails:\n", repoDir)
+	for _, v := range violations {
+		fmt.Printf("  - %s\n", v)
+	}
+	return withCode(ErrGuardrailViolation, fmt.Errorf("generated output violates %d guardrail(s)", len(violations)))
+}
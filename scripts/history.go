@@ -0,0 +1,182 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/tabwriter"
+	"time"
+)
+
+// registryHistoryDir chứa lịch sử chạy của từng service (JSON Lines, chỉ append),
+// commit cùng state/ để audit được ai đã generate gì, lúc nào.
+const registryHistoryDir = "state/history"
+
+// RunRecord là một lần provisioning / regenerate (hoặc archive / restore / delete) của service
+type RunRecord struct {
+	Timestamp       string `json:"timestamp"`
+	Action          string `json:"action"` // provision | archive | restore | delete
+	Actor           string `json:"actor"`
+	TemplateVersion string `json:"template_version,omitempty"`
+	CommitSHA       string `json:"commit_sha,omitempty"`
+	Result          string `json:"result"` // success | failed
+	ErrorCode       string `json:"error_code,omitempty"`
+	RunURL          string `json:"run_url,omitempty"`
+}
+
+// currentActor: người (hoặc hệ thống) kích hoạt lần chạy
+func currentActor() string {
+	for _, env := range []string{"JUPITER_ACTOR", "GITHUB_ACTOR", "USER"} {
+		if v := os.Getenv(env); v != "" {
+			return v
+		}
+	}
+	return "unknown"
+}
+
+// currentRunURL trỏ về workflow run khi chạy trong GitHub Actions
+func currentRunURL() string {
+	if os.Getenv("GITHUB_RUN_ID") == "" || os.Getenv("GITHUB_REPOSITORY") == "" {
+		return ""
+	}
+	server := os.Getenv("GITHUB_SERVER_URL")
+	if server == "" {
+		server = "https://github.com"
+	}
+	return fmt.Sprintf("%s/%s/actions/runs/%s", server, os.Getenv("GITHUB_REPOSITORY"), os.Getenv("GITHUB_RUN_ID"))
+}
+
+func historyFile(name string) (string, error) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		return "", withCode(ErrUsage, fmt.Errorf("invalid service name: %s", name))
+	}
+	return filepath.Join(registryHistoryDir, name+".jsonl"), nil
+}
+
+// recordRun append một RunRecord vào lịch sử của service (lỗi chỉ cảnh báo, không fail run)
+func recordRun(name string, record RunRecord) {
+	record.Timestamp = time.Now().UTC().Format(time.RFC3339)
+	record.Actor = currentActor()
+	record.RunURL = currentRunURL()
+	if err := appendRunRecord(name, record); err != nil {
+		fmt.Printf("⚠️ Failed to record run history: %v\n", err)
+	}
+}
+
+func appendRunRecord(name string, record RunRecord) error {
+	path, err := historyFile(name)
+	if err != nil {
+		return err
+	}
+	line, err := json.Marshal(record)
+	if err != nil {
+		return err
+	}
+
+	stateMu.Lock()
+	defer stateMu.Unlock()
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create history dir: %w", err)
+	}
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	_, err = f.Write(append(line, '\n'))
+	return err
+}
+
+func loadRunHistory(name string) ([]RunRecord, error) {
+	path, err := historyFile(name)
+	if err != nil {
+		return nil, err
+	}
+	f, err := os.Open(path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	var records []RunRecord
+	scanner := bufio.NewScanner(f)
+	for line := 1; scanner.Scan(); line++ {
+		if strings.TrimSpace(scanner.Text()) == "" {
+			continue
+		}
+		var record RunRecord
+		if err := json.Unmarshal(scanner.Bytes(), &record); err != nil {
+			return nil, fmt.Errorf("failed to parse %s line %d: %w", path, line, err)
+		}
+		records = append(records, record)
+	}
+	return records, scanner.Err()
+}
+
+// remoteHeadSHA: commit trên default branch sau khi push (rỗng nếu không đọc được)
+func remoteHeadSHA(ex Executor, dto GeneratorSourceDto) string {
+	out, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/%s/commits/%s", dto.Owner, dto.AppName, dto.branch()), "--jq", ".sha")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(out)
+}
+
+// runHistoryCommand: `history [--json] [--limit N] <app-name>`, mới nhất trước
+func runHistoryCommand(args []string) error {
+	fs := flag.NewFlagSet("history", flag.ExitOnError)
+	asJSON := fs.Bool("json", false, "print runs as JSON")
+	limit := fs.Int("limit", 0, "show only the N most recent runs")
+	fs.Parse(args)
+	if fs.NArg() != 1 {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts history [--json] [--limit N] <app-name>"))
+	}
+	name := fs.Arg(0)
+
+	records, err := loadRunHistory(name)
+	if err != nil {
+		return err
+	}
+	for i, j := 0, len(records)-1; i < j; i, j = i+1, j-1 {
+		records[i], records[j] = records[j], records[i]
+	}
+	if *limit > 0 && len(records) > *limit {
+		records = records[:*limit]
+	}
+
+	if *asJSON {
+		if records == nil {
+			records = []RunRecord{}
+		}
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(records)
+	}
+
+	if len(records) == 0 {
+		fmt.Printf("ℹ️ No runs recorded for %s\n", name)
+		return nil
+	}
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "TIMESTAMP\tACTION\tACTOR\tTEMPLATE\tCOMMIT\tRESULT")
+	for _, r := range records {
+		sha := r.CommitSHA
+		if len(sha) > 12 {
+			sha = sha[:12]
+		}
+		result := r.Result
+		if r.ErrorCode != "" {
+			result += " (" + r.ErrorCode + ")"
+		}
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\n", r.Timestamp, r.Action, r.Actor, r.TemplateVersion, sha, result)
+	}
+	return w.Flush()
+}
@@ -0,0 +1,244 @@
+//go:build integration
+
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// fakeGitHub là GitHub API giả (httptest) dùng cho integration suite.
+// Repo được tạo sẽ có một bare git repo local tương ứng để nhận push.
+type fakeGitHub struct {
+	server  *httptest.Server
+	rootDir string
+
+	mu       sync.Mutex
+	repos    map[string]map[string]interface{}
+	requests []string
+}
+
+func newFakeGitHub(rootDir string) *fakeGitHub {
+	f := &fakeGitHub{rootDir: rootDir, repos: map[string]map[string]interface{}{}}
+	f.server = httptest.NewServer(http.HandlerFunc(f.handle))
+	return f
+}
+
+func (f *fakeGitHub) Close() {
+	f.server.Close()
+}
+
+// bareRepoPath là nơi lưu bare repo của owner/name
+func (f *fakeGitHub) bareRepoPath(fullName string) string {
+	return filepath.Join(f.rootDir, "remotes", fullName+".git")
+}
+
+func (f *fakeGitHub) handle(w http.ResponseWriter, r *http.Request) {
+	body, _ := io.ReadAll(r.Body)
+
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.requests = append(f.requests, r.Method+" "+r.URL.Path)
+
+	parts := strings.Split(strings.Trim(r.URL.Path, "/"), "/")
+	switch {
+	// POST /orgs/{owner}/repos: tạo repo + bare repo
+	case r.Method == http.MethodPost && len(parts) == 3 && parts[0] == "orgs" && parts[2] == "repos":
+		var payload map[string]interface{}
+		json.Unmarshal(body, &payload)
+		fullName := fmt.Sprintf("%s/%v", parts[1], payload["name"])
+		if _, exists := f.repos[fullName]; exists {
+			writeJSON(w, http.StatusUnprocessableEntity, map[string]string{"message": "name already exists on this account"})
+			return
+		}
+		if _, err := (shellExecutor{}).Run(Command{Name: "git", Args: []string{"init", "--bare", "-q", f.bareRepoPath(fullName)}}); err != nil {
+			writeJSON(w, http.StatusInternalServerError, map[string]string{"message": err.Error()})
+			return
+		}
+		payload["id"] = len(f.repos) + 1
+		payload["full_name"] = fullName
+		f.repos[fullName] = payload
+		writeJSON(w, http.StatusCreated, payload)
+
+	// /repos/{owner}/{name}[/...]
+	case len(parts) >= 3 && parts[0] == "repos":
+		fullName := parts[1] + "/" + parts[2]
+		repo, exists := f.repos[fullName]
+		if !exists {
+			writeJSON(w, http.StatusNotFound, map[string]string{"message": "Not Found"})
+			return
+		}
+		if len(parts) == 3 && r.Method == http.MethodPatch {
+			var patch map[string]interface{}
+			json.Unmarshal(body, &patch)
+			for k, v := range patch {
+				repo[k] = v
+			}
+		}
+		if len(parts) == 3 {
+			writeJSON(w, http.StatusOK, repo)
+			return
+		}
+		writeJSON(w, http.StatusOK, map[string]interface{}{"id": 1})
+
+	case len(parts) == 2 && parts[0] == "users":
+		writeJSON(w, http.StatusOK, map[string]interface{}{"login": parts[1], "id": 1000})
+
+	default:
+		writeJSON(w, http.StatusOK, map[string]interface{}{})
+	}
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
+
+// harnessExecutor chuyển `gh` sang fake API, rewrite git remote sang bare repo local,
+// giả lập uranus; các command còn lại (git, ...) chạy thật trong workspace tạm.
+type harnessExecutor struct {
+	inner  Executor
+	github *fakeGitHub
+}
+
+func (h *harnessExecutor) Run(c Command) (string, error) {
+	switch {
+	case c.Name == "gh" && len(c.Args) > 0 && c.Args[0] == "api":
+		return h.ghAPI(c)
+	case c.Name == "gh" && len(c.Args) > 1 && c.Args[0] == "repo" && c.Args[1] == "create":
+		owner, name, _ := strings.Cut(c.Args[2], "/")
+		body, _ := json.Marshal(map[string]interface{}{"name": name, "private": containsString(c.Args, "--private")})
+		return h.request(http.MethodPost, "orgs/"+owner+"/repos", body, "")
+	case c.Name == "gh":
+		return "", nil
+	case c.Name == "git":
+		// https://.../owner/name.git -> bare repo local (remote add, clone, ls-remote, ...)
+		args := make([]string, len(c.Args))
+		for i, arg := range c.Args {
+			args[i] = arg
+			if strings.Contains(arg, "github.com") && strings.HasSuffix(arg, ".git") {
+				segments := strings.FieldsFunc(strings.TrimSuffix(arg, ".git"), func(r rune) bool { return r == '/' || r == ':' })
+				args[i] = h.github.bareRepoPath(segments[len(segments)-2] + "/" + segments[len(segments)-1])
+			}
+		}
+		c.Args = args
+		return h.inner.Run(c)
+	case strings.Contains(c.Name, "uranus") && len(c.Args) > 0 && c.Args[0] == "generate":
+		return "", fakeUranusGenerate(c)
+	case c.Name == "go" && len(c.Args) > 0 && c.Args[0] == "install":
+		return "", nil
+	}
+	return h.inner.Run(c)
+}
+
+// ghAPI parse `gh api [-X METHOD] [-f k=v] [-F k=v] [--input -] [--jq .field] endpoint`
+func (h *harnessExecutor) ghAPI(c Command) (string, error) {
+	method, endpoint, jq := "", "", ""
+	fields := map[string]interface{}{}
+	var body []byte
+
+	args := c.Args[1:]
+	for i := 0; i < len(args); i++ {
+		switch args[i] {
+		case "-X":
+			i++
+			method = args[i]
+		case "-f", "-F":
+			typed := args[i] == "-F"
+			i++
+			k, v, _ := strings.Cut(args[i], "=")
+			if b, err := strconv.ParseBool(v); typed && err == nil {
+				fields[k] = b
+				continue
+			}
+			fields[k] = v
+		case "--input":
+			i++
+			body = c.Stdin
+		case "--jq":
+			i++
+			jq = args[i]
+		case "--paginate":
+		default:
+			endpoint = args[i]
+		}
+	}
+
+	if body == nil && len(fields) > 0 {
+		body, _ = json.Marshal(fields)
+	}
+	if method == "" {
+		method = http.MethodGet
+		if body != nil {
+			method = http.MethodPost
+		}
+	}
+	return h.request(method, endpoint, body, jq)
+}
+
+func (h *harnessExecutor) request(method, endpoint string, body []byte, jq string) (string, error) {
+	req, err := http.NewRequest(method, h.github.server.URL+"/"+strings.TrimLeft(endpoint, "/"), bytes.NewReader(body))
+	if err != nil {
+		return "", err
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", err
+	}
+	defer resp.Body.Close()
+
+	out, _ := io.ReadAll(resp.Body)
+	if resp.StatusCode >= 300 {
+		return "", fmt.Errorf("gh: %s %s: HTTP %d", method, endpoint, resp.StatusCode)
+	}
+
+	// Chỉ hỗ trợ jq dạng ".field" (đủ cho pipeline)
+	if strings.HasPrefix(jq, ".") && !strings.ContainsAny(jq, " |[") {
+		var obj map[string]interface{}
+		if json.Unmarshal(out, &obj) == nil {
+			return fmt.Sprint(obj[strings.TrimPrefix(jq, ".")]), nil
+		}
+	}
+	return string(out), nil
+}
+
+// fakeUranusGenerate tạo scaffold tối thiểu thay cho uranus generate app (flags hoặc --contract)
+func fakeUranusGenerate(c Command) error {
+	name := ""
+	for i := 0; i < len(c.Args)-1; i++ {
+		switch c.Args[i] {
+		case "--name", "-n":
+			name = c.Args[i+1]
+		case "--contract":
+			data := c.Stdin
+			if c.Args[i+1] != "-" {
+				var err error
+				if data, err = os.ReadFile(c.Args[i+1]); err != nil {
+					return err
+				}
+			}
+			var contract GeneratorContract
+			if err := json.Unmarshal(data, &contract); err != nil {
+				return fmt.Errorf("fake uranus: invalid contract: %w", err)
+			}
+			name = contract.Options.OutputDir
+		}
+	}
+	if name == "" {
+		return fmt.Errorf("fake uranus: --name is required")
+	}
+	if err := os.MkdirAll(name, 0755); err != nil {
+		return err
+	}
+	return os.WriteFile(filepath.Join(name, "main.go"), []byte("package main\n\nfunc main() {}\n"), 0644)
+}
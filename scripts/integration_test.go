@@ -0,0 +1,139 @@
+//go:build integration
+
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// Integration suite: generate -> create -> push với fake GitHub API (httptest) + bare git repo local.
+// Chỉ build khi có tag: go test -tags integration ./scripts
+
+// integrationScenario là một case end-to-end: generate -> create -> push
+type integrationScenario struct {
+	Name  string
+	DTO   GeneratorSourceDto
+	Git   GitConfig
+	Runs  int
+	Check func(github *fakeGitHub, fullName string) error
+}
+
+func TestIntegration(t *testing.T) {
+	registryRoot, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	workspace := t.TempDir()
+
+	github := newFakeGitHub(workspace)
+	t.Cleanup(github.Close)
+
+	previous := commandExecutor
+	commandExecutor = &harnessExecutor{inner: shellExecutor{}, github: github}
+	t.Cleanup(func() { commandExecutor = previous })
+
+	// Pipeline dùng relative path (dist/, <app>/), nên chạy trong workspace
+	if err := os.Chdir(workspace); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { os.Chdir(registryRoot) })
+	t.Setenv("GH_TOKEN", "")
+	t.Setenv("GITHUB_TOKEN", "")
+
+	hasWiki := false
+	registryConfig := RegistryConfig{
+		RepoSettings:    RepoSettings{HasWiki: &hasWiki},
+		GithubTemplates: GithubTemplates{Default: filepath.Join(registryRoot, "templates", "github", "default")},
+	}
+
+	scenarios := []integrationScenario{
+		{
+			Name:  "golang service is generated, created and pushed",
+			DTO:   GeneratorSourceDto{AppName: "integration-go", Owner: repoOwner, ProgrammingLanguage: "golang", Visibility: "private"},
+			Runs:  1,
+			Check: checkPushedRepo("main.go", ".github/PULL_REQUEST_TEMPLATE.md"),
+		},
+		{
+			Name:  "re-provisioning an existing repo is idempotent",
+			DTO:   GeneratorSourceDto{AppName: "integration-rerun", Owner: repoOwner, ProgrammingLanguage: "golang", Visibility: "private"},
+			Runs:  2,
+			Check: checkPushedRepo("main.go"),
+		},
+		{
+			Name:  "preserve history strategy does not rewrite or add empty commits",
+			DTO:   GeneratorSourceDto{AppName: "integration-preserve", Owner: repoOwner, ProgrammingLanguage: "golang", Visibility: "private"},
+			Git:   GitConfig{History: "preserve"},
+			Runs:  2,
+			Check: checkCommitCount(1),
+		},
+	}
+
+	for _, scenario := range scenarios {
+		scenario := scenario
+		t.Run(scenario.Name, func(t *testing.T) {
+			if err := runIntegrationScenario(github, registryConfig, scenario); err != nil {
+				t.Fatal(err)
+			}
+		})
+	}
+}
+
+func runIntegrationScenario(github *fakeGitHub, registryConfig RegistryConfig, scenario integrationScenario) error {
+	for i := 0; i < scenario.Runs; i++ {
+		// Mỗi lần chạy giống một workflow run mới: workspace sạch
+		if err := os.RemoveAll(scenario.DTO.AppName); err != nil {
+			return err
+		}
+		registryConfig.Git = scenario.Git
+		if err := processService(commandExecutor, scenario.DTO, registryConfig); err != nil {
+			return fmt.Errorf("run %d: %w", i+1, err)
+		}
+	}
+	return scenario.Check(github, scenario.DTO.Owner+"/"+scenario.DTO.AppName)
+}
+
+// checkCommitCount kiểm tra số commit trên main của bare repo
+func checkCommitCount(expected int) func(github *fakeGitHub, fullName string) error {
+	return func(github *fakeGitHub, fullName string) error {
+		out, err := (shellExecutor{}).Run(Command{Name: "git", Args: []string{"--git-dir", github.bareRepoPath(fullName), "rev-list", "--count", "main"}, CaptureOutput: true})
+		if err != nil {
+			return fmt.Errorf("main branch not pushed: %w", err)
+		}
+		if got := strings.TrimSpace(out); got != fmt.Sprint(expected) {
+			return fmt.Errorf("expected %d commits on main, got %s", expected, got)
+		}
+		return nil
+	}
+}
+
+// checkPushedRepo kiểm tra repo đã được tạo, settings đã apply và main branch có đủ file
+func checkPushedRepo(expectedFiles ...string) func(github *fakeGitHub, fullName string) error {
+	return func(github *fakeGitHub, fullName string) error {
+		github.mu.Lock()
+		repo, exists := github.repos[fullName]
+		github.mu.Unlock()
+		if !exists {
+			return fmt.Errorf("repo %s was not created", fullName)
+		}
+		if repo["has_wiki"] != false {
+			return fmt.Errorf("repo settings not applied: has_wiki=%v", repo["has_wiki"])
+		}
+
+		bareRepo := github.bareRepoPath(fullName)
+		out, err := (shellExecutor{}).Run(Command{Name: "git", Args: []string{"--git-dir", bareRepo, "ls-tree", "-r", "--name-only", "main"}, CaptureOutput: true})
+		if err != nil {
+			return fmt.Errorf("main branch not pushed to %s: %w", bareRepo, err)
+		}
+		files := strings.Split(strings.TrimSpace(out), "\n")
+		for _, expected := range expectedFiles {
+			if !containsString(files, expected) {
+				return fmt.Errorf("%s missing from pushed tree (got %v)", expected, files)
+			}
+		}
+		return nil
+	}
+}
@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"sync"
+	"time"
+)
+
+// jobQueueFile là queue mặc định của server mode, lưu cạnh state/services.json
+const jobQueueFile = "state/jobs.json"
+
+const (
+	jobQueued    = "queued"
+	jobRunning   = "running"
+	jobSucceeded = "succeeded"
+	jobFailed    = "failed"
+)
+
+// Job là một yêu cầu provisioning trong queue
+type Job struct {
+	ID            string `json:"id"`
+	ServicePath   string `json:"service_path"`
+	Confirm       string `json:"confirm,omitempty"` // app name đã xác nhận force push bằng confirmation token
+	Status        string `json:"status"`
+	Attempts      int    `json:"attempts"`
+	MaxAttempts   int    `json:"max_attempts"`
+	Error         string `json:"error,omitempty"`
+	ErrorCode     string `json:"error_code,omitempty"`
+	NextAttemptAt string `json:"next_attempt_at,omitempty"`
+	CreatedAt     string `json:"created_at"`
+	UpdatedAt     string `json:"updated_at"`
+}
+
+// JobQueue là queue bền vững dạng JSON file: mọi thay đổi được ghi xuống đĩa ngay,
+// job đang running khi process chết sẽ được chạy lại lúc khởi động.
+type JobQueue struct {
+	mu     sync.Mutex
+	path   string
+	Jobs   map[string]*Job `json:"jobs"`
+	notify chan struct{}
+}
+
+func loadJobQueue(path string) (*JobQueue, error) {
+	q := &JobQueue{path: path, Jobs: map[string]*Job{}, notify: make(chan struct{}, 1)}
+
+	data, err := os.ReadFile(path)
+	if err != nil && !os.IsNotExist(err) {
+		return nil, fmt.Errorf("failed to read job queue %s: %w", path, err)
+	}
+	if err == nil {
+		if err := json.Unmarshal(data, q); err != nil {
+			return nil, fmt.Errorf("failed to parse job queue %s: %w", path, err)
+		}
+	}
+	if q.Jobs == nil {
+		q.Jobs = map[string]*Job{}
+	}
+
+	// Crash recovery
+	for _, job := range q.Jobs {
+		if job.Status == jobRunning {
+			job.Status = jobQueued
+		}
+	}
+	return q, q.save()
+}
+
+func (q *JobQueue) save() error {
+	if err := os.MkdirAll(filepath.Dir(q.path), 0755); err != nil {
+		return fmt.Errorf("failed to create queue dir: %w", err)
+	}
+	data, err := json.MarshalIndent(q, "", "  ")
+	if err != nil {
+		return err
+	}
+	// Ghi file tạm rồi rename để không làm hỏng queue khi crash giữa chừng
+	tmp := q.path + ".tmp"
+	if err := os.WriteFile(tmp, append(data, '\n'), 0644); err != nil {
+		return err
+	}
+	return os.Rename(tmp, q.path)
+}
+
+// enqueue thêm job mới; nếu service đã có job queued/running thì trả về job đó (dedupe burst)
+func (q *JobQueue) enqueue(servicePath string, maxAttempts int, confirm string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	for _, job := range q.Jobs {
+		if job.ServicePath == servicePath && job.Status == jobQueued && confirm != "" && job.Confirm == "" {
+			job.Confirm = confirm
+			c := *job
+			return &c, q.save()
+		}
+		if job.ServicePath == servicePath && (job.Status == jobQueued || job.Status == jobRunning) {
+			c := *job
+			return &c, nil
+		}
+	}
+
+	id, err := newSourceID()
+	if err != nil {
+		return nil, err
+	}
+	now := time.Now().UTC().Format(time.RFC3339)
+	job := &Job{ID: id, ServicePath: servicePath, Confirm: confirm, Status: jobQueued, MaxAttempts: maxAttempts, CreatedAt: now, UpdatedAt: now}
+	q.Jobs[id] = job
+	if err := q.save(); err != nil {
+		return nil, err
+	}
+	q.wake()
+	// Bản copy: caller encode JSON ngoài q.mu trong khi worker sửa job (claim / finish)
+	c := *job
+	return &c, nil
+}
+
+// retry đưa job failed về lại queue
+func (q *JobQueue) retry(id string) (*Job, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.Jobs[id]
+	if !ok {
+		return nil, fmt.Errorf("job %s not found", id)
+	}
+	if job.Status != jobFailed {
+		return nil, fmt.Errorf("job %s is %s, only failed jobs can be retried", id, job.Status)
+	}
+	job.Status = jobQueued
+	job.Attempts = 0
+	job.NextAttemptAt = ""
+	job.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	if err := q.save(); err != nil {
+		return nil, err
+	}
+	q.wake()
+	c := *job
+	return &c, nil
+}
+
+func (q *JobQueue) wake() {
+	select {
+	case q.notify <- struct{}{}:
+	default:
+	}
+}
+
+// claim lấy job queued cũ nhất đã tới hạn chạy và chuyển sang running
+func (q *JobQueue) claim() *Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	var ready []*Job
+	now := time.Now().UTC().Format(time.RFC3339)
+	for _, job := range q.Jobs {
+		if job.Status == jobQueued && job.NextAttemptAt <= now {
+			ready = append(ready, job)
+		}
+	}
+	if len(ready) == 0 {
+		return nil
+	}
+	sort.Slice(ready, func(i, j int) bool { return ready[i].CreatedAt < ready[j].CreatedAt })
+
+	job := ready[0]
+	job.Status = jobRunning
+	job.Attempts++
+	job.UpdatedAt = now
+	if err := q.save(); err != nil {
+		fmt.Printf("⚠️ Failed to persist job queue: %v\n", err)
+	}
+	c := *job
+	return &c
+}
+
+// finish ghi kết quả; lỗi còn lượt retry thì xếp lại với backoff tăng dần
+func (q *JobQueue) finish(id string, jobErr error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+
+	job, ok := q.Jobs[id]
+	if !ok {
+		return
+	}
+	now := time.Now().UTC()
+	job.UpdatedAt = now.Format(time.RFC3339)
+	job.Error, job.ErrorCode, job.NextAttemptAt = "", "", ""
+
+	switch {
+	case jobErr == nil:
+		job.Status = jobSucceeded
+	case job.Attempts < job.MaxAttempts:
+		job.Status = jobQueued
+		job.Error, job.ErrorCode = redact(jobErr.Error()), string(errorCode(jobErr))
+		job.NextAttemptAt = now.Add(time.Duration(job.Attempts*job.Attempts) * 30 * time.Second).Format(time.RFC3339)
+	default:
+		job.Status = jobFailed
+		job.Error, job.ErrorCode = redact(jobErr.Error()), string(errorCode(jobErr))
+	}
+	if err := q.save(); err != nil {
+		fmt.Printf("⚠️ Failed to persist job queue: %v\n", err)
+	}
+}
+
+func (q *JobQueue) get(id string) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	job, ok := q.Jobs[id]
+	if !ok {
+		return Job{}, false
+	}
+	return *job, true
+}
+
+// list trả về các job (lọc theo status nếu có), mới nhất trước
+func (q *JobQueue) list(status string) []Job {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	jobs := []Job{}
+	for _, job := range q.Jobs {
+		if status == "" || job.Status == status {
+			jobs = append(jobs, *job)
+		}
+	}
+	sort.Slice(jobs, func(i, j int) bool { return jobs[i].CreatedAt > jobs[j].CreatedAt })
+	return jobs
+}
+
+// latest trả về job mới nhất của service (theo created_at)
+func (q *JobQueue) latest(servicePath string) (Job, bool) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	var found *Job
+	for _, job := range q.Jobs {
+		if job.ServicePath == servicePath && (found == nil || job.CreatedAt > found.CreatedAt) {
+			found = job
+		}
+	}
+	if found == nil {
+		return Job{}, false
+	}
+	return *found, true
+}
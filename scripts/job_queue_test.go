@@ -0,0 +1,108 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+func TestJobQueueReturnsCopies(t *testing.T) {
+	q, err := loadJobQueue(filepath.Join(t.TempDir(), "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.enqueue("sources-service/orders", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	job.Status = jobSucceeded
+	if stored, _ := q.get(job.ID); stored.Status != jobQueued {
+		t.Fatalf("mutating the enqueued job changed the queue: status %s", stored.Status)
+	}
+
+	// Dedupe trả về job đang queued, cũng là bản copy
+	again, err := q.enqueue("sources-service/orders", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if again.ID != job.ID || again == job {
+		t.Fatalf("dedupe returned %p (%s), first %p (%s)", again, again.ID, job, job.ID)
+	}
+}
+
+// Chạy với -race: caller đọc job trả về trong khi worker claim / finish
+func TestJobQueueConcurrentWorker(t *testing.T) {
+	q, err := loadJobQueue(filepath.Join(t.TempDir(), "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	job, err := q.enqueue("sources-service/orders", 1, "")
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	var wg sync.WaitGroup
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if claimed := q.claim(); claimed != nil {
+			q.finish(claimed.ID, errors.New("boom"))
+		}
+	}()
+	_ = job.Status + job.Error
+	_ = job.Attempts
+	wg.Wait()
+
+	retried, err := q.retry(job.ID)
+	if err != nil {
+		t.Fatal(err)
+	}
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if claimed := q.claim(); claimed != nil {
+			q.finish(claimed.ID, nil)
+		}
+	}()
+	_ = retried.Status + retried.Error
+	wg.Wait()
+}
+
+func TestRegistryWebhookRequiresSecret(t *testing.T) {
+	q, err := loadJobQueue(filepath.Join(t.TempDir(), "jobs.json"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	body := `{"ref":"refs/heads/main","commits":[{"added":["sources-service/orders/source.yml"]}]}`
+	deliver := func(server ServerConfig, signature string) int {
+		req := httptest.NewRequest(http.MethodPost, "/webhooks/github", strings.NewReader(body))
+		req.Header.Set("X-GitHub-Event", "push")
+		req.Header.Set("X-Hub-Signature-256", signature)
+		rec := httptest.NewRecorder()
+		handleRegistryWebhook(q, server)(rec, req)
+		return rec.Code
+	}
+
+	// Không có secret: push event giả không được enqueue job
+	if code := deliver(ServerConfig{}, ""); code != http.StatusUnauthorized {
+		t.Fatalf("unsigned webhook without secret: status %d, want %d", code, http.StatusUnauthorized)
+	}
+	if jobs := q.list(""); len(jobs) != 0 {
+		t.Fatalf("forged webhook queued jobs: %+v", jobs)
+	}
+
+	t.Setenv("TEST_WEBHOOK_SECRET", "s3cret")
+	mac := hmac.New(sha256.New, []byte("s3cret"))
+	mac.Write([]byte(body))
+	signature := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	if code := deliver(ServerConfig{WebhookSecretEnv: "TEST_WEBHOOK_SECRET"}, signature); code != http.StatusAccepted {
+		t.Fatalf("signed webhook: status %d, want %d", code, http.StatusAccepted)
+	}
+}
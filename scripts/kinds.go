@@ -0,0 +1,73 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+type kindTemplateData struct {
+	Service     GeneratorSourceDto
+	Module      string // Go module path hoặc npm package name
+	PackageName string // Go package name
+	Publish     PublishTarget
+}
+
+// processTemplateKind scaffold project theo kind (library, cli, ...) hoàn toàn từ
+// templates/<kind>/<language>/ rồi tạo repo như service thường
+func processTemplateKind(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	fmt.Printf("\n📚 Processing %s %s...\n", dto.ProgrammingLanguage, dto.Kind)
+
+	if registryConfig.FrameworkTemplates == "" {
+		return withCode(ErrGeneratorFailed, fmt.Errorf("framework_templates is not configured"))
+	}
+	srcDir := filepath.Join(registryConfig.FrameworkTemplates, dto.Kind, dto.ProgrammingLanguage)
+	if _, err := os.Stat(srcDir); err != nil {
+		return withCode(ErrGeneratorFailed, fmt.Errorf("no %s templates for %s in %s", dto.Kind, dto.ProgrammingLanguage, registryConfig.FrameworkTemplates))
+	}
+
+	if dto.Kind == "library" {
+		if err := validatePublishing(dto, registryConfig.Publishing); err != nil {
+			return withCode(ErrValidationFailed, err)
+		}
+	}
+
+	data := kindTemplateData{Service: dto, PackageName: goPackageName(dto.AppName), Publish: registryConfig.Publishing.target(dto)}
+	switch dto.ProgrammingLanguage {
+	case "golang":
+		data.Module = goModulePath(dto)
+	case "nodejs":
+		data.Module = nodePackageName(dto)
+	}
+
+	fmt.Printf("🚀 Generating %s: %s\n", dto.Kind, dto.AppName)
+	if err := renderTemplateDir(srcDir, dto.AppName, data); err != nil {
+		return withCode(ErrGeneratorFailed, fmt.Errorf("failed to generate %s: %w", dto.Kind, err))
+	}
+
+	// Resolve dependencies để go.sum / package-lock.json có sẵn trong initial commit
+	switch dto.ProgrammingLanguage {
+	case "golang":
+		if err := runCommandInDir(ex, dto.AppName, "go", "mod", "tidy"); err != nil {
+			return withCode(ErrGeneratorFailed, fmt.Errorf("go mod tidy failed: %w", err))
+		}
+	case "nodejs":
+		if err := runCommandInDir(ex, dto.AppName, "npm", "install", "--package-lock-only"); err != nil {
+			return withCode(ErrGeneratorFailed, fmt.Errorf("npm install failed: %w", err))
+		}
+	}
+
+	return provisionRepository(ex, dto, registryConfig)
+}
+
+// goPackageName: "shared-utils" -> "sharedutils"
+func goPackageName(name string) string {
+	var b strings.Builder
+	for _, r := range strings.ToLower(name) {
+		if (r >= 'a' && r <= 'z') || (r >= '0' && r <= '9') {
+			b.WriteRune(r)
+		}
+	}
+	return b.String()
+}
@@ -0,0 +1,326 @@
+package main
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// LicensePolicy kiểm tra license của dependency mà scaffold khai báo (go.mod, package.json) trước khi push,
+// để template không kéo dependency GPL vào service proprietary. License dùng SPDX ID.
+type LicensePolicy struct {
+	Allow []string `yaml:"allow"` // rỗng = mọi license không bị deny
+	Deny  []string `yaml:"deny"`  // hỗ trợ wildcard cuối, vd. GPL-*
+	// Overrides khai báo tay license của dependency không detect được (module / package -> SPDX)
+	Overrides    map[string]string `yaml:"overrides"`
+	AllowUnknown bool              `yaml:"allow_unknown"`
+	// ExemptPublic bỏ qua service visibility public (repo open source được dùng copyleft)
+	ExemptPublic bool `yaml:"exempt_public"`
+}
+
+func (p LicensePolicy) enabled() bool {
+	return len(p.Allow) > 0 || len(p.Deny) > 0
+}
+
+// Dependency là một dependency khai báo trong scaffold và license detect được ("" = không rõ)
+type Dependency struct {
+	Ecosystem string
+	Name      string
+	Version   string
+	License   string
+}
+
+// licenseMatches so SPDX ID với pattern (không phân biệt hoa thường, "*" ở cuối là prefix)
+func licenseMatches(license, pattern string) bool {
+	if prefix, ok := strings.CutSuffix(pattern, "*"); ok {
+		return strings.HasPrefix(strings.ToLower(license), strings.ToLower(prefix))
+	}
+	return strings.EqualFold(license, pattern)
+}
+
+func (p LicensePolicy) allowsID(license string) bool {
+	for _, pattern := range p.Deny {
+		if licenseMatches(license, pattern) {
+			return false
+		}
+	}
+	if len(p.Allow) == 0 {
+		return true
+	}
+	for _, pattern := range p.Allow {
+		if licenseMatches(license, pattern) {
+			return true
+		}
+	}
+	return false
+}
+
+// allows đánh giá SPDX expression đơn giản: "A OR B" cần một vế được phép, "A AND B" cần cả hai
+func (p LicensePolicy) allows(expression string) bool {
+	expression = strings.NewReplacer("(", "", ")", "").Replace(expression)
+	for _, alternative := range strings.Split(expression, " OR ") {
+		ok := true
+		for _, license := range strings.Split(alternative, " AND ") {
+			if !p.allowsID(strings.TrimSpace(license)) {
+				ok = false
+				break
+			}
+		}
+		if ok {
+			return true
+		}
+	}
+	return false
+}
+
+// checkLicenses trả về danh sách vi phạm của dependency trong repoDir
+func checkLicenses(ex Executor, repoDir string, p LicensePolicy) ([]string, error) {
+	deps, err := scaffoldDependencies(ex, repoDir)
+	if err != nil {
+		return nil, err
+	}
+	var violations []string
+	for _, dep := range deps {
+		if license, ok := p.Overrides[dep.Name]; ok {
+			dep.License = license
+		}
+		switch {
+		case dep.License == "":
+			if !p.AllowUnknown {
+				violations = append(violations, fmt.Sprintf("%s %s@%s: license could not be detected (add it to licenses.overrides)", dep.Ecosystem, dep.Name, dep.Version))
+			}
+		case !p.allows(dep.License):
+			violations = append(violations, fmt.Sprintf("%s %s@%s: license %s is not allowed", dep.Ecosystem, dep.Name, dep.Version, dep.License))
+		}
+	}
+	return violations, nil
+}
+
+func enforceLicensePolicy(ex Executor, repoDir string, dto GeneratorSourceDto, p LicensePolicy) error {
+	if !p.enabled() || (p.ExemptPublic && dto.Visibility == "public") {
+		return nil
+	}
+	fmt.Println("⚖️  Checking dependency licenses...")
+	violations, err := checkLicenses(ex, repoDir, p)
+	if err != nil {
+		return fmt.Errorf("failed to inspect dependencies: %w", err)
+	}
+	if len(violations) == 0 {
+		return nil
+	}
+
+	fmt.Printf("❌ Dependencies of %s violate the license policy:\n", repoDir)
+	for _, v := range violations {
+		fmt.Printf("  - %s\n", v)
+	}
+	return withCode(ErrGuardrailViolation, fmt.Errorf("%d dependency license(s) violate the license policy", len(violations)))
+}
+
+// scaffoldDependencies đọc dependency của go.mod và package.json (nếu có) ở root repo
+func scaffoldDependencies(ex Executor, repoDir string) ([]Dependency, error) {
+	var deps []Dependency
+	if _, err := os.Stat(filepath.Join(repoDir, "go.mod")); err == nil {
+		goDeps, err := goDependencies(ex, repoDir)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, goDeps...)
+	}
+	if _, err := os.Stat(filepath.Join(repoDir, "package.json")); err == nil {
+		nodeDeps, err := nodeDependencies(ex, repoDir)
+		if err != nil {
+			return nil, err
+		}
+		deps = append(deps, nodeDeps...)
+	}
+	return deps, nil
+}
+
+// goModRequires đọc các module trong require của go.mod (cả direct và indirect)
+func goModRequires(path string) (map[string]string, error) {
+	f, err := os.Open(path)
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	requires := map[string]string{}
+	inBlock := false
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		line := strings.TrimSpace(scanner.Text())
+		if i := strings.Index(line, "//"); i >= 0 {
+			line = strings.TrimSpace(line[:i])
+		}
+		switch {
+		case line == "require (":
+			inBlock = true
+			continue
+		case inBlock && line == ")":
+			inBlock = false
+			continue
+		case strings.HasPrefix(line, "require "):
+			line = strings.TrimSpace(strings.TrimPrefix(line, "require "))
+		case !inBlock:
+			continue
+		}
+		if fields := strings.Fields(line); len(fields) == 2 {
+			requires[fields[0]] = fields[1]
+		}
+	}
+	return requires, scanner.Err()
+}
+
+// goDependencies: license đọc từ file LICENSE trong module cache (go mod tidy đã tải module về)
+func goDependencies(ex Executor, repoDir string) ([]Dependency, error) {
+	requires, err := goModRequires(filepath.Join(repoDir, "go.mod"))
+	if err != nil {
+		return nil, err
+	}
+	if len(requires) == 0 {
+		return nil, nil
+	}
+
+	dirs := map[string]string{}
+	out, err := runCommandOutputInDir(ex, repoDir, "go", "list", "-m", "-json", "all")
+	if err != nil {
+		fmt.Printf("  ⚠️ Failed to list Go modules: %v\n", err)
+	}
+	decoder := json.NewDecoder(strings.NewReader(out))
+	for {
+		var module struct {
+			Path string
+			Dir  string
+		}
+		if err := decoder.Decode(&module); err != nil {
+			if err != io.EOF {
+				fmt.Printf("  ⚠️ Failed to parse go list output: %v\n", err)
+			}
+			break
+		}
+		dirs[module.Path] = module.Dir
+	}
+
+	var deps []Dependency
+	for name, version := range requires {
+		dep := Dependency{Ecosystem: "go", Name: name, Version: version}
+		if dir := dirs[name]; dir != "" {
+			dep.License = detectLicenseFile(dir)
+		}
+		deps = append(deps, dep)
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps, nil
+}
+
+// licenseFileNames là các tên file license thường gặp ở root module
+var licenseFileNames = []string{"LICENSE", "LICENSE.md", "LICENSE.txt", "LICENCE", "COPYING", "COPYING.md", "LICENSE-MIT", "LICENSE-APACHE"}
+
+func detectLicenseFile(dir string) string {
+	for _, name := range licenseFileNames {
+		data, err := os.ReadFile(filepath.Join(dir, name))
+		if err == nil {
+			return classifyLicense(string(data))
+		}
+	}
+	return ""
+}
+
+// classifyLicense nhận diện license phổ biến theo đoạn văn bản đặc trưng ("" = không nhận diện được)
+func classifyLicense(text string) string {
+	text = strings.Join(strings.Fields(text), " ")
+	version3 := strings.Contains(text, "Version 3")
+	switch {
+	case strings.Contains(text, "GNU AFFERO GENERAL PUBLIC LICENSE"):
+		return "AGPL-3.0"
+	case strings.Contains(text, "GNU LESSER GENERAL PUBLIC LICENSE"):
+		if version3 {
+			return "LGPL-3.0"
+		}
+		return "LGPL-2.1"
+	case strings.Contains(text, "GNU GENERAL PUBLIC LICENSE"):
+		if version3 {
+			return "GPL-3.0"
+		}
+		return "GPL-2.0"
+	case strings.Contains(text, "Mozilla Public License Version 2.0"), strings.Contains(text, "Mozilla Public License, version 2.0"):
+		return "MPL-2.0"
+	case strings.Contains(text, "Apache License") && strings.Contains(text, "Version 2.0"):
+		return "Apache-2.0"
+	case strings.Contains(text, "Permission is hereby granted, free of charge"):
+		return "MIT"
+	case strings.Contains(text, "Redistribution and use in source and binary forms"):
+		if strings.Contains(text, "Neither the name") || strings.Contains(text, "names of its contributors") {
+			return "BSD-3-Clause"
+		}
+		return "BSD-2-Clause"
+	case strings.Contains(text, "Permission to use, copy, modify, and/or distribute this software"):
+		return "ISC"
+	case strings.Contains(text, "This is free and unencumbered software released into the public domain"):
+		return "Unlicense"
+	}
+	return ""
+}
+
+// nodeDependencies: license lấy từ package-lock.json (lockfileVersion 2+ ghi license từng package),
+// không có lockfile thì hỏi registry cho dependency trực tiếp của package.json
+func nodeDependencies(ex Executor, repoDir string) ([]Dependency, error) {
+	var deps []Dependency
+	data, err := os.ReadFile(filepath.Join(repoDir, "package-lock.json"))
+	if err == nil {
+		var lock struct {
+			Packages map[string]struct {
+				Version string      `json:"version"`
+				License interface{} `json:"license"`
+			} `json:"packages"`
+		}
+		if err := json.Unmarshal(data, &lock); err != nil {
+			return nil, fmt.Errorf("failed to parse package-lock.json: %w", err)
+		}
+		for path, pkg := range lock.Packages {
+			if path == "" {
+				continue
+			}
+			name := path[strings.LastIndex(path, "node_modules/")+len("node_modules/"):]
+			license, _ := pkg.License.(string)
+			deps = append(deps, Dependency{Ecosystem: "npm", Name: name, Version: pkg.Version, License: license})
+		}
+	} else if os.IsNotExist(err) {
+		data, err := os.ReadFile(filepath.Join(repoDir, "package.json"))
+		if err != nil {
+			return nil, err
+		}
+		var pkg struct {
+			Dependencies    map[string]string `json:"dependencies"`
+			DevDependencies map[string]string `json:"devDependencies"`
+		}
+		if err := json.Unmarshal(data, &pkg); err != nil {
+			return nil, fmt.Errorf("failed to parse package.json: %w", err)
+		}
+		for _, list := range []map[string]string{pkg.Dependencies, pkg.DevDependencies} {
+			for name, version := range list {
+				license, err := runCommandOutputInDir(ex, repoDir, "npm", "view", name+"@"+version, "license")
+				if err != nil {
+					fmt.Printf("  ⚠️ Failed to read license of %s: %v\n", name, err)
+				}
+				// Range khớp nhiều version thì npm in mỗi dòng "<name>@<version> '<license>'", lấy version mới nhất
+				lines := strings.Split(strings.TrimSpace(license), "\n")
+				license = lines[len(lines)-1]
+				if strings.HasPrefix(license, name+"@") {
+					_, license, _ = strings.Cut(license, " ")
+					license = strings.Trim(license, "'")
+				}
+				deps = append(deps, Dependency{Ecosystem: "npm", Name: name, Version: version, License: license})
+			}
+		}
+	} else {
+		return nil, err
+	}
+	sort.Slice(deps, func(i, j int) bool { return deps[i].Name < deps[j].Name })
+	return deps, nil
+}
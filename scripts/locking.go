@@ -0,0 +1,240 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Locking chặn 2 run chồng nhau cùng provisioning (force push) một service, key theo source_id.
+//   - file:   lock file trong Dir (chạy local / server mode trên một máy)
+//   - github: ref jupiter-lock/<source_id> trên chính repo jupiter-registry (CI, nhiều runner)
+//   - auto:   github khi chạy trong GitHub Actions, ngược lại file
+//   - none:   tắt
+type Locking struct {
+	Backend string `yaml:"backend"`
+	Dir     string `yaml:"dir"`  // mặc định .jupiter-locks
+	TTL     string `yaml:"ttl"`  // lock cũ hơn TTL được coi là bỏ rơi, mặc định 30m
+	Wait    string `yaml:"wait"` // thời gian chờ lock, mặc định 0 (fail ngay)
+}
+
+func (l Locking) durations() (ttl, wait time.Duration, err error) {
+	ttl = 30 * time.Minute
+	if l.TTL != "" {
+		if ttl, err = time.ParseDuration(l.TTL); err != nil {
+			return 0, 0, fmt.Errorf("invalid locking.ttl %q: %w", l.TTL, err)
+		}
+	}
+	if l.Wait != "" {
+		if wait, err = time.ParseDuration(l.Wait); err != nil {
+			return 0, 0, fmt.Errorf("invalid locking.wait %q: %w", l.Wait, err)
+		}
+	}
+	return ttl, wait, nil
+}
+
+// errLockHeld: lock đang bị run khác giữ
+var errLockHeld = errors.New("lock is held")
+
+type lockBackend interface {
+	tryAcquire(key string, ttl time.Duration) (release func(), err error)
+}
+
+// acquireServiceLock lấy lock cho key (source_id), đợi tối đa locking.wait
+func acquireServiceLock(key string, locking Locking) (func(), error) {
+	backend, err := newLockBackend(locking)
+	if err != nil || backend == nil {
+		return func() {}, err
+	}
+	ttl, wait, err := locking.durations()
+	if err != nil {
+		return nil, withCode(ErrUsage, err)
+	}
+
+	deadline := time.Now().Add(wait)
+	for {
+		release, err := backend.tryAcquire(key, ttl)
+		if err == nil {
+			return holdLock(release), nil
+		}
+		if !errors.Is(err, errLockHeld) {
+			return nil, fmt.Errorf("failed to acquire lock %s: %w", key, err)
+		}
+		if time.Now().After(deadline) {
+			return nil, withCode(ErrLocked, fmt.Errorf("service %s is being provisioned by another run", key))
+		}
+		fmt.Printf("⏳ Waiting for lock %s...\n", key)
+		time.Sleep(10 * time.Second)
+	}
+}
+
+// heldLocks: release của các lock process đang giữ, để exitWithError trả lock trước os.Exit
+var heldLocks = struct {
+	sync.Mutex
+	next     int
+	releases map[int]func()
+}{releases: map[int]func(){}}
+
+// holdLock ghi nhận lock đang giữ; release trả về chỉ chạy một lần (defer hoặc releaseHeldLocks)
+func holdLock(release func()) func() {
+	heldLocks.Lock()
+	defer heldLocks.Unlock()
+	id := heldLocks.next
+	heldLocks.next++
+	var once sync.Once
+	wrapped := func() {
+		heldLocks.Lock()
+		delete(heldLocks.releases, id)
+		heldLocks.Unlock()
+		once.Do(release)
+	}
+	heldLocks.releases[id] = wrapped
+	return wrapped
+}
+
+func releaseHeldLocks() {
+	heldLocks.Lock()
+	releases := make([]func(), 0, len(heldLocks.releases))
+	for _, release := range heldLocks.releases {
+		releases = append(releases, release)
+	}
+	heldLocks.Unlock()
+	for _, release := range releases {
+		release()
+	}
+}
+
+func newLockBackend(locking Locking) (lockBackend, error) {
+	backend := locking.Backend
+	if backend == "" || backend == "auto" {
+		backend = "file"
+		if os.Getenv("GITHUB_ACTIONS") == "true" && os.Getenv("GITHUB_REPOSITORY") != "" {
+			backend = "github"
+		}
+	}
+
+	switch backend {
+	case "none":
+		return nil, nil
+	case "file":
+		dir := locking.Dir
+		if dir == "" {
+			dir = ".jupiter-locks"
+		}
+		return fileLock{dir: dir}, nil
+	case "github":
+		return githubLock{repo: os.Getenv("GITHUB_REPOSITORY")}, nil
+	default:
+		return nil, withCode(ErrUsage, fmt.Errorf("unsupported locking backend: %s", locking.Backend))
+	}
+}
+
+// lockHolder mô tả run đang giữ lock
+func lockHolder() string {
+	if runID := os.Getenv("GITHUB_RUN_ID"); runID != "" {
+		return fmt.Sprintf("%s run %s", os.Getenv("GITHUB_WORKFLOW"), runID)
+	}
+	host, _ := os.Hostname()
+	return fmt.Sprintf("%s pid %d", host, os.Getpid())
+}
+
+var lockKeyPattern = regexp.MustCompile(`[^A-Za-z0-9._-]`)
+
+// fileLock dùng O_EXCL để tạo lock file một cách atomic
+type fileLock struct {
+	dir string
+}
+
+type fileLockInfo struct {
+	Holder     string `json:"holder"`
+	AcquiredAt string `json:"acquired_at"`
+}
+
+func (l fileLock) tryAcquire(key string, ttl time.Duration) (func(), error) {
+	if err := os.MkdirAll(l.dir, 0755); err != nil {
+		return nil, err
+	}
+	path := filepath.Join(l.dir, lockKeyPattern.ReplaceAllString(key, "_")+".lock")
+
+	f, err := os.OpenFile(path, os.O_CREATE|os.O_EXCL|os.O_WRONLY, 0644)
+	if os.IsExist(err) {
+		// Lock bị bỏ rơi (process chết) thì dọn và thử lại lần sau
+		var info fileLockInfo
+		if data, readErr := os.ReadFile(path); readErr == nil && json.Unmarshal(data, &info) == nil {
+			if acquired, parseErr := time.Parse(time.RFC3339, info.AcquiredAt); parseErr == nil && time.Since(acquired) > ttl {
+				fmt.Printf("⚠️ Removing stale lock %s held by %s\n", key, info.Holder)
+				os.Remove(path)
+			}
+		}
+		return nil, errLockHeld
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	json.NewEncoder(f).Encode(fileLockInfo{Holder: lockHolder(), AcquiredAt: time.Now().UTC().Format(time.RFC3339)})
+	return func() { os.Remove(path) }, nil
+}
+
+// githubLock: tạo ref trên jupiter-registry là thao tác atomic (422 nếu ref đã tồn tại).
+// Ref trỏ tới một commit rời có message ghi holder, committer date dùng để xét TTL.
+type githubLock struct {
+	repo string
+}
+
+func (l githubLock) tryAcquire(key string, ttl time.Duration) (func(), error) {
+	ref := "heads/jupiter-lock/" + lockKeyPattern.ReplaceAllString(key, "_")
+
+	tree, err := ghRegistryOutput(commandExecutor, "api", fmt.Sprintf("repos/%s/commits/HEAD", l.repo), "--jq", ".commit.tree.sha")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read registry HEAD: %w", err)
+	}
+	commitBody, _ := json.Marshal(map[string]interface{}{
+		"message": fmt.Sprintf("jupiter lock %s held by %s", key, lockHolder()),
+		"tree":    tree,
+		"parents": []string{},
+	})
+	commit, err := ghRegistryOutputWithInput(commandExecutor, commitBody, "api", "-X", "POST", fmt.Sprintf("repos/%s/git/commits", l.repo), "--input", "-", "--jq", ".sha")
+	if err != nil {
+		return nil, fmt.Errorf("failed to create lock commit: %w", err)
+	}
+
+	// Chỉ 422 "Reference already exists" là lock đang bị giữ; 401/403, thiếu contents: write, network... là lỗi thật
+	refBody, _ := json.Marshal(map[string]string{"ref": "refs/" + ref, "sha": commit})
+	if out, err := ghRegistryOutputWithInput(commandExecutor, refBody, "api", "-X", "POST", fmt.Sprintf("repos/%s/git/refs", l.repo), "--input", "-"); err != nil {
+		if status, _ := ghErrorStatus(out); status != http.StatusUnprocessableEntity {
+			return nil, withCode(ErrGitHubAPI, fmt.Errorf("failed to create lock ref %s: %w", ref, err))
+		}
+		l.removeIfStale(ref, ttl)
+		return nil, errLockHeld
+	}
+
+	return func() {
+		if err := ghRegistryWithInput(commandExecutor, nil, "api", "-X", "DELETE", fmt.Sprintf("repos/%s/git/refs/%s", l.repo, ref)); err != nil {
+			fmt.Printf("⚠️ Failed to release lock %s: %v\n", key, err)
+		}
+	}, nil
+}
+
+func (l githubLock) removeIfStale(ref string, ttl time.Duration) {
+	out, err := ghRegistryOutput(commandExecutor, "api", fmt.Sprintf("repos/%s/git/ref/%s", l.repo, ref), "--jq", ".object.sha")
+	if err != nil {
+		return
+	}
+	date, err := ghRegistryOutput(commandExecutor, "api", fmt.Sprintf("repos/%s/git/commits/%s", l.repo, strings.TrimSpace(out)), "--jq", ".committer.date")
+	if err != nil {
+		return
+	}
+	if acquired, err := time.Parse(time.RFC3339, date); err == nil && time.Since(acquired) > ttl {
+		fmt.Printf("⚠️ Removing stale lock ref %s\n", ref)
+		ghRegistryWithInput(commandExecutor, nil, "api", "-X", "DELETE", fmt.Sprintf("repos/%s/git/refs/%s", l.repo, ref))
+	}
+}
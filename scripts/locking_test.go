@@ -0,0 +1,66 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+)
+
+func TestGithubLockHeldOnlyOn422(t *testing.T) {
+	t.Setenv("GITHUB_REPOSITORY", "acme/jupiter-registry")
+	locking := Locking{Backend: "github"}
+
+	fake := useFakeExecutor(t)
+	fake.On("gh api -X POST repos/acme/jupiter-registry/git/refs", FakeResponse{
+		Output: `{"message":"Reference already exists","status":"422"}`,
+		Err:    errors.New("exit status 1"),
+	})
+	if _, err := acquireServiceLock("orders", locking); errorCode(err) != ErrLocked {
+		t.Fatalf("422: error code = %s (%v), want %s", errorCode(err), err, ErrLocked)
+	}
+
+	fake = useFakeExecutor(t)
+	fake.On("gh api -X POST repos/acme/jupiter-registry/git/refs", FakeResponse{
+		Output: `{"message":"Resource not accessible by integration","status":"403"}`,
+		Err:    errors.New("exit status 1"),
+	})
+	if _, err := acquireServiceLock("orders", locking); errorCode(err) != ErrGitHubAPI {
+		t.Fatalf("403: error code = %s (%v), want %s", errorCode(err), err, ErrGitHubAPI)
+	}
+
+	// Network / gh không chạy được: không có body
+	fake = useFakeExecutor(t)
+	fake.On("gh api -X POST repos/acme/jupiter-registry/git/refs", FakeResponse{Err: errors.New("dial tcp: i/o timeout")})
+	if _, err := acquireServiceLock("orders", locking); errorCode(err) != ErrGitHubAPI {
+		t.Fatalf("network: error code = %s (%v), want %s", errorCode(err), err, ErrGitHubAPI)
+	}
+}
+
+func TestReleaseHeldLocks(t *testing.T) {
+	dir := t.TempDir()
+	release, err := acquireServiceLock("orders", Locking{Backend: "file", Dir: dir})
+	if err != nil {
+		t.Fatal(err)
+	}
+	lockFile := filepath.Join(dir, "orders.lock")
+	if _, err := os.Stat(lockFile); err != nil {
+		t.Fatalf("lock file not created: %v", err)
+	}
+
+	// exitWithError gọi releaseHeldLocks trước os.Exit
+	releaseHeldLocks()
+	if _, err := os.Stat(lockFile); !os.IsNotExist(err) {
+		t.Fatalf("lock not released: %v", err)
+	}
+
+	// defer release() sau đó không xoá lock của run khác
+	if _, err := acquireServiceLock("orders", Locking{Backend: "file", Dir: dir}); err != nil {
+		t.Fatal(err)
+	}
+	release()
+	if _, err := os.Stat(lockFile); err != nil {
+		t.Fatalf("second release removed a lock held by another acquisition: %v", err)
+	}
+	releaseHeldLocks()
+}
@@ -0,0 +1,269 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// ManagedFiles là các file policy của org (SECURITY.md, CODE_OF_CONDUCT.md, workflow dùng chung...) mà registry
+// giữ đồng bộ trên mọi repo đã provisioning: file lệch so với bản trong registry được sửa qua PR, không push thẳng.
+type ManagedFiles struct {
+	Files []ManagedFile `yaml:"files"`
+	// Branch: branch của PR sync trên repo đích, mặc định jupiter/managed-files
+	Branch string `yaml:"branch"`
+	// Exclude: service không sync (vd. repo có policy riêng)
+	Exclude []string `yaml:"exclude"`
+}
+
+// ManagedFile: Source là file trong registry, Path là đường dẫn trong repo đích
+type ManagedFile struct {
+	Path   string `yaml:"path"`
+	Source string `yaml:"source"`
+}
+
+func (m ManagedFiles) branch() string {
+	if m.Branch == "" {
+		return "jupiter/managed-files"
+	}
+	return m.Branch
+}
+
+func (m ManagedFiles) excluded(service string) bool {
+	for _, name := range m.Exclude {
+		if name == service {
+			return true
+		}
+	}
+	return false
+}
+
+// managedContent là nội dung mong muốn của một managed file
+type managedContent struct {
+	Path string
+	Data []byte
+	SHA  string // git blob SHA, so trực tiếp với sha của contents API
+}
+
+// loadManagedFiles đọc nội dung các managed file từ registry checkout
+func loadManagedFiles(m ManagedFiles) ([]managedContent, error) {
+	var contents []managedContent
+	for _, f := range m.Files {
+		if f.Path == "" || f.Source == "" {
+			return nil, withCode(ErrValidationFailed, fmt.Errorf("managed_files: path and source are required"))
+		}
+		if path.IsAbs(f.Path) || strings.HasPrefix(path.Clean(f.Path), "..") {
+			return nil, withCode(ErrValidationFailed, fmt.Errorf("managed_files: path %q must be relative to the repository root", f.Path))
+		}
+		data, err := os.ReadFile(f.Source)
+		if err != nil {
+			return nil, withCode(ErrValidationFailed, fmt.Errorf("managed_files: %w", err))
+		}
+		contents = append(contents, managedContent{Path: path.Clean(f.Path), Data: data, SHA: gitBlobSHA(data)})
+	}
+	return contents, nil
+}
+
+// ManagedFilesResult là kết quả sync một repo
+type ManagedFilesResult struct {
+	Service  string   `json:"service"`
+	Repo     string   `json:"repo"`
+	Outdated []string `json:"outdated"`
+	PR       string   `json:"pull_request,omitempty"`
+	Error    string   `json:"error,omitempty"`
+}
+
+// remoteFileSHA: blob SHA của file trên ref, "" nếu file chưa có
+func remoteFileSHA(repo, filePath, ref string) string {
+	sha, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/contents/%s?ref=%s", repo, filePath, ref), "--jq", ".sha")
+	if err != nil {
+		return ""
+	}
+	return strings.TrimSpace(sha)
+}
+
+// outdatedManagedFiles: managed file thiếu hoặc khác bản trong registry trên ref
+func outdatedManagedFiles(repo, ref string, contents []managedContent) []managedContent {
+	var outdated []managedContent
+	for _, c := range contents {
+		if remoteFileSHA(repo, c.Path, ref) != c.SHA {
+			outdated = append(outdated, c)
+		}
+	}
+	return outdated
+}
+
+// syncManagedFiles mở (hoặc cập nhật) PR đưa các managed file bị lệch về bản trong registry.
+// Branch sync đã chứa đúng nội dung và PR còn mở thì không commit lại mỗi vòng reconcile.
+func syncManagedFiles(repo string, m ManagedFiles, contents []managedContent, dryRun bool) (ManagedFilesResult, error) {
+	result := ManagedFilesResult{Repo: repo, Outdated: []string{}}
+	base, err := runCommandOutput(commandExecutor, "gh", "api", "repos/"+repo, "--jq", ".default_branch")
+	if err != nil {
+		return result, withCode(ErrGitHubAPI, fmt.Errorf("failed to read %s: %w", repo, err))
+	}
+	base = strings.TrimSpace(base)
+
+	outdated := outdatedManagedFiles(repo, base, contents)
+	for _, c := range outdated {
+		result.Outdated = append(result.Outdated, c.Path)
+	}
+	if len(outdated) == 0 || dryRun {
+		return result, nil
+	}
+
+	branch := m.branch()
+	openPR, _ := runCommandOutput(commandExecutor, "gh", "pr", "list", "--repo", repo, "--head", branch, "--state", "open", "--json", "url", "--jq", ".[0].url")
+	openPR = strings.TrimSpace(openPR)
+	if openPR != "" && len(outdatedManagedFiles(repo, branch, outdated)) == 0 {
+		result.PR = openPR
+		return result, nil
+	}
+
+	// Branch sync thuộc về registry: reset về default branch rồi commit lại bản mới nhất
+	head, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/git/ref/heads/%s", repo, base), "--jq", ".object.sha")
+	if err != nil {
+		return result, withCode(ErrGitHubAPI, fmt.Errorf("failed to read %s %s: %w", repo, base, err))
+	}
+	head = strings.TrimSpace(head)
+	if _, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/git/ref/heads/%s", repo, branch), "--jq", ".object.sha"); err == nil {
+		_, err = runCommandOutput(commandExecutor, "gh", "api", "-X", "PATCH", fmt.Sprintf("repos/%s/git/refs/heads/%s", repo, branch), "-f", "sha="+head, "-F", "force=true")
+		if err != nil {
+			return result, withCode(ErrGitHubAPI, fmt.Errorf("failed to reset %s on %s: %w", branch, repo, err))
+		}
+	} else if _, err := runCommandOutput(commandExecutor, "gh", "api", "-X", "POST", fmt.Sprintf("repos/%s/git/refs", repo), "-f", "ref=refs/heads/"+branch, "-f", "sha="+head); err != nil {
+		return result, withCode(ErrGitHubAPI, fmt.Errorf("failed to create branch %s on %s: %w", branch, repo, err))
+	}
+
+	for _, c := range outdated {
+		body := map[string]string{
+			"message": "chore(jupiter): sync " + c.Path,
+			"content": base64.StdEncoding.EncodeToString(c.Data),
+			"branch":  branch,
+		}
+		if sha := remoteFileSHA(repo, c.Path, branch); sha != "" {
+			body["sha"] = sha
+		}
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return result, err
+		}
+		if _, err := runCommandOutputWithInput(commandExecutor, payload, "gh", "api", "-X", "PUT", fmt.Sprintf("repos/%s/contents/%s", repo, c.Path), "--input", "-"); err != nil {
+			return result, withCode(ErrGitHubAPI, fmt.Errorf("failed to commit %s to %s: %w", c.Path, repo, err))
+		}
+	}
+
+	if openPR != "" {
+		result.PR = openPR
+		fmt.Printf("  🔀 Managed files PR updated: %s\n", openPR)
+		return result, nil
+	}
+	prBody := fmt.Sprintf("Syncs org-wide files managed by jupiter-registry (`managed_files` in jupiter.yml):\n\n- `%s`\n\n"+
+		"Change these files in jupiter-registry instead of editing them here.", strings.Join(result.Outdated, "`\n- `"))
+	url, err := runCommandOutput(commandExecutor, "gh", "pr", "create",
+		"--repo", repo,
+		"--base", base,
+		"--head", branch,
+		"--title", "chore(jupiter): sync managed files",
+		"--body", prBody)
+	if err != nil {
+		return result, withCode(ErrGitHubAPI, fmt.Errorf("failed to open managed files PR on %s: %w", repo, err))
+	}
+	result.PR = strings.TrimSpace(url)
+	fmt.Printf("  🔀 Managed files PR opened: %s\n", result.PR)
+	return result, nil
+}
+
+// runManagedFilesCommand: `managed-files [--dry-run] [--json] [service...]` đồng bộ managed_files lên
+// mọi repo đã provisioning (hoặc các service chỉ định), bỏ qua service archived / decommissioned / exclude
+func runManagedFilesCommand(args []string) error {
+	fs := flag.NewFlagSet("managed-files", flag.ExitOnError)
+	dryRun := fs.Bool("dry-run", false, "only report outdated files, do not open pull requests")
+	asJSON := fs.Bool("json", false, "print results as JSON")
+	fs.Parse(args)
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	if len(registryConfig.ManagedFiles.Files) == 0 {
+		return withCode(ErrUsage, fmt.Errorf("no managed_files configured in %s", registryConfigFile))
+	}
+	contents, err := loadManagedFiles(registryConfig.ManagedFiles)
+	if err != nil {
+		return err
+	}
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	only := map[string]bool{}
+	for _, name := range fs.Args() {
+		only[name] = true
+	}
+
+	results := []ManagedFilesResult{}
+	failed := 0
+	for _, s := range services {
+		config := resolveSourceConfig(s.Config, registryConfig)
+		if len(only) > 0 && !only[config.Name] {
+			continue
+		}
+		entry := state.Services[config.Name]
+		// Chỉ repo đã provisioning và còn hoạt động
+		if entry == nil || entry.ArchivedAt != "" || entry.LastStatus == "deleted" || entry.LastStatus == "decommissioned" {
+			continue
+		}
+		if registryConfig.ManagedFiles.excluded(config.Name) {
+			continue
+		}
+		repo := fmt.Sprintf("%s/%s", registryConfig.ownerFor(config), config.Name)
+		if entry.Repo != "" {
+			repo = resolveRepo(*entry)
+		}
+		result, err := syncManagedFiles(repo, registryConfig.ManagedFiles, contents, *dryRun)
+		result.Service = config.Name
+		if err != nil {
+			failed++
+			result.Error = redact(err.Error())
+		}
+		results = append(results, result)
+	}
+
+	if *asJSON {
+		out, err := json.MarshalIndent(results, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
+		outdated := 0
+		for _, r := range results {
+			switch {
+			case r.Error != "":
+				fmt.Printf("❌ %s: %s\n", r.Repo, r.Error)
+			case len(r.Outdated) == 0:
+				fmt.Printf("✅ %s is in sync\n", r.Repo)
+			case r.PR != "":
+				fmt.Printf("🔀 %s: %s (%s)\n", r.Repo, strings.Join(r.Outdated, ", "), r.PR)
+			default:
+				fmt.Printf("⚠️ %s: %s out of date\n", r.Repo, strings.Join(r.Outdated, ", "))
+			}
+			if len(r.Outdated) > 0 {
+				outdated++
+			}
+		}
+		fmt.Printf("📋 %d/%d repo(s) with outdated managed files\n", outdated, len(results))
+	}
+	if failed > 0 {
+		return withCode(ErrGitHubAPI, fmt.Errorf("managed files sync failed for %d repo(s)", failed))
+	}
+	return nil
+}
@@ -0,0 +1,147 @@
+package main
+
+import (
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// manifestPath là marker file registry ghi vào mọi repo được generate
+const manifestPath = ".jupiter/manifest.yaml"
+
+// Manifest ghi lại input đã dùng để generate repo, cho phép bỏ qua service không đổi ở lần chạy sau
+type Manifest struct {
+	SourceID        string `yaml:"source_id"`
+	TemplateVersion string `yaml:"template_version"`
+	SourceDigest    string `yaml:"source_digest"` // sha256 của source.yml
+	GeneratedAt     string `yaml:"generated_at,omitempty"`
+	// Files: path -> git blob SHA của từng file lúc generate, dùng để phát hiện file bị sửa tay
+	Files map[string]string `yaml:"files,omitempty"`
+}
+
+// upToDate: cùng source.yml và cùng bộ template thì không cần regenerate
+func (m Manifest) upToDate(other Manifest) bool {
+	return m.SourceDigest == other.SourceDigest && m.TemplateVersion == other.TemplateVersion
+}
+
+// buildManifest tính digest của source.yml và version của templates hiện tại
+func buildManifest(servicePath string, config SourceConfig, registryConfig RegistryConfig) (Manifest, error) {
+	data, err := os.ReadFile(filepath.Join(servicePath, "source.yml"))
+	if err != nil {
+		return Manifest{}, withCode(ErrSourceNotFound, err)
+	}
+	sum := sha256.Sum256(data)
+
+	version, err := templateVersion(registryConfig.FrameworkTemplates)
+	if err != nil {
+		return Manifest{}, err
+	}
+
+	return Manifest{
+		SourceID:        config.SourceID,
+		TemplateVersion: version,
+		SourceDigest:    "sha256:" + hex.EncodeToString(sum[:]),
+	}, nil
+}
+
+// templateVersion là digest của toàn bộ thư mục templates (path + nội dung từng file)
+func templateVersion(templatesDir string) (string, error) {
+	if templatesDir == "" {
+		return "none", nil
+	}
+	h := sha256.New()
+	err := filepath.WalkDir(templatesDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(templatesDir, path)
+		fmt.Fprintf(h, "%s\x00%d\x00", filepath.ToSlash(rel), len(data))
+		h.Write(data)
+		return nil
+	})
+	if err != nil {
+		return "", fmt.Errorf("failed to hash templates %s: %w", templatesDir, err)
+	}
+	return hex.EncodeToString(h.Sum(nil))[:12], nil
+}
+
+// writeManifest ghi .jupiter/manifest.yaml vào repoDir, kèm hash của mọi file đã generate
+func writeManifest(repoDir string, manifest Manifest) error {
+	manifest.GeneratedAt = time.Now().UTC().Format(time.RFC3339)
+
+	files, err := hashGeneratedFiles(repoDir)
+	if err != nil {
+		return err
+	}
+	manifest.Files = files
+
+	data, err := yaml.Marshal(manifest)
+	if err != nil {
+		return err
+	}
+	header := []byte("# Generated by jupiter-registry. Do not edit.\n")
+	return writeRepoFile(repoDir, manifestPath, append(header, data...))
+}
+
+// hashGeneratedFiles tính git blob SHA (giống `git hash-object`) cho mọi file trong repoDir
+// để so sánh trực tiếp với git tree API mà không cần tải nội dung file
+func hashGeneratedFiles(repoDir string) (map[string]string, error) {
+	files := map[string]string{}
+	err := filepath.WalkDir(repoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			if d.Name() == ".git" {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		rel, _ := filepath.Rel(repoDir, path)
+		rel = filepath.ToSlash(rel)
+		if rel == manifestPath {
+			return nil
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		files[rel] = gitBlobSHA(data)
+		return nil
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to hash generated files: %w", err)
+	}
+	return files, nil
+}
+
+func gitBlobSHA(data []byte) string {
+	h := sha1.New()
+	fmt.Fprintf(h, "blob %d\x00", len(data))
+	h.Write(data)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// readRemoteManifest đọc manifest trên default branch của repo đã provisioning
+func readRemoteManifest(ex Executor, owner, repoName string) (Manifest, bool) {
+	var manifest Manifest
+	content, err := readRepoFile(ex, fmt.Sprintf("%s/%s", owner, repoName), manifestPath)
+	if err != nil {
+		return manifest, false
+	}
+	if err := yaml.Unmarshal([]byte(content), &manifest); err != nil {
+		return manifest, false
+	}
+	return manifest, true
+}
@@ -0,0 +1,364 @@
+package main
+
+import (
+	"bufio"
+	"encoding/base64"
+	"flag"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// git.history: merge giống preserve (clone history, giữ file team tự thêm) nhưng file generated đã bị sửa tay
+// được merge 3-way thay vì bị ghi đè: base = bản generated lần trước (blob SHA trong manifest cũ),
+// ours = bản trên repo, theirs = bản vừa generate. Conflict xử lý theo git.on_conflict / `upgrade --strategy`.
+
+// conflictStrategies: fail (mặc định) | ours (giữ sửa tay) | theirs (lấy template) | union (giữ cả hai)
+// | interactive (hỏi từng hunk, chỉ khi chạy trong terminal)
+var conflictStrategies = []string{"fail", "ours", "theirs", "union", "interactive"}
+
+func (g GitConfig) onConflict() string {
+	if g.OnConflict == "" {
+		return "fail"
+	}
+	return g.OnConflict
+}
+
+// MergeResult tóm tắt kết quả merge của một lần regenerate
+type MergeResult struct {
+	Regenerated []string // chỉ template đổi: lấy bản generated
+	Kept        []string // chỉ team sửa: giữ bản trên repo
+	Merged      []string // cả hai đổi, merge sạch
+	Resolved    []string // cả hai đổi, conflict đã xử lý theo strategy
+	Conflicts   []string // conflict chưa xử lý (strategy fail)
+}
+
+const (
+	conflictOurs   = "<<<<<<< "
+	conflictSep    = "======="
+	conflictTheirs = ">>>>>>> "
+)
+
+// mergeGeneratedFiles merge output mới trong repoDir (working tree) với HEAD (history của repo)
+func mergeGeneratedFiles(ex Executor, repoDir, fullName string, gitConfig GitConfig) (MergeResult, error) {
+	var result MergeResult
+	strategy := gitConfig.onConflict()
+	if !containsString(conflictStrategies, strategy) {
+		return result, withCode(ErrUsage, fmt.Errorf("git.on_conflict %q must be one of %v", strategy, conflictStrategies))
+	}
+	if strategy == "interactive" && !stdinIsTerminal() {
+		return result, withCode(ErrUsage, fmt.Errorf("git.on_conflict interactive needs a terminal, use --strategy in CI"))
+	}
+
+	// Repo chưa có manifest kèm hash (generate trước khi ghi hash): không có base, generated ghi đè như preserve
+	previous, err := runCommandOutputInDir(ex, repoDir, "git", "show", "HEAD:"+manifestPath)
+	if err != nil {
+		return result, nil
+	}
+	var base Manifest
+	if err := yaml.Unmarshal([]byte(previous), &base); err != nil || len(base.Files) == 0 {
+		return result, nil
+	}
+
+	paths := make([]string, 0, len(base.Files))
+	for path := range base.Files {
+		paths = append(paths, path)
+	}
+	sort.Strings(paths)
+
+	for _, path := range paths {
+		baseSHA := base.Files[path]
+		target := filepath.Join(repoDir, filepath.FromSlash(path))
+		theirs, err := os.ReadFile(target)
+		if err != nil {
+			// Template đã bỏ file này: --ignore-removal giữ nguyên bản trên repo
+			continue
+		}
+		theirsSHA := gitBlobSHA(theirs)
+		oursSHA, err := runCommandOutputInDir(ex, repoDir, "git", "rev-parse", "--verify", "--quiet", "HEAD:"+path)
+		switch {
+		case err != nil:
+			// Team đã xoá file: template không đổi thì tôn trọng việc xoá
+			if theirsSHA == baseSHA {
+				if err := os.Remove(target); err != nil {
+					return result, err
+				}
+				result.Kept = append(result.Kept, path)
+			}
+		case oursSHA == baseSHA || oursSHA == theirsSHA:
+			result.Regenerated = append(result.Regenerated, path)
+		case theirsSHA == baseSHA:
+			if err := runCommandInDir(ex, repoDir, "git", "checkout", "HEAD", "--", path); err != nil {
+				return result, fmt.Errorf("failed to keep %s: %w", path, err)
+			}
+			result.Kept = append(result.Kept, path)
+		default:
+			outcome, err := mergeFile(ex, repoDir, fullName, path, baseSHA, theirs, strategy)
+			if err != nil {
+				return result, err
+			}
+			switch outcome {
+			case "merged":
+				result.Merged = append(result.Merged, path)
+			case "resolved":
+				result.Resolved = append(result.Resolved, path)
+			default:
+				result.Conflicts = append(result.Conflicts, path)
+			}
+		}
+	}
+
+	fmt.Printf("  🔀 Merge: %d regenerated, %d kept, %d merged, %d resolved (%s), %d conflicted\n",
+		len(result.Regenerated), len(result.Kept), len(result.Merged), len(result.Resolved), strategy, len(result.Conflicts))
+	if len(result.Conflicts) > 0 {
+		return result, withCode(ErrMergeConflict, fmt.Errorf("conflicts between regenerated templates and manual edits in %s: %s (set git.on_conflict or rerun `upgrade --strategy ours|theirs|union` / `--interactive`)",
+			fullName, strings.Join(result.Conflicts, ", ")))
+	}
+	return result, nil
+}
+
+// mergeFile merge 3-way một file bằng `git merge-file`, trả về merged | resolved | conflict
+func mergeFile(ex Executor, repoDir, fullName, path, baseSHA string, theirs []byte, strategy string) (string, error) {
+	baseContent, err := readBlob(ex, repoDir, fullName, baseSHA)
+	if err != nil {
+		return "", fmt.Errorf("failed to read generated base of %s: %w", path, err)
+	}
+	tmp, err := os.MkdirTemp("", "jupiter-merge-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(tmp)
+	baseFile, theirsFile := filepath.Join(tmp, "base"), filepath.Join(tmp, "theirs")
+	if err := os.WriteFile(baseFile, baseContent, 0644); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(theirsFile, theirs, 0644); err != nil {
+		return "", err
+	}
+	target := filepath.Join(repoDir, filepath.FromSlash(path))
+	// merge-file ghi kết quả vào file "current" = bản trên repo. Exit code > 0 là số conflict
+	// nên dựa vào marker trong kết quả thay vì exit code
+	merge := func(extra ...string) ([]byte, error) {
+		if err := runCommandInDir(ex, repoDir, "git", "checkout", "HEAD", "--", path); err != nil {
+			return nil, err
+		}
+		args := append([]string{"merge-file", "-L", "ours (repository)", "-L", "base (previous templates)", "-L", "theirs (new templates)"}, extra...)
+		mergeErr := runCommandInDir(ex, repoDir, "git", append(args, path, baseFile, theirsFile)...)
+		merged, err := os.ReadFile(target)
+		if err != nil {
+			return nil, err
+		}
+		if mergeErr != nil && !hasConflictMarkers(merged) {
+			return nil, fmt.Errorf("git merge-file failed for %s: %w", path, mergeErr)
+		}
+		return merged, nil
+	}
+
+	merged, err := merge()
+	if err != nil {
+		return "", err
+	}
+	if !hasConflictMarkers(merged) {
+		return "merged", nil
+	}
+	switch strategy {
+	case "ours", "theirs", "union":
+		if _, err := merge("--" + strategy); err != nil {
+			return "", err
+		}
+		return "resolved", nil
+	case "interactive":
+	default:
+		return "conflict", nil
+	}
+
+	resolved, err := resolveInteractively(ex, path, merged, bufio.NewReader(os.Stdin))
+	if err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(target, resolved, 0644); err != nil {
+		return "", err
+	}
+	return "resolved", nil
+}
+
+func hasConflictMarkers(content []byte) bool {
+	for _, line := range strings.Split(string(content), "\n") {
+		if strings.HasPrefix(line, conflictOurs) {
+			return true
+		}
+	}
+	return false
+}
+
+// readBlob đọc nội dung blob từ clone (shallow clone có thể không có) hoặc git blobs API
+func readBlob(ex Executor, repoDir, fullName, sha string) ([]byte, error) {
+	// Không qua runCommandOutput vì output bị trim, nội dung base phải giữ nguyên từng byte
+	fmt.Printf("%s  → Running in %s: git cat-file blob %s\n", logPrefix(ex), repoDir, sha)
+	if out, err := ex.Run(Command{Dir: repoDir, Name: "git", Args: []string{"cat-file", "blob", sha}, CaptureOutput: true}); err == nil {
+		return []byte(out), nil
+	}
+	out, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/git/blobs/%s", fullName, sha), "--jq", ".content")
+	if err != nil {
+		return nil, withCode(ErrGitHubAPI, err)
+	}
+	return base64.StdEncoding.DecodeString(strings.ReplaceAll(out, "\n", ""))
+}
+
+// conflictHunk là một đoạn <<<<<<< ... ======= ... >>>>>>> trong file
+type conflictHunk struct {
+	ours, theirs []string
+}
+
+// resolveInteractively hỏi từng hunk: ours / theirs / both / edit
+func resolveInteractively(ex Executor, path string, content []byte, in *bufio.Reader) ([]byte, error) {
+	lines := strings.Split(string(content), "\n")
+	var out []string
+	total := strings.Count(string(content), "\n"+conflictOurs)
+	if strings.HasPrefix(string(content), conflictOurs) {
+		total++
+	}
+	index := 0
+	for i := 0; i < len(lines); i++ {
+		if !strings.HasPrefix(lines[i], conflictOurs) {
+			out = append(out, lines[i])
+			continue
+		}
+		var hunk conflictHunk
+		side := &hunk.ours
+		for i++; i < len(lines) && !strings.HasPrefix(lines[i], conflictTheirs); i++ {
+			if lines[i] == conflictSep {
+				side = &hunk.theirs
+				continue
+			}
+			*side = append(*side, lines[i])
+		}
+		index++
+		chosen, err := promptHunk(ex, path, index, total, hunk, in)
+		if err != nil {
+			return nil, err
+		}
+		out = append(out, chosen...)
+	}
+	return []byte(strings.Join(out, "\n")), nil
+}
+
+func promptHunk(ex Executor, path string, index, total int, hunk conflictHunk, in *bufio.Reader) ([]string, error) {
+	fmt.Printf("\n⚔️  %s — conflict %d/%d\n", path, index, total)
+	fmt.Println("--- ours (repository)")
+	for _, l := range hunk.ours {
+		fmt.Printf("  - %s\n", l)
+	}
+	fmt.Println("+++ theirs (new templates)")
+	for _, l := range hunk.theirs {
+		fmt.Printf("  + %s\n", l)
+	}
+	for {
+		fmt.Print("Keep [o]urs, [t]heirs, [b]oth, or [e]dit? ")
+		answer, err := in.ReadString('\n')
+		if err != nil {
+			return nil, fmt.Errorf("conflict resolution aborted: %w", err)
+		}
+		switch strings.ToLower(strings.TrimSpace(answer)) {
+		case "o", "ours":
+			return hunk.ours, nil
+		case "t", "theirs":
+			return hunk.theirs, nil
+		case "b", "both":
+			return append(append([]string{}, hunk.ours...), hunk.theirs...), nil
+		case "e", "edit":
+			return editHunk(ex, hunk)
+		}
+	}
+}
+
+// editHunk mở $EDITOR với cả hai phía của hunk, nội dung lưu lại là kết quả
+func editHunk(ex Executor, hunk conflictHunk) ([]string, error) {
+	file, err := os.CreateTemp("", "jupiter-hunk-*.txt")
+	if err != nil {
+		return nil, err
+	}
+	defer os.Remove(file.Name())
+	content := append(append(append([]string{conflictOurs + "ours (repository)"}, hunk.ours...), conflictSep), hunk.theirs...)
+	content = append(content, conflictTheirs+"theirs (new templates)")
+	if _, err := file.WriteString(strings.Join(content, "\n") + "\n"); err != nil {
+		file.Close()
+		return nil, err
+	}
+	file.Close()
+
+	editor := os.Getenv("EDITOR")
+	if editor == "" {
+		editor = "vi"
+	}
+	// Editor cần terminal thật nên chạy trực tiếp, không qua commandExecutor (output bị capture / log)
+	cmd := exec.Command("sh", "-c", editor+` "$0"`, file.Name())
+	cmd.Stdin, cmd.Stdout, cmd.Stderr = os.Stdin, os.Stdout, os.Stderr
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("editor %s failed: %w", editor, err)
+	}
+	edited, err := os.ReadFile(file.Name())
+	if err != nil {
+		return nil, err
+	}
+	lines := strings.Split(strings.TrimSuffix(string(edited), "\n"), "\n")
+	if hasConflictMarkers(edited) {
+		return nil, fmt.Errorf("edited hunk still contains conflict markers")
+	}
+	return lines, nil
+}
+
+func stdinIsTerminal() bool {
+	info, err := os.Stdin.Stat()
+	return err == nil && info.Mode()&os.ModeCharDevice != 0
+}
+
+// runUpgradeCommand: `upgrade [--strategy fail|ours|theirs|union] [--interactive] [--pull-request] <service>...`
+// regenerate với git.history merge, giữ sửa tay và merge 3-way với template mới
+func runUpgradeCommand(args []string) error {
+	fs := flag.NewFlagSet("upgrade", flag.ExitOnError)
+	strategy := fs.String("strategy", "", "conflict strategy for CI: fail | ours | theirs | union (default git.on_conflict)")
+	interactive := fs.Bool("interactive", false, "resolve each conflicted hunk interactively (ours / theirs / both / edit)")
+	pullRequest := fs.Bool("pull-request", false, "push the upgrade to jupiter/upgrade-<version> and open a pull request")
+	fs.Parse(args)
+	if fs.NArg() == 0 {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts upgrade [--strategy fail|ours|theirs|union] [--interactive] [--pull-request] <service>..."))
+	}
+	if *interactive && *strategy != "" {
+		return withCode(ErrUsage, fmt.Errorf("--interactive and --strategy are mutually exclusive"))
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	registryConfig.Git.History = "merge"
+	switch {
+	case *interactive:
+		registryConfig.Git.OnConflict = "interactive"
+	case *strategy != "":
+		registryConfig.Git.OnConflict = *strategy
+	}
+	if *pullRequest {
+		registryConfig.Git.UpdatePush = "pull_request"
+	}
+
+	var failed []string
+	for _, name := range fs.Args() {
+		fmt.Printf("\n⬆️  Upgrading %s\n", name)
+		if err := provisionService(filepath.Join(sourcesDir, name), registryConfig, fs.NArg(), nil); err != nil {
+			fmt.Printf("❌ [%s] %s: %v\n", errorCode(err), name, err)
+			printLogExcerpt(err)
+			failed = append(failed, name)
+		}
+	}
+	if len(failed) > 0 {
+		return withCode(ErrGeneratorFailed, fmt.Errorf("%d/%d upgrade(s) failed: %s", len(failed), fs.NArg(), strings.Join(failed, ", ")))
+	}
+	return nil
+}
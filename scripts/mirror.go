@@ -0,0 +1,69 @@
+package main
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Mirror backup initial push của mỗi repo mới sang remote thứ hai (disaster recovery).
+//   - git:        push sang git remote bất kỳ (GitLab nội bộ, ...); url hỗ trợ {owner} và {name}
+//   - github_org: tạo repo cùng tên trong org dự phòng rồi push
+//   - bundle:     tạo git bundle rồi chạy command (vd. aws s3 cp "$JUPITER_BUNDLE" s3://...)
+type Mirror struct {
+	Type     string `yaml:"type"`
+	URL      string `yaml:"url"`
+	Org      string `yaml:"org"`
+	TokenEnv string `yaml:"token_env"` // env chứa token cho remote https
+	Command  string `yaml:"command"`
+	Required bool   `yaml:"required"` // true: mirror lỗi thì provisioning fail
+}
+
+func mirrorRepository(ex Executor, repoDir string, dto GeneratorSourceDto, mirror Mirror) error {
+	switch mirror.Type {
+	case "git":
+		remote := strings.NewReplacer("{owner}", dto.Owner, "{name}", dto.AppName).Replace(mirror.URL)
+		return pushToMirror(ex, repoDir, remote, mirror.TokenEnv)
+	case "github_org":
+		if err := createGitHubRepo(ex, mirror.Org, dto.AppName, "private"); err != nil {
+			return err
+		}
+		return pushToMirror(ex, repoDir, fmt.Sprintf("https://github.com/%s/%s.git", mirror.Org, dto.AppName), mirror.TokenEnv)
+	case "bundle":
+		bundleDir, err := os.MkdirTemp("", "jupiter-bundle-")
+		if err != nil {
+			return err
+		}
+		defer os.RemoveAll(bundleDir)
+
+		bundlePath := filepath.Join(bundleDir, dto.AppName+".bundle")
+		if err := runCommandInDir(ex, repoDir, "git", "bundle", "create", bundlePath, "--all"); err != nil {
+			return fmt.Errorf("failed to create bundle: %w", err)
+		}
+		fmt.Printf("  → Running mirror bundle command for %s\n", dto.AppName)
+		_, err = ex.Run(Command{
+			Name: "sh",
+			Args: []string{"-c", mirror.Command},
+			Env:  []string{"JUPITER_SERVICE=" + dto.AppName, "JUPITER_OWNER=" + dto.Owner, "JUPITER_BUNDLE=" + bundlePath},
+		})
+		return err
+	default:
+		return fmt.Errorf("unsupported mirror type: %s", mirror.Type)
+	}
+}
+
+// pushToMirror push toàn bộ branch + tag sang remote, chèn token vào URL https nếu có
+func pushToMirror(ex Executor, repoDir, remote, tokenEnv string) error {
+	if token := os.Getenv(tokenEnv); tokenEnv != "" && token != "" {
+		if u, err := url.Parse(remote); err == nil && u.Scheme == "https" {
+			u.User = url.UserPassword("oauth2", token)
+			remote = u.String()
+		}
+	}
+	if err := runCommandInDir(ex, repoDir, "git", "push", "--force", remote, "refs/heads/*:refs/heads/*", "refs/tags/*:refs/tags/*"); err != nil {
+		return fmt.Errorf("failed to push mirror: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,68 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"regexp"
+	"strings"
+)
+
+// NamingConvention là quy tắc đặt tên service (cũng chính là tên GitHub repo)
+type NamingConvention struct {
+	Pattern         string            `yaml:"pattern"`
+	MaxLength       int               `yaml:"max_length"`
+	Reserved        []string          `yaml:"reserved"`
+	TeamPrefixes    map[string]string `yaml:"team_prefixes"`
+	CheckCollisions bool              `yaml:"check_collisions"`
+	// OnCollision: repo trùng tên đã tồn tại mà registry không quản lý: fail (mặc định) | suffix | adopt
+	OnCollision       string   `yaml:"on_collision"`
+	CollisionSuffixes []string `yaml:"collision_suffixes"` // mặc định -svc, -2
+}
+
+// validateServiceName kiểm tra tên theo pattern, độ dài, reserved names và prefix của team
+func validateServiceName(name, team string, cfg NamingConvention) []string {
+	if name == "" {
+		return nil
+	}
+
+	var problems []string
+	if cfg.Pattern != "" {
+		re, err := regexp.Compile(cfg.Pattern)
+		if err != nil {
+			return []string{fmt.Sprintf("naming: invalid pattern in registry config: %v", err)}
+		}
+		if !re.MatchString(name) {
+			problems = append(problems, fmt.Sprintf("name '%s' does not match naming pattern %s", name, cfg.Pattern))
+		}
+	}
+	if cfg.MaxLength > 0 && len(name) > cfg.MaxLength {
+		problems = append(problems, fmt.Sprintf("name '%s' is longer than %d characters", name, cfg.MaxLength))
+	}
+	if containsStringFold(cfg.Reserved, name) {
+		problems = append(problems, fmt.Sprintf("name '%s' is reserved", name))
+	}
+	if prefix, ok := cfg.TeamPrefixes[team]; ok && team != "" && !strings.HasPrefix(name, prefix) {
+		problems = append(problems, fmt.Sprintf("name '%s' must start with '%s' for team %s", name, prefix, team))
+	}
+	return problems
+}
+
+// checkNameCollisions: trùng tên với service khác trong registry, hoặc với repo có sẵn không do registry quản lý.
+// repoTaken = true khi repo đã tồn tại trên GitHub mà registry không quản lý (xử lý theo on_collision).
+func checkNameCollisions(ex Executor, servicePath, owner, name, sourceID string) (problems []string, repoTaken bool) {
+	services, err := loadRegisteredServices(sourcesDir)
+	if err == nil {
+		folder := filepath.Base(servicePath)
+		for _, s := range services {
+			if s.Folder != folder && strings.EqualFold(s.Config.Name, name) {
+				problems = append(problems, fmt.Sprintf("name '%s' is already used by %s/%s", name, sourcesDir, s.Folder))
+			}
+		}
+	}
+
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return append(problems, err.Error()), false
+	}
+	return problems, !repoManagedBy(ex, owner, name, sourceID, state) && repoExists(ex, owner, name)
+}
@@ -0,0 +1,64 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// nodejsFrameworks là các framework NodeJS được hỗ trợ (nestjs là mặc định)
+var nodejsFrameworks = []string{"nestjs", "express", "fastify"}
+
+type nodeTemplateData struct {
+	Service     GeneratorSourceDto
+	PackageName string
+}
+
+// generateNodeApp dispatch theo metadata.framework, mỗi framework có generator và CI workflow riêng
+func generateNodeApp(ex Executor, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	framework := dto.Framework
+	if framework == "" {
+		framework = "nestjs"
+	}
+
+	switch framework {
+	case "nestjs":
+		// NestJS dùng CLI chính thức, sau đó overlay CI workflow từ template
+		fmt.Printf("🚀 Generating nestjs app: %s\n", dto.AppName)
+		if err := runCommand(ex, "npx", "--yes", "@nestjs/cli@latest", "new", dto.AppName,
+			"--package-manager", "npm", "--skip-git"); err != nil {
+			return fmt.Errorf("failed to generate app: %w", err)
+		}
+	case "express", "fastify":
+		fmt.Printf("🚀 Generating %s app: %s\n", framework, dto.AppName)
+	default:
+		return fmt.Errorf("unsupported nodejs framework: %s", framework)
+	}
+
+	if registryConfig.FrameworkTemplates == "" {
+		return fmt.Errorf("framework_templates is not configured")
+	}
+	srcDir := filepath.Join(registryConfig.FrameworkTemplates, "nodejs", framework)
+	if _, err := os.Stat(srcDir); err != nil {
+		return fmt.Errorf("no templates for nodejs/%s in %s", framework, registryConfig.FrameworkTemplates)
+	}
+
+	data := nodeTemplateData{Service: dto, PackageName: nodePackageName(dto)}
+	if err := renderTemplateDir(srcDir, dto.AppName, data); err != nil {
+		return fmt.Errorf("failed to render %s templates: %w", framework, err)
+	}
+
+	// Tạo package-lock.json để CI dùng được npm ci
+	if err := runCommandInDir(ex, dto.AppName, "npm", "install", "--package-lock-only"); err != nil {
+		return fmt.Errorf("npm install failed: %w", err)
+	}
+	return nil
+}
+
+// nodePackageName: metadata.module nếu có (vd @org/name), ngược lại dùng tên app
+func nodePackageName(dto GeneratorSourceDto) string {
+	if dto.Module != "" {
+		return dto.Module
+	}
+	return dto.AppName
+}
@@ -0,0 +1,187 @@
+package main
+
+import (
+	"archive/tar"
+	"compress/gzip"
+	"encoding/json"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// OCIArtifacts publish scaffold vừa generate thành OCI artifact (qua oras CLI) để giao cho môi trường
+// air-gapped và giữ snapshot bất biến của đúng nội dung đã generate. Digest được ghi vào state.
+//   - also (mặc định): push git như bình thường rồi publish artifact
+//   - only:           chỉ publish artifact, không tạo repo / gọi GitHub
+type OCIArtifacts struct {
+	Registry    string `yaml:"registry"` // vd. ghcr.io/acme/scaffolds, trống = tắt; artifact = <registry>/<name>:<tag>
+	Mode        string `yaml:"mode"`
+	UsernameEnv string `yaml:"username_env"`
+	PasswordEnv string `yaml:"password_env"`
+	Required    bool   `yaml:"required"` // true: publish lỗi thì provisioning fail (luôn fail với mode only)
+}
+
+const (
+	scaffoldArtifactType = "application/vnd.jupiter.scaffold.v1"
+	scaffoldLayerType    = "application/vnd.jupiter.scaffold.layer.v1.tar+gzip"
+)
+
+func (o OCIArtifacts) enabled() bool {
+	return o.Registry != ""
+}
+
+// only: artifact thay cho git, mọi bước GitHub bị bỏ qua
+func (o OCIArtifacts) only() bool {
+	return o.enabled() && o.Mode == "only"
+}
+
+func (o OCIArtifacts) validate() error {
+	switch o.Mode {
+	case "", "also", "only":
+		return nil
+	default:
+		return withCode(ErrUsage, fmt.Errorf("unsupported oci.mode: %s", o.Mode))
+	}
+}
+
+// ScaffoldArtifact là artifact đã publish của lần generate gần nhất
+type ScaffoldArtifact struct {
+	Ref             string `json:"ref"`
+	Digest          string `json:"digest"`
+	SourceDigest    string `json:"source_digest"`
+	TemplateVersion string `json:"template_version"`
+	PublishedAt     string `json:"published_at"`
+}
+
+// upToDate: artifact đã được publish từ đúng source.yml + templates này
+func (a *ScaffoldArtifact) upToDate(manifest Manifest) bool {
+	return a != nil && a.SourceDigest == manifest.SourceDigest && a.TemplateVersion == manifest.TemplateVersion
+}
+
+// scaffoldTag: <template_version>-<12 ký tự đầu của source digest>, mỗi cặp input một tag bất biến
+func scaffoldTag(manifest Manifest) string {
+	digest := strings.TrimPrefix(manifest.SourceDigest, "sha256:")
+	if len(digest) > 12 {
+		digest = digest[:12]
+	}
+	return manifest.TemplateVersion + "-" + digest
+}
+
+// publishScaffoldArtifact đóng gói repoDir (trừ .git) thành tar.gz rồi `oras push`, trả về ref + digest
+func publishScaffoldArtifact(ex Executor, repoDir string, dto GeneratorSourceDto, manifest Manifest, cfg OCIArtifacts) (*ScaffoldArtifact, error) {
+	workDir, err := os.MkdirTemp("", "jupiter-oci-")
+	if err != nil {
+		return nil, err
+	}
+	defer os.RemoveAll(workDir)
+
+	if err := writeScaffoldArchive(repoDir, filepath.Join(workDir, "scaffold.tar.gz")); err != nil {
+		return nil, fmt.Errorf("failed to package scaffold: %w", err)
+	}
+
+	ref := fmt.Sprintf("%s/%s:%s", strings.TrimRight(cfg.Registry, "/"), dto.AppName, scaffoldTag(manifest))
+	args := []string{"push", ref, "scaffold.tar.gz:" + scaffoldLayerType,
+		"--artifact-type", scaffoldArtifactType,
+		"--annotation", "org.opencontainers.image.title=" + dto.AppName,
+		"--annotation", "org.opencontainers.image.source=" + fmt.Sprintf("https://github.com/%s/%s", dto.Owner, dto.AppName),
+		"--annotation", "dev.jupiter.source_id=" + manifest.SourceID,
+		"--annotation", "dev.jupiter.template_version=" + manifest.TemplateVersion,
+		"--format", "json",
+	}
+	var stdin []byte
+	if username := os.Getenv(cfg.UsernameEnv); cfg.UsernameEnv != "" && username != "" {
+		password := os.Getenv(cfg.PasswordEnv)
+		registerSecret(password)
+		args = append(args, "--username", username, "--password-stdin")
+		stdin = []byte(password)
+	}
+
+	fmt.Printf("%s  → Running in %s: oras %s\n", logPrefix(ex), workDir, strings.Join(args, " "))
+	out, err := ex.Run(Command{Dir: workDir, Name: "oras", Args: args, Stdin: stdin, CaptureOutput: true})
+	if err != nil {
+		return nil, fmt.Errorf("oras push %s failed: %w", ref, err)
+	}
+	var pushed struct {
+		Digest string `json:"digest"`
+	}
+	if err := json.Unmarshal([]byte(out), &pushed); err != nil || pushed.Digest == "" {
+		return nil, fmt.Errorf("unexpected oras push output for %s: %s", ref, strings.TrimSpace(out))
+	}
+	return &ScaffoldArtifact{
+		Ref:             ref,
+		Digest:          pushed.Digest,
+		SourceDigest:    manifest.SourceDigest,
+		TemplateVersion: manifest.TemplateVersion,
+		PublishedAt:     time.Now().UTC().Format(time.RFC3339),
+	}, nil
+}
+
+// writeScaffoldArchive ghi tar.gz tái lập được: thứ tự file cố định, mtime / owner bằng 0,
+// cùng nội dung generate ra cùng digest
+func writeScaffoldArchive(repoDir, archivePath string) error {
+	f, err := os.Create(archivePath)
+	if err != nil {
+		return err
+	}
+	defer f.Close()
+	gz := gzip.NewWriter(f)
+	tw := tar.NewWriter(gz)
+
+	err = filepath.WalkDir(repoDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() && d.Name() == ".git" {
+			return filepath.SkipDir
+		}
+		if d.IsDir() || !d.Type().IsRegular() {
+			return nil
+		}
+		info, err := d.Info()
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		rel, _ := filepath.Rel(repoDir, path)
+		header := &tar.Header{
+			Name:    filepath.ToSlash(rel),
+			Mode:    int64(info.Mode().Perm()),
+			Size:    int64(len(data)),
+			ModTime: time.Unix(0, 0),
+			Format:  tar.FormatPAX,
+		}
+		if err := tw.WriteHeader(header); err != nil {
+			return err
+		}
+		_, err = tw.Write(data)
+		return err
+	})
+	if err != nil {
+		return err
+	}
+	if err := tw.Close(); err != nil {
+		return err
+	}
+	return gz.Close()
+}
+
+// publishScaffold chạy sau khi generate thành công; lỗi chỉ là cảnh báo trừ khi oci.required hoặc mode only
+func publishScaffold(ex Executor, dto GeneratorSourceDto, manifest Manifest, cfg OCIArtifacts) (*ScaffoldArtifact, error) {
+	fmt.Printf("📦 Publishing scaffold artifact to %s...\n", cfg.Registry)
+	artifact, err := publishScaffoldArtifact(ex, dto.AppName, dto, manifest, cfg)
+	if err != nil {
+		if cfg.only() || cfg.Required {
+			return nil, withCode(ErrInternal, fmt.Errorf("failed to publish scaffold artifact: %w", err))
+		}
+		fmt.Printf("  ⚠️ Scaffold artifact failed: %v\n", err)
+		return nil, nil
+	}
+	fmt.Printf("  ✅ %s@%s\n", artifact.Ref, artifact.Digest)
+	return artifact, nil
+}
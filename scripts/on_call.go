@@ -0,0 +1,230 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+)
+
+// OnCall tạo (hoặc link) service on-call cho service có metadata.tier 1 | 2, members làm responder.
+//   - pagerduty: escalation policy "<name> on-call" gồm members, service gắn với policy đó
+//   - opsgenie:  team "<name>" gồm members, service thuộc team đó
+type OnCall struct {
+	Provider string `yaml:"provider"` // "" = tắt
+	TokenEnv string `yaml:"token_env"`
+	// Users map GitHub login -> email trên PagerDuty/Opsgenie
+	Users map[string]string `yaml:"users"`
+	// EscalationPolicyID dùng policy có sẵn thay vì tạo policy theo members (pagerduty)
+	EscalationPolicyID string `yaml:"escalation_policy_id"`
+	APIURL             string `yaml:"api_url"` // override cho Opsgenie EU / proxy
+	Required           bool   `yaml:"required"`
+}
+
+// onCallTiers là các tier cần có người trực
+var onCallTiers = []int{1, 2}
+
+func (o OnCall) enabledFor(tier int) bool {
+	if o.Provider == "" {
+		return false
+	}
+	for _, t := range onCallTiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+// responders: email của members theo on_call.users (member chưa map thì bỏ qua kèm cảnh báo)
+func (o OnCall) responders(members []string) []string {
+	var emails []string
+	for _, m := range members {
+		if email, ok := o.Users[m]; ok {
+			emails = append(emails, email)
+		} else {
+			fmt.Printf("  ⚠️ %s has no on-call account in on_call.users, not added as responder\n", m)
+		}
+	}
+	return emails
+}
+
+// provisionOnCall trả về ID service trên provider (service có sẵn cùng tên thì link lại)
+func provisionOnCall(dto GeneratorSourceDto, o OnCall) (string, error) {
+	token := os.Getenv(o.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("on-call token $%s is not set", o.TokenEnv)
+	}
+	client := onCallClient{provider: o.Provider, token: token, baseURL: o.APIURL}
+
+	switch o.Provider {
+	case "pagerduty":
+		return client.pagerDutyService(dto, o)
+	case "opsgenie":
+		return client.opsgenieService(dto, o)
+	default:
+		return "", fmt.Errorf("unsupported on-call provider: %s", o.Provider)
+	}
+}
+
+type onCallClient struct {
+	provider string
+	token    string
+	baseURL  string
+}
+
+func (c onCallClient) do(method, path string, body, out interface{}) error {
+	base := c.baseURL
+	if base == "" {
+		base = map[string]string{"pagerduty": "https://api.pagerduty.com", "opsgenie": "https://api.opsgenie.com"}[c.provider]
+	}
+	var reader *bytes.Reader
+	if body != nil {
+		payload, err := json.Marshal(body)
+		if err != nil {
+			return err
+		}
+		reader = bytes.NewReader(payload)
+	} else {
+		reader = bytes.NewReader(nil)
+	}
+	req, err := http.NewRequest(method, base+path, reader)
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if c.provider == "pagerduty" {
+		req.Header.Set("Authorization", "Token token="+c.token)
+		req.Header.Set("Accept", "application/vnd.pagerduty+json;version=2")
+	} else {
+		req.Header.Set("Authorization", "GenieKey "+c.token)
+	}
+	return doJSONRequest(req, out)
+}
+
+type pagerDutyRef struct {
+	ID   string `json:"id"`
+	Type string `json:"type"`
+}
+
+func (c onCallClient) pagerDutyService(dto GeneratorSourceDto, o OnCall) (string, error) {
+	var found struct {
+		Services []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"services"`
+	}
+	if err := c.do(http.MethodGet, "/services?query="+url.QueryEscape(dto.AppName), nil, &found); err != nil {
+		return "", fmt.Errorf("failed to look up PagerDuty service: %w", err)
+	}
+	for _, s := range found.Services {
+		if s.Name == dto.AppName {
+			fmt.Printf("  ℹ️ Linked existing PagerDuty service %s\n", s.ID)
+			return s.ID, nil
+		}
+	}
+
+	policyID := o.EscalationPolicyID
+	if policyID == "" {
+		var targets []pagerDutyRef
+		for _, email := range o.responders(dto.Members) {
+			var users struct {
+				Users []pagerDutyRef `json:"users"`
+			}
+			if err := c.do(http.MethodGet, "/users?query="+url.QueryEscape(email), nil, &users); err != nil {
+				return "", fmt.Errorf("failed to look up PagerDuty user %s: %w", email, err)
+			}
+			if len(users.Users) == 0 {
+				fmt.Printf("  ⚠️ PagerDuty user %s not found\n", email)
+				continue
+			}
+			targets = append(targets, pagerDutyRef{ID: users.Users[0].ID, Type: "user_reference"})
+		}
+		if len(targets) == 0 {
+			return "", fmt.Errorf("no responders for %s: map members in on_call.users or set escalation_policy_id", dto.AppName)
+		}
+
+		var policy struct {
+			EscalationPolicy pagerDutyRef `json:"escalation_policy"`
+		}
+		err := c.do(http.MethodPost, "/escalation_policies", map[string]interface{}{
+			"escalation_policy": map[string]interface{}{
+				"type": "escalation_policy",
+				"name": dto.AppName + " on-call",
+				"escalation_rules": []map[string]interface{}{
+					{"escalation_delay_in_minutes": 30, "targets": targets},
+				},
+			},
+		}, &policy)
+		if err != nil {
+			return "", fmt.Errorf("failed to create escalation policy: %w", err)
+		}
+		policyID = policy.EscalationPolicy.ID
+	}
+
+	var created struct {
+		Service pagerDutyRef `json:"service"`
+	}
+	err := c.do(http.MethodPost, "/services", map[string]interface{}{
+		"service": map[string]interface{}{
+			"type":              "service",
+			"name":              dto.AppName,
+			"description":       fmt.Sprintf("https://github.com/%s/%s", dto.Owner, dto.AppName),
+			"escalation_policy": pagerDutyRef{ID: policyID, Type: "escalation_policy_reference"},
+		},
+	}, &created)
+	if err != nil {
+		return "", fmt.Errorf("failed to create PagerDuty service: %w", err)
+	}
+	return created.Service.ID, nil
+}
+
+func (c onCallClient) opsgenieService(dto GeneratorSourceDto, o OnCall) (string, error) {
+	var found struct {
+		Data []struct {
+			ID   string `json:"id"`
+			Name string `json:"name"`
+		} `json:"data"`
+	}
+	if err := c.do(http.MethodGet, "/v1/services?query="+url.QueryEscape("name:"+dto.AppName), nil, &found); err != nil {
+		return "", fmt.Errorf("failed to look up Opsgenie service: %w", err)
+	}
+	for _, s := range found.Data {
+		if s.Name == dto.AppName {
+			fmt.Printf("  ℹ️ Linked existing Opsgenie service %s\n", s.ID)
+			return s.ID, nil
+		}
+	}
+
+	var members []map[string]interface{}
+	for _, email := range o.responders(dto.Members) {
+		members = append(members, map[string]interface{}{"user": map[string]string{"username": email}, "role": "user"})
+	}
+	var team struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.do(http.MethodPost, "/v2/teams", map[string]interface{}{
+		"name":        dto.AppName,
+		"description": fmt.Sprintf("On-call for %s/%s", dto.Owner, dto.AppName),
+		"members":     members,
+	}, &team); err != nil {
+		return "", fmt.Errorf("failed to create Opsgenie team: %w", err)
+	}
+
+	var created struct {
+		Data struct {
+			ID string `json:"id"`
+		} `json:"data"`
+	}
+	if err := c.do(http.MethodPost, "/v1/services", map[string]interface{}{
+		"name":   dto.AppName,
+		"teamId": team.Data.ID,
+	}, &created); err != nil {
+		return "", fmt.Errorf("failed to create Opsgenie service: %w", err)
+	}
+	return created.Data.ID, nil
+}
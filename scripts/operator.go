@@ -0,0 +1,247 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Operator mode: service được khai báo bằng custom resource `Source` (spec = nội dung source.yml)
+// thay vì file trong sources-service. Controller chạy qua kubectl như các tích hợp CLI khác
+// (không kéo client-go / controller-runtime vào registry): list CR, provisioning, ghi .status.
+// Xoá CR không archive repo, dọn repo mồ côi bằng `gc`.
+const (
+	sourceCRDGroup    = "registry.jupiter.dev"
+	sourceCRDResource = "sources." + sourceCRDGroup
+	// operatorDir chứa source.yml render từ CR (mỗi CR một folder <namespace>-<name>)
+	operatorDir = ".jupiter-cache/operator"
+	// confirmAnnotation = app name cho phép force push / adopt repo trùng tên (tương đương --confirm)
+	confirmAnnotation = sourceCRDGroup + "/confirm"
+)
+
+// sourceResource là phần của CR mà controller đọc
+type sourceResource struct {
+	Metadata struct {
+		Name        string            `json:"name"`
+		Namespace   string            `json:"namespace"`
+		UID         string            `json:"uid"`
+		Generation  int64             `json:"generation"`
+		Annotations map[string]string `json:"annotations"`
+	} `json:"metadata"`
+	Spec   map[string]interface{} `json:"spec"`
+	Status sourceStatus           `json:"status"`
+}
+
+type sourceStatus struct {
+	Phase              string `json:"phase,omitempty"` // Ready | Failed
+	ObservedGeneration int64  `json:"observedGeneration,omitempty"`
+	Repo               string `json:"repo,omitempty"`
+	TemplateVersion    string `json:"templateVersion,omitempty"`
+	ErrorCode          string `json:"errorCode,omitempty"`
+	Message            string `json:"message,omitempty"`
+	LastSyncedAt       string `json:"lastSyncedAt,omitempty"`
+}
+
+// runOperatorCommand: `operator crd` in CRD, `operator run [--namespace ns] [--interval 1m] [--once]` chạy controller
+func runOperatorCommand(args []string) error {
+	if len(args) == 0 {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts operator crd | run [--namespace ns] [--interval 1m] [--once]"))
+	}
+	switch args[0] {
+	case "crd":
+		fmt.Print(sourceCRD())
+		return nil
+	case "run":
+		fs := flag.NewFlagSet("operator run", flag.ExitOnError)
+		namespace := fs.String("namespace", "", "only watch this namespace (default: all namespaces)")
+		interval := fs.Duration("interval", time.Minute, "resync interval")
+		once := fs.Bool("once", false, "reconcile once and exit")
+		fs.Parse(args[1:])
+
+		registryConfig, err := loadRegistryConfig(registryConfigFile)
+		if err != nil {
+			return fmt.Errorf("error loading registry config: %w", err)
+		}
+		for {
+			if err := reconcileSources(*namespace, registryConfig); err != nil {
+				if *once {
+					return err
+				}
+				fmt.Printf("⚠️ Operator: %v\n", err)
+			}
+			if *once {
+				return nil
+			}
+			time.Sleep(*interval)
+		}
+	default:
+		return withCode(ErrUsage, fmt.Errorf("unknown operator command: %s", args[0]))
+	}
+}
+
+// reconcileSources đưa mọi Source CR về trạng thái mong muốn. CR đã Ready ở generation hiện tại
+// vẫn được kiểm tra lại nhưng provisionService bỏ qua nhanh nhờ manifest (templates đổi vẫn được áp).
+func reconcileSources(namespace string, registryConfig RegistryConfig) error {
+	args := []string{"get", sourceCRDResource, "-o", "json"}
+	if namespace == "" {
+		args = append(args, "--all-namespaces")
+	} else {
+		args = append(args, "-n", namespace)
+	}
+	out, err := commandExecutor.Run(Command{Name: "kubectl", Args: args, CaptureOutput: true})
+	if err != nil {
+		return fmt.Errorf("failed to list %s: %w", sourceCRDResource, err)
+	}
+	var list struct {
+		Items []sourceResource `json:"items"`
+	}
+	if err := json.Unmarshal([]byte(out), &list); err != nil {
+		return fmt.Errorf("failed to parse %s list: %w", sourceCRDResource, err)
+	}
+
+	for _, item := range list.Items {
+		status := reconcileSource(item, registryConfig)
+		if err := patchSourceStatus(item, status); err != nil {
+			fmt.Printf("⚠️ Failed to update status of %s/%s: %v\n", item.Metadata.Namespace, item.Metadata.Name, err)
+		}
+	}
+	return nil
+}
+
+func reconcileSource(item sourceResource, registryConfig RegistryConfig) sourceStatus {
+	status := sourceStatus{ObservedGeneration: item.Metadata.Generation, LastSyncedAt: time.Now().UTC().Format(time.RFC3339)}
+	failed := func(err error) sourceStatus {
+		status.Phase = "Failed"
+		status.ErrorCode = string(errorCode(err))
+		status.Message = redact(err.Error())
+		return status
+	}
+
+	servicePath, err := writeSourceFromCR(item)
+	if err != nil {
+		return failed(err)
+	}
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	fmt.Printf("☸️  Reconciling Source %s/%s\n", item.Metadata.Namespace, item.Metadata.Name)
+	fmt.Println("━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━━")
+	if err := provisionService(servicePath, registryConfig, 1, parseConfirm(item.Metadata.Annotations[confirmAnnotation])); err != nil {
+		fmt.Printf("❌ [%s] %v\n", errorCode(err), err)
+		return failed(err)
+	}
+
+	config, err := loadSourceConfig(servicePath)
+	if err != nil {
+		return failed(err)
+	}
+	status.Phase = "Ready"
+	if entry, err := serviceStateEntry(config.Name); err == nil {
+		status.Repo = entry.Repo
+		status.TemplateVersion = entry.TemplateVersion
+	}
+	return status
+}
+
+// writeSourceFromCR render spec của CR thành source.yml; source_id mặc định là UID của CR (ổn định qua rename)
+func writeSourceFromCR(item sourceResource) (string, error) {
+	spec := item.Spec
+	if spec == nil {
+		return "", withCode(ErrValidationFailed, fmt.Errorf("Source %s/%s has no spec", item.Metadata.Namespace, item.Metadata.Name))
+	}
+	if _, ok := spec["source_id"]; !ok {
+		spec["source_id"] = item.Metadata.UID
+	}
+	if _, ok := spec["name"]; !ok {
+		spec["name"] = item.Metadata.Name
+	}
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		return "", err
+	}
+	servicePath := filepath.Join(operatorDir, item.Metadata.Namespace+"-"+item.Metadata.Name)
+	if err := os.MkdirAll(servicePath, 0755); err != nil {
+		return "", err
+	}
+	if err := os.WriteFile(filepath.Join(servicePath, "source.yml"), data, 0644); err != nil {
+		return "", err
+	}
+	return servicePath, nil
+}
+
+func patchSourceStatus(item sourceResource, status sourceStatus) error {
+	patch, err := json.Marshal(map[string]interface{}{"status": status})
+	if err != nil {
+		return err
+	}
+	_, err = commandExecutor.Run(Command{Name: "kubectl", Args: []string{
+		"patch", sourceCRDResource, item.Metadata.Name, "-n", item.Metadata.Namespace,
+		"--subresource=status", "--type=merge", "-p", string(patch),
+	}, CaptureOutput: true})
+	return err
+}
+
+// sourceCRD là CustomResourceDefinition của Source; spec giữ nguyên schema của source.yml
+// (validate bằng `validate` của registry nên không lặp lại OpenAPI schema ở đây)
+func sourceCRD() string {
+	return strings.TrimLeft(`
+apiVersion: apiextensions.k8s.io/v1
+kind: CustomResourceDefinition
+metadata:
+  name: `+sourceCRDResource+`
+spec:
+  group: `+sourceCRDGroup+`
+  scope: Namespaced
+  names:
+    kind: Source
+    listKind: SourceList
+    plural: sources
+    singular: source
+  versions:
+    - name: v1alpha1
+      served: true
+      storage: true
+      subresources:
+        status: {}
+      additionalPrinterColumns:
+        - name: Phase
+          type: string
+          jsonPath: .status.phase
+        - name: Repo
+          type: string
+          jsonPath: .status.repo
+        - name: Synced
+          type: date
+          jsonPath: .status.lastSyncedAt
+      schema:
+        openAPIV3Schema:
+          type: object
+          properties:
+            spec:
+              description: Same fields as sources-service/<name>/source.yml
+              type: object
+              x-kubernetes-preserve-unknown-fields: true
+            status:
+              type: object
+              properties:
+                phase:
+                  type: string
+                observedGeneration:
+                  type: integer
+                repo:
+                  type: string
+                templateVersion:
+                  type: string
+                errorCode:
+                  type: string
+                message:
+                  type: string
+                lastSyncedAt:
+                  type: string
+                  format: date-time
+`, "\n")
+}
@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// OwnershipTags cấu hình nơi ghi tags/labels (cost attribution) trong repo được generate.
+// Path để trống thì bỏ qua output đó.
+type OwnershipTags struct {
+	TerraformTfvars  string `yaml:"terraform_tfvars"`
+	KubernetesLabels string `yaml:"kubernetes_labels"`
+	HelmValues       string `yaml:"helm_values"`
+}
+
+var invalidLabelChars = regexp.MustCompile(`[^a-z0-9._-]+`)
+
+// serviceTags gom các tag chuẩn (service, team, cost_center) và labels tự khai báo
+func serviceTags(dto GeneratorSourceDto) map[string]string {
+	tags := map[string]string{"service": dto.AppName}
+	if dto.Team != "" {
+		tags["team"] = dto.Team
+	}
+	if dto.CostCenter != "" {
+		tags["cost_center"] = dto.CostCenter
+	}
+	if dto.DataClassification != "" {
+		tags["data_classification"] = dto.DataClassification
+	}
+	if dto.Hostname != "" {
+		tags["hostname"] = dto.Hostname
+	}
+	for k, v := range dto.Labels {
+		tags[k] = v
+	}
+	return tags
+}
+
+// kubernetesLabels chuẩn hoá key/value theo cú pháp label của Kubernetes (prefix jupiter.io/)
+func kubernetesLabels(tags map[string]string) map[string]string {
+	labels := make(map[string]string, len(tags))
+	for k, v := range tags {
+		key := "jupiter.io/" + strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(k), "-"), "-._")
+		value := strings.Trim(invalidLabelChars.ReplaceAllString(strings.ToLower(v), "-"), "-._")
+		if len(value) > 63 {
+			value = value[:63]
+		}
+		labels[key] = value
+	}
+	return labels
+}
+
+func writeOwnershipTags(repoDir string, dto GeneratorSourceDto, cfg OwnershipTags) error {
+	tags := serviceTags(dto)
+
+	if cfg.TerraformTfvars != "" {
+		data, err := json.MarshalIndent(map[string]interface{}{"tags": tags}, "", "  ")
+		if err != nil {
+			return err
+		}
+		if err := writeRepoFile(repoDir, cfg.TerraformTfvars, append(data, '\n')); err != nil {
+			return err
+		}
+	}
+
+	labels := kubernetesLabels(tags)
+	if cfg.KubernetesLabels != "" {
+		// Dạng kustomize component: commonLabels áp cho mọi resource
+		data, err := yaml.Marshal(map[string]interface{}{
+			"apiVersion": "kustomize.config.k8s.io/v1alpha1",
+			"kind":       "Component",
+			"labels": []map[string]interface{}{
+				{"pairs": labels, "includeSelectors": false},
+			},
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeRepoFile(repoDir, cfg.KubernetesLabels, data); err != nil {
+			return err
+		}
+	}
+
+	if cfg.HelmValues != "" {
+		data, err := yaml.Marshal(map[string]interface{}{
+			"commonLabels": labels,
+			"podLabels":    labels,
+		})
+		if err != nil {
+			return err
+		}
+		if err := writeRepoFile(repoDir, cfg.HelmValues, data); err != nil {
+			return err
+		}
+	}
+
+	keys := make([]string, 0, len(tags))
+	for k := range tags {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	fmt.Printf("🏷️  Ownership tags: %s\n", strings.Join(keys, ", "))
+	return nil
+}
+
+// writeRepoFile ghi file vào repo được generate (tạo thư mục cha nếu cần)
+func writeRepoFile(repoDir, relPath string, data []byte) error {
+	target := filepath.Join(repoDir, relPath)
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create dir for %s: %w", target, err)
+	}
+	if err := os.WriteFile(target, data, 0644); err != nil {
+		return fmt.Errorf("failed to write %s: %w", target, err)
+	}
+	return nil
+}
@@ -0,0 +1,214 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path"
+	"sort"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// PlanChange là một thay đổi của catalog giữa hai revision của jupiter-registry
+type PlanChange struct {
+	Action  string   `json:"action"` // create | update | rename | archive
+	Service string   `json:"service"`
+	Folder  string   `json:"folder"`
+	From    string   `json:"from,omitempty"` // tên cũ khi rename
+	Reasons []string `json:"reasons,omitempty"`
+}
+
+// Plan giống terraform plan cho service catalog: chỉ đọc git, không gọi GitHub
+type Plan struct {
+	From             string       `json:"from"`
+	To               string       `json:"to"`
+	TemplatesChanged bool         `json:"templates_changed"`
+	ConfigChanged    bool         `json:"config_changed"`
+	Changes          []PlanChange `json:"changes"`
+	Warnings         []string     `json:"warnings,omitempty"`
+}
+
+type revisionService struct {
+	Folder string
+	Config SourceConfig
+	Digest [32]byte
+}
+
+// key: source_id là định danh ổn định (đổi name / folder vẫn là cùng service)
+func (s revisionService) key() string {
+	if s.Config.SourceID != "" {
+		return s.Config.SourceID
+	}
+	return "name:" + s.Config.Name
+}
+
+// gitRead chạy git không echo command (output dùng cho --json)
+func gitRead(args ...string) (string, error) {
+	out, err := commandExecutor.Run(Command{Name: "git", Args: args, CaptureOutput: true})
+	return strings.TrimSpace(out), err
+}
+
+// servicesAtRevision đọc mọi sources-service/<folder>/source.yml tại ref.
+// source_id trùng nhau thì folder sau được key riêng theo folder và trả về cảnh báo.
+func servicesAtRevision(ref string) (map[string]revisionService, []string, error) {
+	out, err := gitRead("ls-tree", "-r", "--name-only", ref, "--", sourcesDir)
+	if err != nil {
+		return nil, nil, withCode(ErrUsage, fmt.Errorf("failed to list %s at %s: %w", sourcesDir, ref, err))
+	}
+	services := map[string]revisionService{}
+	var warnings []string
+	for _, file := range strings.Split(out, "\n") {
+		if path.Base(file) != "source.yml" || path.Dir(path.Dir(file)) != sourcesDir {
+			continue
+		}
+		data, err := gitRead("show", ref+":"+file)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to read %s at %s: %w", file, ref, err)
+		}
+		var config SourceConfig
+		if err := yaml.Unmarshal([]byte(data), &config); err != nil {
+			return nil, nil, withCode(ErrYAMLInvalid, fmt.Errorf("error parsing YAML %s at %s: %w", file, ref, err))
+		}
+		s := revisionService{Folder: path.Base(path.Dir(file)), Config: config, Digest: sha256.Sum256([]byte(data))}
+		key := s.key()
+		if existing, ok := services[key]; ok {
+			warnings = append(warnings, fmt.Sprintf("%s: %s and %s share %s", ref, existing.Folder, s.Folder, key))
+			key += "@" + s.Folder
+		}
+		services[key] = s
+	}
+	return services, warnings, nil
+}
+
+func pathChanged(from, to string, paths ...string) (bool, error) {
+	out, err := gitRead(append([]string{"diff", "--name-only", from, to, "--"}, paths...)...)
+	if err != nil {
+		return false, fmt.Errorf("git diff %s..%s failed: %w", from, to, err)
+	}
+	return out != "", nil
+}
+
+func buildPlan(from, to string, registryConfig RegistryConfig) (Plan, error) {
+	plan := Plan{From: from, To: to, Changes: []PlanChange{}}
+
+	before, _, err := servicesAtRevision(from)
+	if err != nil {
+		return plan, err
+	}
+	after, warnings, err := servicesAtRevision(to)
+	if err != nil {
+		return plan, err
+	}
+	plan.Warnings = warnings
+	// template_version là digest của cả thư mục templates: đổi bất kỳ file nào thì mọi service đều regenerate
+	if registryConfig.FrameworkTemplates != "" {
+		if plan.TemplatesChanged, err = pathChanged(from, to, registryConfig.FrameworkTemplates); err != nil {
+			return plan, err
+		}
+	}
+	if plan.ConfigChanged, err = pathChanged(from, to, registryConfigFile); err != nil {
+		return plan, err
+	}
+
+	for key, s := range after {
+		old, existed := before[key]
+		change := PlanChange{Service: s.Config.Name, Folder: s.Folder}
+		switch {
+		case !existed:
+			change.Action = "create"
+		case old.Config.Name != s.Config.Name:
+			change.Action = "rename"
+			change.From = old.Config.Name
+		default:
+			change.Action = "update"
+		}
+		if existed {
+			if old.Digest != s.Digest {
+				change.Reasons = append(change.Reasons, "source.yml changed")
+			}
+			if old.Config.Owner != s.Config.Owner {
+				change.Reasons = append(change.Reasons, fmt.Sprintf("owner %s -> %s", old.Config.Owner, s.Config.Owner))
+			}
+			if plan.TemplatesChanged {
+				change.Reasons = append(change.Reasons, "templates changed")
+			}
+			if change.Action == "update" && len(change.Reasons) == 0 {
+				continue
+			}
+		}
+		plan.Changes = append(plan.Changes, change)
+	}
+	for key, s := range before {
+		if _, ok := after[key]; !ok {
+			plan.Changes = append(plan.Changes, PlanChange{Action: "archive", Service: s.Config.Name, Folder: s.Folder})
+		}
+	}
+
+	sort.Slice(plan.Changes, func(i, j int) bool {
+		if plan.Changes[i].Service != plan.Changes[j].Service {
+			return plan.Changes[i].Service < plan.Changes[j].Service
+		}
+		return plan.Changes[i].Folder < plan.Changes[j].Folder
+	})
+	return plan, nil
+}
+
+var planSymbols = map[string]string{"create": "+", "update": "~", "rename": ">", "archive": "-"}
+
+// runPlanCommand: `plan --from <git-ref> [--to <git-ref>] [--json]`
+func runPlanCommand(args []string) error {
+	fs := flag.NewFlagSet("plan", flag.ExitOnError)
+	from := fs.String("from", "", "base git ref of jupiter-registry")
+	to := fs.String("to", "HEAD", "target git ref of jupiter-registry")
+	asJSON := fs.Bool("json", false, "print the plan as JSON")
+	fs.Parse(args)
+	if *from == "" {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts plan --from <git-ref> [--to <git-ref>] [--json]"))
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	plan, err := buildPlan(*from, *to, registryConfig)
+	if err != nil {
+		return err
+	}
+
+	if *asJSON {
+		enc := json.NewEncoder(os.Stdout)
+		enc.SetEscapeHTML(false)
+		enc.SetIndent("", "  ")
+		return enc.Encode(plan)
+	}
+
+	fmt.Printf("📋 Plan %s..%s\n", plan.From, plan.To)
+	if plan.ConfigChanged {
+		fmt.Printf("  ⚠️ %s changed, review registry-wide settings separately\n", registryConfigFile)
+	}
+	for _, w := range plan.Warnings {
+		fmt.Printf("  ⚠️ %s\n", w)
+	}
+	counts := map[string]int{}
+	for _, c := range plan.Changes {
+		counts[c.Action]++
+		line := fmt.Sprintf("  %s %s", planSymbols[c.Action], c.Service)
+		if c.From != "" {
+			line = fmt.Sprintf("  %s %s -> %s", planSymbols[c.Action], c.From, c.Service)
+		}
+		if len(c.Reasons) > 0 {
+			line += " (" + strings.Join(c.Reasons, ", ") + ")"
+		}
+		fmt.Println(line)
+	}
+	if len(plan.Changes) == 0 {
+		fmt.Println("  No changes.")
+	}
+	fmt.Printf("\nPlan: %d to create, %d to update, %d to rename, %d to archive.\n",
+		counts["create"], counts["update"], counts["rename"], counts["archive"])
+	return nil
+}
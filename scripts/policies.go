@@ -0,0 +1,75 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Policies cấu hình org policies viết bằng Rego, evaluate bằng `opa eval`
+type Policies struct {
+	Dir   string `yaml:"dir"`
+	Query string `yaml:"query"`
+}
+
+// evaluatePolicies chạy policies với input là source.yml (dạng JSON), trả về danh sách vi phạm
+func evaluatePolicies(ex Executor, sourceFile string, policies Policies) ([]string, error) {
+	query := policies.Query
+	if query == "" {
+		query = "data.jupiter.deny"
+	}
+
+	data, err := os.ReadFile(sourceFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", sourceFile, err)
+	}
+
+	// Dùng nội dung gốc (không qua SourceConfig) để policy đọc được mọi field
+	var raw map[string]interface{}
+	if err := yaml.Unmarshal(data, &raw); err != nil {
+		return nil, fmt.Errorf("failed to parse %s: %w", sourceFile, err)
+	}
+	input, err := json.Marshal(raw)
+	if err != nil {
+		return nil, err
+	}
+
+	out, err := runCommandOutputWithInput(ex, input, "opa", "eval", "--format", "json",
+		"--data", policies.Dir, "--stdin-input", query)
+	if err != nil {
+		return nil, fmt.Errorf("opa eval failed: %w", err)
+	}
+
+	var result struct {
+		Result []struct {
+			Expressions []struct {
+				Value interface{} `json:"value"`
+			} `json:"expressions"`
+		} `json:"result"`
+	}
+	if err := json.Unmarshal([]byte(out), &result); err != nil {
+		return nil, fmt.Errorf("failed to parse opa output: %w", err)
+	}
+
+	// deny có thể là set (array) hoặc object message -> true
+	var violations []string
+	for _, r := range result.Result {
+		for _, expr := range r.Expressions {
+			switch value := expr.Value.(type) {
+			case []interface{}:
+				for _, v := range value {
+					violations = append(violations, fmt.Sprintf("policy: %v (%s)", v, sourceFile))
+				}
+			case map[string]interface{}:
+				for msg := range value {
+					violations = append(violations, fmt.Sprintf("policy: %s (%s)", msg, sourceFile))
+				}
+			}
+		}
+	}
+	sort.Strings(violations)
+	return violations, nil
+}
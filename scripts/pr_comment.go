@@ -0,0 +1,113 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strconv"
+	"strings"
+	"sync"
+)
+
+// stickyCommentMarker đánh dấu comment tổng hợp của registry trên PR
+const stickyCommentMarker = "<!-- jupiter-registry:summary -->"
+
+// triggeringPullRequest trả về PR đã trigger lần chạy hiện tại:
+//   - pull_request: chính PR đó
+//   - push lên main: PR đã merge commit GITHUB_SHA
+func triggeringPullRequest(ex Executor) (repo string, number int, ok bool) {
+	if pr, found := currentPullRequest(); found {
+		return pr.Repository, pr.Number, true
+	}
+
+	repo = os.Getenv("GITHUB_REPOSITORY")
+	sha := os.Getenv("GITHUB_SHA")
+	if os.Getenv("GITHUB_EVENT_NAME") != "push" || repo == "" || sha == "" {
+		return "", 0, false
+	}
+
+	out, err := ghRegistryOutput(ex, "api", fmt.Sprintf("repos/%s/commits/%s/pulls", repo, sha), "--jq", ".[0].number")
+	if err != nil || out == "" || out == "null" {
+		return "", 0, false
+	}
+	number, err = strconv.Atoi(out)
+	if err != nil {
+		return "", 0, false
+	}
+	return repo, number, true
+}
+
+// reportProvisioningOutcome cập nhật section của service trong sticky comment
+func reportProvisioningOutcome(dto GeneratorSourceDto, registryConfig RegistryConfig, processErr error) {
+	repo, number, ok := triggeringPullRequest(commandExecutor)
+	if !ok {
+		return
+	}
+
+	var section strings.Builder
+	repoURL := fmt.Sprintf("https://github.com/%s/%s", dto.Owner, dto.AppName)
+	if processErr != nil {
+		fmt.Fprintf(&section, "#### ❌ %s\n\nProvisioning failed:\n\n```\n%s\n```\n", dto.AppName, redact(processErr.Error()))
+	} else {
+		fmt.Fprintf(&section, "#### ✅ %s\n\nRepository: [%s/%s](%s)\n", dto.AppName, dto.Owner, dto.AppName, repoURL)
+	}
+	fmt.Fprintf(&section, "\n<details><summary>Plan</summary>\n\n%s\n</details>\n", buildPlanSummary(dto, registryConfig, ValidationResult{}))
+
+	if err := upsertStickyComment(repo, number, dto.AppName, section.String()); err != nil {
+		fmt.Printf("⚠️ Failed to update PR comment: %v\n", err)
+	}
+}
+
+// commentMu serialize việc cập nhật sticky comment khi batch chạy song song
+var commentMu sync.Mutex
+
+func upsertStickyComment(repo string, number int, service, section string) error {
+	commentMu.Lock()
+	defer commentMu.Unlock()
+
+	commentID, err := ghRegistryOutput(commandExecutor, "api", "--paginate",
+		fmt.Sprintf("repos/%s/issues/%d/comments", repo, number),
+		"--jq", fmt.Sprintf(".[] | select(.body | contains(%q)) | .id", stickyCommentMarker))
+	if err != nil {
+		return fmt.Errorf("failed to list comments: %w", err)
+	}
+	commentID = strings.SplitN(commentID, "\n", 2)[0]
+
+	existing := ""
+	if commentID != "" {
+		existing, err = ghRegistryOutput(commandExecutor, "api", fmt.Sprintf("repos/%s/issues/comments/%s", repo, commentID), "--jq", ".body")
+		if err != nil {
+			return fmt.Errorf("failed to read comment: %w", err)
+		}
+	}
+
+	body, err := json.Marshal(map[string]string{"body": mergeCommentSection(existing, service, section)})
+	if err != nil {
+		return err
+	}
+
+	if commentID == "" {
+		return ghRegistryWithInput(commandExecutor, body, "api", "-X", "POST",
+			fmt.Sprintf("repos/%s/issues/%d/comments", repo, number), "--input", "-")
+	}
+	return ghRegistryWithInput(commandExecutor, body, "api", "-X", "PATCH",
+		fmt.Sprintf("repos/%s/issues/comments/%s", repo, commentID), "--input", "-")
+}
+
+// mergeCommentSection thay thế (hoặc thêm mới) section của service trong body hiện tại
+func mergeCommentSection(existing, service, section string) string {
+	start := fmt.Sprintf("<!-- jupiter-registry:%s:start -->", service)
+	end := fmt.Sprintf("<!-- jupiter-registry:%s:end -->", service)
+	block := start + "\n" + section + end
+
+	if existing == "" {
+		return stickyCommentMarker + "\n## 🪐 jupiter-registry provisioning summary\n\n" + block + "\n"
+	}
+
+	startIdx := strings.Index(existing, start)
+	endIdx := strings.Index(existing, end)
+	if startIdx >= 0 && endIdx > startIdx {
+		return existing[:startIdx] + block + existing[endIdx+len(end):]
+	}
+	return strings.TrimRight(existing, "\n") + "\n\n" + block + "\n"
+}
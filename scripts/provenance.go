@@ -0,0 +1,366 @@
+package main
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Provenance sinh attestation kiểu SLSA (in-toto statement, predicate slsa.dev/provenance/v1) cho mỗi repo
+// được generate: input là digest của source.yml, template version, revision của registry; builder là
+// workflow đang chạy. Subject là digest của cây file generated (xem treeDigest) nên consumer kiểm tra lại được
+// bằng `provenance verify` mà không cần tải nội dung repo.
+type Provenance struct {
+	Enabled bool `yaml:"enabled"`
+	// Attach: commit (mặc định, .jupiter/provenance.intoto.json nằm trong commit generate)
+	// | release (asset của release jupiter-<template version>)
+	Attach string `yaml:"attach"`
+	// Sign ký attestation bằng `cosign sign-blob` (keyless, OIDC của workflow), bundle ghi cạnh attestation
+	Sign bool `yaml:"sign"`
+	// TrustedBuilders: builder.id `provenance verify` chấp nhận, rỗng = chỉ kiểm tra digest
+	TrustedBuilders []string `yaml:"trusted_builders"`
+}
+
+const (
+	provenancePath       = ".jupiter/provenance.intoto.json"
+	provenanceBundlePath = ".jupiter/provenance.sigstore.json"
+	provenanceBuildType  = "https://github.com/tqhuy-dev/jupiter-registry/provenance/v1"
+	// treeDigestAlgorithm: sha256 của danh sách "path blob-sha" (đã sort) của mọi file tracked
+	treeDigestAlgorithm = "jupiterTree"
+)
+
+func (p Provenance) attach() string {
+	if p.Attach == "" {
+		return "commit"
+	}
+	return p.Attach
+}
+
+func (p Provenance) validate() error {
+	if a := p.attach(); a != "commit" && a != "release" {
+		return fmt.Errorf("provenance.attach %q must be commit or release", p.Attach)
+	}
+	return nil
+}
+
+// Statement là in-toto statement v1
+type Statement struct {
+	Type          string               `json:"_type"`
+	Subject       []ResourceDescriptor `json:"subject"`
+	PredicateType string               `json:"predicateType"`
+	Predicate     ProvenancePredicate  `json:"predicate"`
+}
+
+type ResourceDescriptor struct {
+	Name   string            `json:"name,omitempty"`
+	URI    string            `json:"uri,omitempty"`
+	Digest map[string]string `json:"digest,omitempty"`
+}
+
+type ProvenancePredicate struct {
+	BuildDefinition struct {
+		BuildType            string                 `json:"buildType"`
+		ExternalParameters   map[string]interface{} `json:"externalParameters"`
+		InternalParameters   map[string]interface{} `json:"internalParameters,omitempty"`
+		ResolvedDependencies []ResourceDescriptor   `json:"resolvedDependencies"`
+	} `json:"buildDefinition"`
+	RunDetails struct {
+		Builder struct {
+			ID string `json:"id"`
+		} `json:"builder"`
+		Metadata struct {
+			InvocationID string `json:"invocationId"`
+			StartedOn    string `json:"startedOn"`
+		} `json:"metadata"`
+	} `json:"runDetails"`
+}
+
+// builderID là identity của workflow đang chạy (GITHUB_WORKFLOW_REF), ngoài Actions là máy local + actor
+func builderID() string {
+	if ref := os.Getenv("GITHUB_WORKFLOW_REF"); ref != "" {
+		server := os.Getenv("GITHUB_SERVER_URL")
+		if server == "" {
+			server = "https://github.com"
+		}
+		return fmt.Sprintf("%s/%s", server, ref)
+	}
+	return "local:" + currentActor()
+}
+
+// registryRevision là commit của registry đang chạy generator
+func registryRevision(ex Executor) string {
+	if sha := os.Getenv("GITHUB_SHA"); sha != "" {
+		return sha
+	}
+	out, err := runCommandOutput(ex, "git", "rev-parse", "HEAD")
+	if err != nil {
+		return "unknown"
+	}
+	return out
+}
+
+// digestBlobs: sha256 của "path sha\n" theo thứ tự path, bỏ qua chính attestation / bundle
+func digestBlobs(blobs map[string]string) string {
+	paths := make([]string, 0, len(blobs))
+	for path := range blobs {
+		if path != provenancePath && path != provenanceBundlePath {
+			paths = append(paths, path)
+		}
+	}
+	sort.Strings(paths)
+	h := sha256.New()
+	for _, path := range paths {
+		fmt.Fprintf(h, "%s %s\n", path, blobs[path])
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// treeDigest tính digest của các file sẽ được commit trong repoDir. Dùng git dir tạm để tôn trọng .gitignore
+// (vd. node_modules do CLI scaffold cài) mà không đụng tới .git của bước push.
+func treeDigest(ex Executor, repoDir string) (string, error) {
+	gitDir, err := os.MkdirTemp("", "jupiter-provenance-")
+	if err != nil {
+		return "", err
+	}
+	defer os.RemoveAll(gitDir)
+
+	git := []string{"--git-dir", gitDir, "--work-tree", "."}
+	if err := runCommandInDir(ex, repoDir, "git", "--git-dir", gitDir, "init", "--quiet"); err != nil {
+		return "", err
+	}
+	if err := runCommandInDir(ex, repoDir, "git", append(git, "add", "-A")...); err != nil {
+		return "", err
+	}
+	// "<mode> <sha> <stage>\t<path>", bỏ qua submodule (mode 160000)
+	out, err := runCommandOutputInDir(ex, repoDir, "git", append(git, "ls-files", "-s")...)
+	if err != nil {
+		return "", err
+	}
+	blobs := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		meta, path, ok := strings.Cut(line, "\t")
+		fields := strings.Fields(meta)
+		if !ok || len(fields) < 2 || fields[0] == "160000" {
+			continue
+		}
+		blobs[path] = fields[1]
+	}
+	return digestBlobs(blobs), nil
+}
+
+// remoteTreeDigest tính cùng digest từ git tree API của ref trên GitHub
+func remoteTreeDigest(fullName, ref string) (string, error) {
+	out, err := runCommandOutput(commandExecutor, "gh", "api", fmt.Sprintf("repos/%s/git/trees/%s?recursive=1", fullName, ref),
+		"--jq", `.tree[] | select(.type == "blob") | "\(.path) \(.sha)"`)
+	if err != nil {
+		return "", withCode(ErrGitHubAPI, fmt.Errorf("failed to read tree of %s: %w", fullName, err))
+	}
+	blobs := map[string]string{}
+	for _, line := range strings.Split(out, "\n") {
+		if path, sha, ok := strings.Cut(line, " "); ok {
+			blobs[path] = sha
+		}
+	}
+	return digestBlobs(blobs), nil
+}
+
+func buildProvenance(ex Executor, repoDir string, dto GeneratorSourceDto) (Statement, error) {
+	digest, err := treeDigest(ex, repoDir)
+	if err != nil {
+		return Statement{}, fmt.Errorf("failed to hash generated tree: %w", err)
+	}
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	manifest := Manifest{TemplateVersion: "unknown"}
+	if dto.Manifest != nil {
+		manifest = *dto.Manifest
+	}
+
+	statement := Statement{
+		Type:          "https://in-toto.io/Statement/v1",
+		Subject:       []ResourceDescriptor{{Name: fullName, Digest: map[string]string{treeDigestAlgorithm: digest}}},
+		PredicateType: "https://slsa.dev/provenance/v1",
+	}
+	def := &statement.Predicate.BuildDefinition
+	def.BuildType = provenanceBuildType
+	def.ExternalParameters = map[string]interface{}{
+		"source_id":  manifest.SourceID,
+		"repository": fullName,
+	}
+	def.InternalParameters = map[string]interface{}{
+		"kind":                       dto.Kind,
+		"programming_language":       dto.ProgrammingLanguage,
+		"framework":                  dto.Framework,
+		"generator_contract_version": generatorContractVersion,
+	}
+	def.ResolvedDependencies = []ResourceDescriptor{
+		{Name: "source.yml", Digest: map[string]string{"sha256": strings.TrimPrefix(manifest.SourceDigest, "sha256:")}},
+		{Name: "templates", Digest: map[string]string{"jupiterTemplateVersion": manifest.TemplateVersion}},
+		{URI: "git+https://github.com/tqhuy-dev/jupiter-registry", Digest: map[string]string{"gitCommit": registryRevision(ex)}},
+	}
+
+	run := &statement.Predicate.RunDetails
+	run.Builder.ID = builderID()
+	run.Metadata.InvocationID = currentRunURL()
+	if run.Metadata.InvocationID == "" {
+		run.Metadata.InvocationID = currentRunID
+	}
+	run.Metadata.StartedOn = time.Now().UTC().Format(time.RFC3339)
+	return statement, nil
+}
+
+// writeProvenance ghi attestation (và bundle nếu sign) vào dir, trả về các file đã ghi
+func writeProvenance(ex Executor, repoDir, dir string, dto GeneratorSourceDto, p Provenance) ([]string, error) {
+	statement, err := buildProvenance(ex, repoDir, dto)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.MarshalIndent(statement, "", "  ")
+	if err != nil {
+		return nil, err
+	}
+	attestation := filepath.Join(dir, filepath.FromSlash(provenancePath))
+	if err := writeRepoFile(dir, provenancePath, append(data, '\n')); err != nil {
+		return nil, err
+	}
+	files := []string{attestation}
+	if p.Sign {
+		bundle := filepath.Join(dir, filepath.FromSlash(provenanceBundlePath))
+		if err := runCommand(ex, "cosign", "sign-blob", "--yes", "--bundle", bundle, attestation); err != nil {
+			return nil, fmt.Errorf("failed to sign provenance: %w", err)
+		}
+		files = append(files, bundle)
+	}
+	return files, nil
+}
+
+// attachProvenance: attach commit ghi attestation vào repoDir trước push,
+// attach release ghi ra thư mục tạm, trả về hàm upload chạy sau push
+func attachProvenance(ex Executor, repoDir string, dto GeneratorSourceDto, p Provenance) (func() error, error) {
+	if p.attach() == "commit" {
+		_, err := writeProvenance(ex, repoDir, repoDir, dto, p)
+		return func() error { return nil }, err
+	}
+
+	tmp, err := os.MkdirTemp("", "jupiter-provenance-")
+	if err != nil {
+		return nil, err
+	}
+	files, err := writeProvenance(ex, repoDir, tmp, dto, p)
+	if err != nil {
+		os.RemoveAll(tmp)
+		return nil, err
+	}
+	return func() error {
+		defer os.RemoveAll(tmp)
+		// Release trỏ đúng commit vừa push (default branch, bootstrap branch hoặc upgrade branch)
+		commit, err := runCommandOutputInDir(ex, repoDir, "git", "rev-parse", "HEAD")
+		if err != nil {
+			return err
+		}
+		return uploadProvenanceRelease(ex, dto, commit, files)
+	}, nil
+}
+
+func provenanceReleaseTag(dto GeneratorSourceDto) string {
+	if dto.Manifest != nil {
+		return "jupiter-" + dto.Manifest.TemplateVersion
+	}
+	return "jupiter-unknown"
+}
+
+// uploadProvenanceRelease tạo release jupiter-<template version> (hoặc thay asset nếu đã có)
+func uploadProvenanceRelease(ex Executor, dto GeneratorSourceDto, commit string, files []string) error {
+	repo := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	tag := provenanceReleaseTag(dto)
+	if _, err := runCommandOutput(ex, "gh", "release", "view", tag, "--repo", repo, "--json", "tagName"); err == nil {
+		args := append([]string{"release", "upload", tag, "--repo", repo, "--clobber"}, files...)
+		return runCommand(ex, "gh", args...)
+	}
+	args := append([]string{"release", "create", tag, "--repo", repo,
+		"--target", commit,
+		"--title", "Scaffold " + tag,
+		"--notes", "Generated by jupiter-registry. Verify with `go run ./scripts provenance verify --release " + tag + " " + repo + "`."},
+		files...)
+	return runCommand(ex, "gh", args...)
+}
+
+// runProvenanceCommand: `provenance verify [--ref HEAD] [--release <tag>] <owner/repo>`
+func runProvenanceCommand(args []string) error {
+	if len(args) == 0 || args[0] != "verify" {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts provenance verify [--ref HEAD] [--release <tag>] <owner/repo>"))
+	}
+	fs := flag.NewFlagSet("provenance verify", flag.ExitOnError)
+	ref := fs.String("ref", "HEAD", "git ref whose tree must match the attested digest")
+	release := fs.String("release", "", "read the attestation from this release instead of the repository")
+	fs.Parse(args[1:])
+	if fs.NArg() != 1 || !strings.Contains(fs.Arg(0), "/") {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts provenance verify [--ref HEAD] [--release <tag>] <owner/repo>"))
+	}
+	repo := fs.Arg(0)
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+
+	var content string
+	if *release != "" {
+		content, err = runCommandOutput(commandExecutor, "gh", "release", "download", *release, "--repo", repo,
+			"--pattern", filepath.Base(provenancePath), "--output", "-")
+		// Release attestation mô tả tree lúc generate: so với tag của release
+		if *ref == "HEAD" {
+			*ref = *release
+		}
+	} else {
+		content, err = readRepoFile(commandExecutor, repo, provenancePath)
+	}
+	if err != nil {
+		return withCode(ErrSourceNotFound, fmt.Errorf("no provenance attestation found for %s: %w", repo, err))
+	}
+	var statement Statement
+	if err := json.Unmarshal([]byte(content), &statement); err != nil {
+		return withCode(ErrValidationFailed, fmt.Errorf("invalid provenance attestation: %w", err))
+	}
+
+	var problems []string
+	if statement.Predicate.BuildDefinition.BuildType != provenanceBuildType {
+		problems = append(problems, fmt.Sprintf("buildType %q was not produced by jupiter-registry", statement.Predicate.BuildDefinition.BuildType))
+	}
+	builder := statement.Predicate.RunDetails.Builder.ID
+	if trusted := registryConfig.Provenance.TrustedBuilders; len(trusted) > 0 && !containsString(trusted, builder) {
+		problems = append(problems, fmt.Sprintf("builder %s is not in provenance.trusted_builders", builder))
+	}
+	if len(statement.Subject) != 1 || statement.Subject[0].Name != repo {
+		problems = append(problems, fmt.Sprintf("attestation subject is not %s", repo))
+	} else {
+		actual, err := remoteTreeDigest(repo, *ref)
+		if err != nil {
+			return err
+		}
+		if expected := statement.Subject[0].Digest[treeDigestAlgorithm]; expected != actual {
+			problems = append(problems, fmt.Sprintf("tree of %s@%s does not match the attested digest (files changed since generation)", repo, *ref))
+		}
+	}
+
+	for _, dep := range statement.Predicate.BuildDefinition.ResolvedDependencies {
+		for algorithm, digest := range dep.Digest {
+			fmt.Printf("  %s%s %s:%s\n", dep.Name, dep.URI, algorithm, digest)
+		}
+	}
+	fmt.Printf("  builder: %s\n  invocation: %s\n", builder, statement.Predicate.RunDetails.Metadata.InvocationID)
+	if len(problems) > 0 {
+		for _, p := range problems {
+			fmt.Printf("❌ %s\n", p)
+		}
+		return withCode(ErrValidationFailed, fmt.Errorf("provenance verification failed for %s", repo))
+	}
+	fmt.Printf("✅ %s@%s matches its jupiter-registry provenance\n", repo, *ref)
+	return nil
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+)
+
+// Publishing cấu hình nơi kind: library release package, để repo library publish được ngay từ release đầu tiên
+type Publishing struct {
+	NPM NPMPublishing `yaml:"npm"`
+	Go  GoPublishing  `yaml:"go"`
+}
+
+// NPMPublishing: registry npm (npmjs, scope của org) hoặc github (GitHub Packages, scope phải là owner)
+type NPMPublishing struct {
+	Registry string `yaml:"registry"` // npm (mặc định) | github
+	Access   string `yaml:"access"`   // restricted (mặc định) | public
+	// TokenEnv: env chứa npm automation token, được set thành secret NPM_TOKEN của repo (chỉ registry npm)
+	TokenEnv string `yaml:"token_env"`
+}
+
+// GoPublishing: Go module proxy được warm sau mỗi release, allowlist module mới trên proxy nội bộ
+type GoPublishing struct {
+	Proxy string `yaml:"proxy"` // mặc định https://proxy.golang.org
+	// AllowlistCommand chạy khi tạo library mới (env JUPITER_MODULE), vd. thêm module vào allowlist của Athens
+	AllowlistCommand string `yaml:"allowlist_command"`
+}
+
+// PublishTarget là phần publish được render vào templates/library/<language>/
+type PublishTarget struct {
+	RegistryURL string // npm registry-url cho actions/setup-node
+	Access      string
+	TokenSecret string // expression token trong workflow
+	GoProxy     string
+}
+
+func (p Publishing) target(dto GeneratorSourceDto) PublishTarget {
+	target := PublishTarget{
+		RegistryURL: "https://registry.npmjs.org",
+		Access:      p.NPM.Access,
+		TokenSecret: "secrets.NPM_TOKEN",
+		GoProxy:     strings.TrimSuffix(p.Go.Proxy, "/"),
+	}
+	if target.Access == "" {
+		target.Access = "restricted"
+	}
+	if p.NPM.Registry == "github" {
+		target.RegistryURL = "https://npm.pkg.github.com"
+		target.TokenSecret = "secrets.GITHUB_TOKEN"
+	}
+	if target.GoProxy == "" {
+		target.GoProxy = "https://proxy.golang.org"
+	}
+	return target
+}
+
+// validatePublishing: GitHub Packages chỉ nhận package scope trùng owner của repo
+func validatePublishing(dto GeneratorSourceDto, p Publishing) error {
+	if dto.ProgrammingLanguage != "nodejs" || p.NPM.Registry != "github" {
+		return nil
+	}
+	scope := "@" + strings.ToLower(dto.Owner) + "/"
+	if !strings.HasPrefix(strings.ToLower(nodePackageName(dto)), scope) {
+		return fmt.Errorf("GitHub Packages requires the npm package to be scoped as %s<name>, got %s", scope, nodePackageName(dto))
+	}
+	return nil
+}
+
+// setupLibraryPublishing cấp secret / allowlist cần cho release đầu tiên của library
+func setupLibraryPublishing(ex Executor, dto GeneratorSourceDto, p Publishing) error {
+	switch dto.ProgrammingLanguage {
+	case "nodejs":
+		if p.NPM.Registry == "github" {
+			return nil // GITHUB_TOKEN với packages: write là đủ
+		}
+		token := os.Getenv(p.NPM.TokenEnv)
+		if p.NPM.TokenEnv == "" || token == "" {
+			fmt.Printf("  ⚠️ NPM_TOKEN not set: $%s is empty, set it before the first release\n", p.NPM.TokenEnv)
+			return nil
+		}
+		fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+		if err := runCommandWithInput(ex, []byte(token), "gh", "secret", "set", "NPM_TOKEN", "--repo", fullName); err != nil {
+			return fmt.Errorf("failed to set NPM_TOKEN: %w", err)
+		}
+	case "golang":
+		if p.Go.AllowlistCommand == "" {
+			return nil
+		}
+		fmt.Printf("  → Allowlisting %s on the Go proxy\n", goModulePath(dto))
+		_, err := ex.Run(Command{
+			Name: "sh",
+			Args: []string{"-c", p.Go.AllowlistCommand},
+			Env:  []string{"JUPITER_SERVICE=" + dto.AppName, "JUPITER_OWNER=" + dto.Owner, "JUPITER_MODULE=" + goModulePath(dto)},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to allowlist module: %w", err)
+		}
+	}
+	return nil
+}
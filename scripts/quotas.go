@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+)
+
+// Quotas giới hạn số repo được tạo, tránh một merge lỗi tạo hàng trăm repo
+type Quotas struct {
+	MaxNewReposPerRun int            `yaml:"max_new_repos_per_run"`
+	MaxReposPerTeam   int            `yaml:"max_repos_per_team"`
+	Teams             map[string]int `yaml:"teams"` // override max_repos_per_team theo team
+}
+
+func (q Quotas) teamLimit(team string) int {
+	if limit, ok := q.Teams[team]; ok {
+		return limit
+	}
+	return q.MaxReposPerTeam
+}
+
+// enforceQuotas kiểm tra số repo mới trong batch và tổng số service của mỗi team trong registry
+func enforceQuotas(servicePaths []string, registryConfig RegistryConfig) error {
+	quotas := registryConfig.Quotas
+	configs := make([]SourceConfig, 0, len(servicePaths))
+	for _, servicePath := range servicePaths {
+		config, err := loadSourceConfig(servicePath)
+		if err != nil {
+			return err
+		}
+		configs = append(configs, config)
+	}
+
+	if quotas.MaxNewReposPerRun > 0 {
+		newRepos, err := countNewRepos(configs, registryConfig)
+		if err != nil {
+			return err
+		}
+		if newRepos > quotas.MaxNewReposPerRun {
+			return fmt.Errorf("quota exceeded: %d new repositories in this run, max_new_repos_per_run is %d",
+				newRepos, quotas.MaxNewReposPerRun)
+		}
+	}
+
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	perTeam := map[string]int{}
+	for _, s := range services {
+		if s.Config.Metadata.Team != "" {
+			perTeam[s.Config.Metadata.Team]++
+		}
+	}
+
+	checked := map[string]bool{}
+	for _, config := range configs {
+		team := config.Metadata.Team
+		if team == "" || checked[team] {
+			continue
+		}
+		checked[team] = true
+
+		if limit := quotas.teamLimit(team); limit > 0 && perTeam[team] > limit {
+			return fmt.Errorf("quota exceeded: team %s has %d services, limit is %d", team, perTeam[team], limit)
+		}
+	}
+	return nil
+}
+
+// countNewRepos: service chưa có trong state và repo chưa tồn tại trên GitHub (reconcile repo có sẵn không tính)
+func countNewRepos(configs []SourceConfig, registryConfig RegistryConfig) (int, error) {
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return 0, err
+	}
+	count := 0
+	for _, config := range configs {
+		if _, ok := state.Services[config.Name]; ok {
+			continue
+		}
+		if repoExists(commandExecutor, registryConfig.ownerFor(config), config.Name) {
+			continue
+		}
+		count++
+	}
+	return count, nil
+}
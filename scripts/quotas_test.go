@@ -0,0 +1,49 @@
+package main
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// writeSources tạo sources-service/<name>/source.yml cho từng service, trả về service path
+func writeSources(t *testing.T, names ...string) []string {
+	t.Helper()
+	var paths []string
+	for _, name := range names {
+		dir := filepath.Join(sourcesDir, name)
+		if err := os.MkdirAll(dir, 0755); err != nil {
+			t.Fatal(err)
+		}
+		source := "name: " + name + "\nowner: acme\nmetadata:\n  programming_language: golang\n"
+		if err := os.WriteFile(filepath.Join(dir, "source.yml"), []byte(source), 0644); err != nil {
+			t.Fatal(err)
+		}
+		paths = append(paths, dir)
+	}
+	return paths
+}
+
+func TestEnforceQuotasCountsOnlyNewRepos(t *testing.T) {
+	inTempDir(t)
+	fake := useFakeExecutor(t)
+	paths := writeSources(t, "orders", "billing", "search", "ledger")
+	state := &RegistryState{Services: map[string]*ServiceState{"orders": {Repo: "acme/orders"}}}
+	if err := state.save(registryStateFile); err != nil {
+		t.Fatal(err)
+	}
+	// billing: repo có sẵn nhưng chưa có trong state; search / ledger: repo mới
+	fake.On("gh api repos/acme/billing --jq .id", FakeResponse{Output: "1"})
+	fake.On("gh api repos/acme/search --jq .id", FakeResponse{Err: errors.New("HTTP 404")})
+	fake.On("gh api repos/acme/ledger --jq .id", FakeResponse{Err: errors.New("HTTP 404")})
+
+	if err := enforceQuotas(paths[:3], RegistryConfig{Quotas: Quotas{MaxNewReposPerRun: 1}}); err != nil {
+		t.Fatalf("existing repositories must not count towards the quota: %v", err)
+	}
+	err := enforceQuotas(paths, RegistryConfig{Quotas: Quotas{MaxNewReposPerRun: 1}})
+	if err == nil || !strings.Contains(err.Error(), "2 new repositories") {
+		t.Fatalf("err = %v, want 2 new repositories over the quota", err)
+	}
+}
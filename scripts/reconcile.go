@@ -0,0 +1,189 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Reconcile bật vòng reconcile định kỳ của server mode: đọc lại sources-service, so với state
+// và repo downstream rồi hội tụ (enqueue provisioning, áp lại repo settings), thay vì chỉ chạy khi có push.
+type Reconcile struct {
+	Interval     string `yaml:"interval"`      // vd. 15m, trống = tắt
+	GitPull      bool   `yaml:"git_pull"`      // git pull --ff-only checkout của registry trước mỗi vòng
+	Settings     bool   `yaml:"settings"`      // áp lại repo_settings khi repo bị đổi tay
+	Drift        bool   `yaml:"drift"`         // report file generated bị sửa tay (không ghi đè)
+	ManagedFiles bool   `yaml:"managed_files"` // mở PR sync managed_files khi repo lệch so với registry
+}
+
+// ReconcileReport là kết quả một vòng reconcile
+type ReconcileReport struct {
+	StartedAt    string   `json:"started_at"`
+	Services     int      `json:"services"`
+	Enqueued     []string `json:"enqueued"`
+	Settings     []string `json:"settings_fixed"`
+	Drifted      []string `json:"drifted"`
+	ManagedFiles []string `json:"managed_files"` // repo có PR sync managed_files đang mở
+	Errors       []string `json:"errors"`
+}
+
+// lastReconcile giữ report gần nhất cho /api/reconcile
+var (
+	lastReconcileMu sync.Mutex
+	lastReconcile   *ReconcileReport
+)
+
+// runReconcileLoop chạy reconcile ngay khi start rồi lặp theo interval
+func runReconcileLoop(queue *JobQueue, registryConfig RegistryConfig, interval time.Duration) {
+	for {
+		report := reconcileOnce(queue, registryConfig)
+		lastReconcileMu.Lock()
+		lastReconcile = &report
+		lastReconcileMu.Unlock()
+		time.Sleep(interval)
+	}
+}
+
+func reconcileOnce(queue *JobQueue, registryConfig RegistryConfig) ReconcileReport {
+	cfg := registryConfig.Server.Reconcile
+	report := ReconcileReport{StartedAt: time.Now().UTC().Format(time.RFC3339), Enqueued: []string{}, Settings: []string{}, Drifted: []string{}, ManagedFiles: []string{}, Errors: []string{}}
+	fail := func(format string, args ...interface{}) {
+		msg := redact(fmt.Sprintf(format, args...))
+		fmt.Printf("⚠️ Reconcile: %s\n", msg)
+		report.Errors = append(report.Errors, msg)
+	}
+
+	if cfg.GitPull {
+		if err := runCommand(commandExecutor, "git", "pull", "--ff-only"); err != nil {
+			fail("git pull failed: %v", err)
+		}
+	}
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		fail("%v", err)
+		return report
+	}
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		fail("%v", err)
+		return report
+	}
+	report.Services = len(services)
+
+	var managed []managedContent
+	if cfg.ManagedFiles {
+		if managed, err = loadManagedFiles(registryConfig.ManagedFiles); err != nil {
+			fail("%v", err)
+		}
+	}
+
+	for _, s := range services {
+		servicePath := filepath.Join(sourcesDir, s.Folder)
+		config := resolveSourceConfig(s.Config, registryConfig)
+		repo := fmt.Sprintf("%s/%s", registryConfig.ownerFor(config), config.Name)
+		entry := state.Services[config.Name]
+		if entry != nil {
+			// Archive / delete là quyết định có chủ đích, không tự tạo lại
+			if entry.ArchivedAt != "" || entry.LastStatus == "deleted" {
+				continue
+			}
+			if entry.Repo != "" {
+				repo = resolveRepo(*entry)
+			}
+		}
+
+		// Đang có job, hoặc job gần nhất fail hẳn: chờ người retry (/api/jobs/{id}/retry), không lặp lại mỗi vòng
+		if job, ok := queue.latest(servicePath); ok && (job.Status == jobFailed || job.Status == jobQueued || job.Status == jobRunning) {
+			continue
+		}
+
+		// Repo thiếu, source.yml hoặc templates đổi: manifest trên repo không khớp
+		manifest, err := buildManifest(servicePath, config, registryConfig)
+		if err != nil {
+			fail("%s: %v", s.Folder, err)
+			continue
+		}
+		owner, name, _ := strings.Cut(repo, "/")
+		if remote, ok := readRemoteManifest(commandExecutor, owner, name); !ok || !remote.upToDate(manifest) {
+			if _, err := queue.enqueue(servicePath, registryConfig.Server.maxAttempts(), ""); err != nil {
+				fail("%s: %v", s.Folder, err)
+				continue
+			}
+			report.Enqueued = append(report.Enqueued, s.Folder)
+			continue
+		}
+
+		if cfg.Settings {
+			stale, err := staleRepoSettings(repo, registryConfig.RepoSettings)
+			if err != nil {
+				fail("%s: %v", repo, err)
+			} else if len(stale) > 0 {
+				fmt.Printf("🛠️  Reconcile: %s settings drifted (%s), re-applying\n", repo, strings.Join(stale, ", "))
+				if err := applyRepoSettings(commandExecutor, owner, name, registryConfig.RepoSettings); err != nil {
+					fail("%s: %v", repo, err)
+				} else {
+					report.Settings = append(report.Settings, repo)
+				}
+			}
+		}
+
+		if cfg.Drift {
+			if modifications, err := detectModifications(owner, name); err == nil && len(modifications.Modified)+len(modifications.Deleted) > 0 {
+				report.Drifted = append(report.Drifted, repo)
+			}
+		}
+
+		if len(managed) > 0 && !registryConfig.ManagedFiles.excluded(config.Name) {
+			result, err := syncManagedFiles(repo, registryConfig.ManagedFiles, managed, false)
+			if err != nil {
+				fail("%s: %v", repo, err)
+			} else if result.PR != "" {
+				report.ManagedFiles = append(report.ManagedFiles, repo)
+			}
+		}
+	}
+
+	fmt.Printf("🔁 Reconcile: %d service(s), %d enqueued, %d settings fixed, %d drifted, %d managed files PR(s), %d error(s)\n",
+		report.Services, len(report.Enqueued), len(report.Settings), len(report.Drifted), len(report.ManagedFiles), len(report.Errors))
+	return report
+}
+
+// staleRepoSettings so repo_settings (các field PATCH được) với giá trị hiện tại của repo
+func staleRepoSettings(repo string, settings RepoSettings) ([]string, error) {
+	out, err := runCommandOutput(commandExecutor, "gh", "api", "repos/"+repo)
+	if err != nil {
+		return nil, withCode(ErrGitHubAPI, fmt.Errorf("failed to read %s: %w", repo, err))
+	}
+	current := map[string]interface{}{}
+	if err := json.Unmarshal([]byte(out), &current); err != nil {
+		return nil, err
+	}
+	var stale []string
+	for _, f := range settings.fields() {
+		if f.value == nil {
+			continue
+		}
+		if value, ok := current[f.key].(bool); ok && value != *f.value {
+			stale = append(stale, f.key)
+		}
+	}
+	return stale, nil
+}
+
+// GET /api/reconcile: report của vòng reconcile gần nhất
+func handleReconcileReport() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		lastReconcileMu.Lock()
+		report := lastReconcile
+		lastReconcileMu.Unlock()
+		if report == nil {
+			http.Error(w, "no reconcile run yet (server.reconcile.interval not set?)", http.StatusNotFound)
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, report)
+	}
+}
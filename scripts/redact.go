@@ -0,0 +1,118 @@
+package main
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"regexp"
+	"sort"
+	"strings"
+	"sync"
+)
+
+// secretPatterns bắt các credential có format nhận dạng được kể cả khi không biết trước giá trị
+var secretPatterns = []*regexp.Regexp{
+	regexp.MustCompile(`(https?://)[^/\s:@]+(:[^/\s@]+)?@`), // user:token@ trong URL
+	regexp.MustCompile(`gh[pousr]_[A-Za-z0-9]{20,}`),
+	regexp.MustCompile(`github_pat_[A-Za-z0-9_]{20,}`),
+	regexp.MustCompile(`(?i)(authorization:\s*(bearer|token)\s+)\S+`),
+}
+
+// secretEnvSuffixes: env có tên như vậy được coi là secret và giá trị bị che khỏi output
+var secretEnvSuffixes = []string{"TOKEN", "SECRET", "PASSWORD", "PAT", "API_KEY"}
+
+var secrets = struct {
+	mu     sync.RWMutex
+	values []string
+}{}
+
+// registerSecret thêm giá trị cần che (token lấy được lúc runtime, vd. OIDC exchange)
+func registerSecret(value string) {
+	if len(value) < 8 {
+		return
+	}
+	secrets.mu.Lock()
+	defer secrets.mu.Unlock()
+	for _, v := range secrets.values {
+		if v == value {
+			return
+		}
+	}
+	secrets.values = append(secrets.values, value)
+	// Giá trị dài trước để không che dở một secret chứa secret khác
+	sort.Slice(secrets.values, func(i, j int) bool { return len(secrets.values[i]) > len(secrets.values[j]) })
+}
+
+// registerEnvSecrets đăng ký giá trị của mọi env trông giống credential
+func registerEnvSecrets() {
+	for _, kv := range os.Environ() {
+		name, value, _ := strings.Cut(kv, "=")
+		for _, suffix := range secretEnvSuffixes {
+			if strings.HasSuffix(strings.ToUpper(name), suffix) {
+				registerSecret(value)
+				break
+			}
+		}
+	}
+}
+
+// redact che mọi secret đã biết và mọi chuỗi khớp secretPatterns
+func redact(s string) string {
+	secrets.mu.RLock()
+	for _, v := range secrets.values {
+		s = strings.ReplaceAll(s, v, "***")
+	}
+	secrets.mu.RUnlock()
+
+	s = secretPatterns[0].ReplaceAllString(s, "${1}***@")
+	s = secretPatterns[1].ReplaceAllString(s, "***")
+	s = secretPatterns[2].ReplaceAllString(s, "***")
+	s = secretPatterns[3].ReplaceAllString(s, "${1}***")
+	return s
+}
+
+// redactedOutput thay os.Stdout/os.Stderr bằng pipe lọc từng dòng qua redact,
+// nên cả log của registry lẫn output của subprocess (git, gh, npm) đều được che.
+var redactedOutput struct {
+	writers []*os.File
+	wg      sync.WaitGroup
+}
+
+func installRedactingOutput() error {
+	registerEnvSecrets()
+
+	for _, target := range []**os.File{&os.Stdout, &os.Stderr} {
+		r, w, err := os.Pipe()
+		if err != nil {
+			return err
+		}
+		original := *target
+		*target = w
+		redactedOutput.writers = append(redactedOutput.writers, w)
+
+		redactedOutput.wg.Add(1)
+		go func() {
+			defer redactedOutput.wg.Done()
+			reader := bufio.NewReader(r)
+			for {
+				line, err := reader.ReadString('\n')
+				if line != "" {
+					io.WriteString(original, redact(line))
+				}
+				if err != nil {
+					return
+				}
+			}
+		}()
+	}
+	return nil
+}
+
+// flushOutput đóng pipe và đợi toàn bộ output được ghi ra trước khi process exit
+func flushOutput() {
+	for _, w := range redactedOutput.writers {
+		w.Close()
+	}
+	redactedOutput.wg.Wait()
+	redactedOutput.writers = nil
+}
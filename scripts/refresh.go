@@ -0,0 +1,166 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// refresh: <n>d / <n>w trong source.yml là tuổi tối đa của scaffolding. Service có manifest trên repo cũ hơn
+// TTL và template version khác hiện tại bị flag bởi `refresh`, `--open-prs` regenerate thành upgrade PR
+// (git.update_push: pull_request) thay vì đẩy thẳng lên default branch.
+
+// parseRefreshTTL nhận 90d, 12w hoặc duration của Go (720h)
+func parseRefreshTTL(value string) (time.Duration, error) {
+	for suffix, unit := range map[string]time.Duration{"d": 24 * time.Hour, "w": 7 * 24 * time.Hour} {
+		if n, ok := strings.CutSuffix(value, suffix); ok {
+			days, err := strconv.Atoi(n)
+			if err != nil || days <= 0 {
+				return 0, fmt.Errorf("refresh %q must be a positive number of days or weeks, e.g. 90d", value)
+			}
+			return time.Duration(days) * unit, nil
+		}
+	}
+	d, err := time.ParseDuration(value)
+	if err != nil || d <= 0 {
+		return 0, fmt.Errorf("refresh %q must be a duration like 90d, 12w or 720h", value)
+	}
+	return d, nil
+}
+
+// RefreshStatus là tuổi scaffolding của một service có refresh
+type RefreshStatus struct {
+	Service         string `json:"service"`
+	Repo            string `json:"repo"`
+	TTL             string `json:"ttl"`
+	GeneratedAt     string `json:"generated_at,omitempty"`
+	TemplateVersion string `json:"template_version,omitempty"`
+	Expired         bool   `json:"expired"`
+	Reason          string `json:"reason,omitempty"`
+
+	folder string
+}
+
+// refreshStatuses kiểm tra các service có refresh (bỏ qua service archived / decommissioned)
+func refreshStatuses(services []RegisteredService, registryConfig RegistryConfig, now time.Time) ([]RefreshStatus, error) {
+	current, err := templateVersion(registryConfig.FrameworkTemplates)
+	if err != nil {
+		return nil, err
+	}
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var statuses []RefreshStatus
+	for _, s := range services {
+		if s.Config.Refresh == "" {
+			continue
+		}
+		ttl, err := parseRefreshTTL(s.Config.Refresh)
+		if err != nil {
+			return nil, fmt.Errorf("%s: %w", s.Folder, err)
+		}
+		config := resolveSourceConfig(s.Config, registryConfig)
+		repo := fmt.Sprintf("%s/%s", registryConfig.ownerFor(config), config.Name)
+		if entry := state.Services[config.Name]; entry != nil {
+			if entry.ArchivedAt != "" || entry.LastStatus == "deleted" || entry.LastStatus == "decommissioned" {
+				continue
+			}
+			if entry.Repo != "" {
+				repo = resolveRepo(*entry)
+			}
+		}
+
+		status := RefreshStatus{Service: config.Name, Repo: repo, TTL: s.Config.Refresh, folder: s.Folder}
+		owner, name, _ := strings.Cut(repo, "/")
+		remote, ok := readRemoteManifest(commandExecutor, owner, name)
+		if !ok {
+			status.Reason = "no manifest on the repository"
+			statuses = append(statuses, status)
+			continue
+		}
+		status.GeneratedAt, status.TemplateVersion = remote.GeneratedAt, remote.TemplateVersion
+		generatedAt, err := time.Parse(time.RFC3339, remote.GeneratedAt)
+		switch {
+		case remote.TemplateVersion == current:
+			// Scaffolding cũ nhưng template không đổi: không có gì để refresh
+		case err != nil:
+			status.Expired, status.Reason = true, "manifest has no generated_at"
+		case now.Sub(generatedAt) > ttl:
+			status.Expired = true
+			status.Reason = fmt.Sprintf("generated %d days ago with templates %s (current %s)", int(now.Sub(generatedAt).Hours()/24), remote.TemplateVersion, current)
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses, nil
+}
+
+// runRefreshCommand: `refresh [--open-prs] [--json]` flag service quá refresh TTL, --open-prs mở upgrade PR
+func runRefreshCommand(args []string) error {
+	fs := flag.NewFlagSet("refresh", flag.ExitOnError)
+	openPRs := fs.Bool("open-prs", false, "regenerate expired services as upgrade pull requests")
+	asJSON := fs.Bool("json", false, "print statuses as JSON")
+	fs.Parse(args)
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+	statuses, err := refreshStatuses(services, registryConfig, time.Now().UTC())
+	if err != nil {
+		return withCode(ErrValidationFailed, err)
+	}
+
+	var expired []RefreshStatus
+	for _, s := range statuses {
+		if s.Expired {
+			expired = append(expired, s)
+		}
+	}
+	if *asJSON {
+		out, err := json.MarshalIndent(statuses, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(out))
+	} else {
+		for _, s := range statuses {
+			switch {
+			case s.Expired:
+				fmt.Printf("⏰ %s (refresh %s): %s\n", s.Service, s.TTL, s.Reason)
+			case s.Reason != "":
+				fmt.Printf("⚠️ %s: %s\n", s.Service, s.Reason)
+			default:
+				fmt.Printf("✅ %s (refresh %s) is fresh\n", s.Service, s.TTL)
+			}
+		}
+		fmt.Printf("📋 %d/%d service(s) past their refresh TTL\n", len(expired), len(statuses))
+	}
+	if len(expired) == 0 || !*openPRs {
+		return nil
+	}
+
+	// Upgrade PR: regenerate lên branch riêng, không force push default branch
+	registryConfig.Git.UpdatePush = "pull_request"
+	var failed []string
+	for _, s := range expired {
+		if err := provisionService(filepath.Join(sourcesDir, s.folder), registryConfig, len(expired), nil); err != nil {
+			fmt.Printf("❌ [%s] %s: %v\n", errorCode(err), s.Service, err)
+			printLogExcerpt(err)
+			failed = append(failed, s.Service)
+		}
+	}
+	if len(failed) > 0 {
+		return withCode(ErrGeneratorFailed, fmt.Errorf("%d/%d upgrade PR(s) failed: %s", len(failed), len(expired), strings.Join(failed, ", ")))
+	}
+	return nil
+}
@@ -0,0 +1,47 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"sort"
+)
+
+// sourcesDir là thư mục chứa tất cả service definitions (sources-service/<name>/source.yml)
+const sourcesDir = "sources-service"
+
+// RegisteredService là một service đã được parse từ registry
+type RegisteredService struct {
+	Folder string
+	Config SourceConfig
+}
+
+// loadRegisteredServices đọc toàn bộ source.yml trong dir, sort theo tên folder
+func loadRegisteredServices(dir string) ([]RegisteredService, error) {
+	entries, err := os.ReadDir(dir)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read %s: %w", dir, err)
+	}
+
+	var services []RegisteredService
+	for _, entry := range entries {
+		if !entry.IsDir() {
+			continue
+		}
+		servicePath := filepath.Join(dir, entry.Name())
+		if _, err := os.Stat(filepath.Join(servicePath, "source.yml")); err != nil {
+			continue
+		}
+
+		config, err := loadSourceConfig(servicePath)
+		if err != nil {
+			return nil, err
+		}
+		services = append(services, RegisteredService{Folder: entry.Name(), Config: config})
+	}
+
+	sort.Slice(services, func(i, j int) bool {
+		return services[i].Folder < services[j].Folder
+	})
+	return services, nil
+}
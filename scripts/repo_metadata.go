@@ -0,0 +1,59 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strconv"
+)
+
+// RepoMetadata là dữ liệu canonical đọc lại từ GitHub sau khi provisioning.
+// ID không đổi khi repo bị rename / transfer, các thao tác sau dùng ID thay vì ghép owner/name.
+type RepoMetadata struct {
+	ID            int64  `json:"id"`
+	NodeID        string `json:"node_id"`
+	FullName      string `json:"full_name"`
+	DefaultBranch string `json:"default_branch"`
+	HTMLURL       string `json:"html_url"`
+	CloneURL      string `json:"clone_url"`
+	SSHURL        string `json:"ssh_url"`
+	CreatedAt     string `json:"created_at"`
+}
+
+// fetchRepoMetadata đọc metadata canonical của repo (repos/<owner>/<name>)
+func fetchRepoMetadata(ex Executor, fullName string) (*RepoMetadata, error) {
+	out, err := runCommandOutput(ex, "gh", "api", "repos/"+fullName)
+	if err != nil {
+		return nil, withCode(ErrGitHubAPI, fmt.Errorf("failed to read %s: %w", fullName, err))
+	}
+	var meta RepoMetadata
+	if err := json.Unmarshal([]byte(out), &meta); err != nil {
+		return nil, fmt.Errorf("failed to parse %s metadata: %w", fullName, err)
+	}
+	return &meta, nil
+}
+
+// enrichRepoMetadata: lỗi chỉ cảnh báo, giữ metadata cũ (previous) để state không mất ID
+func enrichRepoMetadata(ex Executor, fullName string, previous *RepoMetadata) *RepoMetadata {
+	meta, err := fetchRepoMetadata(ex, fullName)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to read repository metadata: %v\n", err)
+		return previous
+	}
+	return meta
+}
+
+// resolveRepo trả về owner/name hiện tại của repo trong state: tra theo ID nếu có
+// (repo đã bị rename / transfer ngoài registry vẫn đúng), ngược lại dùng Repo đã lưu.
+func resolveRepo(entry ServiceState) string {
+	if entry.Repository == nil || entry.Repository.ID == 0 {
+		return entry.Repo
+	}
+	out, err := runCommandOutput(commandExecutor, "gh", "api", "repositories/"+strconv.FormatInt(entry.Repository.ID, 10), "--jq", ".full_name")
+	if err != nil || out == "" {
+		return entry.Repo
+	}
+	if out != entry.Repo {
+		fmt.Printf("  ℹ️ %s is now %s (resolved by repository ID)\n", entry.Repo, out)
+	}
+	return out
+}
@@ -0,0 +1,84 @@
+package main
+
+import (
+	"fmt"
+)
+
+// RepoSettings is the settings profile applied to every newly created repo.
+// Field nào để trống (nil) thì giữ nguyên giá trị mặc định của GitHub.
+type RepoSettings struct {
+	HasWiki             *bool `yaml:"has_wiki"`
+	HasProjects         *bool `yaml:"has_projects"`
+	AllowSquashMerge    *bool `yaml:"allow_squash_merge"`
+	AllowMergeCommit    *bool `yaml:"allow_merge_commit"`
+	AllowRebaseMerge    *bool `yaml:"allow_rebase_merge"`
+	DeleteBranchOnMerge *bool `yaml:"delete_branch_on_merge"`
+	VulnerabilityAlerts *bool `yaml:"vulnerability_alerts"`
+	SecretScanning      *bool `yaml:"secret_scanning"`
+}
+
+func applyRepoSettings(ex Executor, owner, repoName string, settings RepoSettings) error {
+	repoPath := fmt.Sprintf("repos/%s/%s", owner, repoName)
+
+	// Step 1: PATCH các setting cơ bản của repo
+	args := []string{"api", "-X", "PATCH", repoPath}
+	for _, f := range settings.fields() {
+		if f.value != nil {
+			args = append(args, "-F", fmt.Sprintf("%s=%t", f.key, *f.value))
+		}
+	}
+	if settings.SecretScanning != nil {
+		args = append(args, "-f", fmt.Sprintf("security_and_analysis[secret_scanning][status]=%s", enabledStatus(*settings.SecretScanning)))
+	}
+	if len(args) > 4 {
+		if err := runCommand(ex, "gh", args...); err != nil {
+			return fmt.Errorf("failed to update repo settings: %w", err)
+		}
+	}
+
+	// Step 2: Vulnerability alerts dùng endpoint riêng (PUT để bật, DELETE để tắt)
+	if settings.VulnerabilityAlerts != nil {
+		method := "DELETE"
+		if *settings.VulnerabilityAlerts {
+			method = "PUT"
+		}
+		if err := runCommand(ex, "gh", "api", "-X", method, repoPath+"/vulnerability-alerts"); err != nil {
+			return fmt.Errorf("failed to update vulnerability alerts: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// markTemplateRepo bật is_template để team khác tạo repo mới từ scaffold này (publish_as_template)
+func markTemplateRepo(ex Executor, owner, repoName string) error {
+	if err := runCommand(ex, "gh", "api", "-X", "PATCH", fmt.Sprintf("repos/%s/%s", owner, repoName), "-F", "is_template=true"); err != nil {
+		return fmt.Errorf("failed to mark repo as template: %w", err)
+	}
+	return nil
+}
+
+// repoSettingField là một field boolean của GitHub repo API
+type repoSettingField struct {
+	key   string
+	value *bool
+}
+
+// fields trả về các setting PATCH được trực tiếp qua repos/{owner}/{repo}
+func (s RepoSettings) fields() []repoSettingField {
+	return []repoSettingField{
+		{"has_wiki", s.HasWiki},
+		{"has_projects", s.HasProjects},
+		{"allow_squash_merge", s.AllowSquashMerge},
+		{"allow_merge_commit", s.AllowMergeCommit},
+		{"allow_rebase_merge", s.AllowRebaseMerge},
+		{"delete_branch_on_merge", s.DeleteBranchOnMerge},
+	}
+}
+
+func enabledStatus(enabled bool) string {
+	if enabled {
+		return "enabled"
+	}
+	return "disabled"
+}
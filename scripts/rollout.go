@@ -0,0 +1,221 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Rollout cấu hình mặc định cho `rollout` (flag override từng giá trị)
+type Rollout struct {
+	CanaryPercent int    `yaml:"canary_percent"` // wave đầu tiên, mặc định 5% (ít nhất 1 service)
+	WavePercent   int    `yaml:"wave_percent"`   // các wave sau, mặc định 25%
+	Pause         string `yaml:"pause"`          // nghỉ giữa các wave, mặc định 10m
+	// MaxFailurePercent: % service fail trong một wave vượt ngưỡng này thì dừng, 0 = dừng ngay khi có lỗi
+	MaxFailurePercent int `yaml:"max_failure_percent"`
+}
+
+func (r Rollout) withDefaults() Rollout {
+	if r.CanaryPercent <= 0 {
+		r.CanaryPercent = 5
+	}
+	if r.WavePercent <= 0 {
+		r.WavePercent = 25
+	}
+	if r.Pause == "" {
+		r.Pause = "10m"
+	}
+	return r
+}
+
+// rolloutWaves chia services thành canary + các wave theo % tổng số service
+func rolloutWaves(services []RegisteredService, canaryPercent, wavePercent int) [][]RegisteredService {
+	waveSize := func(percent int) int {
+		size := (len(services)*percent + 99) / 100
+		if size < 1 {
+			size = 1
+		}
+		return size
+	}
+
+	var waves [][]RegisteredService
+	size := waveSize(canaryPercent)
+	for start := 0; start < len(services); {
+		end := start + size
+		if end > len(services) {
+			end = len(services)
+		}
+		waves = append(waves, services[start:end])
+		start = end
+		size = waveSize(wavePercent)
+	}
+	return waves
+}
+
+// rolloutTier: tier trống coi như tier 3, service ít quan trọng nhất được upgrade trước
+func rolloutTier(s RegisteredService) int {
+	if s.Config.Metadata.Tier == 0 {
+		return 3
+	}
+	return s.Config.Metadata.Tier
+}
+
+// selectRolloutServices: service đã provisioning, chưa archive, template_version khác target
+func selectRolloutServices(targetVersion, team, language string) ([]RegisteredService, error) {
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return nil, err
+	}
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return nil, err
+	}
+
+	var selected []RegisteredService
+	for _, s := range services {
+		if team != "" && s.Config.Metadata.Team != team {
+			continue
+		}
+		if language != "" && s.Config.Metadata.ProgrammingLanguage != language {
+			continue
+		}
+		entry, ok := state.Services[s.Config.Name]
+		if !ok || entry.Origin != "provisioned" || entry.ArchivedAt != "" || entry.TemplateVersion == targetVersion {
+			continue
+		}
+		selected = append(selected, s)
+	}
+	sort.SliceStable(selected, func(i, j int) bool {
+		if rolloutTier(selected[i]) != rolloutTier(selected[j]) {
+			return rolloutTier(selected[i]) > rolloutTier(selected[j])
+		}
+		return selected[i].Config.Name < selected[j].Config.Name
+	})
+	return selected, nil
+}
+
+// runRolloutCommand: `rollout --template-version X [--team t] [--language l] [--canary 5] [--wave 25]
+// [--pause 10m] [--max-failure-percent 0] [--dry-run] [--confirm X]`
+func runRolloutCommand(args []string) error {
+	fs := flag.NewFlagSet("rollout", flag.ExitOnError)
+	targetVersion := fs.String("template-version", "", "template version to roll out (must match the templates checked out)")
+	team := fs.String("team", "", "only services of this metadata.team")
+	language := fs.String("language", "", "only services of this programming language")
+	canary := fs.Int("canary", 0, "percent of services in the first wave (overrides rollout.canary_percent)")
+	wave := fs.Int("wave", 0, "percent of services in each following wave (overrides rollout.wave_percent)")
+	pause := fs.String("pause", "", "pause between waves, e.g. 10m (overrides rollout.pause)")
+	maxFailures := fs.Int("max-failure-percent", -1, "abort when more than this percent of a wave fails (overrides rollout.max_failure_percent)")
+	dryRun := fs.Bool("dry-run", false, "print the waves without provisioning")
+	confirm := fs.String("confirm", "", "repeat the template version to confirm force pushes to every service in the rollout")
+	fs.Parse(args)
+	if *targetVersion == "" {
+		return withCode(ErrUsage, fmt.Errorf("usage: go run ./scripts rollout --template-version <version> [--team t] [--language l] [--canary 5] [--wave 25] [--pause 10m] [--max-failure-percent 0] [--dry-run] [--confirm <version>]"))
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return fmt.Errorf("error loading registry config: %w", err)
+	}
+
+	// Chỉ roll out đúng version đang checkout, tránh đẩy nhầm templates chưa review
+	current, err := templateVersion(registryConfig.FrameworkTemplates)
+	if err != nil {
+		return err
+	}
+	if current != *targetVersion {
+		return withCode(ErrUsage, fmt.Errorf("templates checked out are version %s, not %s", current, *targetVersion))
+	}
+
+	rollout := registryConfig.Rollout.withDefaults()
+	if *canary > 0 {
+		rollout.CanaryPercent = *canary
+	}
+	if *wave > 0 {
+		rollout.WavePercent = *wave
+	}
+	if *pause != "" {
+		rollout.Pause = *pause
+	}
+	if *maxFailures >= 0 {
+		rollout.MaxFailurePercent = *maxFailures
+	}
+	pauseDuration, err := time.ParseDuration(rollout.Pause)
+	if err != nil {
+		return withCode(ErrUsage, fmt.Errorf("invalid pause %q: %w", rollout.Pause, err))
+	}
+
+	services, err := selectRolloutServices(*targetVersion, *team, *language)
+	if err != nil {
+		return err
+	}
+	if len(services) == 0 {
+		fmt.Printf("ℹ️ No services need template version %s\n", *targetVersion)
+		return nil
+	}
+	waves := rolloutWaves(services, rollout.CanaryPercent, rollout.WavePercent)
+
+	fmt.Printf("🌊 Rolling out templates %s to %d service(s) in %d wave(s)\n", *targetVersion, len(services), len(waves))
+	for i, w := range waves {
+		names := make([]string, len(w))
+		for j, s := range w {
+			names[j] = s.Config.Name
+		}
+		fmt.Printf("  Wave %d: %s\n", i+1, strings.Join(names, ", "))
+	}
+	if *dryRun {
+		return nil
+	}
+
+	// Regenerate repo có sẵn sẽ force push: gõ lại version thay cho --confirm từng service
+	var confirmed []string
+	if registryConfig.Git.forcePushes() && registryConfig.Destructive.requiresConfirmation(actionForcePush) {
+		if *confirm != *targetVersion {
+			return withCode(ErrConfirmationRequired, fmt.Errorf("rollout force pushes to %d repositories, re-run with --confirm %s", len(services), *targetVersion))
+		}
+	}
+	if *confirm == *targetVersion {
+		for _, s := range services {
+			confirmed = append(confirmed, s.Config.Name)
+		}
+	}
+
+	throttled, err := newThrottledExecutor(commandExecutor, registryConfig.Throttle)
+	if err != nil {
+		return withCode(ErrUsage, err)
+	}
+	commandExecutor = throttled
+
+	upgraded := 0
+	for i, w := range waves {
+		if i > 0 && pauseDuration > 0 {
+			fmt.Printf("⏸️  Pausing %s before wave %d/%d\n", pauseDuration, i+1, len(waves))
+			time.Sleep(pauseDuration)
+		}
+		fmt.Printf("\n🌊 Wave %d/%d (%d service(s))\n", i+1, len(waves), len(w))
+
+		paths := make([]string, len(w))
+		for j, s := range w {
+			paths[j] = filepath.Join(sourcesDir, s.Folder)
+		}
+		failed, _, firstErr := provisionPool(paths, registryConfig, registryConfig.Throttle.generations(), len(services), confirmed, "continue")
+		upgraded += len(w) - len(failed)
+
+		if len(failed)*100 > rollout.MaxFailurePercent*len(w) {
+			remaining := 0
+			for _, rest := range waves[i+1:] {
+				remaining += len(rest)
+			}
+			return withCode(errorCode(firstErr), fmt.Errorf("rollout aborted at wave %d/%d: %d/%d failed (%s), %d upgraded, %d not started",
+				i+1, len(waves), len(failed), len(w), strings.Join(failed, ", "), upgraded, remaining))
+		}
+		if len(failed) > 0 {
+			fmt.Printf("⚠️ Wave %d: %d/%d failed (%s), within threshold of %d%%\n", i+1, len(failed), len(w), strings.Join(failed, ", "), rollout.MaxFailurePercent)
+		}
+	}
+
+	fmt.Printf("✅ Rollout of %s complete: %d/%d service(s) upgraded\n", *targetVersion, upgraded, len(services))
+	return nil
+}
@@ -0,0 +1,198 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+)
+
+// Ruleset is a policy-defined GitHub ruleset applied to each provisioned repo.
+// Scope "org" dùng một ruleset ở cấp organization, target mọi repo đã provisioning.
+type Ruleset struct {
+	Name               string        `yaml:"name"`
+	Scope              string        `yaml:"scope"`       // repo (default) | org
+	Target             string        `yaml:"target"`      // branch (default) | tag
+	Enforcement        string        `yaml:"enforcement"` // active (default) | evaluate | disabled
+	Include            []string      `yaml:"include"`
+	Exclude            []string      `yaml:"exclude"`
+	RequiredChecks     []string      `yaml:"required_checks"`
+	RequiredSignatures bool          `yaml:"required_signatures"`
+	RestrictPushes     bool          `yaml:"restrict_pushes"`
+	BlockForcePushes   bool          `yaml:"block_force_pushes"`
+	BlockDeletions     bool          `yaml:"block_deletions"`
+	BypassActors       []BypassActor `yaml:"bypass_actors"`
+}
+
+type BypassActor struct {
+	ActorID    int    `yaml:"actor_id" json:"actor_id"`
+	ActorType  string `yaml:"actor_type" json:"actor_type"`
+	BypassMode string `yaml:"bypass_mode" json:"bypass_mode"`
+}
+
+type rulesetRule struct {
+	Type       string      `json:"type"`
+	Parameters interface{} `json:"parameters,omitempty"`
+}
+
+type requiredStatusCheck struct {
+	Context string `json:"context"`
+}
+
+// applyRulesets tạo hoặc cập nhật ruleset theo tên, chạy lại (regenerate / rollout / reconcile) không tạo bản trùng.
+// Ruleset org dùng chung một bản cho mọi repo: repo mới được thêm vào conditions.repository_name.include.
+func applyRulesets(ex Executor, owner, repoName string, rulesets []Ruleset) error {
+	for _, rs := range rulesets {
+		endpoint := fmt.Sprintf("repos/%s/%s/rulesets", owner, repoName)
+		if rs.Scope == "org" {
+			endpoint = fmt.Sprintf("orgs/%s/rulesets", owner)
+		}
+
+		list := endpoint + "?includes_parents=false" // ruleset org kế thừa không PUT được qua endpoint của repo
+		if rs.Scope == "org" {
+			list = endpoint
+		}
+		id, err := findRuleset(ex, list, rs.Name)
+		if err != nil {
+			return fmt.Errorf("failed to list rulesets for '%s': %w", rs.Name, err)
+		}
+
+		repos := []string{repoName}
+		if rs.Scope == "org" && id != "" {
+			if repos, err = rulesetRepositories(ex, endpoint+"/"+id, repoName); err != nil {
+				return fmt.Errorf("failed to read ruleset '%s': %w", rs.Name, err)
+			}
+		}
+		body, err := buildRulesetPayload(repos, rs)
+		if err != nil {
+			return fmt.Errorf("ruleset '%s': %w", rs.Name, err)
+		}
+
+		if id == "" {
+			fmt.Printf("  📜 Ruleset: %s\n", rs.Name)
+			if err := runCommandWithInput(ex, body, "gh", "api", "-X", "POST", endpoint, "--input", "-"); err != nil {
+				return fmt.Errorf("failed to create ruleset '%s': %w", rs.Name, err)
+			}
+			continue
+		}
+		fmt.Printf("  📜 Ruleset: %s (updating #%s)\n", rs.Name, id)
+		if err := runCommandWithInput(ex, body, "gh", "api", "-X", "PUT", endpoint+"/"+id, "--input", "-"); err != nil {
+			return fmt.Errorf("failed to update ruleset '%s': %w", rs.Name, err)
+		}
+	}
+	return nil
+}
+
+// findRuleset trả id của ruleset trùng tên, "" nếu chưa có
+func findRuleset(ex Executor, endpoint, name string) (string, error) {
+	out, err := runCommandOutput(ex, "gh", "api", "--paginate", endpoint, "--jq", ".[] | \"\\(.id)\\t\\(.name)\"")
+	if err != nil {
+		return "", withCode(ErrGitHubAPI, err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		id, rulesetName, ok := strings.Cut(line, "\t")
+		if ok && rulesetName == name {
+			return id, nil
+		}
+	}
+	return "", nil
+}
+
+// rulesetRepositories: repo đã được ruleset org target, thêm repoName nếu chưa có
+func rulesetRepositories(ex Executor, endpoint, repoName string) ([]string, error) {
+	out, err := runCommandOutput(ex, "gh", "api", endpoint, "--jq", ".conditions.repository_name.include // [] | .[]")
+	if err != nil {
+		return nil, withCode(ErrGitHubAPI, err)
+	}
+	var repos []string
+	for _, name := range strings.Split(out, "\n") {
+		if name = strings.TrimSpace(name); name == "" {
+			continue
+		}
+		if name == repoName {
+			repoName = ""
+		}
+		repos = append(repos, name)
+	}
+	if repoName != "" {
+		repos = append(repos, repoName)
+	}
+	return repos, nil
+}
+
+func buildRulesetPayload(repos []string, rs Ruleset) ([]byte, error) {
+	if rs.Name == "" {
+		return nil, fmt.Errorf("name is required")
+	}
+
+	target := rs.Target
+	if target == "" {
+		target = "branch"
+	}
+	enforcement := rs.Enforcement
+	if enforcement == "" {
+		enforcement = "active"
+	}
+	include := rs.Include
+	if len(include) == 0 {
+		include = []string{"~DEFAULT_BRANCH"}
+	}
+	exclude := rs.Exclude
+	if exclude == nil {
+		exclude = []string{}
+	}
+
+	// Build danh sách rule theo policy
+	var rules []rulesetRule
+	if len(rs.RequiredChecks) > 0 {
+		checks := make([]requiredStatusCheck, 0, len(rs.RequiredChecks))
+		for _, c := range rs.RequiredChecks {
+			checks = append(checks, requiredStatusCheck{Context: c})
+		}
+		rules = append(rules, rulesetRule{
+			Type: "required_status_checks",
+			Parameters: map[string]interface{}{
+				"strict_required_status_checks_policy": false,
+				"required_status_checks":               checks,
+			},
+		})
+	}
+	if rs.RequiredSignatures {
+		rules = append(rules, rulesetRule{Type: "required_signatures"})
+	}
+	if rs.RestrictPushes {
+		rules = append(rules, rulesetRule{Type: "update"})
+	}
+	if rs.BlockForcePushes {
+		rules = append(rules, rulesetRule{Type: "non_fast_forward"})
+	}
+	if rs.BlockDeletions {
+		rules = append(rules, rulesetRule{Type: "deletion"})
+	}
+
+	conditions := map[string]interface{}{
+		"ref_name": map[string][]string{
+			"include": include,
+			"exclude": exclude,
+		},
+	}
+	if rs.Scope == "org" {
+		conditions["repository_name"] = map[string][]string{
+			"include": repos,
+			"exclude": {},
+		}
+	}
+
+	bypassActors := rs.BypassActors
+	if bypassActors == nil {
+		bypassActors = []BypassActor{}
+	}
+
+	return json.Marshal(map[string]interface{}{
+		"name":          rs.Name,
+		"target":        target,
+		"enforcement":   enforcement,
+		"conditions":    conditions,
+		"rules":         rules,
+		"bypass_actors": bypassActors,
+	})
+}
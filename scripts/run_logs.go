@@ -0,0 +1,260 @@
+package main
+
+import (
+	"bufio"
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"sync"
+	"time"
+)
+
+// RunLogs lưu stdout/stderr của từng external command theo service + step vào run directory
+// (<dir>/<run-id>/<service>/<NNN>-<command>.log) và prefix output đang stream bằng [service],
+// để output song song của batch đọc được và lỗi kèm đúng đoạn log của subprocess.
+type RunLogs struct {
+	Disabled     bool   `yaml:"disabled"`
+	Dir          string `yaml:"dir"`           // mặc định .jupiter-runs
+	ExcerptLines int    `yaml:"excerpt_lines"` // số dòng cuối in kèm lỗi, mặc định 20
+}
+
+func (r RunLogs) dir() string {
+	if r.Dir == "" {
+		return ".jupiter-runs"
+	}
+	return r.Dir
+}
+
+func (r RunLogs) excerptLines() int {
+	if r.ExcerptLines <= 0 {
+		return 20
+	}
+	return r.ExcerptLines
+}
+
+// runLogger là run directory của process hiện tại, nil khi run_logs.disabled
+type runLogger struct {
+	dir     string
+	excerpt int
+}
+
+// serviceLog là log của một service, đi theo Command.Log tới executor (kể cả khi command
+// được chạy từ goroutine khác, vd. throttle / fault injection)
+type serviceLog struct {
+	service string
+	dir     string
+
+	mu    sync.Mutex
+	steps int
+}
+
+var runLogs *runLogger
+
+// currentRunID định danh process hiện tại (run directory, key trong artifact store)
+var currentRunID = fmt.Sprintf("%s-%d", time.Now().UTC().Format("20060102T150405Z"), os.Getpid())
+
+func setupRunLogs(cfg RunLogs) {
+	if cfg.Disabled {
+		return
+	}
+	runLogs = &runLogger{dir: filepath.Join(cfg.dir(), currentRunID), excerpt: cfg.excerptLines()}
+}
+
+// serviceExecutor gắn log của service vào mọi command chạy qua nó. provisionService tạo một
+// serviceExecutor cho mỗi service và truyền xuống pipeline thay cho commandExecutor.
+type serviceExecutor struct {
+	inner Executor
+	log   *serviceLog
+}
+
+func (s serviceExecutor) Run(c Command) (string, error) {
+	c.Log = s.log
+	return s.inner.Run(c)
+}
+
+// newServiceExecutor bọc inner bằng log của service; run_logs.disabled thì trả inner
+func newServiceExecutor(inner Executor, service string) Executor {
+	if runLogs == nil {
+		return inner
+	}
+	return serviceExecutor{inner: inner, log: &serviceLog{service: service, dir: filepath.Join(runLogs.dir, service)}}
+}
+
+// prefix là "[service] " cho output của service, rỗng khi không có log
+func (s *serviceLog) prefix() string {
+	if s == nil {
+		return ""
+	}
+	return "[" + s.service + "] "
+}
+
+// openStep tạo file log cho command tiếp theo của service, header là command line (đã redact)
+func (s *serviceLog) openStep(c Command) (*os.File, error) {
+	if err := os.MkdirAll(s.dir, 0755); err != nil {
+		return nil, err
+	}
+	s.mu.Lock()
+	s.steps++
+	step := s.steps
+	s.mu.Unlock()
+	name := filepath.Base(c.Name)
+	if len(c.Args) > 0 && !strings.HasPrefix(c.Args[0], "-") {
+		name += "-" + filepath.Base(c.Args[0])
+	}
+	f, err := os.Create(filepath.Join(s.dir, fmt.Sprintf("%03d-%s.log", step, sanitizeLogName(name))))
+	if err != nil {
+		return nil, err
+	}
+	fmt.Fprintf(f, "$ %s\n", redact(commandLine(c)))
+	if c.Dir != "" {
+		fmt.Fprintf(f, "# dir: %s\n", c.Dir)
+	}
+	return f, nil
+}
+
+func sanitizeLogName(name string) string {
+	return strings.Map(func(r rune) rune {
+		if r >= 'a' && r <= 'z' || r >= 'A' && r <= 'Z' || r >= '0' && r <= '9' || r == '-' || r == '_' || r == '.' {
+			return r
+		}
+		return '_'
+	}, name)
+}
+
+// prefixWriter ghi từng dòng ra target với prefix [service] (dòng dở được giữ tới lần ghi sau / flush)
+type prefixWriter struct {
+	prefix  string
+	target  io.Writer
+	pending []byte
+}
+
+func (w *prefixWriter) Write(p []byte) (int, error) {
+	w.pending = append(w.pending, p...)
+	for {
+		i := bytes.IndexByte(w.pending, '\n')
+		if i < 0 {
+			return len(p), nil
+		}
+		fmt.Fprintf(w.target, "%s%s", w.prefix, w.pending[:i+1])
+		w.pending = w.pending[i+1:]
+	}
+}
+
+func (w *prefixWriter) flush() {
+	if len(w.pending) > 0 {
+		fmt.Fprintf(w.target, "%s%s\n", w.prefix, w.pending)
+		w.pending = nil
+	}
+}
+
+// subprocessError gắn file log và đoạn cuối output của command lỗi, message giữ nguyên lỗi gốc
+type subprocessError struct {
+	err     error
+	logPath string
+	excerpt []string
+}
+
+func (e *subprocessError) Error() string { return e.err.Error() }
+func (e *subprocessError) Unwrap() error { return e.err }
+
+// newSubprocessError đọc lại n dòng cuối của file log
+func newSubprocessError(err error, logPath string, n int) error {
+	f, err2 := os.Open(logPath)
+	if err2 != nil {
+		return err
+	}
+	defer f.Close()
+	var lines []string
+	scanner := bufio.NewScanner(f)
+	for scanner.Scan() {
+		lines = append(lines, scanner.Text())
+		if len(lines) > n {
+			lines = lines[1:]
+		}
+	}
+	return &subprocessError{err: err, logPath: logPath, excerpt: lines}
+}
+
+// printLogExcerpt in đoạn log của subprocess gây lỗi (nếu có) ngay sau dòng lỗi
+func printLogExcerpt(err error) {
+	var sub *subprocessError
+	if !errors.As(err, &sub) {
+		return
+	}
+	fmt.Printf("   ↳ %s\n", sub.logPath)
+	for _, line := range sub.excerpt {
+		fmt.Printf("   │ %s\n", redact(line))
+	}
+}
+
+// redactingWriter che secret trước khi ghi xuống file log (được gọi theo từng dòng qua prefixWriter)
+type redactingWriter struct {
+	target io.Writer
+}
+
+func (w redactingWriter) Write(p []byte) (int, error) {
+	if _, err := io.WriteString(w.target, redact(string(p))); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// runLogged chạy command của service: stdout/stderr ghi vào log của step, output stream ra console
+// (stderr luôn, stdout khi không capture) có prefix [service]. Lỗi được gắn file log + đoạn cuối output.
+func runLogged(cmd *exec.Cmd, c Command, log *serviceLog) (string, error) {
+	prefix := log.prefix()
+	consoleOut := &prefixWriter{prefix: prefix, target: os.Stdout}
+	consoleErr := &prefixWriter{prefix: prefix, target: os.Stderr}
+	var captured bytes.Buffer
+	var stdout io.Writer = consoleOut
+	if c.CaptureOutput {
+		stdout = &captured
+	}
+	var stderr io.Writer = consoleErr
+
+	f, err := log.openStep(c)
+	if err != nil {
+		fmt.Printf("⚠️ Failed to open run log: %v\n", err)
+	}
+	var fileOut, fileErr *prefixWriter
+	if f != nil {
+		fileOut = &prefixWriter{target: redactingWriter{f}}
+		fileErr = &prefixWriter{target: redactingWriter{f}}
+		stdout = io.MultiWriter(stdout, fileOut)
+		stderr = io.MultiWriter(stderr, fileErr)
+	}
+	cmd.Stdout, cmd.Stderr = stdout, stderr
+
+	start := time.Now()
+	err = cmd.Run()
+	consoleOut.flush()
+	consoleErr.flush()
+	if f == nil {
+		return captured.String(), err
+	}
+	fileOut.flush()
+	fileErr.flush()
+	status := "0"
+	if err != nil {
+		status = err.Error()
+	}
+	fmt.Fprintf(f, "# exit: %s (%s)\n", status, time.Since(start).Round(time.Millisecond))
+	f.Close()
+	if err != nil {
+		err = newSubprocessError(err, f.Name(), runLogs.excerpt)
+	}
+	return captured.String(), err
+}
+
+// logPrefix là "[service] " khi ex là executor của một service (dùng cho dòng echo command)
+func logPrefix(ex Executor) string {
+	if s, ok := ex.(serviceExecutor); ok {
+		return s.log.prefix()
+	}
+	return ""
+}
@@ -0,0 +1,62 @@
+package main
+
+import (
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// useRunLogs bật run log vào thư mục tạm cho test
+func useRunLogs(t *testing.T) string {
+	t.Helper()
+	previous := runLogs
+	dir := t.TempDir()
+	runLogs = &runLogger{dir: dir, excerpt: 5}
+	t.Cleanup(func() { runLogs = previous })
+	return dir
+}
+
+func TestServiceExecutorAttributesCommandsFromOtherGoroutines(t *testing.T) {
+	dir := useRunLogs(t)
+	orders := newServiceExecutor(shellExecutor{}, "orders")
+	billing := newServiceExecutor(shellExecutor{}, "billing")
+
+	// Command chạy trên goroutine khác (throttle, fault injection...) vẫn thuộc đúng service
+	var wg sync.WaitGroup
+	for _, ex := range []Executor{orders, billing} {
+		wg.Add(1)
+		go func(ex Executor) {
+			defer wg.Done()
+			if _, err := ex.Run(Command{Name: "sh", Args: []string{"-c", "echo hello"}, CaptureOutput: true}); err != nil {
+				t.Error(err)
+			}
+		}(ex)
+	}
+	wg.Wait()
+
+	for _, service := range []string{"orders", "billing"} {
+		data, err := os.ReadFile(filepath.Join(dir, service, "001-sh.log"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		if !strings.Contains(string(data), "hello") {
+			t.Fatalf("%s log = %q", service, data)
+		}
+	}
+	if logPrefix(orders) != "[orders] " || logPrefix(commandExecutor) != "" {
+		t.Fatalf("log prefix = %q / %q", logPrefix(orders), logPrefix(commandExecutor))
+	}
+}
+
+func TestServiceExecutorWithoutRunLogs(t *testing.T) {
+	previous := runLogs
+	runLogs = nil
+	t.Cleanup(func() { runLogs = previous })
+
+	fake := newFakeExecutor()
+	if ex := newServiceExecutor(fake, "orders"); ex != Executor(fake) {
+		t.Fatalf("run_logs.disabled must use the executor as is, got %T", ex)
+	}
+}
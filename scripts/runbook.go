@@ -0,0 +1,98 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"path"
+	"strings"
+)
+
+// Runbook render docs/runbook.md (alert handling, dashboards, escalation) vào repo mới.
+// Service thuộc RequiredTiers bị chặn ở validate nếu registry không render được runbook.
+type Runbook struct {
+	Templates string `yaml:"templates"`
+	// DashboardURL là dashboard chung của service, {name} được thay bằng tên service
+	DashboardURL  string `yaml:"dashboard_url"`
+	RequiredTiers []int  `yaml:"required_tiers"` // mặc định [1]
+}
+
+func (r Runbook) requiredFor(tier int) bool {
+	tiers := r.RequiredTiers
+	if len(tiers) == 0 {
+		tiers = []int{1}
+	}
+	for _, t := range tiers {
+		if t == tier {
+			return true
+		}
+	}
+	return false
+}
+
+type runbookLink struct {
+	Name string
+	URL  string
+}
+
+// runbookAlert: nội dung (ý nghĩa, cách xử lý) nằm trong template theo Kind để dịch được
+type runbookAlert struct {
+	Name      string
+	Kind      string // error_budget | latency
+	Threshold string // latency threshold (kind latency)
+}
+
+type runbookData struct {
+	Service    GeneratorSourceDto
+	Dashboards []runbookLink
+	AlertRules string // file Prometheus rules của SLO (rỗng khi service không khai báo slo)
+	Alerts     []runbookAlert
+	OnCall     string // provider trực (rỗng khi tier không cần on-call)
+}
+
+// validateRunbook: tier bắt buộc có runbook thì registry phải có template runbook
+func validateRunbook(config SourceConfig, r Runbook) []string {
+	if !r.requiredFor(config.Metadata.Tier) || r.Templates != "" {
+		return nil
+	}
+	return []string{fmt.Sprintf("tier-%d service '%s' requires a runbook but runbook.templates is not configured",
+		config.Metadata.Tier, config.Name)}
+}
+
+func writeRunbook(repoDir string, dto GeneratorSourceDto, registryConfig RegistryConfig) error {
+	r := registryConfig.Runbook
+	if r.Templates == "" {
+		return nil
+	}
+	if _, err := os.Stat(r.Templates); err != nil {
+		return fmt.Errorf("template dir not found: %s", r.Templates)
+	}
+
+	data := runbookData{Service: dto}
+	if r.DashboardURL != "" {
+		data.Dashboards = append(data.Dashboards, runbookLink{Name: "Service dashboard", URL: strings.ReplaceAll(r.DashboardURL, "{name}", dto.AppName)})
+	}
+	if !dto.SLO.empty() {
+		c := registryConfig.SLO.withDefaults()
+		dir := c.dir(dto.AppName)
+		dashboard, alerts := path.Join(dir, "grafana", "slo-dashboard.json"), path.Join(dir, "prometheus", "slo-rules.yaml")
+		link := "../" + dashboard // tương đối so với docs/runbook.md
+		if c.MonitoringRepo != "" {
+			base := fmt.Sprintf("https://github.com/%s/blob/HEAD/", c.MonitoringRepo)
+			link, alerts = base+dashboard, base+alerts
+		}
+		data.Dashboards = append(data.Dashboards, runbookLink{Name: "SLO dashboard", URL: link})
+		data.AlertRules = alerts
+		if dto.SLO.Availability > 0 {
+			data.Alerts = append(data.Alerts, runbookAlert{Name: sloAlertName("SLOErrorBudgetBurn", dto.AppName), Kind: "error_budget"})
+		}
+		if dto.SLO.Latency != nil {
+			data.Alerts = append(data.Alerts, runbookAlert{Name: sloAlertName("SLOLatency", dto.AppName), Kind: "latency", Threshold: dto.SLO.Latency.Threshold})
+		}
+	}
+	if registryConfig.OnCall.enabledFor(dto.Tier) {
+		data.OnCall = registryConfig.OnCall.Provider
+	}
+
+	fmt.Println("📝 Rendering docs/runbook.md...")
+	return renderLocalizedTemplateDir(r.Templates, repoDir, dto.DocsLanguage, data)
+}
@@ -0,0 +1,79 @@
+package main
+
+import (
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"strings"
+	"text/template"
+)
+
+// renderTemplateDir render toàn bộ file trong srcDir (text/template) vào destDir,
+// giữ nguyên cấu trúc thư mục. Hậu tố ".tmpl" sẽ được bỏ khi ghi file.
+func renderTemplateDir(srcDir, destDir string, data interface{}) error {
+	return filepath.WalkDir(srcDir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			// Bản dịch nằm trong i18n/<lang>/, chỉ render qua renderLocalizedTemplateDir
+			if d.Name() == "i18n" && path != srcDir {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+
+		rel, err := filepath.Rel(srcDir, path)
+		if err != nil {
+			return err
+		}
+		target := filepath.Join(destDir, strings.TrimSuffix(rel, ".tmpl"))
+
+		return renderTemplateFile(path, target, data)
+	})
+}
+
+// renderLocalizedTemplateDir render srcDir rồi đè bản dịch srcDir/i18n/<language> (nếu có) lên trên.
+// File chưa dịch giữ bản tiếng Anh, nên bộ template dịch có thể làm dần từng file.
+func renderLocalizedTemplateDir(srcDir, destDir, language string, data interface{}) error {
+	if err := renderTemplateDir(srcDir, destDir, data); err != nil {
+		return err
+	}
+	if language == "" || language == "en" {
+		return nil
+	}
+	localized := filepath.Join(srcDir, "i18n", language)
+	if _, err := os.Stat(localized); err != nil {
+		fmt.Printf("  ⚠️ No %s translation in %s, using English\n", language, srcDir)
+		return nil
+	}
+	return renderTemplateDir(localized, destDir, data)
+}
+
+func renderTemplateFile(src, target string, data interface{}) error {
+	content, err := os.ReadFile(src)
+	if err != nil {
+		return fmt.Errorf("failed to read template %s: %w", src, err)
+	}
+
+	tmpl, err := template.New(filepath.Base(src)).Option("missingkey=error").Parse(string(content))
+	if err != nil {
+		return fmt.Errorf("failed to parse template %s: %w", src, err)
+	}
+
+	if err := os.MkdirAll(filepath.Dir(target), 0755); err != nil {
+		return fmt.Errorf("failed to create dir for %s: %w", target, err)
+	}
+
+	f, err := os.Create(target)
+	if err != nil {
+		return fmt.Errorf("failed to create %s: %w", target, err)
+	}
+	defer f.Close()
+
+	if err := tmpl.Execute(f, data); err != nil {
+		return fmt.Errorf("failed to render template %s: %w", src, err)
+	}
+	return nil
+}
@@ -0,0 +1,171 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"fmt"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// SearchFilter là các điều kiện lọc của lệnh search (rỗng = không lọc)
+type SearchFilter struct {
+	Query     string
+	Language  string
+	Framework string
+	Team      string
+	Module    string
+	Member    string
+	// Repo / Status lọc theo state store (repo đã provision, kết quả generate gần nhất)
+	Repo   string
+	Status string
+}
+
+func (f SearchFilter) matches(e CatalogEntry) bool {
+	if f.Language != "" && !strings.EqualFold(e.ProgrammingLanguage, f.Language) {
+		return false
+	}
+	if f.Framework != "" && !strings.EqualFold(e.Framework, f.Framework) {
+		return false
+	}
+	if f.Team != "" && !strings.EqualFold(e.Team, f.Team) {
+		return false
+	}
+	if f.Module != "" && !containsFold(e.Module, f.Module) {
+		return false
+	}
+	if f.Member != "" && !containsStringFold(e.Members, f.Member) {
+		return false
+	}
+	if f.Repo != "" && !containsFold(e.RepoURL, f.Repo) {
+		return false
+	}
+	if f.Status != "" && !strings.EqualFold(e.Status, f.Status) {
+		return false
+	}
+	if f.Query != "" {
+		// Query tự do khớp với name, folder, module, framework, team hoặc repo / status / template version từ state
+		fields := []string{e.Name, e.Folder, e.Module, e.Framework, e.Team, e.ProgrammingLanguage, e.RepoURL, e.Status, e.TemplateVersion}
+		for _, field := range fields {
+			if containsFold(field, f.Query) {
+				return true
+			}
+		}
+		return false
+	}
+	return true
+}
+
+func runSearchCommand(args []string) error {
+	fs := flag.NewFlagSet("search", flag.ExitOnError)
+	var filter SearchFilter
+	fs.StringVar(&filter.Language, "language", "", "filter by programming language")
+	fs.StringVar(&filter.Framework, "framework", "", "filter by framework")
+	fs.StringVar(&filter.Team, "team", "", "filter by team")
+	fs.StringVar(&filter.Module, "module", "", "filter by module path keyword")
+	fs.StringVar(&filter.Member, "member", "", "filter by member GitHub handle")
+	fs.StringVar(&filter.Repo, "repo", "", "filter by provisioned repository (owner/name keyword)")
+	fs.StringVar(&filter.Status, "status", "", "filter by last generate status: success|failed")
+	output := fs.String("output", "table", "output format: table|json")
+	fs.Parse(args)
+	filter.Query = strings.Join(fs.Args(), " ")
+
+	services, err := loadRegisteredServices(sourcesDir)
+	if err != nil {
+		return err
+	}
+
+	entries := buildCatalog(services)
+	stateEntries, err := stateOnlyEntries(services)
+	if err != nil {
+		return err
+	}
+	entries = append(entries, stateEntries...)
+
+	matches := []CatalogEntry{}
+	for _, entry := range entries {
+		if filter.matches(entry) {
+			matches = append(matches, entry)
+		}
+	}
+
+	switch *output {
+	case "json":
+		data, err := json.MarshalIndent(matches, "", "  ")
+		if err != nil {
+			return err
+		}
+		fmt.Println(string(data))
+	case "table":
+		printCatalogTable(matches)
+	default:
+		return fmt.Errorf("unsupported output format: %s", *output)
+	}
+	return nil
+}
+
+func printCatalogTable(entries []CatalogEntry) {
+	w := tabwriter.NewWriter(os.Stdout, 0, 0, 2, ' ', 0)
+	fmt.Fprintln(w, "NAME\tLANGUAGE\tFRAMEWORK\tTEAM\tMEMBERS\tREPOSITORY\tSTATUS")
+	for _, e := range entries {
+		fmt.Fprintf(w, "%s\t%s\t%s\t%s\t%s\t%s\t%s\n",
+			e.Name, e.ProgrammingLanguage, e.Framework, e.Team, strings.Join(e.Members, ","), e.RepoURL, e.Status)
+	}
+	w.Flush()
+	fmt.Printf("\n%d service(s) found\n", len(entries))
+}
+
+// stateOnlyEntries: service có trong state/services.json nhưng không còn source.yml (archived, adopted...),
+// để vẫn tìm được theo repo hoặc status
+func stateOnlyEntries(services []RegisteredService) ([]CatalogEntry, error) {
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return nil, err
+	}
+	known := map[string]bool{}
+	for _, s := range services {
+		known[s.Config.Name] = true
+	}
+	names := make([]string, 0, len(state.Services))
+	for name := range state.Services {
+		if !known[name] {
+			names = append(names, name)
+		}
+	}
+	sort.Strings(names)
+
+	entries := make([]CatalogEntry, 0, len(names))
+	for _, name := range names {
+		s := state.Services[name]
+		repoURL := ""
+		if s.Repo != "" {
+			repoURL = "https://github.com/" + s.Repo
+		}
+		if s.Repository != nil && s.Repository.HTMLURL != "" {
+			repoURL = s.Repository.HTMLURL
+		}
+		entries = append(entries, CatalogEntry{
+			SourceID:        s.SourceID,
+			Name:            name,
+			RepoURL:         repoURL,
+			Status:          s.LastStatus,
+			TemplateVersion: s.TemplateVersion,
+		})
+	}
+	return entries, nil
+}
+
+func containsFold(s, substr string) bool {
+	return strings.Contains(strings.ToLower(s), strings.ToLower(substr))
+}
+
+func containsStringFold(list []string, value string) bool {
+	for _, item := range list {
+		if strings.EqualFold(item, value) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"testing"
+)
+
+func TestSearchFilterMatchesState(t *testing.T) {
+	entry := CatalogEntry{Name: "orders", RepoURL: "https://github.com/acme-payments/orders", Status: "failed", TemplateVersion: "v1.4.0"}
+	for _, tc := range []struct {
+		filter SearchFilter
+		want   bool
+	}{
+		{SearchFilter{Repo: "acme-payments/orders"}, true},
+		{SearchFilter{Repo: "acme/orders"}, false},
+		{SearchFilter{Status: "FAILED"}, true},
+		{SearchFilter{Status: "success"}, false},
+		{SearchFilter{Query: "acme-payments"}, true},
+		{SearchFilter{Query: "v1.4"}, true},
+	} {
+		if got := tc.filter.matches(entry); got != tc.want {
+			t.Errorf("%+v matches = %v, want %v", tc.filter, got, tc.want)
+		}
+	}
+}
+
+func TestStateOnlyEntries(t *testing.T) {
+	inTempDir(t)
+	state := &RegistryState{Services: map[string]*ServiceState{
+		"orders":  {Repo: "acme/orders", LastStatus: "success"},
+		"billing": {SourceID: "b-1", Repo: "acme-finance/billing", LastStatus: "failed", TemplateVersion: "v2.0.0"},
+	}}
+	if err := state.save(registryStateFile); err != nil {
+		t.Fatal(err)
+	}
+
+	services := []RegisteredService{{Folder: "orders", Config: SourceConfig{Name: "orders"}}}
+	entries, err := stateOnlyEntries(services)
+	if err != nil {
+		t.Fatalf("stateOnlyEntries: %v", err)
+	}
+	if len(entries) != 1 {
+		t.Fatalf("entries = %+v, want only billing", entries)
+	}
+	got := entries[0]
+	if got.Name != "billing" || got.RepoURL != "https://github.com/acme-finance/billing" || got.Status != "failed" || got.TemplateVersion != "v2.0.0" {
+		t.Fatalf("entry = %+v", got)
+	}
+}
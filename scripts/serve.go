@@ -0,0 +1,366 @@
+package main
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+)
+
+// runServeCommand chạy registry ở server mode: nhận provisioning request (API + webhook),
+// đưa vào job queue bền vững và xử lý bằng một số worker cố định.
+// Server chạy trên checkout của jupiter-registry, việc đồng bộ checkout nằm ngoài phạm vi này.
+func runServeCommand(args []string) error {
+	fs := flag.NewFlagSet("serve", flag.ExitOnError)
+	addr := fs.String("addr", "", "listen address (overrides server.listen)")
+	fs.Parse(args)
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+	server := registryConfig.Server
+	if *addr != "" {
+		server.Listen = *addr
+	}
+
+	queue, err := loadJobQueue(server.queueFile())
+	if err != nil {
+		return err
+	}
+
+	// Metrics: kết quả provisioning qua event bus, latency GitHub qua executor
+	serverMetrics = newServerMetrics()
+	events.Subscribe("metrics", func(e Event) error {
+		serverMetrics.observeProvisioning(e)
+		return nil
+	}, EventServiceGenerated, EventServiceFailed)
+	commandExecutor = metricsExecutor{inner: commandExecutor, metrics: serverMetrics}
+
+	for i := 0; i < server.workers(); i++ {
+		go runJobWorker(queue, registryConfig)
+	}
+
+	if server.Reconcile.Interval != "" {
+		interval, err := time.ParseDuration(server.Reconcile.Interval)
+		if err != nil || interval <= 0 {
+			return withCode(ErrUsage, fmt.Errorf("invalid server.reconcile.interval %q", server.Reconcile.Interval))
+		}
+		go runReconcileLoop(queue, registryConfig, interval)
+	}
+
+	// Server chạy lâu dài: retention của artifact store được áp dụng mỗi ngày
+	if artifactStore != nil && artifactStore.RetentionDays > 0 {
+		go runArtifactRetention(*artifactStore, 24*time.Hour)
+	}
+
+	auth := server.Auth
+	if len(auth.Providers) == 0 {
+		fmt.Println("⚠️ server.auth has no providers, the API is open to anyone who can reach it")
+	}
+
+	// Badge (nhúng vào README), webhook (ký HMAC), Backstage template, health và metrics không cần xác thực
+	mux := http.NewServeMux()
+	mux.HandleFunc("/api/provision", withAuth(auth, roleOperator, handleProvisionRequest(queue, registryConfig)))
+	mux.HandleFunc("/api/services/", withAuth(auth, roleViewer, handleServiceAction(queue, registryConfig)))
+	mux.HandleFunc("/api/jobs", withAuth(auth, roleViewer, handleListJobs(queue)))
+	mux.HandleFunc("/api/jobs/", withAuth(auth, roleViewer, handleJob(queue)))
+	mux.HandleFunc("/api/reconcile", withAuth(auth, roleViewer, handleReconcileReport()))
+	mux.HandleFunc("/api/scaffolder/sources", withAuth(auth, roleOperator, handleScaffolderRequest(registryConfig)))
+	mux.HandleFunc("/scaffolder/template.yaml", handleBackstageTemplate(registryConfig))
+	mux.HandleFunc("/api/badges/", handleBadge())
+	// Webhook không có secret thì ai cũng giả được push event: không mở route
+	if server.webhookSecret() != "" {
+		mux.HandleFunc(server.webhookPath(), handleRegistryWebhook(queue, server))
+	} else {
+		fmt.Printf("⚠️ server.webhook_secret_env is not set, %s is disabled\n", server.webhookPath())
+	}
+	mux.HandleFunc("/healthz", handleHealthz())
+	mux.HandleFunc("/readyz", handleReadyz(queue))
+	mux.HandleFunc("/metrics", handleMetrics(queue))
+
+	fmt.Printf("🚀 Jupiter registry listening on %s (%d workers)\n", server.listen(), server.workers())
+	return http.ListenAndServe(server.listen(), mux)
+}
+
+// runJobWorker lấy job từ queue và provisioning tuần tự
+func runJobWorker(queue *JobQueue, registryConfig RegistryConfig) {
+	ticker := time.NewTicker(5 * time.Second)
+	defer ticker.Stop()
+
+	for {
+		job := queue.claim()
+		if job == nil {
+			// Đợi job mới hoặc tới hạn retry
+			select {
+			case <-queue.notify:
+			case <-ticker.C:
+			}
+			continue
+		}
+
+		fmt.Printf("📦 Job %s: provisioning %s (attempt %d/%d)\n", job.ID, job.ServicePath, job.Attempts, job.MaxAttempts)
+		err := provisionService(job.ServicePath, registryConfig, 1, parseConfirm(job.Confirm))
+		if err != nil {
+			fmt.Printf("❌ Job %s: [%s] %v\n", job.ID, errorCode(err), err)
+			printLogExcerpt(err)
+		}
+		queue.finish(job.ID, err)
+		if job, ok := queue.get(job.ID); ok {
+			serverMetrics.observeJob(job.Status)
+		}
+	}
+}
+
+// POST /api/provision {"service": "sample", "confirm_token": "..."}
+// confirm_token (action force_push) chỉ cần khi regenerate sẽ force push đè history
+func handleProvisionRequest(queue *JobQueue, registryConfig RegistryConfig) http.HandlerFunc {
+	server := registryConfig.Server
+	return func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req struct {
+			Service      string `json:"service"`
+			ConfirmToken string `json:"confirm_token"`
+		}
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil || req.Service == "" {
+			http.Error(w, `body must be {"service": "<folder in sources-service>"}`, http.StatusBadRequest)
+			return
+		}
+
+		servicePath, err := registeredServicePath(req.Service)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		if !requireRole(w, r, roleOperator, serviceTeam(req.Service)) {
+			return
+		}
+		var confirm string
+		if req.ConfirmToken != "" {
+			config, err := loadSourceConfig(servicePath)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if err := verifyConfirmation(registryConfig.Destructive.tokenSecret(), req.ConfirmToken, config.Name, actionForcePush); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			confirm = config.Name
+		}
+		job, err := queue.enqueue(servicePath, server.maxAttempts(), confirm)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		writeJSONResponse(w, http.StatusAccepted, job)
+	}
+}
+
+// GET|PUT|DELETE /api/services/{name} (service_resource.go), GET /api/services/{name}/history,
+// POST /api/services/{name}/archive|delete {"confirm_token": "..."}, POST /api/services/{name}/restore
+func handleServiceAction(queue *JobQueue, registryConfig RegistryConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		name, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/services/"), "/")
+		if action == "" {
+			handleServiceResource(w, r, name, queue, registryConfig)
+			return
+		}
+		// history: viewer, restore: operator, archive / delete: admin
+		role := roleOperator
+		switch action {
+		case "history":
+			role = roleViewer
+		case actionArchive, actionDelete:
+			role = roleAdmin
+		}
+		if !requireRole(w, r, role, serviceTeam(name)) {
+			return
+		}
+		if action == "history" && r.Method == http.MethodGet {
+			records, err := loadRunHistory(name)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusBadRequest)
+				return
+			}
+			if records == nil {
+				records = []RunRecord{}
+			}
+			writeJSONResponse(w, http.StatusOK, records)
+			return
+		}
+		if r.Method != http.MethodPost {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		var req struct {
+			ConfirmToken string `json:"confirm_token"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid body", http.StatusBadRequest)
+				return
+			}
+		}
+
+		// Server không có người gõ --confirm: token ký đúng service + action thay cho xác nhận
+		var confirmed []string
+		if action == actionArchive || action == actionDelete {
+			if err := verifyConfirmation(registryConfig.Destructive.tokenSecret(), req.ConfirmToken, name, action); err != nil {
+				http.Error(w, err.Error(), http.StatusForbidden)
+				return
+			}
+			confirmed = []string{name}
+		}
+
+		var err error
+		switch action {
+		case actionArchive:
+			err = archiveService(name, confirmed, registryConfig)
+		case actionDelete:
+			err = deleteService(name, confirmed, registryConfig)
+		case "restore":
+			err = restoreService(name, registryConfig)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+			return
+		}
+		if err != nil {
+			writeJSONResponse(w, http.StatusConflict, map[string]interface{}{"error": errorCode(err), "message": redact(err.Error())})
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, map[string]string{"service": name, "action": action})
+	}
+}
+
+// GET /api/jobs?status=failed
+func handleListJobs(queue *JobQueue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, http.StatusOK, queue.list(r.URL.Query().Get("status")))
+	}
+}
+
+// GET /api/jobs/{id}, POST /api/jobs/{id}/retry
+func handleJob(queue *JobQueue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id, action, _ := strings.Cut(strings.TrimPrefix(r.URL.Path, "/api/jobs/"), "/")
+		switch {
+		case action == "" && r.Method == http.MethodGet:
+			job, ok := queue.get(id)
+			if !ok {
+				http.Error(w, "job not found", http.StatusNotFound)
+				return
+			}
+			writeJSONResponse(w, http.StatusOK, job)
+		case action == "retry" && r.Method == http.MethodPost:
+			existing, ok := queue.get(id)
+			if !ok {
+				http.Error(w, "job not found", http.StatusNotFound)
+				return
+			}
+			if !requireRole(w, r, roleOperator, serviceTeam(filepath.Base(existing.ServicePath))) {
+				return
+			}
+			job, err := queue.retry(id)
+			if err != nil {
+				http.Error(w, err.Error(), http.StatusConflict)
+				return
+			}
+			writeJSONResponse(w, http.StatusAccepted, job)
+		default:
+			http.Error(w, "not found", http.StatusNotFound)
+		}
+	}
+}
+
+// handleRegistryWebhook nhận push event của jupiter-registry và enqueue các service có source.yml thay đổi.
+// Event từ các repo được generate chỉ được acknowledge.
+func handleRegistryWebhook(queue *JobQueue, server ServerConfig) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		body, err := io.ReadAll(r.Body)
+		if err != nil {
+			http.Error(w, "failed to read body", http.StatusBadRequest)
+			return
+		}
+		if !validWebhookSignature(body, r.Header.Get("X-Hub-Signature-256"), server.webhookSecret()) {
+			http.Error(w, "invalid signature", http.StatusUnauthorized)
+			return
+		}
+		if r.Header.Get("X-GitHub-Event") != "push" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var event struct {
+			Ref     string `json:"ref"`
+			Commits []struct {
+				Added    []string `json:"added"`
+				Modified []string `json:"modified"`
+			} `json:"commits"`
+		}
+		if err := json.Unmarshal(body, &event); err != nil {
+			http.Error(w, "invalid payload", http.StatusBadRequest)
+			return
+		}
+		if event.Ref != "refs/heads/main" {
+			w.WriteHeader(http.StatusNoContent)
+			return
+		}
+
+		var jobs []*Job
+		for _, commit := range event.Commits {
+			for _, path := range append(commit.Added, commit.Modified...) {
+				if filepath.Base(path) != "source.yml" || filepath.Dir(filepath.Dir(path)) != sourcesDir {
+					continue
+				}
+				job, err := queue.enqueue(filepath.Dir(path), server.maxAttempts(), "")
+				if err != nil {
+					http.Error(w, err.Error(), http.StatusInternalServerError)
+					return
+				}
+				jobs = append(jobs, job)
+			}
+		}
+		writeJSONResponse(w, http.StatusAccepted, jobs)
+	}
+}
+
+// validWebhookSignature kiểm tra X-Hub-Signature-256; không có secret thì không request nào hợp lệ
+func validWebhookSignature(body []byte, signature, secret string) bool {
+	if secret == "" {
+		return false
+	}
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	expected := "sha256=" + hex.EncodeToString(mac.Sum(nil))
+	return hmac.Equal([]byte(expected), []byte(signature))
+}
+
+// registeredServicePath chỉ cho phép service folder nằm trong sources-service
+func registeredServicePath(service string) (string, error) {
+	if strings.ContainsAny(service, `/\`) || strings.HasPrefix(service, ".") {
+		return "", fmt.Errorf("invalid service name: %s", service)
+	}
+	servicePath := filepath.Join(sourcesDir, service)
+	if _, err := os.Stat(filepath.Join(servicePath, "source.yml")); err != nil {
+		return "", fmt.Errorf("service %s not found in %s", service, sourcesDir)
+	}
+	return servicePath, nil
+}
+
+func writeJSONResponse(w http.ResponseWriter, status int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(v)
+}
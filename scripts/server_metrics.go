@@ -0,0 +1,223 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+)
+
+// latencyBuckets (giây) cho histogram latency của provider API
+var latencyBuckets = []float64{0.05, 0.1, 0.25, 0.5, 1, 2.5, 5, 10, 30}
+
+type latencyHistogram struct {
+	counts []uint64 // theo latencyBuckets, không cộng dồn
+	sum    float64
+	total  uint64
+	errors uint64
+}
+
+// ServerMetrics là metrics của registry ở server mode, expose dạng Prometheus text trên /metrics
+type ServerMetrics struct {
+	mu           sync.Mutex
+	startedAt    time.Time
+	provisioning map[[2]string]uint64 // {result, error_code} -> count
+	jobs         map[string]uint64    // status kết thúc của job -> count
+	providers    map[string]*latencyHistogram
+}
+
+// serverMetrics chỉ khác nil khi chạy `serve`
+var serverMetrics *ServerMetrics
+
+func newServerMetrics() *ServerMetrics {
+	return &ServerMetrics{
+		startedAt:    time.Now(),
+		provisioning: map[[2]string]uint64{},
+		jobs:         map[string]uint64{},
+		providers:    map[string]*latencyHistogram{},
+	}
+}
+
+func (m *ServerMetrics) observeProvisioning(e Event) {
+	result := "success"
+	if e.Type == EventServiceFailed {
+		result = "failed"
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.provisioning[[2]string{result, e.ErrorCode}]++
+}
+
+func (m *ServerMetrics) observeJob(status string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[status]++
+}
+
+// observeProvider ghi latency một request tới provider (github, api.pagerduty.com, ...)
+func (m *ServerMetrics) observeProvider(provider string, elapsed time.Duration, err error) {
+	if m == nil {
+		return
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.providers[provider]
+	if !ok {
+		h = &latencyHistogram{counts: make([]uint64, len(latencyBuckets))}
+		m.providers[provider] = h
+	}
+	seconds := elapsed.Seconds()
+	for i, bound := range latencyBuckets {
+		if seconds <= bound {
+			h.counts[i]++
+			break
+		}
+	}
+	h.sum += seconds
+	h.total++
+	if err != nil {
+		h.errors++
+	}
+}
+
+// metricsExecutor đo latency của command gọi GitHub (gh, git push/fetch/clone/ls-remote)
+type metricsExecutor struct {
+	inner   Executor
+	metrics *ServerMetrics
+}
+
+func (e metricsExecutor) Run(c Command) (string, error) {
+	// Cache hit không gọi GitHub nên không tính vào latency
+	if !isGitHubCommand(c) || (apiCache != nil && apiCache.has(c)) {
+		return e.inner.Run(c)
+	}
+	start := time.Now()
+	out, err := e.inner.Run(c)
+	e.metrics.observeProvider("github", time.Since(start), err)
+	return out, err
+}
+
+// writePrometheus render metrics theo text exposition format của Prometheus
+func (m *ServerMetrics) writePrometheus(w *strings.Builder, queue *JobQueue) {
+	depth := map[string]uint64{jobQueued: 0, jobRunning: 0, jobFailed: 0}
+	for _, job := range queue.list("") {
+		if _, ok := depth[job.Status]; ok {
+			depth[job.Status]++
+		}
+	}
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	fmt.Fprintf(w, "# HELP jupiter_up_seconds Seconds since the registry server started.\n# TYPE jupiter_up_seconds gauge\n")
+	fmt.Fprintf(w, "jupiter_up_seconds %.0f\n", time.Since(m.startedAt).Seconds())
+
+	fmt.Fprintf(w, "# HELP jupiter_queue_depth Jobs in the provisioning queue by status.\n# TYPE jupiter_queue_depth gauge\n")
+	for _, status := range sortedKeys(depth) {
+		fmt.Fprintf(w, "jupiter_queue_depth{status=%q} %d\n", status, depth[status])
+	}
+
+	fmt.Fprintf(w, "# HELP jupiter_jobs_total Finished job attempts by resulting job status (queued = retry scheduled).\n# TYPE jupiter_jobs_total counter\n")
+	for _, status := range sortedKeys(m.jobs) {
+		fmt.Fprintf(w, "jupiter_jobs_total{status=%q} %d\n", status, m.jobs[status])
+	}
+
+	fmt.Fprintf(w, "# HELP jupiter_provisioning_total Provisioning runs by result and error code.\n# TYPE jupiter_provisioning_total counter\n")
+	keys := make([][2]string, 0, len(m.provisioning))
+	var succeeded, total uint64
+	for k, n := range m.provisioning {
+		keys = append(keys, k)
+		total += n
+		if k[0] == "success" {
+			succeeded += n
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i][0]+keys[i][1] < keys[j][0]+keys[j][1] })
+	for _, k := range keys {
+		fmt.Fprintf(w, "jupiter_provisioning_total{result=%q,error_code=%q} %d\n", k[0], k[1], m.provisioning[k])
+	}
+	fmt.Fprintf(w, "# HELP jupiter_provisioning_success_ratio Successful provisioning runs / all runs since start.\n# TYPE jupiter_provisioning_success_ratio gauge\n")
+	ratio := 1.0
+	if total > 0 {
+		ratio = float64(succeeded) / float64(total)
+	}
+	fmt.Fprintf(w, "jupiter_provisioning_success_ratio %g\n", ratio)
+
+	providers := make([]string, 0, len(m.providers))
+	for provider := range m.providers {
+		providers = append(providers, provider)
+	}
+	sort.Strings(providers)
+	fmt.Fprintf(w, "# HELP jupiter_provider_request_duration_seconds Latency of requests to provider APIs.\n# TYPE jupiter_provider_request_duration_seconds histogram\n")
+	for _, provider := range providers {
+		h := m.providers[provider]
+		var cumulative uint64
+		for i, bound := range latencyBuckets {
+			cumulative += h.counts[i]
+			fmt.Fprintf(w, "jupiter_provider_request_duration_seconds_bucket{provider=%q,le=\"%g\"} %d\n", provider, bound, cumulative)
+		}
+		fmt.Fprintf(w, "jupiter_provider_request_duration_seconds_bucket{provider=%q,le=\"+Inf\"} %d\n", provider, h.total)
+		fmt.Fprintf(w, "jupiter_provider_request_duration_seconds_sum{provider=%q} %g\n", provider, h.sum)
+		fmt.Fprintf(w, "jupiter_provider_request_duration_seconds_count{provider=%q} %d\n", provider, h.total)
+	}
+	fmt.Fprintf(w, "# HELP jupiter_provider_request_errors_total Failed requests to provider APIs.\n# TYPE jupiter_provider_request_errors_total counter\n")
+	for _, provider := range providers {
+		fmt.Fprintf(w, "jupiter_provider_request_errors_total{provider=%q} %d\n", provider, m.providers[provider].errors)
+	}
+}
+
+func sortedKeys(m map[string]uint64) []string {
+	keys := make([]string, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// GET /healthz: process còn sống
+func handleHealthz() http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		writeJSONResponse(w, http.StatusOK, map[string]string{"status": "ok"})
+	}
+}
+
+// GET /readyz: đọc được registry (sources, state) và ghi được job queue thì mới nhận request
+func handleReadyz(queue *JobQueue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		checks := map[string]string{}
+		if _, err := os.ReadDir(sourcesDir); err != nil {
+			checks["sources"] = err.Error()
+		}
+		if _, err := loadRegistryState(registryStateFile); err != nil {
+			checks["state"] = err.Error()
+		}
+		// Thử ghi file tạm cạnh queue thay vì ghi lại cả queue mỗi lần probe
+		if f, err := os.CreateTemp(filepath.Dir(queue.path), ".readyz-*"); err != nil {
+			checks["queue"] = err.Error()
+		} else {
+			f.Close()
+			os.Remove(f.Name())
+		}
+
+		if len(checks) > 0 {
+			writeJSONResponse(w, http.StatusServiceUnavailable, map[string]interface{}{"status": "not ready", "checks": checks})
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, map[string]string{"status": "ready"})
+	}
+}
+
+// GET /metrics (Prometheus)
+func handleMetrics(queue *JobQueue) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		var b strings.Builder
+		serverMetrics.writePrometheus(&b, queue)
+		w.Header().Set("Content-Type", "text/plain; version=0.0.4")
+		fmt.Fprint(w, b.String())
+	}
+}
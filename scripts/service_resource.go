@@ -0,0 +1,236 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// Resource API cho client khai báo (Terraform provider `jupiter_service`): service là một resource
+// có spec = nội dung source.yml, đọc / ghi / huỷ theo tên.
+//   - GET    /api/services/{name}: spec + state + job gần nhất (Read)
+//   - PUT    /api/services/{name}[?dry_run=true]: validate, ghi source.yml và enqueue provisioning (Create / Update);
+//     dry_run chỉ validate và báo có thay đổi hay không (plan)
+//   - DELETE /api/services/{name} {"confirm_token": "..."}: archive repo (Delete, không xoá hẳn)
+//
+// source.yml ghi qua API nằm trong checkout của server, cần được commit vào registry như mọi service khác.
+
+// ServiceResource là representation của service trả về cho client
+type ServiceResource struct {
+	Name    string                 `json:"name"`
+	Spec    map[string]interface{} `json:"spec"`
+	State   *ServiceState          `json:"state,omitempty"`
+	LastJob *Job                   `json:"last_job,omitempty"`
+}
+
+// ServicePlan là kết quả PUT ?dry_run=true
+type ServicePlan struct {
+	Name     string   `json:"name"`
+	Action   string   `json:"action"` // create | update | none
+	Problems []string `json:"problems"`
+	Warnings []string `json:"warnings"`
+}
+
+func handleServiceResource(w http.ResponseWriter, r *http.Request, name string, queue *JobQueue, registryConfig RegistryConfig) {
+	switch r.Method {
+	case http.MethodGet:
+		if !requireRole(w, r, roleViewer, serviceTeam(name)) {
+			return
+		}
+		resource, err := readServiceResource(name, queue)
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusNotFound)
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, resource)
+	case http.MethodPut:
+		putServiceResource(w, r, name, queue, registryConfig)
+	case http.MethodDelete:
+		var req struct {
+			ConfirmToken string `json:"confirm_token"`
+		}
+		if r.ContentLength != 0 {
+			if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+				http.Error(w, "invalid body", http.StatusBadRequest)
+				return
+			}
+		}
+		if !requireRole(w, r, roleAdmin, serviceTeam(name)) {
+			return
+		}
+		if err := verifyConfirmation(registryConfig.Destructive.tokenSecret(), req.ConfirmToken, name, actionArchive); err != nil {
+			http.Error(w, err.Error(), http.StatusForbidden)
+			return
+		}
+		if err := archiveService(name, []string{name}, registryConfig); err != nil {
+			writeJSONResponse(w, http.StatusConflict, map[string]interface{}{"error": errorCode(err), "message": redact(err.Error())})
+			return
+		}
+		writeJSONResponse(w, http.StatusOK, map[string]string{"service": name, "action": actionArchive})
+	default:
+		http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+	}
+}
+
+func readServiceResource(name string, queue *JobQueue) (ServiceResource, error) {
+	servicePath, err := registeredServicePath(name)
+	if err != nil {
+		return ServiceResource{}, err
+	}
+	spec, err := readSourceSpec(servicePath)
+	if err != nil {
+		return ServiceResource{}, err
+	}
+	resource := ServiceResource{Name: name, Spec: spec}
+	if state, err := loadRegistryState(registryStateFile); err == nil {
+		if config, err := loadSourceConfig(servicePath); err == nil {
+			resource.State = state.Services[config.Name]
+		}
+	}
+	if job, ok := queue.latest(servicePath); ok {
+		resource.LastJob = &job
+	}
+	return resource, nil
+}
+
+func readSourceSpec(servicePath string) (map[string]interface{}, error) {
+	data, err := os.ReadFile(filepath.Join(servicePath, "source.yml"))
+	if err != nil {
+		return nil, err
+	}
+	spec := map[string]interface{}{}
+	if err := yaml.Unmarshal(data, &spec); err != nil {
+		return nil, err
+	}
+	return spec, nil
+}
+
+func putServiceResource(w http.ResponseWriter, r *http.Request, name string, queue *JobQueue, registryConfig RegistryConfig) {
+	if name == "" || strings.ContainsAny(name, `/\`) || strings.HasPrefix(name, ".") {
+		http.Error(w, fmt.Sprintf("invalid service name: %s", name), http.StatusBadRequest)
+		return
+	}
+	spec := map[string]interface{}{}
+	if err := json.NewDecoder(r.Body).Decode(&spec); err != nil {
+		http.Error(w, "body must be the source.yml content as JSON", http.StatusBadRequest)
+		return
+	}
+	if specName, ok := spec["name"]; ok && specName != name {
+		http.Error(w, fmt.Sprintf("spec name %v does not match %s", specName, name), http.StatusBadRequest)
+		return
+	}
+	spec["name"] = name
+
+	// Update: giữ source_id cũ, cần quyền trên cả team hiện tại lẫn team mới
+	plan := ServicePlan{Name: name, Action: "create", Problems: []string{}, Warnings: []string{}}
+	servicePath := filepath.Join(sourcesDir, name)
+	existing, err := readSourceSpec(servicePath)
+	if err == nil {
+		plan.Action = "update"
+		if !requireRole(w, r, roleOperator, serviceTeam(name)) {
+			return
+		}
+		if _, ok := spec["source_id"]; !ok {
+			spec["source_id"] = existing["source_id"]
+		}
+	} else if _, ok := spec["source_id"]; !ok {
+		sourceID, err := newSourceID()
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		spec["source_id"] = sourceID
+	}
+
+	data, err := yaml.Marshal(spec)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	draft, err := validateDraftSource(name, data, registryConfig)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusBadRequest)
+		return
+	}
+	if !requireRole(w, r, roleOperator, draft.Config.Metadata.Team) {
+		return
+	}
+	result := draft.Result
+	plan.Problems = append(plan.Problems, result.Problems...)
+	plan.Warnings = append(plan.Warnings, result.Warnings...)
+	if plan.Action == "update" && reflect.DeepEqual(draft.Spec, existing) {
+		plan.Action = "none"
+	}
+
+	if r.URL.Query().Get("dry_run") == "true" {
+		writeJSONResponse(w, http.StatusOK, plan)
+		return
+	}
+	if !result.ok() {
+		writeJSONResponse(w, http.StatusUnprocessableEntity, plan)
+		return
+	}
+	if plan.Action != "none" {
+		if err := os.MkdirAll(servicePath, 0755); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+		if err := os.WriteFile(filepath.Join(servicePath, "source.yml"), data, 0644); err != nil {
+			http.Error(w, err.Error(), http.StatusInternalServerError)
+			return
+		}
+	}
+	// Spec không đổi vẫn enqueue: provisioning tự bỏ qua khi manifest trên repo đã khớp
+	if _, err := queue.enqueue(servicePath, registryConfig.Server.maxAttempts(), ""); err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	resource, err := readServiceResource(name, queue)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	status := http.StatusOK
+	if plan.Action == "create" {
+		status = http.StatusCreated
+	}
+	writeJSONResponse(w, status, resource)
+}
+
+// draftSource là source.yml chưa ghi vào sources-service, đã validate như lúc provisioning
+type draftSource struct {
+	Config SourceConfig
+	Spec   map[string]interface{} // nội dung sau khi parse lại, để so với bản đang có
+	Result ValidationResult
+}
+
+// validateDraftSource validate source.yml trong thư mục tạm (tên folder = name để check trùng tên đúng như trong sources-service)
+func validateDraftSource(name string, data []byte, registryConfig RegistryConfig) (draftSource, error) {
+	draftDir, err := os.MkdirTemp("", "jupiter-draft-*")
+	if err != nil {
+		return draftSource{}, err
+	}
+	defer os.RemoveAll(draftDir)
+	draftPath := filepath.Join(draftDir, name)
+	if err := os.MkdirAll(draftPath, 0755); err != nil {
+		return draftSource{}, err
+	}
+	if err := os.WriteFile(filepath.Join(draftPath, "source.yml"), data, 0644); err != nil {
+		return draftSource{}, err
+	}
+	config, err := loadSourceConfig(draftPath)
+	if err != nil {
+		return draftSource{}, err
+	}
+	spec, err := readSourceSpec(draftPath)
+	if err != nil {
+		return draftSource{}, err
+	}
+	return draftSource{Config: config, Spec: spec, Result: validateService(commandExecutor, draftPath, config, registryConfig)}, nil
+}
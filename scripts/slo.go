@@ -0,0 +1,284 @@
+package main
+
+import (
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path"
+	"strconv"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// SLO là block `slo:` trong source.yml
+type SLO struct {
+	Availability float64     `yaml:"availability,omitempty"` // % request không lỗi 5xx, vd. 99.9
+	Latency      *LatencySLO `yaml:"latency,omitempty"`
+	Window       string      `yaml:"window,omitempty"` // mặc định 30d
+}
+
+type LatencySLO struct {
+	Threshold string  `yaml:"threshold"` // vd. 300ms (phải trùng một bucket của histogram)
+	Target    float64 `yaml:"target"`    // % request nhanh hơn threshold, vd. 99
+}
+
+func (s SLO) empty() bool {
+	return s.Availability == 0 && s.Latency == nil
+}
+
+func (s SLO) window() string {
+	if s.Window == "" {
+		return "30d"
+	}
+	return s.Window
+}
+
+// SLOConfig (jupiter.yml slo:) cấu hình tên metric và nơi commit dashboard / rules
+type SLOConfig struct {
+	RequestsMetric string `yaml:"requests_metric"` // mặc định http_requests_total
+	DurationMetric string `yaml:"duration_metric"` // mặc định http_request_duration_seconds
+	ServiceLabel   string `yaml:"service_label"`   // mặc định service
+	StatusLabel    string `yaml:"status_label"`    // mặc định code
+	// MonitoringRepo: commit vào repo monitoring chung (owner/name) thay vì repo của service
+	MonitoringRepo string `yaml:"monitoring_repo"`
+	Dir            string `yaml:"dir"` // mặc định monitoring (trong repo service) / slo/<name> (monitoring repo)
+}
+
+func (c SLOConfig) withDefaults() SLOConfig {
+	if c.RequestsMetric == "" {
+		c.RequestsMetric = "http_requests_total"
+	}
+	if c.DurationMetric == "" {
+		c.DurationMetric = "http_request_duration_seconds"
+	}
+	if c.ServiceLabel == "" {
+		c.ServiceLabel = "service"
+	}
+	if c.StatusLabel == "" {
+		c.StatusLabel = "code"
+	}
+	return c
+}
+
+// dir là thư mục chứa rules/dashboard của service: monitoring/ trong repo service hoặc slo/<name> trong monitoring repo
+func (c SLOConfig) dir(name string) string {
+	if c.MonitoringRepo == "" {
+		if c.Dir == "" {
+			return "monitoring"
+		}
+		return c.Dir
+	}
+	if c.Dir == "" {
+		return path.Join("slo", name)
+	}
+	return path.Join(c.Dir, name)
+}
+
+// validateSLO chạy trong validateSourceConfig
+func validateSLO(s SLO) []string {
+	var problems []string
+	if s.Availability != 0 && (s.Availability <= 0 || s.Availability >= 100) {
+		problems = append(problems, fmt.Sprintf("slo.availability %v must be between 0 and 100 (exclusive)", s.Availability))
+	}
+	if s.Latency != nil {
+		if _, err := time.ParseDuration(s.Latency.Threshold); err != nil {
+			problems = append(problems, fmt.Sprintf("slo.latency.threshold %q is not a duration", s.Latency.Threshold))
+		}
+		if s.Latency.Target <= 0 || s.Latency.Target >= 100 {
+			problems = append(problems, fmt.Sprintf("slo.latency.target %v must be between 0 and 100 (exclusive)", s.Latency.Target))
+		}
+	}
+	if _, err := parsePromDuration(s.window()); err != nil {
+		problems = append(problems, fmt.Sprintf("slo.window %q must be a Prometheus duration like 30d or 4w", s.Window))
+	}
+	return problems
+}
+
+func parsePromDuration(d string) (time.Duration, error) {
+	if len(d) < 2 {
+		return 0, fmt.Errorf("invalid duration")
+	}
+	n, err := strconv.Atoi(d[:len(d)-1])
+	if err != nil {
+		return 0, err
+	}
+	unit := map[byte]time.Duration{'m': time.Minute, 'h': time.Hour, 'd': 24 * time.Hour, 'w': 7 * 24 * time.Hour}[d[len(d)-1]]
+	if unit == 0 {
+		return 0, fmt.Errorf("invalid unit")
+	}
+	return time.Duration(n) * unit, nil
+}
+
+type sloQueries struct {
+	Availability string // tỉ lệ request thành công trong window
+	Latency      string // tỉ lệ request dưới threshold trong window
+	ErrorRate    string // tỉ lệ lỗi 5m cho burn rate alert
+}
+
+func buildSLOQueries(name string, s SLO, c SLOConfig) sloQueries {
+	sel := fmt.Sprintf(`%s="%s"`, c.ServiceLabel, name)
+	errSel := fmt.Sprintf(`%s,%s=~"5.."`, sel, c.StatusLabel)
+	q := sloQueries{
+		Availability: fmt.Sprintf(`1 - (sum(rate(%s{%s}[%s])) / sum(rate(%s{%s}[%s])))`, c.RequestsMetric, errSel, s.window(), c.RequestsMetric, sel, s.window()),
+		ErrorRate:    fmt.Sprintf(`sum(rate(%s{%s}[5m])) / sum(rate(%s{%s}[5m]))`, c.RequestsMetric, errSel, c.RequestsMetric, sel),
+	}
+	if s.Latency != nil {
+		threshold, _ := time.ParseDuration(s.Latency.Threshold)
+		le := strconv.FormatFloat(threshold.Seconds(), 'f', -1, 64)
+		q.Latency = fmt.Sprintf(`sum(rate(%s_bucket{%s,le="%s"}[%s])) / sum(rate(%s_count{%s}[%s]))`,
+			c.DurationMetric, sel, le, s.window(), c.DurationMetric, sel, s.window())
+	}
+	return q
+}
+
+// sloAlertName: tên alert theo service (Prometheus không cho dấu "-" trong tên)
+func sloAlertName(prefix, name string) string {
+	return prefix + "_" + strings.ReplaceAll(name, "-", "_")
+}
+
+// sloPrometheusRules: recording rules + alert burn rate nhanh (14.4x trong 5m, theo SRE workbook)
+func sloPrometheusRules(name string, s SLO, c SLOConfig) ([]byte, error) {
+	q := buildSLOQueries(name, s, c)
+	var rules []map[string]interface{}
+	if s.Availability > 0 {
+		budget := 1 - s.Availability/100
+		rules = append(rules,
+			map[string]interface{}{"record": "slo:availability:ratio_" + s.window(), "expr": q.Availability, "labels": map[string]string{c.ServiceLabel: name}},
+			map[string]interface{}{
+				"alert":       sloAlertName("SLOErrorBudgetBurn", name),
+				"expr":        fmt.Sprintf("(%s) > %s", q.ErrorRate, formatRatio(14.4*budget)),
+				"for":         "5m",
+				"labels":      map[string]string{"severity": "page", c.ServiceLabel: name},
+				"annotations": map[string]string{"summary": fmt.Sprintf("%s is burning its %v%% availability error budget too fast", name, s.Availability)},
+			},
+		)
+	}
+	if s.Latency != nil {
+		rules = append(rules,
+			map[string]interface{}{"record": "slo:latency:ratio_" + s.window(), "expr": q.Latency, "labels": map[string]string{c.ServiceLabel: name}},
+			map[string]interface{}{
+				"alert":       sloAlertName("SLOLatency", name),
+				"expr":        fmt.Sprintf("(%s) < %s", q.Latency, formatRatio(s.Latency.Target/100)),
+				"for":         "15m",
+				"labels":      map[string]string{"severity": "ticket", c.ServiceLabel: name},
+				"annotations": map[string]string{"summary": fmt.Sprintf("%s: fewer than %v%% of requests under %s", name, s.Latency.Target, s.Latency.Threshold)},
+			},
+		)
+	}
+	return yaml.Marshal(map[string]interface{}{
+		"groups": []map[string]interface{}{{"name": name + "-slo", "rules": rules}},
+	})
+}
+
+// formatRatio làm tròn để tránh sai số float (0.014399999... -> 0.0144)
+func formatRatio(x float64) string {
+	return strconv.FormatFloat(math.Round(x*1e6)/1e6, 'f', -1, 64)
+}
+
+// sloDashboard: Grafana dashboard gồm stat SLI so với target và timeseries error rate
+func sloDashboard(name string, s SLO, c SLOConfig) ([]byte, error) {
+	q := buildSLOQueries(name, s, c)
+	stat := func(id int, title, expr string, target float64, x int) map[string]interface{} {
+		return map[string]interface{}{
+			"id": id, "type": "stat", "title": title,
+			"gridPos": map[string]int{"x": x, "y": 0, "w": 8, "h": 6},
+			"targets": []map[string]string{{"expr": expr, "refId": "A"}},
+			"fieldConfig": map[string]interface{}{"defaults": map[string]interface{}{
+				"unit": "percentunit", "decimals": 3,
+				"thresholds": map[string]interface{}{"mode": "absolute", "steps": []map[string]interface{}{
+					{"color": "red", "value": nil}, {"color": "green", "value": math.Round(target*1e4) / 1e6},
+				}},
+			}},
+		}
+	}
+
+	var panels []map[string]interface{}
+	if s.Availability > 0 {
+		panels = append(panels, stat(1, fmt.Sprintf("Availability (%s, target %v%%)", s.window(), s.Availability), q.Availability, s.Availability, 0))
+	}
+	if s.Latency != nil {
+		panels = append(panels, stat(2, fmt.Sprintf("Requests < %s (%s, target %v%%)", s.Latency.Threshold, s.window(), s.Latency.Target), q.Latency, s.Latency.Target, 8))
+	}
+	panels = append(panels, map[string]interface{}{
+		"id": 3, "type": "timeseries", "title": "Error rate (5m)",
+		"gridPos":     map[string]int{"x": 0, "y": 6, "w": 24, "h": 8},
+		"targets":     []map[string]string{{"expr": q.ErrorRate, "refId": "A"}},
+		"fieldConfig": map[string]interface{}{"defaults": map[string]string{"unit": "percentunit"}},
+	})
+
+	return json.MarshalIndent(map[string]interface{}{
+		"uid":           "slo-" + name,
+		"title":         name + " SLO",
+		"tags":          []string{"slo", "jupiter"},
+		"schemaVersion": 39,
+		"time":          map[string]string{"from": "now-" + s.window(), "to": "now"},
+		"panels":        panels,
+	}, "", "  ")
+}
+
+// sloFiles trả về path -> nội dung của rules và dashboard
+func sloFiles(name string, s SLO, c SLOConfig, dir string) (map[string][]byte, error) {
+	rules, err := sloPrometheusRules(name, s, c)
+	if err != nil {
+		return nil, err
+	}
+	dashboard, err := sloDashboard(name, s, c)
+	if err != nil {
+		return nil, err
+	}
+	return map[string][]byte{
+		path.Join(dir, "prometheus", "slo-rules.yaml"):  rules,
+		path.Join(dir, "grafana", "slo-dashboard.json"): append(dashboard, '\n'),
+	}, nil
+}
+
+// writeSLOFiles commit dashboard + rules vào repo service, hoặc monitoring repo chung nếu có cấu hình
+func writeSLOFiles(ex Executor, repoDir string, dto GeneratorSourceDto, cfg SLOConfig) error {
+	if dto.SLO.empty() {
+		return nil
+	}
+	c := cfg.withDefaults()
+
+	dir := c.dir(dto.AppName)
+	if c.MonitoringRepo == "" {
+		files, err := sloFiles(dto.AppName, dto.SLO, c, dir)
+		if err != nil {
+			return err
+		}
+		for p, data := range files {
+			if err := writeRepoFile(repoDir, p, data); err != nil {
+				return err
+			}
+		}
+		fmt.Printf("📈 SLO rules and dashboard written to %s/\n", dir)
+		return nil
+	}
+
+	files, err := sloFiles(dto.AppName, dto.SLO, c, dir)
+	if err != nil {
+		return err
+	}
+	for p, data := range files {
+		if err := putRepoContent(ex, c.MonitoringRepo, p, data, fmt.Sprintf("chore(slo): update %s", dto.AppName)); err != nil {
+			return fmt.Errorf("failed to commit %s to %s: %w", p, c.MonitoringRepo, err)
+		}
+	}
+	fmt.Printf("📈 SLO rules and dashboard committed to %s\n", c.MonitoringRepo)
+	return nil
+}
+
+// putRepoContent tạo/cập nhật một file qua contents API (cần sha của file cũ khi cập nhật)
+func putRepoContent(ex Executor, repo, filePath string, data []byte, message string) error {
+	body := map[string]string{"message": message, "content": base64.StdEncoding.EncodeToString(data)}
+	if sha, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("repos/%s/contents/%s", repo, filePath), "--jq", ".sha"); err == nil && strings.TrimSpace(sha) != "" {
+		body["sha"] = strings.TrimSpace(sha)
+	}
+	payload, err := json.Marshal(body)
+	if err != nil {
+		return err
+	}
+	return runCommandWithInput(ex, payload, "gh", "api", "-X", "PUT", fmt.Sprintf("repos/%s/contents/%s", repo, filePath), "--input", "-")
+}
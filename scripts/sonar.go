@@ -0,0 +1,97 @@
+package main
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Quality là block `quality:` trong source.yml
+type Quality struct {
+	Sonar bool `yaml:"sonar,omitempty"`
+}
+
+// Sonar là server SonarQube dùng cho service có quality.sonar: true
+type Sonar struct {
+	URL       string `yaml:"url"`
+	TokenEnv  string `yaml:"token_env"`  // token admin tạo project / token phân tích
+	KeyPrefix string `yaml:"key_prefix"` // project key = <prefix><name>
+	Required  bool   `yaml:"required"`
+}
+
+type sonarTemplateData struct {
+	Service    GeneratorSourceDto
+	ProjectKey string
+	Branch     string
+}
+
+func (s Sonar) projectKey(name string) string {
+	return s.KeyPrefix + name
+}
+
+// provisionSonarProject tạo project (nếu chưa có), cấp token phân tích thành secret và overlay workflow scan
+func provisionSonarProject(ex Executor, repoDir string, dto GeneratorSourceDto, s Sonar, templatesDir string) error {
+	if s.URL == "" {
+		return fmt.Errorf("sonar.url is not configured")
+	}
+	token := os.Getenv(s.TokenEnv)
+	if token == "" {
+		return fmt.Errorf("sonar token $%s is not set", s.TokenEnv)
+	}
+	base := strings.TrimSuffix(s.URL, "/")
+	key := s.projectKey(dto.AppName)
+
+	sonarRequest := func(method, path string, params url.Values, out interface{}) error {
+		req, err := http.NewRequest(method, base+path+"?"+params.Encode(), nil)
+		if err != nil {
+			return err
+		}
+		req.SetBasicAuth(token, "")
+		return doJSONRequest(req, out)
+	}
+
+	var search struct {
+		Components []struct {
+			Key string `json:"key"`
+		} `json:"components"`
+	}
+	if err := sonarRequest(http.MethodGet, "/api/projects/search", url.Values{"projects": {key}}, &search); err != nil {
+		return fmt.Errorf("failed to look up sonar project: %w", err)
+	}
+	if len(search.Components) == 0 {
+		var created struct{}
+		if err := sonarRequest(http.MethodPost, "/api/projects/create", url.Values{
+			"project": {key}, "name": {dto.AppName}, "mainBranch": {dto.branch()},
+		}, &created); err != nil {
+			return fmt.Errorf("failed to create sonar project: %w", err)
+		}
+	} else {
+		fmt.Printf("  ℹ️ Sonar project %s already exists\n", key)
+	}
+
+	// Token cùng tên không generate lại được, revoke token cũ (lần provisioning trước) nếu có
+	_ = sonarRequest(http.MethodPost, "/api/user_tokens/revoke", url.Values{"name": {dto.AppName + "-ci"}}, &struct{}{})
+
+	var generated struct {
+		Token string `json:"token"`
+	}
+	if err := sonarRequest(http.MethodPost, "/api/user_tokens/generate", url.Values{
+		"name": {dto.AppName + "-ci"}, "type": {"PROJECT_ANALYSIS_TOKEN"}, "projectKey": {key},
+	}, &generated); err != nil {
+		return fmt.Errorf("failed to generate analysis token: %w", err)
+	}
+	registerSecret(generated.Token)
+
+	fullName := fmt.Sprintf("%s/%s", dto.Owner, dto.AppName)
+	for name, value := range map[string]string{"SONAR_TOKEN": generated.Token, "SONAR_HOST_URL": base} {
+		if err := runCommandWithInput(ex, []byte(value), "gh", "secret", "set", name, "--repo", fullName); err != nil {
+			return fmt.Errorf("failed to set %s: %w", name, err)
+		}
+	}
+
+	return renderTemplateDir(filepath.Join(templatesDir, "quality", "sonar"), repoDir,
+		sonarTemplateData{Service: dto, ProjectKey: key, Branch: dto.branch()})
+}
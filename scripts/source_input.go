@@ -0,0 +1,90 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+)
+
+// maxSourceInputBytes giới hạn source.yml đọc từ stdin / URL
+const maxSourceInputBytes = 1 << 20
+
+// sourceTokenEnv: bearer token khi tải source.yml từ URL cần auth (vd. raw content của repo private)
+const sourceTokenEnv = "JUPITER_SOURCE_TOKEN"
+
+// isRemoteSource: tham số của generate là "-" (stdin) hoặc https:// URL thay vì thư mục service
+func isRemoteSource(arg string) bool {
+	return arg == "-" || strings.HasPrefix(arg, "https://")
+}
+
+// materializeSource ghi source.yml từ stdin / URL vào thư mục tạm ngoài registry checkout
+// để phần còn lại của pipeline vẫn làm việc với <servicePath>/source.yml. cleanup xoá thư mục tạm.
+func materializeSource(arg string) (string, func(), error) {
+	var data []byte
+	var err error
+	if arg == "-" {
+		data, err = io.ReadAll(io.LimitReader(os.Stdin, maxSourceInputBytes+1))
+		if err != nil {
+			return "", nil, withCode(ErrSourceNotFound, fmt.Errorf("failed to read source definition from stdin: %w", err))
+		}
+	} else {
+		data, err = fetchSource(arg)
+		if err != nil {
+			return "", nil, err
+		}
+	}
+	if len(data) > maxSourceInputBytes {
+		return "", nil, withCode(ErrValidationFailed, fmt.Errorf("source definition from %s exceeds %d bytes", arg, maxSourceInputBytes))
+	}
+	if len(strings.TrimSpace(string(data))) == 0 {
+		return "", nil, withCode(ErrSourceNotFound, fmt.Errorf("source definition from %s is empty", arg))
+	}
+
+	var config SourceConfig
+	if err := yaml.Unmarshal(data, &config); err != nil {
+		return "", nil, withCode(ErrYAMLInvalid, fmt.Errorf("error parsing source definition from %s: %w", arg, err))
+	}
+	// Tên thư mục = name để collision check coi đây là cùng service với sources-service/<name>
+	folder := config.Name
+	if folder == "" || strings.ContainsAny(folder, `/\`) || strings.HasPrefix(folder, ".") {
+		folder = "source"
+	}
+
+	tmp, err := os.MkdirTemp("", "jupiter-source-")
+	if err != nil {
+		return "", nil, err
+	}
+	cleanup := func() { os.RemoveAll(tmp) }
+	servicePath := filepath.Join(tmp, folder)
+	if err := writeRepoFile(servicePath, "source.yml", data); err != nil {
+		cleanup()
+		return "", nil, err
+	}
+	return servicePath, cleanup, nil
+}
+
+func fetchSource(url string) ([]byte, error) {
+	req, err := http.NewRequest(http.MethodGet, url, nil)
+	if err != nil {
+		return nil, withCode(ErrUsage, fmt.Errorf("invalid source URL %s: %w", url, err))
+	}
+	if token := os.Getenv(sourceTokenEnv); token != "" {
+		req.Header.Set("Authorization", "Bearer "+token)
+	}
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, withCode(ErrSourceNotFound, fmt.Errorf("failed to fetch source definition: %w", err))
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, withCode(ErrSourceNotFound, fmt.Errorf("failed to fetch source definition from %s: HTTP %d", url, resp.StatusCode))
+	}
+	return io.ReadAll(io.LimitReader(resp.Body, maxSourceInputBytes+1))
+}
@@ -0,0 +1,128 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"sync"
+	"time"
+)
+
+// registryStateFile lưu trạng thái các service đã được registry quản lý.
+// File được commit lại vào jupiter-registry cùng với catalog sau mỗi lần chạy.
+const registryStateFile = "state/services.json"
+
+// ServiceState là trạng thái của một service (key theo tên service)
+type ServiceState struct {
+	SourceID  string `json:"source_id"`
+	Repo      string `json:"repo"`
+	Origin    string `json:"origin"` // provisioned | adopted
+	UpdatedAt string `json:"updated_at"`
+
+	// Kết quả lần generate gần nhất (badge / catalog)
+	LastStatus      string `json:"last_status,omitempty"` // success | failed
+	TemplateVersion string `json:"template_version,omitempty"`
+	Hostname        string `json:"hostname,omitempty"`
+	OnCallProvider  string `json:"on_call_provider,omitempty"`
+	OnCallServiceID string `json:"on_call_service_id,omitempty"`
+	// ArchivedAt: thời điểm archive, restore được trong destructive.recycle_days
+	ArchivedAt string `json:"archived_at,omitempty"`
+	// Repository: metadata canonical đọc lại từ GitHub (ID, default branch, clone URL, created_at)
+	Repository *RepoMetadata `json:"repository,omitempty"`
+	// Artifact: OCI artifact của lần generate gần nhất (oci.registry)
+	Artifact *ScaffoldArtifact `json:"artifact,omitempty"`
+}
+
+type RegistryState struct {
+	Services map[string]*ServiceState `json:"services"`
+}
+
+func loadRegistryState(path string) (*RegistryState, error) {
+	state := &RegistryState{Services: map[string]*ServiceState{}}
+
+	data, err := os.ReadFile(path)
+	if os.IsNotExist(err) {
+		return state, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to read state %s: %w", path, err)
+	}
+	if err := json.Unmarshal(data, state); err != nil {
+		return nil, fmt.Errorf("failed to parse state %s: %w", path, err)
+	}
+	if state.Services == nil {
+		state.Services = map[string]*ServiceState{}
+	}
+	return state, nil
+}
+
+func (s *RegistryState) save(path string) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+		return fmt.Errorf("failed to create state dir: %w", err)
+	}
+	data, err := json.MarshalIndent(s, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// stateMu serialize read-modify-write state file khi batch chạy song song
+var stateMu sync.Mutex
+
+// recordServiceState ghi (hoặc cập nhật) state entry của service rồi lưu file
+func recordServiceState(name string, entry ServiceState) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	entry.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	state.Services[name] = &entry
+	return state.save(registryStateFile)
+}
+
+// recordGenerationFailure đánh dấu lần generate gần nhất thất bại, giữ nguyên phần còn lại của entry
+func recordGenerationFailure(name string, entry ServiceState) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	if existing, ok := state.Services[name]; ok {
+		entry.Origin = existing.Origin
+		entry.TemplateVersion = existing.TemplateVersion
+		entry.Hostname = existing.Hostname
+		entry.OnCallProvider = existing.OnCallProvider
+		entry.OnCallServiceID = existing.OnCallServiceID
+		entry.Repository = existing.Repository
+		entry.Artifact = existing.Artifact
+	}
+	entry.LastStatus = "failed"
+	entry.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	state.Services[name] = &entry
+	return state.save(registryStateFile)
+}
+
+// updateServiceState sửa entry có sẵn của service (archive / restore / delete)
+func updateServiceState(name string, update func(*ServiceState)) error {
+	stateMu.Lock()
+	defer stateMu.Unlock()
+
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return err
+	}
+	entry, ok := state.Services[name]
+	if !ok {
+		return fmt.Errorf("service %s has no state entry", name)
+	}
+	update(entry)
+	entry.UpdatedAt = time.Now().UTC().Format(time.RFC3339)
+	return state.save(registryStateFile)
+}
@@ -0,0 +1,113 @@
+package main
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"gopkg.in/yaml.v3"
+)
+
+// unknownFieldPattern khớp lỗi KnownFields của yaml.v3: "line 3: field metdata not found in type main.SourceConfig"
+var unknownFieldPattern = regexp.MustCompile(`^line (\d+): field (\S+) not found in type (\S+)$`)
+
+// validateSourceKeys parse lại source.yml với KnownFields để bắt key lạ / viết sai
+// (vd. metdata:), vốn bị yaml.Unmarshal bỏ qua và sinh ra repo không dùng được
+func validateSourceKeys(servicePath string) []string {
+	data, err := os.ReadFile(filepath.Join(servicePath, "source.yml"))
+	if err != nil {
+		return nil // loadSourceConfig đã báo lỗi đọc file
+	}
+
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	var config SourceConfig
+	err = decoder.Decode(&config)
+
+	var typeErr *yaml.TypeError
+	if err == nil || !errors.As(err, &typeErr) {
+		return nil
+	}
+
+	fields := knownYAMLFields(reflect.TypeOf(SourceConfig{}))
+	var problems []string
+	for _, msg := range typeErr.Errors {
+		m := unknownFieldPattern.FindStringSubmatch(msg)
+		if m == nil {
+			continue // lỗi sai kiểu đã bị loadSourceConfig báo
+		}
+		problem := fmt.Sprintf("line %s: unknown key '%s'", m[1], m[2])
+		if suggestion := closestField(m[2], fields[m[3]]); suggestion != "" {
+			problem += fmt.Sprintf(" (did you mean '%s'?)", suggestion)
+		}
+		problems = append(problems, problem)
+	}
+	return problems
+}
+
+// knownYAMLFields trả về yaml key hợp lệ của từng struct (key theo tên type trong lỗi yaml.v3, vd. main.Metadata)
+func knownYAMLFields(t reflect.Type) map[string][]string {
+	result := map[string][]string{}
+	var walk func(t reflect.Type)
+	walk = func(t reflect.Type) {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Map {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || result[t.String()] != nil {
+			return
+		}
+		names := []string{}
+		result[t.String()] = names
+		for i := 0; i < t.NumField(); i++ {
+			f := t.Field(i)
+			name := strings.Split(f.Tag.Get("yaml"), ",")[0]
+			if name == "-" || !f.IsExported() {
+				continue
+			}
+			if name == "" {
+				name = strings.ToLower(f.Name)
+			}
+			names = append(names, name)
+			walk(f.Type)
+		}
+		result[t.String()] = names
+	}
+	walk(t)
+	return result
+}
+
+// closestField gợi ý key gần nhất theo edit distance (bỏ qua khi khác quá nhiều)
+func closestField(key string, candidates []string) string {
+	best, bestDistance := "", len(key)/2+1
+	for _, c := range candidates {
+		if d := editDistance(key, c); d < bestDistance {
+			best, bestDistance = c, d
+		}
+	}
+	return best
+}
+
+func editDistance(a, b string) int {
+	prev := make([]int, len(b)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(a); i++ {
+		cur := make([]int, len(b)+1)
+		cur[0] = i
+		for j := 1; j <= len(b); j++ {
+			cost := 1
+			if a[i-1] == b[j-1] {
+				cost = 0
+			}
+			cur[j] = min(prev[j]+1, cur[j-1]+1, prev[j-1]+cost)
+		}
+		prev = cur
+	}
+	return prev[len(b)]
+}
@@ -0,0 +1,169 @@
+package main
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"os"
+	"strings"
+	"sync"
+	"time"
+)
+
+// Telemetry: usage report ẩn danh (opt-in) để platform team biết generator path nào được dùng.
+// Chỉ gửi khi enabled: true và có endpoint; JUPITER_TELEMETRY=off hoặc DO_NOT_TRACK=1 tắt cho từng lần chạy.
+// Payload không chứa tên service, repo, team hay error message: chỉ command, số đếm language/framework/kind,
+// duration và error code.
+type Telemetry struct {
+	Enabled  bool   `yaml:"enabled"`
+	Endpoint string `yaml:"endpoint"`
+	// TokenEnv: env chứa bearer token cho endpoint (tuỳ chọn)
+	TokenEnv string `yaml:"token_env"`
+}
+
+func (t Telemetry) active() bool {
+	if !t.Enabled || t.Endpoint == "" {
+		return false
+	}
+	if os.Getenv("DO_NOT_TRACK") == "1" {
+		return false
+	}
+	switch strings.ToLower(os.Getenv("JUPITER_TELEMETRY")) {
+	case "0", "off", "false":
+		return false
+	}
+	return true
+}
+
+// TelemetryReport là payload gửi một lần khi process kết thúc
+type TelemetryReport struct {
+	// Installation: hash của registry repo, phân biệt các registry mà không lộ tên org
+	Installation string         `json:"installation"`
+	Command      string         `json:"command"`
+	Result       string         `json:"result"`
+	ErrorCode    string         `json:"error_code,omitempty"`
+	DurationMS   int64          `json:"duration_ms"`
+	Generated    int            `json:"generated"`
+	Failed       int            `json:"failed"`
+	Languages    map[string]int `json:"languages"`
+	Frameworks   map[string]int `json:"frameworks"`
+	Kinds        map[string]int `json:"kinds"`
+	Errors       map[string]int `json:"errors"` // error code -> số service fail
+	Timestamp    string         `json:"timestamp"`
+}
+
+type telemetryCollector struct {
+	cfg     Telemetry
+	started time.Time
+	once    sync.Once
+
+	mu     sync.Mutex
+	report TelemetryReport
+}
+
+// telemetry != nil khi telemetry đã được bật trong jupiter.yml
+var telemetry *telemetryCollector
+
+// startTelemetry bắt đầu đo command hiện tại và đếm service generate / fail qua event bus
+func startTelemetry(cfg Telemetry, command string) {
+	if !cfg.active() {
+		return
+	}
+	sum := sha256.Sum256([]byte(registryRepo))
+	telemetry = &telemetryCollector{cfg: cfg, started: time.Now(), report: TelemetryReport{
+		Installation: hex.EncodeToString(sum[:8]),
+		Command:      command,
+		Languages:    map[string]int{},
+		Frameworks:   map[string]int{},
+		Kinds:        map[string]int{},
+		Errors:       map[string]int{},
+	}}
+	events.Subscribe("telemetry", telemetry.record, EventServiceGenerated, EventServiceFailed)
+}
+
+// telemetryCommand: tên subcommand, hoặc generate / dry-run cho flow generate mặc định
+func telemetryCommand(args []string) string {
+	if len(args) > 0 {
+		if _, ok := subcommands[args[0]]; ok {
+			return args[0]
+		}
+	}
+	for _, arg := range args {
+		if arg == "--dry-run" || arg == "-dry-run" {
+			return "dry-run"
+		}
+	}
+	return "generate"
+}
+
+func (t *telemetryCollector) record(e Event) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if e.Type == EventServiceFailed {
+		t.report.Failed++
+		t.report.Errors[e.ErrorCode]++
+	} else {
+		t.report.Generated++
+	}
+	if e.DTO != nil {
+		kind := e.DTO.Kind
+		if kind == "" {
+			kind = "service"
+		}
+		t.report.Kinds[kind]++
+		if e.DTO.ProgrammingLanguage != "" {
+			t.report.Languages[e.DTO.ProgrammingLanguage]++
+		}
+		if e.DTO.Framework != "" {
+			t.report.Frameworks[e.DTO.ProgrammingLanguage+"/"+e.DTO.Framework]++
+		}
+	}
+	return nil
+}
+
+// finishTelemetry gửi report một lần (main và exitWithError đều gọi). Lỗi gửi bị bỏ qua:
+// telemetry không bao giờ làm chậm hay fail run.
+func finishTelemetry(err error) {
+	if telemetry == nil {
+		return
+	}
+	telemetry.once.Do(func() {
+		telemetry.mu.Lock()
+		report := telemetry.report
+		telemetry.mu.Unlock()
+
+		report.Result = "success"
+		if err != nil {
+			report.Result = "failed"
+			report.ErrorCode = string(errorCode(err))
+		}
+		report.DurationMS = time.Since(telemetry.started).Milliseconds()
+		report.Timestamp = time.Now().UTC().Format(time.RFC3339)
+		postTelemetry(telemetry.cfg, report)
+	})
+}
+
+func postTelemetry(cfg Telemetry, report TelemetryReport) {
+	payload, err := json.Marshal(report)
+	if err != nil {
+		return
+	}
+	req, err := http.NewRequest(http.MethodPost, cfg.Endpoint, bytes.NewReader(payload))
+	if err != nil {
+		return
+	}
+	req.Header.Set("Content-Type", "application/json")
+	if cfg.TokenEnv != "" {
+		if token := os.Getenv(cfg.TokenEnv); token != "" {
+			req.Header.Set("Authorization", "Bearer "+token)
+		}
+	}
+	client := &http.Client{Timeout: 5 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return
+	}
+	resp.Body.Close()
+}
@@ -0,0 +1,396 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"text/template"
+)
+
+// goldenDir chứa output đã render của từng combination, commit để CI diff
+const goldenDir = "testdata/golden"
+
+// goldenCase là một combination language/framework/kind (hoặc layer overlay) render được hoàn toàn từ templates.
+// uranus và các CLI scaffold (nest new, create-vite, create-next-app) không nằm trong golden vì phụ thuộc tool ngoài.
+type goldenCase struct {
+	Name   string
+	Render func(destDir string) error
+}
+
+// goldenService là service mẫu cố định để output golden ổn định
+func goldenService(language, framework, kind string) GeneratorSourceDto {
+	return GeneratorSourceDto{
+		AppName:             "golden-app",
+		Owner:               repoOwner,
+		Kind:                kind,
+		ProgrammingLanguage: language,
+		Framework:           framework,
+		Team:                "platform",
+		CostCenter:          "cc-000",
+		Members:             []string{"tqhuy1996"},
+		Visibility:          "private",
+		Branch:              "main",
+		Schedule:            "0 3 * * *",
+		Deploy:              Deploy{Region: "ap-southeast-1", Account: "000000000000"},
+	}
+}
+
+func goldenCases(registryConfig RegistryConfig) []goldenCase {
+	templatesDir := registryConfig.FrameworkTemplates
+	var cases []goldenCase
+
+	for _, framework := range golangFrameworks {
+		if framework == "uranus" {
+			continue
+		}
+		dto := goldenService("golang", framework, "service")
+		cases = append(cases, goldenCase{Name: "golang/" + framework, Render: func(dest string) error {
+			return renderTemplateDir(filepath.Join(templatesDir, "golang", dto.Framework), dest, golangTemplateData{Service: dto, Module: goModulePath(dto)})
+		}})
+	}
+	// Module tuỳ chỉnh (metadata.module) để bắt template hard-code github.com/<owner>
+	custom := goldenService("golang", "gin", "service")
+	custom.Module = "go.example.com/platform/golden-app"
+	cases = append(cases, goldenCase{Name: "golang/gin-custom-module", Render: func(dest string) error {
+		return renderTemplateDir(filepath.Join(templatesDir, "golang", custom.Framework), dest, golangTemplateData{Service: custom, Module: goModulePath(custom)})
+	}})
+
+	for _, framework := range nodejsFrameworks {
+		dto := goldenService("nodejs", framework, "service")
+		cases = append(cases, goldenCase{Name: "nodejs/" + framework, Render: func(dest string) error {
+			return renderTemplateDir(filepath.Join(templatesDir, "nodejs", dto.Framework), dest, nodeTemplateData{Service: dto, PackageName: nodePackageName(dto)})
+		}})
+	}
+
+	for _, framework := range frontendFrameworks {
+		dto := goldenService("nodejs", framework, "frontend")
+		cases = append(cases, goldenCase{Name: "frontend/" + framework, Render: func(dest string) error {
+			return renderTemplateDir(filepath.Join(templatesDir, "frontend", dto.Framework), dest, dto)
+		}})
+	}
+
+	for _, kind := range supportedKinds {
+		if kind == "service" || kind == "frontend" {
+			continue
+		}
+		for _, language := range supportedLanguages {
+			dto := goldenService(language, "", kind)
+			data := kindTemplateData{Service: dto, PackageName: goPackageName(dto.AppName), Publish: registryConfig.Publishing.target(dto)}
+			if language == "golang" {
+				data.Module = goModulePath(dto)
+			} else {
+				data.Module = nodePackageName(dto)
+			}
+			srcDir := filepath.Join(templatesDir, kind, language)
+			cases = append(cases, goldenCase{Name: kind + "/" + language, Render: func(dest string) error {
+				return renderTemplateDir(srcDir, dest, data)
+			}})
+		}
+	}
+
+	// Overlay dùng chung cho mọi repo: cloud scaffold, .github, community files
+	for _, cloud := range supportedClouds {
+		dto := goldenService("golang", "gin", "service")
+		dto.Deploy.Cloud = cloud
+		cases = append(cases, goldenCase{Name: "cloud/" + cloud, Render: func(dest string) error {
+			return writeCloudScaffold(dest, dto, registryConfig.CloudTemplates)
+		}})
+	}
+	withHostname := goldenService("golang", "gin", "service")
+	withHostname.Deploy.Cloud = "aws"
+	withHostname.Hostname = "golden-app.internal.example.com"
+	cases = append(cases, goldenCase{Name: "cloud/aws-hostname", Render: func(dest string) error {
+		return writeCloudScaffold(dest, withHostname, registryConfig.CloudTemplates)
+	}})
+	for _, language := range []string{"golang", "nodejs"} {
+		srcDir := filepath.Join(templatesDir, "error_tracking", "sentry", language)
+		service := goldenService(language, "", "service")
+		cases = append(cases, goldenCase{Name: "error_tracking/sentry-" + language, Render: func(dest string) error {
+			return renderTemplateDir(srcDir, dest, errorTrackingData{Service: service})
+		}})
+	}
+	for _, language := range supportedLanguages {
+		srcDir := filepath.Join(templatesDir, "quality", "sonar")
+		service := goldenService(language, "", "service")
+		cases = append(cases, goldenCase{Name: "quality/sonar-" + language, Render: func(dest string) error {
+			return renderTemplateDir(srcDir, dest, sonarTemplateData{Service: service, ProjectKey: "tqhuy-dev_golden-app", Branch: service.branch()})
+		}})
+	}
+	contract := []ConfigVar{
+		{Name: "DATABASE_URL", Type: "url", Required: true, Description: "Postgres connection string", Secret: true},
+		{Name: "HTTP_PORT", Type: "int", Default: "8080"},
+		{Name: "REQUEST_TIMEOUT", Type: "duration", Default: "5s"},
+		{Name: "FEATURE_X_ENABLED", Type: "bool", Default: "false"},
+		{Name: "LOG_LEVEL"},
+	}
+	for _, variant := range []string{"golang", "nodejs", "typescript"} {
+		service := goldenService("golang", "", "service")
+		if variant != "golang" {
+			service.ProgrammingLanguage = "nodejs"
+		}
+		service.Config = contract
+		variant := variant
+		cases = append(cases, goldenCase{Name: "config_contract/" + variant, Render: func(dest string) error {
+			return renderConfigContract(dest, service, templatesDir, variant)
+		}})
+	}
+	sloService := goldenService("golang", "gin", "service")
+	sloService.SLO = SLO{Availability: 99.9, Latency: &LatencySLO{Threshold: "300ms", Target: 99}}
+	cases = append(cases, goldenCase{Name: "slo/default", Render: func(dest string) error {
+		return writeSLOFiles(commandExecutor, dest, sloService, SLOConfig{})
+	}})
+	for _, c := range []struct{ cloud, language string }{{"aws", "golang"}, {"gcp", "nodejs"}, {"azure", "golang"}} {
+		piiService := goldenService(c.language, "", "service")
+		piiService.DataClassification = "pii"
+		piiService.Deploy.Cloud = c.cloud
+		cases = append(cases, goldenCase{Name: "data_classification/pii-" + c.cloud + "-" + c.language, Render: func(dest string) error {
+			return writeDataProtection(dest, piiService, templatesDir)
+		}})
+	}
+	runbookService := sloService
+	runbookService.Tier = 1
+	runbookService.Hostname = "golden-app.svc.tqhuy.dev"
+	cases = append(cases, goldenCase{Name: "runbook/tier1", Render: func(dest string) error {
+		rc := registryConfig
+		rc.Runbook.Templates = filepath.Join(templatesDir, "runbook")
+		rc.OnCall = OnCall{Provider: "pagerduty"}
+		return writeRunbook(dest, runbookService, rc)
+	}})
+	// docs_language: vi render bản dịch trong i18n/vi đè lên template gốc
+	runbookVi := runbookService
+	runbookVi.DocsLanguage = "vi"
+	cases = append(cases, goldenCase{Name: "runbook/tier1-vi", Render: func(dest string) error {
+		rc := registryConfig
+		rc.Runbook.Templates = filepath.Join(templatesDir, "runbook")
+		rc.OnCall = OnCall{Provider: "pagerduty"}
+		return writeRunbook(dest, runbookVi, rc)
+	}})
+	dto := goldenService("golang", "gin", "service")
+	dtoVi := dto
+	dtoVi.DocsLanguage = "vi"
+	cases = append(cases,
+		goldenCase{Name: "container/ghcr", Render: func(dest string) error {
+			return provisionContainerRepository(commandExecutor, dest, dto, ContainerRegistry{Type: "ghcr"}, templatesDir)
+		}},
+		goldenCase{Name: "github/default", Render: func(dest string) error {
+			return writeGithubTemplates(dest, dto, registryConfig.GithubTemplates)
+		}},
+		goldenCase{Name: "community/golang", Render: func(dest string) error {
+			return writeCommunityFiles(dest, dto, registryConfig.CommunityFiles, registryConfig.Server)
+		}},
+		goldenCase{Name: "github/default-vi", Render: func(dest string) error {
+			return writeGithubTemplates(dest, dtoVi, registryConfig.GithubTemplates)
+		}},
+		goldenCase{Name: "community/golang-vi", Render: func(dest string) error {
+			return writeCommunityFiles(dest, dtoVi, registryConfig.CommunityFiles, registryConfig.Server)
+		}},
+	)
+	return cases
+}
+
+// runTemplateCommand: `template lint` (parse mọi template) và `template test [--update]` (so với golden)
+func runTemplateCommand(args []string) error {
+	if len(args) == 0 {
+		return withCode(ErrUsage, fmt.Errorf("usage: template lint | template test [--update] [--golden dir] [case...]"))
+	}
+
+	registryConfig, err := loadRegistryConfig(registryConfigFile)
+	if err != nil {
+		return err
+	}
+
+	switch args[0] {
+	case "lint":
+		return lintTemplates(registryConfig)
+	case "test":
+		fs := flag.NewFlagSet("template test", flag.ExitOnError)
+		update := fs.Bool("update", false, "rewrite golden directories with the current output")
+		dir := fs.String("golden", goldenDir, "golden directory")
+		fs.Parse(args[1:])
+		return testTemplates(registryConfig, *dir, *update, fs.Args())
+	default:
+		return withCode(ErrUsage, fmt.Errorf("unknown template command: %s", args[0]))
+	}
+}
+
+// lintTemplates parse mọi file trong các thư mục template (renderTemplateFile coi mọi file là template)
+func lintTemplates(registryConfig RegistryConfig) error {
+	dirs := []string{registryConfig.FrameworkTemplates, registryConfig.CloudTemplates, registryConfig.CommunityFiles.Templates}
+	checked, problems := 0, 0
+	seen := map[string]bool{}
+	for _, dir := range dirs {
+		if dir == "" {
+			continue
+		}
+		err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || seen[path] {
+				return err
+			}
+			seen[path] = true
+			checked++
+			content, err := os.ReadFile(path)
+			if err != nil {
+				return err
+			}
+			if _, err := template.New(filepath.Base(path)).Option("missingkey=error").Parse(string(content)); err != nil {
+				problems++
+				fmt.Printf("❌ %v\n", err)
+			}
+			return nil
+		})
+		if err != nil {
+			return err
+		}
+	}
+
+	if problems > 0 {
+		return withCode(ErrValidationFailed, fmt.Errorf("%d of %d template(s) failed to parse", problems, checked))
+	}
+	fmt.Printf("✅ %d template(s) parsed\n", checked)
+	return nil
+}
+
+func testTemplates(registryConfig RegistryConfig, dir string, update bool, only []string) error {
+	failed := 0
+	for _, c := range goldenCases(registryConfig) {
+		if len(only) > 0 && !containsString(only, c.Name) {
+			continue
+		}
+
+		tmp, err := os.MkdirTemp("", "jupiter-golden-")
+		if err != nil {
+			return err
+		}
+		actual := filepath.Join(tmp, "out")
+		if err := c.Render(actual); err != nil {
+			os.RemoveAll(tmp)
+			failed++
+			fmt.Printf("❌ %s: render failed: %v\n", c.Name, err)
+			continue
+		}
+
+		golden := filepath.Join(dir, filepath.FromSlash(c.Name))
+		if update {
+			err = replaceDir(actual, golden)
+			os.RemoveAll(tmp)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("📝 %s: golden updated\n", c.Name)
+			continue
+		}
+
+		diffs, err := diffTrees(golden, actual)
+		os.RemoveAll(tmp)
+		if err != nil {
+			return err
+		}
+		if len(diffs) == 0 {
+			fmt.Printf("✅ %s\n", c.Name)
+			continue
+		}
+		failed++
+		fmt.Printf("❌ %s differs from %s:\n", c.Name, golden)
+		for _, d := range diffs {
+			fmt.Printf("  %s\n", d)
+		}
+	}
+
+	if failed > 0 {
+		return withCode(ErrValidationFailed, fmt.Errorf("%d template case(s) failed, run `template test --update` if the change is intended", failed))
+	}
+	return nil
+}
+
+// replaceDir thay nội dung golden bằng output mới (xoá file cũ không còn được generate)
+func replaceDir(src, dest string) error {
+	if err := os.RemoveAll(dest); err != nil {
+		return err
+	}
+	if err := os.MkdirAll(filepath.Dir(dest), 0755); err != nil {
+		return err
+	}
+	return os.Rename(src, dest)
+}
+
+// diffTrees so 2 thư mục: file thiếu (-), file thừa (+), file khác nội dung (~ kèm dòng đầu tiên khác)
+func diffTrees(expectedDir, actualDir string) ([]string, error) {
+	expected, err := readTree(expectedDir)
+	if err != nil {
+		return nil, err
+	}
+	actual, err := readTree(actualDir)
+	if err != nil {
+		return nil, err
+	}
+
+	paths := map[string]bool{}
+	for p := range expected {
+		paths[p] = true
+	}
+	for p := range actual {
+		paths[p] = true
+	}
+	sorted := make([]string, 0, len(paths))
+	for p := range paths {
+		sorted = append(sorted, p)
+	}
+	sort.Strings(sorted)
+
+	var diffs []string
+	for _, p := range sorted {
+		want, inExpected := expected[p]
+		got, inActual := actual[p]
+		switch {
+		case !inActual:
+			diffs = append(diffs, "- "+p)
+		case !inExpected:
+			diffs = append(diffs, "+ "+p)
+		case !bytes.Equal(want, got):
+			diffs = append(diffs, fmt.Sprintf("~ %s (%s)", p, firstDifference(string(want), string(got))))
+		}
+	}
+	return diffs, nil
+}
+
+func readTree(dir string) (map[string][]byte, error) {
+	files := map[string][]byte{}
+	if _, err := os.Stat(dir); os.IsNotExist(err) {
+		return files, nil
+	}
+	err := filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil || d.IsDir() {
+			return err
+		}
+		rel, err := filepath.Rel(dir, path)
+		if err != nil {
+			return err
+		}
+		data, err := os.ReadFile(path)
+		files[filepath.ToSlash(rel)] = data
+		return err
+	})
+	return files, err
+}
+
+func firstDifference(want, got string) string {
+	wantLines, gotLines := strings.Split(want, "\n"), strings.Split(got, "\n")
+	for i := 0; i < len(wantLines) || i < len(gotLines); i++ {
+		var w, g string
+		if i < len(wantLines) {
+			w = wantLines[i]
+		}
+		if i < len(gotLines) {
+			g = gotLines[i]
+		}
+		if w != g {
+			return fmt.Sprintf("line %d: want %q, got %q", i+1, w, g)
+		}
+	}
+	return "content differs"
+}
@@ -0,0 +1,145 @@
+package main
+
+import (
+	"fmt"
+	"os"
+	"strings"
+	"sync"
+)
+
+// Tenant map một nhóm team sang GitHub org + credential riêng, cho phép một registry
+// provisioning cho nhiều business unit mà không dùng chung token.
+type Tenant struct {
+	Name     string   `yaml:"name"`
+	Org      string   `yaml:"org"`
+	TokenEnv string   `yaml:"token_env"` // env chứa token của tenant (GH_TOKEN dùng cho gh + git push)
+	Teams    []string `yaml:"teams"`
+}
+
+// tenantFor trả về tenant của team; team không thuộc tenant nào dùng tenant mặc định (Name rỗng)
+func (r RegistryConfig) tenantFor(team string) Tenant {
+	for _, t := range r.Tenants {
+		if team != "" && containsString(t.Teams, team) {
+			return t
+		}
+	}
+	return Tenant{}
+}
+
+// ownerFor: owner trong source.yml > org của tenant > repoOwner
+func (r RegistryConfig) ownerFor(config SourceConfig) string {
+	if config.Owner != "" {
+		return config.Owner
+	}
+	if t := r.tenantFor(config.Metadata.Team); t.Org != "" {
+		return t.Org
+	}
+	return repoOwner
+}
+
+// validateTenant: service của tenant chỉ được provisioning vào org của tenant đó
+func validateTenant(config SourceConfig, registryConfig RegistryConfig) []string {
+	t := registryConfig.tenantFor(config.Metadata.Team)
+	if t.Name == "" || config.Owner == "" || config.Owner == t.Org {
+		return nil
+	}
+	return []string{fmt.Sprintf("owner '%s' is not allowed for team %s (tenant %s provisions into %s)",
+		config.Owner, config.Metadata.Team, t.Name, t.Org)}
+}
+
+// credentialEnvs là các env gh CLI và pushToRepo đọc token
+var credentialEnvs = []string{"GH_TOKEN", "GITHUB_TOKEN"}
+
+// registryCredentials là env chứa token của chính jupiter-registry (không phải của tenant)
+func registryCredentials() []string {
+	token, err := credentialProvider.token("")
+	if err != nil {
+		fmt.Printf("⚠️ Failed to get registry token: %v\n", err)
+	}
+	var env []string
+	for _, name := range credentialEnvs {
+		env = append(env, name+"="+token)
+	}
+	return env
+}
+
+// ghRegistryOutput gọi gh bằng credential của jupiter-registry (PR comment, check run),
+// kể cả khi đang chạy trong credential của tenant
+func ghRegistryOutput(ex Executor, args ...string) (string, error) {
+	fmt.Printf("%s  → Running: gh %s\n", logPrefix(ex), strings.Join(args, " "))
+	out, err := ex.Run(Command{Name: "gh", Args: args, Env: registryCredentials(), CaptureOutput: true})
+	return strings.TrimSpace(out), err
+}
+
+func ghRegistryOutputWithInput(ex Executor, input []byte, args ...string) (string, error) {
+	fmt.Printf("%s  → Running: gh %s\n", logPrefix(ex), strings.Join(args, " "))
+	out, err := ex.Run(Command{Name: "gh", Args: args, Env: registryCredentials(), Stdin: input, CaptureOutput: true})
+	return strings.TrimSpace(out), err
+}
+
+func ghRegistryWithInput(ex Executor, input []byte, args ...string) error {
+	fmt.Printf("%s  → Running: gh %s\n", logPrefix(ex), strings.Join(args, " "))
+	_, err := ex.Run(Command{Name: "gh", Args: args, Env: registryCredentials(), Stdin: input})
+	return err
+}
+
+// tenantGate đảm bảo tại một thời điểm process chỉ dùng credential của một tenant.
+// Các service cùng tenant vẫn chạy song song; tenant khác phải đợi.
+var tenantGate = &credentialGate{}
+
+type credentialGate struct {
+	mu      sync.Mutex
+	cond    *sync.Cond
+	active  string
+	running int
+}
+
+// withTenantCredentials chạy fn với GH_TOKEN/GITHUB_TOKEN của tenant
+func withTenantCredentials(t Tenant, fn func() error) error {
+	// Token tenant: env riêng nếu có, ngược lại lấy từ credentialProvider (OIDC exchange theo org)
+	var token string
+	if t.TokenEnv != "" {
+		token = os.Getenv(t.TokenEnv)
+		if token == "" {
+			return withCode(ErrUsage, fmt.Errorf("token $%s for tenant %s is not set", t.TokenEnv, t.Name))
+		}
+	} else {
+		var err error
+		if token, err = credentialProvider.token(t.Org); err != nil {
+			return withCode(ErrUsage, fmt.Errorf("failed to get token for %s: %w", t.Org, err))
+		}
+	}
+
+	registerSecret(token)
+
+	g := tenantGate
+	g.mu.Lock()
+	if g.cond == nil {
+		g.cond = sync.NewCond(&g.mu)
+	}
+	for g.running > 0 && g.active != t.Name {
+		g.cond.Wait()
+	}
+	if g.running == 0 {
+		g.active = t.Name
+		for _, env := range credentialEnvs {
+			os.Setenv(env, token)
+		}
+		if t.Name != "" {
+			fmt.Printf("🏢 Using credentials of tenant %s (%s)\n", t.Name, t.Org)
+		}
+	}
+	g.running++
+	g.mu.Unlock()
+
+	defer func() {
+		g.mu.Lock()
+		g.running--
+		if g.running == 0 {
+			g.cond.Broadcast()
+		}
+		g.mu.Unlock()
+	}()
+
+	return fn()
+}
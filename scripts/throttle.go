@@ -0,0 +1,88 @@
+package main
+
+import (
+	"fmt"
+	"sync"
+	"time"
+)
+
+// Throttle điều chỉnh tốc độ batch run: số service generate song song, số GitHub
+// operation song song và delay tối thiểu giữa 2 GitHub request (tránh abuse limit).
+type Throttle struct {
+	MaxConcurrentGenerations int    `yaml:"max_concurrent_generations"` // mặc định 1 (tuần tự)
+	MaxConcurrentGitHubOps   int    `yaml:"max_concurrent_github_ops"`  // 0 = không giới hạn
+	RequestDelay             string `yaml:"request_delay"`              // duration, vd. "250ms"
+}
+
+func (t Throttle) generations() int {
+	if t.MaxConcurrentGenerations < 1 {
+		return 1
+	}
+	return t.MaxConcurrentGenerations
+}
+
+// throttledExecutor giới hạn các command gọi GitHub (gh, git push/ls-remote) theo Throttle,
+// các command local (generator, npm, go) chạy tự do.
+type throttledExecutor struct {
+	inner Executor
+	slots chan struct{}
+	delay time.Duration
+
+	mu   sync.Mutex
+	last time.Time
+}
+
+func newThrottledExecutor(inner Executor, throttle Throttle) (*throttledExecutor, error) {
+	t := &throttledExecutor{inner: inner}
+	if throttle.MaxConcurrentGitHubOps > 0 {
+		t.slots = make(chan struct{}, throttle.MaxConcurrentGitHubOps)
+	}
+	if throttle.RequestDelay != "" {
+		delay, err := time.ParseDuration(throttle.RequestDelay)
+		if err != nil {
+			return nil, fmt.Errorf("invalid throttle.request_delay %q: %w", throttle.RequestDelay, err)
+		}
+		t.delay = delay
+	}
+	return t, nil
+}
+
+func (t *throttledExecutor) Run(c Command) (string, error) {
+	// Lookup đã cache không gọi GitHub, không chiếm slot / delay
+	if !isGitHubCommand(c) || (apiCache != nil && apiCache.has(c)) {
+		return t.inner.Run(c)
+	}
+
+	if t.slots != nil {
+		t.slots <- struct{}{}
+		defer func() { <-t.slots }()
+	}
+	t.pace()
+	return t.inner.Run(c)
+}
+
+// pace chờ cho tới khi cách request trước ít nhất delay
+func (t *throttledExecutor) pace() {
+	if t.delay <= 0 {
+		return
+	}
+	t.mu.Lock()
+	defer t.mu.Unlock()
+	if wait := t.delay - time.Since(t.last); wait > 0 {
+		time.Sleep(wait)
+	}
+	t.last = time.Now()
+}
+
+func isGitHubCommand(c Command) bool {
+	if c.Name == "gh" {
+		return true
+	}
+	if c.Name == "git" && len(c.Args) > 0 {
+		switch c.Args[0] {
+		case "push", "fetch", "clone", "ls-remote":
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,234 @@
+package main
+
+import (
+	"bytes"
+	"encoding/base64"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"os"
+	"strings"
+)
+
+// Tickets mở ticket theo dõi (Jira / Linear) cho các việc cần người xử lý tiếp, thay vì để lẫn trong log CI:
+//   - on_created: service vừa được provision lần đầu (checklist onboarding)
+//   - failure_threshold: provisioning fail N lần liên tiếp (chỉ mở một ticket cho mỗi chuỗi fail)
+//
+// Assignee là member đầu tiên có account trong users, các member còn lại được liệt kê trong description.
+type Tickets struct {
+	Provider string `yaml:"provider"` // jira | linear | "" = tắt
+	URL      string `yaml:"url"`      // Jira: https://<site>.atlassian.net, Linear: mặc định https://api.linear.app
+	// Project: Jira project key, Linear team ID
+	Project   string   `yaml:"project"`
+	IssueType string   `yaml:"issue_type"` // Jira, mặc định Task
+	Labels    []string `yaml:"labels"`     // Jira: tên label, Linear: label ID
+	// Jira dùng basic auth <email>:<token>, Linear dùng API key
+	EmailEnv string `yaml:"email_env"`
+	TokenEnv string `yaml:"token_env"`
+	// Users map GitHub login -> Jira accountId / Linear user ID
+	Users            map[string]string `yaml:"users"`
+	OnCreated        bool              `yaml:"on_created"`
+	FailureThreshold int               `yaml:"failure_threshold"` // 0 = không mở ticket khi fail
+}
+
+// Ticket là yêu cầu tạo issue, độc lập với provider
+type Ticket struct {
+	Title       string
+	Description string
+	Assignee    string // account trên provider
+}
+
+// ticketFor dựng ticket cho event (ok = false khi event không cần ticket)
+func (t Tickets) ticketFor(e Event, history []RunRecord) (Ticket, bool) {
+	dto := e.DTO
+	var ticket Ticket
+	switch e.Type {
+	case EventServiceGenerated:
+		// Lần provision thành công đầu tiên (history subscriber đã ghi record của lần này)
+		if !t.OnCreated || countRuns(history, "success") != 1 {
+			return ticket, false
+		}
+		ticket.Title = fmt.Sprintf("Onboard new service %s", e.Service)
+		ticket.Description = fmt.Sprintf("Service %s was provisioned from jupiter-registry.\n\nRepository: https://github.com/%s", e.Service, e.Repo)
+	case EventServiceFailed:
+		if t.FailureThreshold <= 0 || consecutiveFailures(history) != t.FailureThreshold {
+			return ticket, false
+		}
+		ticket.Title = fmt.Sprintf("Provisioning of %s failed %d times in a row", e.Service, t.FailureThreshold)
+		ticket.Description = fmt.Sprintf("Provisioning of %s keeps failing (error code %s).\n\nLast error: %s", e.Service, e.ErrorCode, redact(fmt.Sprint(e.Err)))
+		if url := currentRunURL(); url != "" {
+			ticket.Description += "\n\nRun: " + url
+		}
+	default:
+		return ticket, false
+	}
+
+	if dto != nil && len(dto.Members) > 0 {
+		ticket.Description += "\n\nOwners: " + strings.Join(dto.Members, ", ")
+		for _, m := range dto.Members {
+			if account, ok := t.Users[m]; ok {
+				ticket.Assignee = account
+				break
+			}
+		}
+		if ticket.Assignee == "" {
+			fmt.Printf("  ⚠️ No member of %s has an account in tickets.users, ticket is unassigned\n", e.Service)
+		}
+	}
+	return ticket, true
+}
+
+// countRuns đếm số lần provision có kết quả result
+func countRuns(history []RunRecord, result string) int {
+	n := 0
+	for _, r := range history {
+		if r.Action == "provision" && r.Result == result {
+			n++
+		}
+	}
+	return n
+}
+
+// consecutiveFailures: số lần provision fail liên tiếp tính từ cuối history
+func consecutiveFailures(history []RunRecord) int {
+	n := 0
+	for i := len(history) - 1; i >= 0; i-- {
+		if history[i].Action != "provision" {
+			continue
+		}
+		if history[i].Result != "failed" {
+			break
+		}
+		n++
+	}
+	return n
+}
+
+// openTicketForEvent là subscriber của event bus
+func openTicketForEvent(t Tickets, e Event) error {
+	history, err := loadRunHistory(e.Service)
+	if err != nil {
+		return err
+	}
+	ticket, ok := t.ticketFor(e, history)
+	if !ok {
+		return nil
+	}
+	url, err := createTicket(t, ticket)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("🎫 Opened %s ticket for %s: %s\n", t.Provider, e.Service, url)
+	return nil
+}
+
+// createTicket tạo issue trên provider, trả về URL của issue
+func createTicket(t Tickets, ticket Ticket) (string, error) {
+	token := os.Getenv(t.TokenEnv)
+	if token == "" {
+		return "", fmt.Errorf("ticket token $%s is not set", t.TokenEnv)
+	}
+	switch t.Provider {
+	case "jira":
+		return createJiraIssue(t, ticket, token)
+	case "linear":
+		return createLinearIssue(t, ticket, token)
+	default:
+		return "", fmt.Errorf("unsupported ticket provider: %s", t.Provider)
+	}
+}
+
+func createJiraIssue(t Tickets, ticket Ticket, token string) (string, error) {
+	email := os.Getenv(t.EmailEnv)
+	if email == "" {
+		return "", fmt.Errorf("jira email $%s is not set", t.EmailEnv)
+	}
+	issueType := t.IssueType
+	if issueType == "" {
+		issueType = "Task"
+	}
+	fields := map[string]interface{}{
+		"project":     map[string]string{"key": t.Project},
+		"summary":     ticket.Title,
+		"description": ticket.Description,
+		"issuetype":   map[string]string{"name": issueType},
+	}
+	if len(t.Labels) > 0 {
+		fields["labels"] = t.Labels
+	}
+	if ticket.Assignee != "" {
+		fields["assignee"] = map[string]string{"accountId": ticket.Assignee}
+	}
+	payload, err := json.Marshal(map[string]interface{}{"fields": fields})
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(t.URL, "/")
+	req, err := http.NewRequest(http.MethodPost, base+"/rest/api/2/issue", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", "Basic "+base64.StdEncoding.EncodeToString([]byte(email+":"+token)))
+	req.Header.Set("Content-Type", "application/json")
+	var issue struct {
+		Key string `json:"key"`
+	}
+	if err := doJSONRequest(req, &issue); err != nil {
+		return "", fmt.Errorf("failed to create jira issue: %w", err)
+	}
+	return fmt.Sprintf("%s/browse/%s", base, issue.Key), nil
+}
+
+func createLinearIssue(t Tickets, ticket Ticket, token string) (string, error) {
+	input := map[string]interface{}{
+		"teamId":      t.Project,
+		"title":       ticket.Title,
+		"description": ticket.Description,
+	}
+	if len(t.Labels) > 0 {
+		input["labelIds"] = t.Labels
+	}
+	if ticket.Assignee != "" {
+		input["assigneeId"] = ticket.Assignee
+	}
+	payload, err := json.Marshal(map[string]interface{}{
+		"query":     "mutation($input: IssueCreateInput!) { issueCreate(input: $input) { success issue { url } } }",
+		"variables": map[string]interface{}{"input": input},
+	})
+	if err != nil {
+		return "", err
+	}
+	base := strings.TrimSuffix(t.URL, "/")
+	if base == "" {
+		base = "https://api.linear.app"
+	}
+	req, err := http.NewRequest(http.MethodPost, base+"/graphql", bytes.NewReader(payload))
+	if err != nil {
+		return "", err
+	}
+	req.Header.Set("Authorization", token)
+	req.Header.Set("Content-Type", "application/json")
+	var resp struct {
+		Data struct {
+			IssueCreate struct {
+				Success bool `json:"success"`
+				Issue   struct {
+					URL string `json:"url"`
+				} `json:"issue"`
+			} `json:"issueCreate"`
+		} `json:"data"`
+		Errors []struct {
+			Message string `json:"message"`
+		} `json:"errors"`
+	}
+	if err := doJSONRequest(req, &resp); err != nil {
+		return "", fmt.Errorf("failed to create linear issue: %w", err)
+	}
+	if len(resp.Errors) > 0 {
+		return "", fmt.Errorf("failed to create linear issue: %s", resp.Errors[0].Message)
+	}
+	if !resp.Data.IssueCreate.Success {
+		return "", fmt.Errorf("failed to create linear issue")
+	}
+	return resp.Data.IssueCreate.Issue.URL, nil
+}
@@ -0,0 +1,87 @@
+package main
+
+import (
+	"fmt"
+	"sort"
+	"strings"
+)
+
+// TokenScope là một OAuth scope token cần có, kèm lý do để hướng dẫn cấp quyền tối thiểu
+type TokenScope struct {
+	Scope  string
+	Reason string
+}
+
+// impliedScopes: scope cha bao gồm các scope con (theo tài liệu OAuth scopes của GitHub)
+var impliedScopes = map[string][]string{
+	"repo":           {"public_repo", "repo:status", "repo_deployment", "repo:invite", "admin:repo_hook", "write:repo_hook", "read:repo_hook"},
+	"admin:org":      {"write:org", "read:org"},
+	"write:org":      {"read:org"},
+	"write:packages": {"read:packages"},
+}
+
+// requiredScopes liệt kê scope cần cho việc provisioning dto theo cấu hình registry
+func requiredScopes(dto GeneratorSourceDto, registryConfig RegistryConfig) []TokenScope {
+	scopes := []TokenScope{
+		{"repo", "create the repository, push code, apply settings, environments and rulesets"},
+		// Mọi repo được generate đều có .github/workflows, push file workflow cần scope riêng
+		{"workflow", "push generated .github/workflows files"},
+	}
+	if registryConfig.Validation.Members.RequireOrgMember {
+		scopes = append(scopes, TokenScope{"read:org", "check that members belong to the organization"})
+	}
+	if registryConfig.DeployKeys.Enabled && registryConfig.DeployKeys.SecretStore.Type == "org_secret" {
+		scopes = append(scopes, TokenScope{"admin:org", "store the deploy key as an organization secret"})
+	}
+	return scopes
+}
+
+// tokenScopes đọc header X-OAuth-Scopes của token hiện tại.
+// Fine-grained PAT và GitHub App token không có header này: ok = false, không kiểm tra được.
+func tokenScopes(ex Executor) (map[string]bool, bool, error) {
+	out, err := ex.Run(Command{Name: "gh", Args: []string{"api", "-i", "user"}, CaptureOutput: true})
+	if err != nil {
+		return nil, false, fmt.Errorf("failed to inspect token: %w", err)
+	}
+	for _, line := range strings.Split(out, "\n") {
+		name, value, found := strings.Cut(line, ":")
+		if !found || !strings.EqualFold(strings.TrimSpace(name), "X-OAuth-Scopes") {
+			continue
+		}
+		granted := map[string]bool{}
+		for _, scope := range strings.Split(value, ",") {
+			if scope = strings.TrimSpace(scope); scope != "" {
+				granted[scope] = true
+				for _, implied := range impliedScopes[scope] {
+					granted[implied] = true
+				}
+			}
+		}
+		return granted, true, nil
+	}
+	return nil, false, nil
+}
+
+// verifyTokenScopes fail sớm (trước khi tạo repo) với danh sách scope còn thiếu
+func verifyTokenScopes(ex Executor, required []TokenScope) error {
+	granted, ok, err := tokenScopes(ex)
+	if err != nil {
+		return withCode(ErrGitHubAPI, err)
+	}
+	if !ok {
+		fmt.Println("  ⚠️ Token has no OAuth scopes (fine-grained PAT or GitHub App), skipping scope check")
+		return nil
+	}
+
+	var missing []string
+	for _, s := range required {
+		if !granted[s.Scope] {
+			missing = append(missing, fmt.Sprintf("%s (%s)", s.Scope, s.Reason))
+		}
+	}
+	if len(missing) == 0 {
+		return nil
+	}
+	sort.Strings(missing)
+	return withCode(ErrInsufficientScopes, fmt.Errorf("token is missing scope(s): %s", strings.Join(missing, "; ")))
+}
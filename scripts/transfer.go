@@ -0,0 +1,55 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// previousRepo trả về repo cũ trong state nếu owner của service đã đổi (vd. source.yml đổi owner)
+// và repo cũ vẫn còn tồn tại, tức là cần transfer thay vì tạo repo mới.
+func previousRepo(ex Executor, dto GeneratorSourceDto) (string, bool) {
+	state, err := loadRegistryState(registryStateFile)
+	if err != nil {
+		return "", false
+	}
+	entry, ok := state.Services[dto.AppName]
+	if !ok || entry.Repo == "" || entry.Repo == fmt.Sprintf("%s/%s", dto.Owner, dto.AppName) {
+		return "", false
+	}
+	oldOwner, oldName, _ := strings.Cut(entry.Repo, "/")
+	if !repoExists(ex, oldOwner, oldName) {
+		return "", false
+	}
+	return entry.Repo, true
+}
+
+// transferRepository chuyển repo sang owner mới qua GitHub API rồi đợi transfer hoàn tất.
+// Module path và state được cập nhật khi provisioning tiếp tục với dto.Owner mới.
+func transferRepository(ex Executor, fromRepo string, dto GeneratorSourceDto) error {
+	if repoExists(ex, dto.Owner, dto.AppName) {
+		return withCode(ErrRepoExists, fmt.Errorf("cannot transfer %s: %s/%s already exists", fromRepo, dto.Owner, dto.AppName))
+	}
+
+	fmt.Printf("🚚 Transferring %s to %s/%s...\n", fromRepo, dto.Owner, dto.AppName)
+	payload, err := json.Marshal(map[string]string{
+		"new_owner": dto.Owner,
+		"new_name":  dto.AppName,
+	})
+	if err != nil {
+		return err
+	}
+	if err := runCommandWithInput(ex, payload, "gh", "api", "-X", "POST", fmt.Sprintf("repos/%s/transfer", fromRepo), "--input", "-"); err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to transfer %s: %w", fromRepo, err))
+	}
+
+	// Transfer chạy bất đồng bộ phía GitHub
+	for attempt := 0; attempt < 10; attempt++ {
+		if repoExists(ex, dto.Owner, dto.AppName) {
+			return nil
+		}
+		time.Sleep(3 * time.Second)
+	}
+	return withCode(ErrGitHubAPI, fmt.Errorf("transfer of %s to %s did not complete in time", fromRepo, dto.Owner))
+}
@@ -0,0 +1,243 @@
+package main
+
+import (
+	"fmt"
+	"path/filepath"
+	"sort"
+	"strings"
+)
+
+// supportedLanguages là các ngôn ngữ processService hỗ trợ
+var supportedLanguages = []string{"golang", "nodejs"}
+
+// supportedKinds là các metadata.kind processService xử lý được
+var supportedKinds = []string{"service", "frontend", "library", "cli", "worker", "cronjob"}
+
+var supportedVisibilities = []string{"private", "internal", "public"}
+
+var supportedDocsLanguages = []string{"en", "vi"}
+
+// validateSourceConfig trả về danh sách lỗi của source.yml (rỗng nếu hợp lệ)
+func validateSourceConfig(config SourceConfig) []string {
+	var problems []string
+
+	if config.Name == "" {
+		problems = append(problems, "name is required")
+	}
+	if len(config.Members) == 0 {
+		problems = append(problems, "members must contain at least one GitHub handle")
+	}
+
+	if config.Metadata.ProgrammingLanguage == "" {
+		problems = append(problems, "metadata.programming_language is required")
+	} else if !containsString(supportedLanguages, config.Metadata.ProgrammingLanguage) {
+		problems = append(problems, fmt.Sprintf("metadata.programming_language '%s' is not supported (supported: %v)",
+			config.Metadata.ProgrammingLanguage, supportedLanguages))
+	}
+
+	if config.Visibility != "" && !containsString(supportedVisibilities, config.Visibility) {
+		problems = append(problems, fmt.Sprintf("visibility '%s' must be one of private, internal, public", config.Visibility))
+	}
+
+	if config.DocsLanguage != "" && !containsString(supportedDocsLanguages, config.DocsLanguage) {
+		problems = append(problems, fmt.Sprintf("docs_language '%s' is not supported (supported: %v)", config.DocsLanguage, supportedDocsLanguages))
+	}
+
+	if config.Refresh != "" {
+		if _, err := parseRefreshTTL(config.Refresh); err != nil {
+			problems = append(problems, err.Error())
+		}
+	}
+
+	for _, dep := range config.DependsOn {
+		if dep == "" || dep == config.Name {
+			problems = append(problems, fmt.Sprintf("depends_on '%s' must name another service", dep))
+		}
+	}
+
+	if config.Deploy.Cloud != "" && !containsString(supportedClouds, config.Deploy.Cloud) {
+		problems = append(problems, fmt.Sprintf("deploy.cloud '%s' is not supported (supported: %v)", config.Deploy.Cloud, supportedClouds))
+	}
+
+	if config.Metadata.Tier != 0 && (config.Metadata.Tier < 1 || config.Metadata.Tier > 3) {
+		problems = append(problems, fmt.Sprintf("metadata.tier %d must be 1, 2 or 3", config.Metadata.Tier))
+	}
+
+	problems = append(problems, validateDataClassification(config)...)
+	problems = append(problems, validateConfigContract(config.Config)...)
+	problems = append(problems, validateSLO(config.SLO)...)
+
+	switch config.Metadata.Kind {
+	case "", "service":
+	case "frontend":
+		if config.Metadata.ProgrammingLanguage != "nodejs" {
+			problems = append(problems, "kind frontend requires metadata.programming_language nodejs")
+		}
+		if config.Metadata.Framework == "" {
+			problems = append(problems, fmt.Sprintf("kind frontend requires metadata.framework (supported: %v)", frontendFrameworks))
+		}
+	case "library", "cli", "worker", "cronjob":
+		if config.Metadata.Framework != "" {
+			problems = append(problems, fmt.Sprintf("metadata.framework must be empty for kind %s", config.Metadata.Kind))
+		}
+		if config.Metadata.Kind == "cronjob" && len(strings.Fields(config.Schedule)) != 5 {
+			problems = append(problems, "kind cronjob requires schedule as a 5-field cron expression")
+		}
+	default:
+		problems = append(problems, fmt.Sprintf("metadata.kind '%s' is not supported (supported: %v)", config.Metadata.Kind, supportedKinds))
+	}
+
+	problems = append(problems, validateFrameworkCompatibility(config.Metadata)...)
+
+	seenEnvs := map[string]bool{}
+	for _, env := range config.Environments {
+		if env.Name == "" {
+			problems = append(problems, "environments: name is required")
+			continue
+		}
+		if seenEnvs[env.Name] {
+			problems = append(problems, fmt.Sprintf("environments: duplicate environment '%s'", env.Name))
+		}
+		seenEnvs[env.Name] = true
+	}
+
+	return problems
+}
+
+// FrameworkSupport là language + kind mà generator của một framework xử lý được
+type FrameworkSupport struct {
+	Language string `json:"language"`
+	Kind     string `json:"kind"`
+}
+
+// frameworkMatrix: framework -> language/kind hợp lệ, build từ danh sách framework của từng generator
+func frameworkMatrix() map[string]FrameworkSupport {
+	matrix := map[string]FrameworkSupport{}
+	for _, group := range []struct {
+		support    FrameworkSupport
+		frameworks []string
+	}{
+		{FrameworkSupport{"golang", "service"}, golangFrameworks},
+		{FrameworkSupport{"nodejs", "service"}, nodejsFrameworks},
+		{FrameworkSupport{"nodejs", "frontend"}, frontendFrameworks},
+	} {
+		for _, f := range group.frameworks {
+			matrix[f] = group.support
+		}
+	}
+	return matrix
+}
+
+// frameworksFor liệt kê framework hợp lệ cho language + kind (để gợi ý trong lỗi)
+func frameworksFor(language, kind string) []string {
+	var frameworks []string
+	for f, support := range frameworkMatrix() {
+		if support.Language == language && support.Kind == kind {
+			frameworks = append(frameworks, f)
+		}
+	}
+	sort.Strings(frameworks)
+	return frameworks
+}
+
+// validateFrameworkCompatibility báo framework không khớp language / kind ngay lúc validate,
+// thay vì để generator của language kia fail giữa chừng
+func validateFrameworkCompatibility(metadata Metadata) []string {
+	kind := metadata.Kind
+	if kind == "" {
+		kind = "service"
+	}
+	// Kind dùng template không có framework (đã báo ở trên), language lạ cũng đã báo
+	if metadata.Framework == "" || (kind != "service" && kind != "frontend") || !containsString(supportedLanguages, metadata.ProgrammingLanguage) {
+		return nil
+	}
+
+	allowed := frameworksFor(metadata.ProgrammingLanguage, kind)
+	if len(allowed) == 0 {
+		// Language không hỗ trợ kind này (vd. frontend golang), lỗi kind đã báo
+		return nil
+	}
+	support, known := frameworkMatrix()[metadata.Framework]
+	switch {
+	case !known:
+		return []string{fmt.Sprintf("metadata.framework '%s' is not supported for %s %s (supported: %v)",
+			metadata.Framework, metadata.ProgrammingLanguage, kind, allowed)}
+	case support.Language != metadata.ProgrammingLanguage:
+		return []string{fmt.Sprintf("metadata.framework '%s' is a %s framework and cannot be used with programming_language %s (supported for %s %s: %v)",
+			metadata.Framework, support.Language, metadata.ProgrammingLanguage, metadata.ProgrammingLanguage, kind, allowed)}
+	case support.Kind != kind:
+		return []string{fmt.Sprintf("metadata.framework '%s' is a %s framework and cannot be used with kind %s (supported for %s %s: %v)",
+			metadata.Framework, support.Kind, kind, metadata.ProgrammingLanguage, kind, allowed)}
+	}
+	return nil
+}
+
+func containsString(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
+
+// ValidationResult gom lỗi (chặn provisioning) và cảnh báo (chỉ hiển thị)
+type ValidationResult struct {
+	Problems []string
+	Warnings []string
+	// Code cụ thể hơn E_VALIDATION_FAILED khi biết rõ nguyên nhân (vd. E_REPO_EXISTS)
+	Code ErrorCode
+}
+
+func (r ValidationResult) ok() bool {
+	return len(r.Problems) == 0
+}
+
+// validateService chạy validate tĩnh của source.yml rồi tới các check cần gọi GitHub API
+func validateService(ex Executor, servicePath string, config SourceConfig, registryConfig RegistryConfig) ValidationResult {
+	result := ValidationResult{Problems: validateSourceKeys(servicePath)}
+	result.Problems = append(result.Problems, validateSourceConfig(config)...)
+	result.Problems = append(result.Problems, validateTenant(config, registryConfig)...)
+
+	naming := registryConfig.Validation.Naming
+	result.Problems = append(result.Problems, validateServiceName(config.Name, config.Metadata.Team, naming)...)
+	if naming.CheckCollisions && config.Name != "" {
+		owner := registryConfig.ownerFor(config)
+		collisions, repoTaken := checkNameCollisions(ex, servicePath, owner, config.Name, config.SourceID)
+		result.Problems = append(result.Problems, collisions...)
+		if repoTaken {
+			taken := fmt.Sprintf("repository %s/%s already exists and is not managed by the registry", owner, config.Name)
+			if strategy := naming.onCollision(); strategy != "fail" {
+				result.Warnings = append(result.Warnings, fmt.Sprintf("%s (on_collision: %s)", taken, strategy))
+			} else {
+				result.Problems = append(result.Problems, taken)
+				result.Code = ErrRepoExists
+			}
+		}
+	}
+
+	result.Problems = append(result.Problems, validateRunbook(config, registryConfig.Runbook)...)
+
+	// Org policies (Rego) đánh giá trên nội dung gốc của source.yml
+	if registryConfig.Policies.Dir != "" {
+		violations, err := evaluatePolicies(ex, filepath.Join(servicePath, "source.yml"), registryConfig.Policies)
+		if err != nil {
+			result.Problems = append(result.Problems, fmt.Sprintf("policy evaluation failed: %v", err))
+		}
+		result.Problems = append(result.Problems, violations...)
+	}
+
+	memberIssues, err := validateMembersOnGitHub(ex, config.Members, registryConfig.Validation.Members)
+	if registryConfig.Validation.Members.Mode == "warn" {
+		result.Warnings = append(result.Warnings, memberIssues...)
+	} else {
+		result.Problems = append(result.Problems, memberIssues...)
+	}
+	if err != nil {
+		// Lỗi hạ tầng (token, rate limit...), không phải lỗi của source.yml: fail với E_GITHUB_API để retry được
+		result.Problems = append(result.Problems, fmt.Sprintf("members: could not verify members on GitHub: %v", err))
+		result.Code = ErrGitHubAPI
+	}
+
+	return result
+}
@@ -0,0 +1,48 @@
+package main
+
+import (
+	"fmt"
+)
+
+// MemberValidation cấu hình việc kiểm tra members với GitHub API
+type MemberValidation struct {
+	CheckGitHub      bool   `yaml:"check_github"`
+	RequireOrgMember bool   `yaml:"require_org_member"`
+	Org              string `yaml:"org"`
+	Mode             string `yaml:"mode"` // fail (default) | warn
+}
+
+// validateMembersOnGitHub kiểm tra từng handle: user tồn tại và (tuỳ chọn) là member của org.
+// Chỉ HTTP 404 mới là lỗi của source.yml; lỗi khác (rate limit, network, token thiếu) trả về err
+// để không báo nhầm mọi member là "does not exist".
+func validateMembersOnGitHub(ex Executor, members []string, cfg MemberValidation) ([]string, error) {
+	if !cfg.CheckGitHub {
+		return nil, nil
+	}
+
+	org := cfg.Org
+	if org == "" {
+		org = repoOwner
+	}
+
+	var issues []string
+	for _, member := range members {
+		if out, err := runCommandOutput(ex, "gh", "api", "users/"+member, "--jq", ".login"); err != nil {
+			if status, _ := ghErrorStatus(out); status != 404 {
+				return issues, withCode(ErrGitHubAPI, fmt.Errorf("failed to look up GitHub user '%s': %v", member, err))
+			}
+			issues = append(issues, fmt.Sprintf("members: GitHub user '%s' does not exist", member))
+			continue
+		}
+		if cfg.RequireOrgMember {
+			// 204 nếu là member, 404 nếu không
+			if out, err := runCommandOutput(ex, "gh", "api", fmt.Sprintf("orgs/%s/members/%s", org, member)); err != nil {
+				if status, _ := ghErrorStatus(out); status != 404 {
+					return issues, withCode(ErrGitHubAPI, fmt.Errorf("failed to check %s membership of '%s': %v", org, member, err))
+				}
+				issues = append(issues, fmt.Sprintf("members: '%s' is not a member of the %s organization", member, org))
+			}
+		}
+	}
+	return issues, nil
+}
@@ -0,0 +1,56 @@
+package main
+
+import (
+	"errors"
+	"reflect"
+	"testing"
+)
+
+func TestValidateMembersNotFound(t *testing.T) {
+	fake := useFakeExecutor(t)
+	fake.On("gh api users/ghost", FakeResponse{Output: `{"message":"Not Found","status":"404"}`, Err: errors.New("exit status 1")})
+
+	issues, err := validateMembersOnGitHub(commandExecutor, []string{"alice", "ghost"}, MemberValidation{CheckGitHub: true})
+	if err != nil {
+		t.Fatalf("validateMembersOnGitHub: %v", err)
+	}
+	want := []string{"members: GitHub user 'ghost' does not exist"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("issues = %v, want %v", issues, want)
+	}
+}
+
+func TestValidateMembersAPIFailure(t *testing.T) {
+	for name, resp := range map[string]FakeResponse{
+		"bad token":  {Output: `{"message":"Bad credentials","status":"401"}`, Err: errors.New("exit status 1")},
+		"rate limit": {Output: `{"message":"API rate limit exceeded","status":"403"}`, Err: errors.New("exit status 1")},
+		"network":    {Err: errors.New("dial tcp: lookup api.github.com: no such host")},
+	} {
+		t.Run(name, func(t *testing.T) {
+			fake := useFakeExecutor(t)
+			fake.On("gh api users/", resp)
+
+			issues, err := validateMembersOnGitHub(commandExecutor, []string{"alice", "bob"}, MemberValidation{CheckGitHub: true})
+			if errorCode(err) != ErrGitHubAPI {
+				t.Fatalf("error code = %s (%v), want %s", errorCode(err), err, ErrGitHubAPI)
+			}
+			if len(issues) != 0 {
+				t.Fatalf("API failures must not be reported as missing users: %v", issues)
+			}
+		})
+	}
+}
+
+func TestValidateMembersOrgMembership(t *testing.T) {
+	fake := useFakeExecutor(t)
+	fake.On("gh api orgs/acme/members/bob", FakeResponse{Output: `{"message":"Not Found","status":"404"}`, Err: errors.New("exit status 1")})
+
+	issues, err := validateMembersOnGitHub(commandExecutor, []string{"alice", "bob"}, MemberValidation{CheckGitHub: true, RequireOrgMember: true, Org: "acme"})
+	if err != nil {
+		t.Fatalf("validateMembersOnGitHub: %v", err)
+	}
+	want := []string{"members: 'bob' is not a member of the acme organization"}
+	if !reflect.DeepEqual(issues, want) {
+		t.Fatalf("issues = %v, want %v", issues, want)
+	}
+}
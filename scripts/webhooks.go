@@ -0,0 +1,123 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"strings"
+)
+
+// ServerConfig describes the registry when it runs in server mode.
+// PublicURL rỗng nghĩa là đang chạy dạng CLI, bỏ qua việc đăng ký webhook.
+type ServerConfig struct {
+	PublicURL        string   `yaml:"public_url"`
+	WebhookPath      string   `yaml:"webhook_path"`
+	WebhookSecretEnv string   `yaml:"webhook_secret_env"`
+	WebhookEvents    []string `yaml:"webhook_events"`
+
+	// Cấu hình cho `serve`
+	Listen      string `yaml:"listen"`       // mặc định :8080
+	Workers     int    `yaml:"workers"`      // số job provisioning chạy song song, mặc định 2
+	MaxAttempts int    `yaml:"max_attempts"` // số lần thử mỗi job, mặc định 3
+	QueueFile   string `yaml:"queue_file"`   // mặc định state/jobs.json
+	// Auth: xác thực + phân quyền cho /api/* (không có provider = API mở, chỉ dùng trong mạng tin cậy)
+	Auth APIAuth `yaml:"auth"`
+	// Reconcile: vòng hội tụ định kỳ giữa registry, state và repo downstream
+	Reconcile Reconcile `yaml:"reconcile"`
+}
+
+func (s ServerConfig) webhookPath() string {
+	if s.WebhookPath == "" {
+		return "/webhooks/github"
+	}
+	return "/" + strings.TrimLeft(s.WebhookPath, "/")
+}
+
+func (s ServerConfig) webhookSecret() string {
+	if s.WebhookSecretEnv == "" {
+		return ""
+	}
+	return os.Getenv(s.WebhookSecretEnv)
+}
+
+func (s ServerConfig) webhookURL() string {
+	return strings.TrimRight(s.PublicURL, "/") + s.webhookPath()
+}
+
+func (s ServerConfig) listen() string {
+	if s.Listen == "" {
+		return ":8080"
+	}
+	return s.Listen
+}
+
+func (s ServerConfig) workers() int {
+	if s.Workers < 1 {
+		return 2
+	}
+	return s.Workers
+}
+
+func (s ServerConfig) maxAttempts() int {
+	if s.MaxAttempts < 1 {
+		return 3
+	}
+	return s.MaxAttempts
+}
+
+func (s ServerConfig) queueFile() string {
+	if s.QueueFile == "" {
+		return jobQueueFile
+	}
+	return s.QueueFile
+}
+
+func registerRegistryWebhook(ex Executor, owner, repoName string, server ServerConfig) error {
+	events := server.WebhookEvents
+	if len(events) == 0 {
+		events = []string{"push", "release", "deployment_status"}
+	}
+
+	hookConfig := map[string]string{
+		"url":          server.webhookURL(),
+		"content_type": "json",
+	}
+	if server.WebhookSecretEnv != "" {
+		secret := os.Getenv(server.WebhookSecretEnv)
+		if secret == "" {
+			return fmt.Errorf("webhook secret $%s is not set", server.WebhookSecretEnv)
+		}
+		hookConfig["secret"] = secret
+	}
+
+	hook := map[string]interface{}{
+		"active": true,
+		"events": events,
+		"config": hookConfig,
+	}
+	hooksPath := fmt.Sprintf("repos/%s/%s/hooks", owner, repoName)
+
+	// Regenerate repo đã có hook: cập nhật hook trỏ về registry thay vì tạo mới (GitHub trả 422 "Hook already exists")
+	out, err := runCommandOutput(ex, "gh", "api", "--paginate", hooksPath, "--jq", ".[] | \"\\(.id)\\t\\(.config.url)\"")
+	if err != nil {
+		return withCode(ErrGitHubAPI, fmt.Errorf("failed to list webhooks: %w", err))
+	}
+	for _, line := range strings.Split(out, "\n") {
+		if id, url, ok := strings.Cut(line, "\t"); ok && url == server.webhookURL() {
+			body, err := json.Marshal(hook)
+			if err != nil {
+				return err
+			}
+			fmt.Printf("  → Webhook URL: %s (updating #%s)\n", server.webhookURL(), id)
+			return runCommandWithInput(ex, body, "gh", "api", "-X", "PATCH", hooksPath+"/"+id, "--input", "-")
+		}
+	}
+
+	hook["name"] = "web"
+	body, err := json.Marshal(hook)
+	if err != nil {
+		return err
+	}
+	fmt.Printf("  → Webhook URL: %s\n", server.webhookURL())
+	return runCommandWithInput(ex, body, "gh", "api", "-X", "POST", hooksPath, "--input", "-")
+}
@@ -0,0 +1,25 @@
+package cmd
+
+import (
+	"fmt"
+	"os"
+
+	"github.com/spf13/cobra"
+)
+
+// version được goreleaser set qua -ldflags khi release
+var version = "dev"
+
+var rootCmd = &cobra.Command{
+	Use:     "golden-app",
+	Short:   "golden-app command-line tool",
+	Version: version,
+}
+
+// Execute chạy root command
+func Execute() {
+	if err := rootCmd.Execute(); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
@@ -0,0 +1,7 @@
+package main
+
+import "github.com/tqhuy-dev/golden-app/cmd"
+
+func main() {
+	cmd.Execute()
+}
@@ -0,0 +1,10 @@
+#!/usr/bin/env node
+const { program } = require('commander');
+const { version } = require('../package.json');
+
+program
+  .name('golden-app')
+  .description('golden-app command-line tool')
+  .version(version);
+
+program.parse();
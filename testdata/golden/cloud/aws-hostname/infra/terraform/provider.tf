@@ -0,0 +1,22 @@
+terraform {
+  required_providers {
+    aws = {
+      source  = "hashicorp/aws"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "aws" {
+  region              = "ap-southeast-1"
+  allowed_account_ids = ["000000000000"]
+
+  default_tags {
+    tags = var.tags
+  }
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
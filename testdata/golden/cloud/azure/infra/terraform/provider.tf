@@ -0,0 +1,23 @@
+terraform {
+  required_providers {
+    azurerm = {
+      source  = "hashicorp/azurerm"
+      version = "~> 3.0"
+    }
+  }
+}
+
+provider "azurerm" {
+  features {}
+  subscription_id = "000000000000"
+}
+
+variable "location" {
+  type    = string
+  default = "ap-southeast-1"
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
@@ -0,0 +1,20 @@
+terraform {
+  required_providers {
+    google = {
+      source  = "hashicorp/google"
+      version = "~> 5.0"
+    }
+  }
+}
+
+provider "google" {
+  project = "000000000000"
+  region  = "ap-southeast-1"
+
+  default_labels = var.tags
+}
+
+variable "tags" {
+  type    = map(string)
+  default = {}
+}
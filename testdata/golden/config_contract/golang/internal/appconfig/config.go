@@ -0,0 +1,25 @@
+// Package appconfig load biến môi trường theo contract `config:` trong source.yml.
+// Generated by jupiter-registry, sửa source.yml thay vì sửa file này.
+package appconfig
+
+import (
+	"time"
+
+	"github.com/kelseyhightower/envconfig"
+)
+
+type Config struct {
+	// Postgres connection string
+	DatabaseURL     string        `envconfig:"DATABASE_URL" required:"true"`
+	HTTPPort        int           `envconfig:"HTTP_PORT" default:"8080"`
+	RequestTimeout  time.Duration `envconfig:"REQUEST_TIMEOUT" default:"5s"`
+	FeatureXEnabled bool          `envconfig:"FEATURE_X_ENABLED" default:"false"`
+	LogLevel        string        `envconfig:"LOG_LEVEL"`
+}
+
+// Load đọc và validate toàn bộ biến môi trường, lỗi nếu thiếu biến required hoặc sai kiểu
+func Load() (Config, error) {
+	var cfg Config
+	err := envconfig.Process("", &cfg)
+	return cfg, err
+}
@@ -0,0 +1,14 @@
+// Load biến môi trường theo contract `config:` trong source.yml.
+// Generated by jupiter-registry, sửa source.yml thay vì sửa file này.
+const { z } = require('zod');
+
+const schema = z.object({
+  // Postgres connection string
+  DATABASE_URL: z.string().url(),
+  HTTP_PORT: z.coerce.number().int().default(8080),
+  REQUEST_TIMEOUT: z.string().default("5s"),
+  FEATURE_X_ENABLED: z.enum(["true", "false"]).default("false").transform((v) => v === "true"),
+  LOG_LEVEL: z.string().optional(),
+});
+
+module.exports = schema.parse(process.env);
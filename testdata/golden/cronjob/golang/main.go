@@ -0,0 +1,31 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// run là logic của scheduled job, phải tôn trọng ctx để dừng kịp khi bị terminate
+func run(ctx context.Context) error {
+	log.Println("running golden-app")
+	return nil
+}
+
+func main() {
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	// Giới hạn thời gian chạy, nhỏ hơn activeDeadlineSeconds của CronJob
+	ctx, cancel := context.WithTimeout(ctx, 10*time.Minute)
+	defer cancel()
+
+	if err := run(ctx); err != nil {
+		log.Printf("job failed: %v", err)
+		os.Exit(1)
+	}
+	log.Println("job completed")
+}
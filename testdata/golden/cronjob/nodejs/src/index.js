@@ -0,0 +1,17 @@
+// golden-app scheduled job
+
+const controller = new AbortController();
+for (const signal of ['SIGINT', 'SIGTERM']) {
+  process.on(signal, () => controller.abort());
+}
+
+async function run(signal) {
+  console.log('running golden-app');
+}
+
+run(controller.signal)
+  .then(() => console.log('job completed'))
+  .catch((err) => {
+    console.error('job failed', err);
+    process.exit(1);
+  });
@@ -0,0 +1,20 @@
+# Sinh bởi jupiter-registry vì metadata.data_classification = pii:
+# mọi storage của service phải mã hoá bằng key này (rotation bật, không xoá được ngay)
+resource "aws_kms_key" "data" {
+  description             = "golden-app data at rest"
+  enable_key_rotation     = true
+  deletion_window_in_days = 30
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+resource "aws_kms_alias" "data" {
+  name          = "alias/golden-app-data"
+  target_key_id = aws_kms_key.data.key_id
+}
+
+output "data_kms_key_arn" {
+  value = aws_kms_key.data.arn
+}
@@ -0,0 +1,39 @@
+# Sinh bởi jupiter-registry vì metadata.data_classification = pii:
+# mọi storage của service phải mã hoá bằng customer-managed key này (purge protection bật)
+data "azurerm_client_config" "current" {}
+
+variable "resource_group_name" {
+  type = string
+}
+
+resource "azurerm_key_vault" "data" {
+  name                       = "golden-app-data"
+  location                   = var.location
+  resource_group_name        = var.resource_group_name
+  tenant_id                  = data.azurerm_client_config.current.tenant_id
+  sku_name                   = "standard"
+  purge_protection_enabled   = true
+  soft_delete_retention_days = 90
+  tags                       = var.tags
+}
+
+resource "azurerm_key_vault_key" "data" {
+  name         = "data"
+  key_vault_id = azurerm_key_vault.data.id
+  key_type     = "RSA"
+  key_size     = 2048
+  key_opts     = ["wrapKey", "unwrapKey"]
+
+  rotation_policy {
+    expire_after         = "P1Y"
+    notify_before_expiry = "P30D"
+
+    automatic {
+      time_before_expiry = "P30D"
+    }
+  }
+}
+
+output "data_key_id" {
+  value = azurerm_key_vault_key.data.id
+}
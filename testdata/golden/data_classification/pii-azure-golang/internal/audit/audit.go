@@ -0,0 +1,40 @@
+// Package audit ghi audit log cho service xử lý PII (metadata.data_classification = pii).
+// Không log query string / body để tránh ghi PII vào log.
+package audit
+
+import (
+	"log/slog"
+	"net/http"
+	"os"
+	"time"
+)
+
+var logger = slog.New(slog.NewJSONHandler(os.Stdout, nil)).With("log_type", "audit", "service", "golden-app")
+
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+// Middleware bọc http.Handler (chi dùng trực tiếp, gin/echo/fiber qua adapter net/http của framework)
+func Middleware(next http.Handler) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		start := time.Now()
+		rec := &statusRecorder{ResponseWriter: w, status: http.StatusOK}
+		next.ServeHTTP(rec, r)
+		logger.Info("request",
+			"actor", r.Header.Get("X-Forwarded-User"),
+			"request_id", r.Header.Get("X-Request-Id"),
+			"method", r.Method,
+			"path", r.URL.Path,
+			"status", rec.status,
+			"remote_addr", r.RemoteAddr,
+			"duration_ms", time.Since(start).Milliseconds(),
+		)
+	})
+}
@@ -0,0 +1,21 @@
+# Sinh bởi jupiter-registry vì metadata.data_classification = pii:
+# mọi storage của service phải dùng CMEK này (rotation 90 ngày)
+resource "google_kms_key_ring" "data" {
+  name     = "golden-app-data"
+  location = "ap-southeast-1"
+}
+
+resource "google_kms_crypto_key" "data" {
+  name            = "golden-app-data"
+  key_ring        = google_kms_key_ring.data.id
+  rotation_period = "7776000s"
+  labels          = var.tags
+
+  lifecycle {
+    prevent_destroy = true
+  }
+}
+
+output "data_kms_key_id" {
+  value = google_kms_crypto_key.data.id
+}
@@ -0,0 +1,21 @@
+// Audit log cho service xử lý PII (metadata.data_classification = pii), dùng như middleware Express/Connect:
+//   app.use(require('./audit'));
+// Không log query string / body để tránh ghi PII vào log.
+module.exports = function audit(req, res, next) {
+  const start = Date.now();
+  res.on('finish', () => {
+    process.stdout.write(JSON.stringify({
+      time: new Date().toISOString(),
+      log_type: 'audit',
+      service: 'golden-app',
+      actor: req.headers['x-forwarded-user'] || '',
+      request_id: req.headers['x-request-id'] || '',
+      method: req.method,
+      path: (req.originalUrl || req.url).split('?')[0],
+      status: res.statusCode,
+      remote_addr: req.socket.remoteAddress,
+      duration_ms: Date.now() - start,
+    }) + '\n');
+  });
+  next();
+};
@@ -0,0 +1,23 @@
+package main
+
+import (
+	"log"
+	"os"
+
+	"github.com/getsentry/sentry-go"
+)
+
+// Sentry được init trước main; SENTRY_DSN là repo secret do jupiter-registry cấp
+func init() {
+	dsn := os.Getenv("SENTRY_DSN")
+	if dsn == "" {
+		return
+	}
+	if err := sentry.Init(sentry.ClientOptions{
+		Dsn:         dsn,
+		ServerName:  "golden-app",
+		Environment: os.Getenv("APP_ENV"),
+	}); err != nil {
+		log.Printf("sentry init failed: %v", err)
+	}
+}
@@ -0,0 +1,10 @@
+// Được load bằng `node --require ./src/instrument.js`; SENTRY_DSN là repo secret do jupiter-registry cấp
+const Sentry = require('@sentry/node');
+
+if (process.env.SENTRY_DSN) {
+  Sentry.init({
+    dsn: process.env.SENTRY_DSN,
+    serverName: 'golden-app',
+    environment: process.env.APP_ENV,
+  });
+}
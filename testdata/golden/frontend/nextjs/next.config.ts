@@ -0,0 +1,9 @@
+import type { NextConfig } from 'next';
+
+const nextConfig: NextConfig = {
+  // Static export cho GitHub Pages / static hosting
+  output: 'export',
+  images: { unoptimized: true },
+};
+
+export default nextConfig;
@@ -0,0 +1,5 @@
+module github.com/tqhuy-dev/golden-app
+
+go 1.21
+
+require github.com/go-chi/chi/v5 v5.0.12
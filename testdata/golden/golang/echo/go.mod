@@ -0,0 +1,5 @@
+module github.com/tqhuy-dev/golden-app
+
+go 1.21
+
+require github.com/labstack/echo/v4 v4.11.4
@@ -0,0 +1,5 @@
+module github.com/tqhuy-dev/golden-app
+
+go 1.21
+
+require github.com/gofiber/fiber/v2 v2.52.0
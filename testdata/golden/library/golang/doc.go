@@ -0,0 +1,2 @@
+// Package goldenapp is a shared library provisioned by jupiter-registry.
+package goldenapp
@@ -0,0 +1,4 @@
+package goldenapp
+
+// Version is the current release of the library, bumped by release-please.
+const Version = "0.1.0" // x-release-please-version
@@ -0,0 +1,3 @@
+// golden-app - shared library provisioned by jupiter-registry.
+
+module.exports = {};
@@ -0,0 +1,13 @@
+const express = require('express');
+
+const app = express();
+app.use(express.json());
+
+app.get('/healthz', (req, res) => {
+  res.json({ status: 'ok', service: 'golden-app' });
+});
+
+const port = process.env.PORT || 8080;
+app.listen(port, () => {
+  console.log(`golden-app listening on :${port}`);
+});
@@ -0,0 +1,11 @@
+const fastify = require('fastify')({ logger: true });
+
+fastify.get('/healthz', async () => {
+  return { status: 'ok', service: 'golden-app' };
+});
+
+const port = Number(process.env.PORT) || 8080;
+fastify.listen({ port, host: '0.0.0.0' }).catch((err) => {
+  fastify.log.error(err);
+  process.exit(1);
+});
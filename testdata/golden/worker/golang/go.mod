@@ -0,0 +1,3 @@
+module github.com/tqhuy-dev/golden-app
+
+go 1.21
@@ -0,0 +1,72 @@
+package main
+
+import (
+	"context"
+	"log"
+	"os/signal"
+	"syscall"
+	"time"
+)
+
+// Message là một job lấy từ queue
+type Message struct {
+	ID   string
+	Body []byte
+}
+
+// Consumer là nguồn message (SQS, Pub/Sub, Kafka, ...). Thay stubConsumer bằng implementation thật.
+type Consumer interface {
+	Receive(ctx context.Context) (*Message, error)
+	Ack(ctx context.Context, msg *Message) error
+}
+
+type stubConsumer struct{}
+
+func (stubConsumer) Receive(ctx context.Context) (*Message, error) {
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case <-time.After(5 * time.Second):
+		return nil, nil
+	}
+}
+
+func (stubConsumer) Ack(ctx context.Context, msg *Message) error { return nil }
+
+func handle(ctx context.Context, msg *Message) error {
+	log.Printf("processing message %s", msg.ID)
+	return nil
+}
+
+func main() {
+	// Graceful shutdown: dừng nhận message mới khi có SIGTERM, xử lý nốt message hiện tại
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	var consumer Consumer = stubConsumer{}
+	log.Println("golden-app worker started")
+
+	for {
+		msg, err := consumer.Receive(ctx)
+		if ctx.Err() != nil {
+			break
+		}
+		if err != nil {
+			log.Printf("receive failed: %v", err)
+			continue
+		}
+		if msg == nil {
+			continue
+		}
+
+		if err := handle(context.WithoutCancel(ctx), msg); err != nil {
+			log.Printf("message %s failed: %v", msg.ID, err)
+			continue
+		}
+		if err := consumer.Ack(context.WithoutCancel(ctx), msg); err != nil {
+			log.Printf("ack %s failed: %v", msg.ID, err)
+		}
+	}
+
+	log.Println("golden-app worker stopped")
+}
@@ -0,0 +1,41 @@
+// golden-app worker: thay receive/ack bằng client queue thật (SQS, Pub/Sub, Kafka, ...)
+
+let stopping = false;
+
+async function receive() {
+  await new Promise((resolve) => setTimeout(resolve, 5000));
+  return null;
+}
+
+async function ack(message) {}
+
+async function handle(message) {
+  console.log(`processing message ${message.id}`);
+}
+
+async function main() {
+  console.log('golden-app worker started');
+  while (!stopping) {
+    const message = await receive();
+    if (!message) continue;
+    try {
+      await handle(message);
+      await ack(message);
+    } catch (err) {
+      console.error(`message ${message.id} failed`, err);
+    }
+  }
+  console.log('golden-app worker stopped');
+}
+
+// Graceful shutdown: xử lý nốt message hiện tại rồi thoát
+for (const signal of ['SIGINT', 'SIGTERM']) {
+  process.on(signal, () => {
+    stopping = true;
+  });
+}
+
+main().catch((err) => {
+  console.error(err);
+  process.exit(1);
+});